@@ -0,0 +1,177 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// altsim simulates mining on an Altcoinchain network to evaluate difficulty
+// and reward economics offline.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Altcoinchain/go-altcoinchain/core"
+	"github.com/Altcoinchain/go-altcoinchain/internal/flags"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	gitCommit = "" // Git SHA1 commit hash of the release (set via linker flags)
+	gitDate   = ""
+
+	app = flags.NewApp(gitCommit, gitDate, "the Altcoinchain mining economics simulator")
+)
+
+var (
+	genesisFlag = &cli.StringFlag{
+		Name:  "genesis",
+		Usage: "Genesis JSON file to simulate on top of (default = mainnet config, empty state)",
+	}
+	blocksFlag = &cli.IntFlag{
+		Name:  "blocks",
+		Usage: "Number of blocks to simulate",
+		Value: 10000,
+	}
+	hashrateFlag = &cli.StringFlag{
+		Name:  "hashrate",
+		Usage: "Network hashrate curve as comma separated block:hashrate points, linearly interpolated (H/s)",
+		Value: "0:1000000000",
+	}
+	jitterFlag = &cli.Uint64Flag{
+		Name:  "jitter",
+		Usage: "Maximum random clock skew in seconds added to the block timestamps",
+	}
+	uncleRateFlag = &cli.Float64Flag{
+		Name:  "uncles",
+		Usage: "Probability of every competing block becoming an included uncle",
+		Value: 0.05,
+	}
+	stakeFlag = &cli.Float64Flag{
+		Name:  "stake",
+		Usage: "Fraction of the PoS/PoT allocation claimed by stakers",
+	}
+	txVolumeFlag = &cli.Float64Flag{
+		Name:  "txvolume",
+		Usage: "Fraction of the PoS/PoT allocation claimed by transaction volume participants",
+	}
+	trustFlag = &cli.Float64Flag{
+		Name:  "trust",
+		Usage: "Fraction of the PoS/PoT allocation claimed by trusted (uptime) participants",
+	}
+	posFactorFlag = &cli.Uint64Flag{
+		Name:  "difficulty.pos",
+		Usage: "PoS factor of the custom difficulty (all factors zero = plain ethash difficulty)",
+	}
+	potFactorFlag = &cli.Uint64Flag{
+		Name:  "difficulty.pot",
+		Usage: "PoT factor of the custom difficulty",
+	}
+	trustFactorFlag = &cli.Uint64Flag{
+		Name:  "difficulty.trust",
+		Usage: "Trust factor of the custom difficulty",
+	}
+	seedFlag = &cli.Int64Flag{
+		Name:  "seed",
+		Usage: "Seed of the random source, for reproducible runs",
+		Value: 1,
+	}
+	outputFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "File to write the CSV time series into (default = stdout)",
+	}
+)
+
+func init() {
+	app.Flags = []cli.Flag{
+		genesisFlag,
+		blocksFlag,
+		hashrateFlag,
+		jitterFlag,
+		uncleRateFlag,
+		stakeFlag,
+		txVolumeFlag,
+		trustFlag,
+		posFactorFlag,
+		potFactorFlag,
+		trustFactorFlag,
+		seedFlag,
+		outputFlag,
+	}
+	app.Action = simulate
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// simulate runs the simulation configured by the command line flags.
+func simulate(ctx *cli.Context) error {
+	curve, err := parseHashrateCurve(ctx.String(hashrateFlag.Name))
+	if err != nil {
+		return err
+	}
+	config := &simConfig{
+		Blocks:      ctx.Int(blocksFlag.Name),
+		Hashrate:    curve,
+		Jitter:      ctx.Uint64(jitterFlag.Name),
+		UncleRate:   ctx.Float64(uncleRateFlag.Name),
+		Stake:       ctx.Float64(stakeFlag.Name),
+		TxVolume:    ctx.Float64(txVolumeFlag.Name),
+		Trust:       ctx.Float64(trustFlag.Name),
+		PoSFactor:   ctx.Uint64(posFactorFlag.Name),
+		PoTFactor:   ctx.Uint64(potFactorFlag.Name),
+		TrustFactor: ctx.Uint64(trustFactorFlag.Name),
+		Seed:        ctx.Int64(seedFlag.Name),
+	}
+	if err := config.validate(); err != nil {
+		return err
+	}
+	genesis := defaultGenesis()
+	if path := ctx.String(genesisFlag.Name); path != "" {
+		if genesis, err = loadGenesis(path); err != nil {
+			return err
+		}
+	}
+	var out io.Writer = os.Stdout
+	if path := ctx.String(outputFlag.Name); path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+	return run(genesis, config, out)
+}
+
+// loadGenesis reads a genesis specification from a JSON file.
+func loadGenesis(path string) (*core.Genesis, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	genesis := new(core.Genesis)
+	if err := json.NewDecoder(file).Decode(genesis); err != nil {
+		return nil, fmt.Errorf("invalid genesis file: %v", err)
+	}
+	return genesis, nil
+}
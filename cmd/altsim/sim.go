@@ -0,0 +1,275 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
+	"github.com/Altcoinchain/go-altcoinchain/core"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/core/state"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+)
+
+var (
+	// minerCoinbase is the coinbase of all simulated canonical blocks.
+	minerCoinbase = common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	// uncleCoinbase is the coinbase of all simulated uncles.
+	uncleCoinbase = common.HexToAddress("0x1000000000000000000000000000000000000002")
+
+	// csvHeader lists the columns of the simulation output.
+	csvHeader = []string{
+		"block", "timestamp", "blocktime", "difficulty", "hashrate", "uncles",
+		"issuance_pow", "issuance_uncles", "issuance_stake", "issuance_txvolume", "issuance_trust",
+		"issuance_treasury", "treasury_balance",
+	}
+)
+
+// hashratePoint is a single point of the network hashrate curve.
+type hashratePoint struct {
+	block    uint64
+	hashrate float64
+}
+
+// hashrateCurve is a piecewise linear network hashrate curve.
+type hashrateCurve []hashratePoint
+
+// parseHashrateCurve parses a comma separated list of block:hashrate points.
+func parseHashrateCurve(input string) (hashrateCurve, error) {
+	var curve hashrateCurve
+	for _, entry := range strings.Split(input, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid hashrate point %q, want block:hashrate", entry)
+		}
+		block, err := strconv.ParseUint(parts[0], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hashrate point block %q: %v", parts[0], err)
+		}
+		hashrate, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || hashrate <= 0 {
+			return nil, fmt.Errorf("invalid hashrate point rate %q", parts[1])
+		}
+		curve = append(curve, hashratePoint{block: block, hashrate: hashrate})
+	}
+	sort.Slice(curve, func(i, j int) bool { return curve[i].block < curve[j].block })
+	return curve, nil
+}
+
+// at returns the network hashrate at the given block, interpolating linearly
+// between the points of the curve and holding the edge values outside of it.
+func (c hashrateCurve) at(block uint64) float64 {
+	if block <= c[0].block {
+		return c[0].hashrate
+	}
+	for i := 1; i < len(c); i++ {
+		if block <= c[i].block {
+			prev, next := c[i-1], c[i]
+			frac := float64(block-prev.block) / float64(next.block-prev.block)
+			return prev.hashrate + frac*(next.hashrate-prev.hashrate)
+		}
+	}
+	return c[len(c)-1].hashrate
+}
+
+// simConfig contains the parameters of a simulation run.
+type simConfig struct {
+	Blocks      int           // Number of blocks to simulate
+	Hashrate    hashrateCurve // Network hashrate over the simulated blocks
+	Jitter      uint64        // Maximum random clock skew of the block timestamps
+	UncleRate   float64       // Probability of a competing block being included as uncle
+	Stake       float64       // Fraction of the PoS/PoT allocation claimed by stakers
+	TxVolume    float64       // Fraction of the PoS/PoT allocation claimed by transaction volume
+	Trust       float64       // Fraction of the PoS/PoT allocation claimed by trusted nodes
+	PoSFactor   uint64        // PoS factor of the custom difficulty
+	PoTFactor   uint64        // PoT factor of the custom difficulty
+	TrustFactor uint64        // Trust factor of the custom difficulty
+	Seed        int64         // Seed of the random source
+}
+
+// validate checks that the simulation parameters are sane.
+func (c *simConfig) validate() error {
+	if c.Blocks <= 0 {
+		return errors.New("number of blocks must be positive")
+	}
+	if len(c.Hashrate) == 0 {
+		return errors.New("hashrate curve is empty")
+	}
+	if c.UncleRate < 0 || c.UncleRate > 1 {
+		return fmt.Errorf("uncle rate %v out of range [0, 1]", c.UncleRate)
+	}
+	for _, share := range []float64{c.Stake, c.TxVolume, c.Trust} {
+		if share < 0 || share > 1 {
+			return fmt.Errorf("participation %v out of range [0, 1]", share)
+		}
+	}
+	if c.Stake+c.TxVolume+c.Trust > 1 {
+		return errors.New("participations exceed the full PoS/PoT allocation")
+	}
+	return nil
+}
+
+// customDifficulty reports whether the custom hybrid difficulty is simulated.
+func (c *simConfig) customDifficulty() bool {
+	return c.PoSFactor != 0 || c.PoTFactor != 0 || c.TrustFactor != 0
+}
+
+// defaultGenesis returns the genesis simulated if none is specified: the main
+// network configuration and difficulty without any allocations.
+func defaultGenesis() *core.Genesis {
+	genesis := core.DefaultGenesisBlock()
+	genesis.Alloc = nil
+	return genesis
+}
+
+// simChain is the minimal chain reader needed to evaluate the difficulty.
+type simChain struct {
+	config *params.ChainConfig
+}
+
+func (c *simChain) Config() *params.ChainConfig                             { return c.config }
+func (c *simChain) CurrentHeader() *types.Header                            { return nil }
+func (c *simChain) GetHeader(hash common.Hash, number uint64) *types.Header { return nil }
+func (c *simChain) GetHeaderByNumber(number uint64) *types.Header           { return nil }
+func (c *simChain) GetHeaderByHash(hash common.Hash) *types.Header          { return nil }
+func (c *simChain) GetTd(hash common.Hash, number uint64) *big.Int          { return nil }
+
+// run simulates the configured number of blocks on top of the genesis and writes
+// the resulting time series as CSV into out.
+func run(genesis *core.Genesis, config *simConfig, out io.Writer) error {
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		engine = ethash.NewFaker()
+		chain  = &simChain{config: genesis.Config}
+		rng    = rand.New(rand.NewSource(config.Seed))
+	)
+	parent, err := genesis.Commit(db)
+	if err != nil {
+		return err
+	}
+	blocks, _ := core.GenerateChain(genesis.Config, parent, engine, db, config.Blocks, func(i int, b *core.BlockGen) {
+		b.SetCoinbase(minerCoinbase)
+
+		// Sample the time needed to find the block at the current hashrate, the
+		// difficulty of the parent being a close enough approximation.
+		prev := b.PrevBlock(i - 1).Header()
+		expected := new(big.Float).Quo(new(big.Float).SetInt(prev.Difficulty), big.NewFloat(config.Hashrate.at(b.Number().Uint64())))
+		mean, _ := expected.Float64()
+
+		delay := uint64(math.Ceil(rng.ExpFloat64() * mean))
+		if config.Jitter > 0 {
+			delay += uint64(rng.Int63n(int64(config.Jitter) + 1))
+		}
+		if delay == 0 {
+			delay = 1
+		}
+		b.OffsetTime(int64(delay) - 10) // GenerateChain spaces blocks 10 seconds apart
+		if config.customDifficulty() {
+			b.SetDifficulty(ethash.CalcCustomDifficulty(chain, prev.Time+delay, prev,
+				new(big.Int).SetUint64(config.PoSFactor), new(big.Int).SetUint64(config.PoTFactor), new(big.Int).SetUint64(config.TrustFactor)))
+		}
+		// Include siblings of the parent as uncles, at most two of them
+		if i < 2 {
+			return
+		}
+		for j := 0; j < 2; j++ {
+			if rng.Float64() >= config.UncleRate {
+				continue
+			}
+			b.AddUncle(&types.Header{
+				ParentHash: b.PrevBlock(i - 2).Hash(),
+				Number:     b.PrevBlock(i - 1).Number(),
+				Coinbase:   uncleCoinbase,
+				Extra:      []byte{byte(j)},
+			})
+		}
+	})
+	return writeSeries(config, state.NewDatabase(db), parent, blocks, out)
+}
+
+// writeSeries derives the per block time series of the simulated chain and
+// writes it as CSV. Miner and uncle issuance are measured on the state, while
+// the PoS/PoT allocation is split according to the configured participation,
+// with the unclaimed remainder accruing to the treasury.
+func writeSeries(config *simConfig, sdb state.Database, parent *types.Block, blocks []*types.Block, out io.Writer) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+	var (
+		treasury  = new(big.Int)
+		prevMiner = new(big.Int)
+		prevUncle = new(big.Int)
+	)
+	claim := func(allocation *big.Int, share float64) *big.Int {
+		amount, _ := new(big.Float).Mul(new(big.Float).SetInt(allocation), big.NewFloat(share)).Int(nil)
+		return amount
+	}
+	for _, block := range blocks {
+		statedb, err := state.New(block.Root(), sdb, nil)
+		if err != nil {
+			return err
+		}
+		miner, uncle := statedb.GetBalance(minerCoinbase), statedb.GetBalance(uncleCoinbase)
+
+		allocation := ethash.ExpectedRewards(genesis.Config, block.Header(), block.Uncles(), nil).Treasury
+		var (
+			stake     = claim(allocation, config.Stake)
+			txvolume  = claim(allocation, config.TxVolume)
+			trust     = claim(allocation, config.Trust)
+			remainder = new(big.Int).Sub(allocation, stake)
+		)
+		remainder.Sub(remainder, txvolume)
+		remainder.Sub(remainder, trust)
+		treasury.Add(treasury, remainder)
+
+		record := []string{
+			block.Number().String(),
+			strconv.FormatUint(block.Time(), 10),
+			strconv.FormatUint(block.Time()-parent.Time(), 10),
+			block.Difficulty().String(),
+			strconv.FormatFloat(config.Hashrate.at(block.NumberU64()), 'f', 0, 64),
+			strconv.Itoa(len(block.Uncles())),
+			new(big.Int).Sub(miner, prevMiner).String(),
+			new(big.Int).Sub(uncle, prevUncle).String(),
+			stake.String(),
+			txvolume.String(),
+			trust.String(),
+			remainder.String(),
+			treasury.String(),
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+		parent, prevMiner, prevUncle = block, miner, uncle
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+)
+
+func TestHashrateCurve(t *testing.T) {
+	curve, err := parseHashrateCurve("100:3000, 0:1000")
+	if err != nil {
+		t.Fatalf("failed to parse curve: %v", err)
+	}
+	tests := []struct {
+		block uint64
+		want  float64
+	}{
+		{0, 1000}, {50, 2000}, {100, 3000}, {1000, 3000},
+	}
+	for _, tt := range tests {
+		if have := curve.at(tt.block); have != tt.want {
+			t.Errorf("block %d: hashrate mismatch: have %v, want %v", tt.block, have, tt.want)
+		}
+	}
+	for _, input := range []string{"", "100", "x:1", "1:-5"} {
+		if _, err := parseHashrateCurve(input); err == nil {
+			t.Errorf("invalid curve %q accepted", input)
+		}
+	}
+}
+
+func TestSimulation(t *testing.T) {
+	curve, _ := parseHashrateCurve("0:1000000000")
+	config := &simConfig{
+		Blocks:    64,
+		Hashrate:  curve,
+		UncleRate: 0.5,
+		Stake:     0.5,
+		Seed:      1,
+	}
+	if err := config.validate(); err != nil {
+		t.Fatalf("invalid config: %v", err)
+	}
+	var out bytes.Buffer
+	if err := run(defaultGenesis(), config, &out); err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV output: %v", err)
+	}
+	if len(records) != config.Blocks+1 {
+		t.Fatalf("record count mismatch: have %d, want %d", len(records), config.Blocks+1)
+	}
+	var uncles bool
+	for _, record := range records[1:] {
+		if record[5] != "0" {
+			uncles = true
+		}
+		if record[8] != record[11] {
+			t.Errorf("block %s: stake and treasury issuance differ with half participation: %s != %s", record[0], record[8], record[11])
+		}
+	}
+	if !uncles {
+		t.Errorf("no uncles simulated")
+	}
+}
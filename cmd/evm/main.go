@@ -226,6 +226,7 @@ func init() {
 		stateTransitionCommand,
 		transactionCommand,
 		blockBuilderCommand,
+		statelessCommand,
 	}
 }
 
@@ -0,0 +1,110 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
+	"github.com/Altcoinchain/go-altcoinchain/consensus"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/beacon"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/clique"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
+	"github.com/Altcoinchain/go-altcoinchain/core"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/core/stateless"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+	"github.com/Altcoinchain/go-altcoinchain/rlp"
+	"github.com/urfave/cli/v2"
+)
+
+var statelessCommand = &cli.Command{
+	Action:    statelessCmd,
+	Name:      "stateless",
+	Usage:     "executes a block using only its witness and checks the resulting state root",
+	ArgsUsage: "<block> <witness>",
+	Description: `
+The block file holds the hex or binary encoded RLP of the block, as returned by
+debug_getRawBlock. The witness file holds the JSON witness returned by
+debug_executionWitness. The chain configuration is read from the genesis file
+given with --prestate, defaulting to mainnet.`,
+}
+
+func statelessCmd(ctx *cli.Context) error {
+	if ctx.Args().Len() != 2 {
+		return errors.New("block and witness files required")
+	}
+	block, err := readBlock(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	input, err := os.ReadFile(ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	witness := new(stateless.Witness)
+	if err := json.Unmarshal(input, witness); err != nil {
+		return fmt.Errorf("invalid witness: %v", err)
+	}
+	config := params.MainnetChainConfig
+	if ctx.IsSet(GenesisFlag.Name) {
+		config = readGenesis(ctx.String(GenesisFlag.Name)).Config
+	}
+	// The block is not sealed again, so the seal verification doesn't matter
+	var engine consensus.Engine
+	if config.Clique != nil {
+		engine = clique.New(config.Clique, rawdb.NewMemoryDatabase())
+	} else {
+		engine = ethash.NewFaker()
+	}
+	root, receiptRoot, err := core.ExecuteStateless(config, beacon.New(engine), block, witness)
+	if err != nil {
+		return fmt.Errorf("failed to execute block %d: %v", block.NumberU64(), err)
+	}
+	if receiptRoot != block.ReceiptHash() {
+		return fmt.Errorf("receipt root mismatch: have %x, want %x", receiptRoot, block.ReceiptHash())
+	}
+	if root != block.Root() {
+		return fmt.Errorf("state root mismatch: have %x, want %x", root, block.Root())
+	}
+	fmt.Printf("block %d (%x) verified, state root %x\n", block.NumberU64(), block.Hash(), root)
+	return nil
+}
+
+// readBlock reads a block from a file holding its RLP encoding, either in binary
+// or as a (possibly quoted) hex string.
+func readBlock(path string) (*types.Block, error) {
+	input, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if text := strings.Trim(strings.TrimSpace(string(input)), `"`); strings.HasPrefix(text, "0x") {
+		if input, err = hexutil.Decode(text); err != nil {
+			return nil, fmt.Errorf("invalid block hex: %v", err)
+		}
+	}
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(input, block); err != nil {
+		return nil, fmt.Errorf("invalid block: %v", err)
+	}
+	return block, nil
+}
@@ -18,6 +18,7 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -37,8 +38,11 @@ import (
 	"github.com/Altcoinchain/go-altcoinchain/internal/flags"
 	"github.com/Altcoinchain/go-altcoinchain/log"
 	"github.com/Altcoinchain/go-altcoinchain/metrics"
+	"github.com/Altcoinchain/go-altcoinchain/miner/dagshare"
+	"github.com/Altcoinchain/go-altcoinchain/miner/pool"
 	"github.com/Altcoinchain/go-altcoinchain/node"
 	"github.com/Altcoinchain/go-altcoinchain/params"
+	"github.com/Altcoinchain/go-altcoinchain/rpc"
 	"github.com/naoina/toml"
 )
 
@@ -52,6 +56,18 @@ var (
 		Description: `The dumpconfig command shows configuration values.`,
 	}
 
+	openrpcCommand = &cli.Command{
+		Action:    dumpOpenRPC,
+		Name:      "openrpc",
+		Usage:     "Export the OpenRPC document of the RPC API",
+		ArgsUsage: "[<filename>]",
+		Flags:     flags.Merge(nodeFlags, rpcFlags),
+		Description: `
+The openrpc command writes the OpenRPC document describing all RPC methods
+of the node configured by the given flags, including the authenticated ones.
+The same document is served by running nodes at rpc_discover.`,
+	}
+
 	configFileFlag = &cli.StringFlag{
 		Name:     "config",
 		Usage:    "TOML configuration file",
@@ -90,6 +106,8 @@ type gethConfig struct {
 	Node     node.Config
 	Ethstats ethstatsConfig
 	Metrics  metrics.Config
+	Pool     pool.Config
+	DAGShare dagshare.Config
 }
 
 func loadConfig(file string, cfg *gethConfig) error {
@@ -121,9 +139,11 @@ func defaultNodeConfig() node.Config {
 func makeConfigNode(ctx *cli.Context) (*node.Node, gethConfig) {
 	// Load defaults.
 	cfg := gethConfig{
-		Eth:     ethconfig.Defaults,
-		Node:    defaultNodeConfig(),
-		Metrics: metrics.DefaultConfig,
+		Eth:      ethconfig.Defaults,
+		Node:     defaultNodeConfig(),
+		Metrics:  metrics.DefaultConfig,
+		Pool:     pool.DefaultConfig,
+		DAGShare: dagshare.DefaultConfig,
 	}
 
 	// Load config file.
@@ -149,6 +169,8 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, gethConfig) {
 		cfg.Ethstats.URL = ctx.String(utils.EthStatsURLFlag.Name)
 	}
 	applyMetricConfig(ctx, &cfg)
+	utils.SetPoolConfig(ctx, &cfg.Pool)
+	utils.SetDAGShareConfig(ctx, &cfg.DAGShare)
 
 	return stack, cfg
 }
@@ -196,6 +218,16 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 	if cfg.Ethstats.URL != "" {
 		utils.RegisterEthStatsService(stack, backend, cfg.Ethstats.URL)
 	}
+
+	// Add the solo pool share ledger if requested.
+	if cfg.Pool.Enabled {
+		utils.RegisterPoolService(stack, backend, eth, &cfg.Pool)
+	}
+
+	// Add the ethash DAG pre-generation and sharing service if requested.
+	if cfg.DAGShare.Enabled {
+		utils.RegisterDAGShareService(stack, backend, &cfg.DAGShare)
+	}
 	return stack, backend
 }
 
@@ -228,6 +260,39 @@ func dumpConfig(ctx *cli.Context) error {
 	return nil
 }
 
+// dumpOpenRPC writes the OpenRPC document of all APIs registered by the node.
+func dumpOpenRPC(ctx *cli.Context) error {
+	stack, _ := makeFullNode(ctx)
+	defer stack.Close()
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	_, apis := stack.GetAPIs()
+	for _, api := range apis {
+		if err := server.RegisterName(api.Namespace, api.Service); err != nil {
+			return err
+		}
+	}
+	doc := server.OpenRPC()
+	doc.Info.Title = "Geth JSON-RPC API"
+	doc.Info.Version = params.VersionWithCommit(gitCommit, gitDate)
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	dump := os.Stdout
+	if ctx.NArg() > 0 {
+		dump, err = os.OpenFile(ctx.Args().Get(0), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer dump.Close()
+	}
+	_, err = dump.Write(append(out, '\n'))
+	return err
+}
+
 func applyMetricConfig(ctx *cli.Context, cfg *gethConfig) {
 	if ctx.IsSet(utils.MetricsEnabledFlag.Name) {
 		cfg.Metrics.Enabled = ctx.Bool(utils.MetricsEnabledFlag.Name)
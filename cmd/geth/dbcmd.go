@@ -18,6 +18,7 @@ package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -31,6 +32,7 @@ import (
 	"github.com/Altcoinchain/go-altcoinchain/common"
 	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
 	"github.com/Altcoinchain/go-altcoinchain/console/prompt"
+	"github.com/Altcoinchain/go-altcoinchain/core"
 	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
 	"github.com/Altcoinchain/go-altcoinchain/core/state/snapshot"
 	"github.com/Altcoinchain/go-altcoinchain/core/types"
@@ -44,6 +46,10 @@ import (
 )
 
 var (
+	scrubRateFlag = &cli.IntFlag{
+		Name:  "rate",
+		Usage: "Maximum number of blocks checked per second (0 = unlimited)",
+	}
 	removedbCommand = &cli.Command{
 		Action:    removeDB,
 		Name:      "removedb",
@@ -71,6 +77,10 @@ Remove blockchain and state databases`,
 			dbMetadataCmd,
 			dbMigrateFreezerCmd,
 			dbCheckStateContentCmd,
+			dbPruneHistoryCmd,
+			dbBackupCmd,
+			dbRestoreCmd,
+			dbScrubCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -206,6 +216,60 @@ WARNING: This is a low-level operation which may cause database corruption!`,
 		Description: `The freezer-migrate command checks your database for receipts in a legacy format and updates those.
 WARNING: please back-up the receipt files in your ancients before running this command.`,
 	}
+	dbPruneHistoryCmd = &cli.Command{
+		Action:    pruneHistory,
+		Name:      "prune-history",
+		Usage:     "Delete the bodies and receipts of old ancient blocks",
+		ArgsUsage: "",
+		Flags: flags.Merge([]cli.Flag{
+			utils.SyncModeFlag,
+			utils.HistoryRetainFlag,
+		}, utils.NetworkFlags, utils.DatabasePathFlags),
+		Description: `The prune-history command deletes the bodies and receipts of all ancient blocks
+more than --history.retain blocks below the chain head. Headers are retained. The
+expired history can't be recovered other than by resyncing the node.`,
+	}
+	dbBackupCmd = &cli.Command{
+		Action:    backupDB,
+		Name:      "backup",
+		Usage:     "Create a consistent copy of the chain database",
+		ArgsUsage: "<dir>",
+		Flags: flags.Merge([]cli.Flag{
+			utils.SyncModeFlag,
+		}, utils.NetworkFlags, utils.DatabasePathFlags),
+		Description: `The backup command copies the key-value store from a snapshot and the freezer
+files up to the synced ancient count into a new directory, along with a manifest
+describing the backup. A running node can be backed up with admin.backup instead.`,
+	}
+	dbRestoreCmd = &cli.Command{
+		Action:    restoreDB,
+		Name:      "restore",
+		Usage:     "Restore the chain database from a backup",
+		ArgsUsage: "<dir>",
+		Flags: flags.Merge([]cli.Flag{
+			utils.SyncModeFlag,
+		}, utils.NetworkFlags, utils.DatabasePathFlags),
+		Description: `The restore command validates the manifest of a backup and the consistency of its
+head block, then copies it into the datadir. The chain database must not exist.`,
+	}
+	dbScrubCmd = &cli.Command{
+		Action:    scrubDB,
+		Name:      "scrub",
+		Usage:     "Verify the consistency of the chain data",
+		ArgsUsage: "[<start> [<end>]]",
+		Flags: flags.Merge([]cli.Flag{
+			utils.SyncModeFlag,
+			scrubRateFlag,
+		}, utils.NetworkFlags, utils.DatabasePathFlags),
+		Description: `The scrub command checks that the canonical hashes, headers, bodies, receipts and
+total difficulties of the canonical blocks in the key-value store and the freezer
+are consistent with each other. Without a start block, an interrupted scrub is
+resumed. The end defaults to the head block.
+
+The issues found are only reported. A running node can scrub its database in the
+background with admin.startScrub, which can repair the corrupted items with data
+fetched from its peers.`,
+	}
 )
 
 func removeDB(ctx *cli.Context) error {
@@ -517,7 +581,7 @@ func dbDumpTrie(ctx *cli.Context) error {
 			return err
 		}
 	}
-	theTrie, err := trie.New(common.Hash{}, stRoot, trie.NewDatabase(db))
+	theTrie, err := trie.New(common.Hash{}, stRoot, utils.MakeTrieDatabase(db, false))
 	if err != nil {
 		return err
 	}
@@ -785,6 +849,122 @@ func freezerMigrate(ctx *cli.Context) error {
 	return nil
 }
 
+func pruneHistory(ctx *cli.Context) error {
+	retain := ctx.Uint64(utils.HistoryRetainFlag.Name)
+	if retain == 0 {
+		return fmt.Errorf("missing --%s", utils.HistoryRetainFlag.Name)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	start := time.Now()
+	tail, err := rawdb.PruneHistory(db, retain, nil)
+	if err != nil {
+		return err
+	}
+	log.Info("History pruning finished", "tail", tail, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+func backupDB(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	engine := rawdb.PreexistingDatabase(stack.ResolvePath("chaindata"))
+	_, err := rawdb.Backup(db, ctx.Args().Get(0), engine)
+	return err
+}
+
+func restoreDB(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	if ctx.IsSet(utils.AncientFlag.Name) {
+		return fmt.Errorf("restoring with a custom --%s is not supported", utils.AncientFlag.Name)
+	}
+	_, err := rawdb.Restore(ctx.Args().Get(0), stack.ResolvePath("chaindata"))
+	return err
+}
+
+func scrubDB(ctx *cli.Context) error {
+	if ctx.NArg() > 2 {
+		return fmt.Errorf("max 2 arguments: %v", ctx.Command.ArgsUsage)
+	}
+	var (
+		stack, _  = makeConfigNode(ctx)
+		interrupt = make(chan os.Signal, 1)
+		stop      = make(chan struct{})
+	)
+	defer stack.Close()
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	defer close(interrupt)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted during scrub, saving progress")
+		}
+		close(stop)
+	}()
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	head := rawdb.ReadHeadBlockHash(db)
+	number := rawdb.ReadHeaderNumber(db, head)
+	if number == nil {
+		return errors.New("head block not found")
+	}
+	var (
+		start uint64
+		end   = *number
+		err   error
+	)
+	if ctx.NArg() > 0 {
+		if start, err = strconv.ParseUint(ctx.Args().Get(0), 10, 64); err != nil {
+			return fmt.Errorf("invalid start block: %v", err)
+		}
+	} else if progress := rawdb.ReadScrubProgress(db); progress != nil {
+		start = *progress
+		log.Info("Resuming interrupted scrub", "start", start)
+	}
+	if ctx.NArg() > 1 {
+		last, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid end block: %v", err)
+		}
+		if last < end {
+			end = last
+		}
+	}
+	if start > end {
+		return fmt.Errorf("invalid range [%d, %d]", start, end)
+	}
+	var issues int
+	report := func(issue *core.ScrubIssue) bool {
+		log.Warn("Found corrupted chain data", "number", issue.Number, "item", issue.Kind, "frozen", issue.Frozen, "err", issue.Err)
+		issues++
+		return false
+	}
+	if next := core.NewScrubber(db, ctx.Int(scrubRateFlag.Name)).Run(start, end, report, stop); next <= end {
+		return fmt.Errorf("scrub interrupted at block %d, %d issues found so far", next, issues)
+	}
+	if issues > 0 {
+		return fmt.Errorf("%d issues found", issues)
+	}
+	return nil
+}
+
 // dbHasLegacyReceipts checks freezer entries for legacy receipts. It stops at the first
 // non-empty receipt and checks its format. The index of this first non-empty element is
 // the second return parameter.
@@ -808,7 +988,7 @@ func dbHasLegacyReceipts(db ethdb.Database, firstIdx uint64) (bool, uint64, erro
 	// Find first block with non-empty receipt, only if
 	// the index is not already provided.
 	if firstIdx == 0 {
-		for i := uint64(0); i < numAncients; i++ {
+		for i := rawdb.ReadHistoryTail(db); i < numAncients; i++ {
 			blob, err = db.Ancient("receipts", i)
 			if err != nil {
 				return false, 0, err
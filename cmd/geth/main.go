@@ -95,6 +95,12 @@ var (
 		utils.GCModeFlag,
 		utils.SnapshotFlag,
 		utils.TxLookupLimitFlag,
+		utils.HistoryRetainFlag,
+		utils.StateSchemeFlag,
+		utils.StateDiffsFlag,
+		utils.StateDiffRetainFlag,
+		utils.StateHistoryFlag,
+		utils.TxWorkersFlag,
 		utils.LightServeFlag,
 		utils.LightIngressFlag,
 		utils.LightEgressFlag,
@@ -131,8 +137,20 @@ var (
 		utils.MinerGasPriceFlag,
 		utils.MinerEtherbaseFlag,
 		utils.MinerExtraDataFlag,
+		utils.MinerBeneficiariesFlag,
 		utils.MinerRecommitIntervalFlag,
 		utils.MinerNoVerifyFlag,
+		utils.PoolEnabledFlag,
+		utils.PoolWindowFlag,
+		utils.PoolShareDifficultyFlag,
+		utils.PoolFeeFlag,
+		utils.PoolPayoutsFlag,
+		utils.PoolPayoutThresholdFlag,
+		utils.DAGShareEnabledFlag,
+		utils.DAGShareAddrFlag,
+		utils.DAGShareSecretFlag,
+		utils.DAGSharePeersFlag,
+		utils.DAGShareDistanceFlag,
 		utils.NATFlag,
 		utils.NoDiscoverFlag,
 		utils.DiscoveryV5Flag,
@@ -185,6 +203,12 @@ var (
 		utils.RPCGlobalEVMTimeoutFlag,
 		utils.RPCGlobalTxFeeCapFlag,
 		utils.AllowUnprotectedTxs,
+		utils.BatchRequestLimitFlag,
+		utils.BatchResponseMaxSizeFlag,
+		utils.RPCAccessPolicyFlag,
+		utils.RPCAuditLogFlag,
+		utils.RPCAuditBackendFlag,
+		utils.RPCAuditResponseHashFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -215,6 +239,8 @@ func init() {
 		initCommand,
 		importCommand,
 		exportCommand,
+		importHistoryCommand,
+		exportHistoryCommand,
 		importPreimagesCommand,
 		exportPreimagesCommand,
 		removedbCommand,
@@ -235,6 +261,7 @@ func init() {
 		licenseCommand,
 		// See config.go
 		dumpConfigCommand,
+		openrpcCommand,
 		// see dbcmd.go
 		dbCommand,
 		// See cmd/utils/flags_legacy.go
@@ -20,17 +20,21 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/Altcoinchain/go-altcoinchain/cmd/utils"
 	"github.com/Altcoinchain/go-altcoinchain/common"
 	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
 	"github.com/Altcoinchain/go-altcoinchain/core/state"
+	"github.com/Altcoinchain/go-altcoinchain/core/state/layout"
 	"github.com/Altcoinchain/go-altcoinchain/core/state/pruner"
 	"github.com/Altcoinchain/go-altcoinchain/core/state/snapshot"
 	"github.com/Altcoinchain/go-altcoinchain/core/types"
 	"github.com/Altcoinchain/go-altcoinchain/crypto"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb"
 	"github.com/Altcoinchain/go-altcoinchain/internal/flags"
 	"github.com/Altcoinchain/go-altcoinchain/log"
 	"github.com/Altcoinchain/go-altcoinchain/rlp"
@@ -109,6 +113,26 @@ data, and verifies that all snapshot storage data has a corresponding account.
 				Description: `
 geth snapshot inspect-account <address | hash> checks all snapshot layers and prints out
 information about the specified address. 
+`,
+			},
+			{
+				Name:      "inspect-contract",
+				Usage:     "Decode the storage of a contract using its storage layout",
+				ArgsUsage: "<address> [<blockHash> | <blockNum>]",
+				Action:    inspectContract,
+				Flags: flags.Merge([]cli.Flag{
+					utils.StorageLayoutFlag,
+					utils.DumpLimitFlag,
+				}, utils.NetworkFlags, utils.DatabasePathFlags),
+				Description: `
+geth snapshot inspect-contract <address> [<blockHash> | <blockNum>]
+decodes the state variables of a contract from the snapshot, using the Solidity
+storage layout emitted by solc. The layout registered via debug_registerStorageLayout
+is used, unless a file is given with --layout. If no block is provided, the latest
+block is used.
+
+Mapping entries are only found if the preimages of their locations were recorded
+while executing the transactions writing them (--vmdebug).
 `,
 			},
 			{
@@ -159,6 +183,39 @@ as the backend data source, making this command a lot faster.
 
 The argument is interpreted as block number or hash. If none is provided, the latest
 block is used.
+`,
+			},
+			{
+				Name:      "export",
+				Usage:     "Export the state of a block from the snapshot into checksummed files",
+				ArgsUsage: "<dir> [<blockHash> | <blockNum>]",
+				Action:    exportSnapshot,
+				Flags: flags.Merge([]cli.Flag{
+					utils.ExportChunkSizeFlag,
+				}, utils.NetworkFlags, utils.DatabasePathFlags),
+				Description: `
+geth snapshot export <dir> [<blockHash> | <blockNum>]
+writes all the accounts, storage slots and contract codes of the state of the
+given block into the new directory <dir>. The state is split into chunk files,
+listed along with their sha256 checksums in the manifest.json file. If no block
+is provided, the latest block is used.
+
+The snapshot of the block's state needs to be fully generated.
+`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import the state exported by 'geth snapshot export'",
+				ArgsUsage: "<dir>",
+				Action:    importSnapshot,
+				Flags:     flags.Merge(utils.NetworkFlags, utils.DatabasePathFlags),
+				Description: `
+geth snapshot import <dir>
+rebuilds the state trie and the snapshot from the files in <dir>, written by
+'geth snapshot export'. The exported block needs to be part of the local
+canonical chain, e.g. imported via 'geth import' or synced beforehand: its
+header is trusted and the rebuilt state is verified against its state root.
+Any existing snapshot is replaced.
 `,
 			},
 		},
@@ -204,7 +261,7 @@ func verifyState(ctx *cli.Context) error {
 		log.Error("Failed to load head block")
 		return errors.New("no head block")
 	}
-	snaptree, err := snapshot.New(chaindb, trie.NewDatabase(chaindb), 256, headBlock.Root(), false, false, false)
+	snaptree, err := snapshot.New(chaindb, utils.MakeTrieDatabase(chaindb, false), 256, headBlock.Root(), false, false, false)
 	if err != nil {
 		log.Error("Failed to open snapshot tree", "err", err)
 		return err
@@ -270,7 +327,7 @@ func traverseState(ctx *cli.Context) error {
 		root = headBlock.Root()
 		log.Info("Start traversing the state", "root", root, "number", headBlock.NumberU64())
 	}
-	triedb := trie.NewDatabase(chaindb)
+	triedb := utils.MakeTrieDatabase(chaindb, false)
 	t, err := trie.NewStateTrie(common.Hash{}, root, triedb)
 	if err != nil {
 		log.Error("Failed to open trie", "root", root, "err", err)
@@ -359,7 +416,7 @@ func traverseRawState(ctx *cli.Context) error {
 		root = headBlock.Root()
 		log.Info("Start traversing the state", "root", root, "number", headBlock.NumberU64())
 	}
-	triedb := trie.NewDatabase(chaindb)
+	triedb := utils.MakeTrieDatabase(chaindb, false)
 	t, err := trie.NewStateTrie(common.Hash{}, root, triedb)
 	if err != nil {
 		log.Error("Failed to open trie", "root", root, "err", err)
@@ -479,7 +536,7 @@ func dumpState(ctx *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	snaptree, err := snapshot.New(db, trie.NewDatabase(db), 256, root, false, false, false)
+	snaptree, err := snapshot.New(db, utils.MakeTrieDatabase(db, false), 256, root, false, false, false)
 	if err != nil {
 		return err
 	}
@@ -541,6 +598,199 @@ func dumpState(ctx *cli.Context) error {
 	return nil
 }
 
+// exportSnapshot writes the state of a block from the snapshot into files.
+func exportSnapshot(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		return errors.New("expected export directory and optional block number or hash")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	header, err := readHeaderArg(chaindb, ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	snaptree, err := openSnapshotTree(chaindb)
+	if err != nil {
+		return err
+	}
+	_, err = snapshot.Export(snaptree, header, ctx.Args().First(), ctx.Uint64(utils.ExportChunkSizeFlag.Name)*1024*1024)
+	return err
+}
+
+// readHeaderArg resolves a block number or hash argument into its header. The
+// head header is returned if the argument is empty.
+func readHeaderArg(db ethdb.Database, arg string) (*types.Header, error) {
+	var header *types.Header
+	switch {
+	case arg == "":
+		header = rawdb.ReadHeadHeader(db)
+	case hashish(arg):
+		hash := common.HexToHash(arg)
+		if number := rawdb.ReadHeaderNumber(db, hash); number != nil {
+			header = rawdb.ReadHeader(db, hash, *number)
+		} else {
+			return nil, fmt.Errorf("block %x not found", hash)
+		}
+	default:
+		number, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if hash := rawdb.ReadCanonicalHash(db, number); hash != (common.Hash{}) {
+			header = rawdb.ReadHeader(db, hash, number)
+		} else {
+			return nil, fmt.Errorf("header for block %d not found", number)
+		}
+	}
+	if header == nil {
+		return nil, errors.New("no head block found")
+	}
+	return header, nil
+}
+
+// openSnapshotTree loads the snapshot tree at the head block. The states of the
+// recent blocks are available as its layers.
+func openSnapshotTree(db ethdb.Database) (*snapshot.Tree, error) {
+	headBlock := rawdb.ReadHeadBlock(db)
+	if headBlock == nil {
+		return nil, errors.New("no head block found")
+	}
+	snaptree, err := snapshot.New(db, utils.MakeTrieDatabase(db, false), 256, headBlock.Root(), false, false, false)
+	if err != nil {
+		log.Error("Failed to open snapshot tree", "err", err)
+		return nil, err
+	}
+	return snaptree, nil
+}
+
+// importSnapshot rebuilds the state and the snapshot from exported files,
+// verified against the local canonical header of the exported block.
+func importSnapshot(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return errors.New("expected export directory")
+	}
+	dir := ctx.Args().First()
+	manifest, err := snapshot.ReadExportManifest(dir)
+	if err != nil {
+		return err
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, false)
+	defer chaindb.Close()
+
+	if rawdb.ReadCanonicalHash(chaindb, manifest.Number) != manifest.Hash {
+		return fmt.Errorf("exported block %d [%x] is not in the local canonical chain, import the chain history first", manifest.Number, manifest.Hash)
+	}
+	header := rawdb.ReadHeader(chaindb, manifest.Hash, manifest.Number)
+	if header == nil {
+		return fmt.Errorf("header of exported block %d [%x] not found", manifest.Number, manifest.Hash)
+	}
+	return snapshot.Import(chaindb, dir, header)
+}
+
+// inspectContract decodes the storage of a contract from the snapshot.
+func inspectContract(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		return errors.New("expected contract address and optional block number or hash")
+	}
+	if !common.IsHexAddress(ctx.Args().First()) {
+		return errors.New("malformed contract address")
+	}
+	address := common.HexToAddress(ctx.Args().First())
+
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, true)
+	defer chaindb.Close()
+
+	blob := rawdb.ReadStorageLayout(chaindb, address)
+	if file := ctx.String(utils.StorageLayoutFlag.Name); file != "" {
+		var err error
+		if blob, err = os.ReadFile(file); err != nil {
+			return err
+		}
+	}
+	if len(blob) == 0 {
+		return fmt.Errorf("no storage layout registered for %#x, provide one with --%s", address, utils.StorageLayoutFlag.Name)
+	}
+	storageLayout, err := layout.Parse(blob)
+	if err != nil {
+		return fmt.Errorf("invalid storage layout: %v", err)
+	}
+	header, err := readHeaderArg(chaindb, ctx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	snaptree, err := openSnapshotTree(chaindb)
+	if err != nil {
+		return err
+	}
+	snap := snaptree.Snapshot(header.Root)
+	if snap == nil {
+		return fmt.Errorf("snapshot of block %d [%x] not available", header.Number, header.Hash())
+	}
+	accHash := crypto.Keccak256Hash(address.Bytes())
+	if account, err := snap.Account(accHash); err != nil {
+		return err
+	} else if account == nil {
+		return fmt.Errorf("account %x doesn't exist", address)
+	}
+	// Collect the keys of the slots in storage, as far as their preimages are known
+	stIt, err := snaptree.StorageIterator(header.Root, accHash, common.Hash{})
+	if err != nil {
+		return err
+	}
+	var slots []common.Hash
+	for stIt.Next() {
+		if preimage := rawdb.ReadPreimage(chaindb, stIt.Hash()); preimage != nil {
+			slots = append(slots, common.BytesToHash(preimage))
+		}
+	}
+	stIt.Release()
+	if err := stIt.Error(); err != nil {
+		return err
+	}
+	storage := &snapshotStorage{db: chaindb, snap: snap, account: accHash}
+	values, err := layout.NewDecoder(storageLayout, storage, slots, ctx.Uint64(utils.DumpLimitFlag.Name)).Decode()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(values)
+}
+
+// snapshotStorage gives the storage layout decoder access to the storage of a
+// contract in a snapshot layer.
+type snapshotStorage struct {
+	db      ethdb.Database
+	snap    snapshot.Snapshot
+	account common.Hash
+}
+
+func (s *snapshotStorage) Slot(key common.Hash) (common.Hash, error) {
+	blob, err := s.snap.Storage(s.account, crypto.Keccak256Hash(key[:]))
+	if err != nil || len(blob) == 0 {
+		return common.Hash{}, err
+	}
+	_, content, _, err := rlp.Split(blob)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(content), nil
+}
+
+func (s *snapshotStorage) Preimage(hash common.Hash) []byte {
+	return rawdb.ReadPreimage(s.db, hash)
+}
+
 // checkAccount iterates the snap data layers, and looks up the given account
 // across all layers.
 func checkAccount(ctx *cli.Context) error {
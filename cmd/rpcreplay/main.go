@@ -0,0 +1,137 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// rpcreplay replays an RPC audit log against a node and reports the responses
+// differing from the recorded ones or from those of a reference node.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/internal/flags"
+	"github.com/Altcoinchain/go-altcoinchain/rpc"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	gitCommit = "" // Git SHA1 commit hash of the release (set via linker flags)
+	gitDate   = ""
+
+	app = flags.NewApp(gitCommit, gitDate, "the RPC audit log replay tool")
+)
+
+// errStop aborts the iteration over the audit log once the limit is reached.
+var errStop = errors.New("limit reached")
+
+var (
+	logFlag = &cli.StringFlag{
+		Name:  "log",
+		Usage: "Audit log file or directory to replay",
+	}
+	dbFlag = &cli.StringFlag{
+		Name:  "db",
+		Usage: "Audit log database to replay",
+	}
+	targetFlag = &cli.StringFlag{
+		Name:  "target",
+		Usage: "Endpoint of the node to replay the calls against",
+	}
+	referenceFlag = &cli.StringFlag{
+		Name:  "reference",
+		Usage: "Endpoint of a node whose responses to compare against (default = the recorded outcome)",
+	}
+	methodFlag = &cli.StringSliceFlag{
+		Name:  "method",
+		Usage: "Methods to replay (default = read-only methods)",
+	}
+	limitFlag = &cli.IntFlag{
+		Name:  "limit",
+		Usage: "Maximum number of calls to replay (0 = all)",
+	}
+)
+
+func init() {
+	app.Flags = []cli.Flag{
+		logFlag,
+		dbFlag,
+		targetFlag,
+		referenceFlag,
+		methodFlag,
+		limitFlag,
+	}
+	app.Action = replayLog
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func replayLog(ctx *cli.Context) error {
+	if ctx.IsSet(logFlag.Name) == ctx.IsSet(dbFlag.Name) {
+		return errors.New("exactly one of --log and --db is required")
+	}
+	if !ctx.IsSet(targetFlag.Name) {
+		return errors.New("--target is required")
+	}
+	target, err := rpc.Dial(ctx.String(targetFlag.Name))
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+
+	r := &replayer{target: target, methods: make(map[string]bool), out: os.Stdout}
+	if ctx.IsSet(referenceFlag.Name) {
+		if r.reference, err = rpc.Dial(ctx.String(referenceFlag.Name)); err != nil {
+			return err
+		}
+		defer r.reference.Close()
+	}
+	for _, method := range ctx.StringSlice(methodFlag.Name) {
+		r.methods[method] = true
+	}
+	limit := ctx.Int(limitFlag.Name)
+	fn := func(rec *rpc.AuditRecord) error {
+		if limit > 0 && r.replayed >= limit {
+			return errStop
+		}
+		return r.replay(context.Background(), rec)
+	}
+	if ctx.IsSet(logFlag.Name) {
+		err = rpc.ReadAuditFiles(ctx.String(logFlag.Name), fn)
+	} else {
+		db, dberr := rawdb.NewLevelDBDatabase(ctx.String(dbFlag.Name), 16, 16, "", true)
+		if dberr != nil {
+			return dberr
+		}
+		defer db.Close()
+		err = rpc.ReadAuditDatabase(db, fn)
+	}
+	if err != nil && err != errStop {
+		return err
+	}
+	fmt.Printf("Replayed %d calls, skipped %d, %d mismatched\n", r.replayed, r.skipped, r.mismatched)
+	if r.mismatched > 0 {
+		return fmt.Errorf("%d of %d responses differ", r.mismatched, r.replayed)
+	}
+	return nil
+}
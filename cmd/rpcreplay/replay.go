@@ -0,0 +1,216 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/Altcoinchain/go-altcoinchain/rpc"
+)
+
+// maxPrintLength is the length beyond which printed values are truncated.
+const maxPrintLength = 256
+
+// readOnlyMethods are the methods replayed by default. They don't change the
+// state of the node, unlike e.g. transaction submissions, account management or
+// admin calls, which are only replayed if selected explicitly.
+var readOnlyMethods = map[string]bool{
+	"web3_clientVersion":                      true,
+	"web3_sha3":                               true,
+	"net_version":                             true,
+	"net_listening":                           true,
+	"net_peerCount":                           true,
+	"eth_protocolVersion":                     true,
+	"eth_chainId":                             true,
+	"eth_syncing":                             true,
+	"eth_blockNumber":                         true,
+	"eth_gasPrice":                            true,
+	"eth_maxPriorityFeePerGas":                true,
+	"eth_feeHistory":                          true,
+	"eth_getBalance":                          true,
+	"eth_getCode":                             true,
+	"eth_getStorageAt":                        true,
+	"eth_getProof":                            true,
+	"eth_getTransactionCount":                 true,
+	"eth_call":                                true,
+	"eth_estimateGas":                         true,
+	"eth_createAccessList":                    true,
+	"eth_getBlockByHash":                      true,
+	"eth_getBlockByNumber":                    true,
+	"eth_getHeaderByHash":                     true,
+	"eth_getHeaderByNumber":                   true,
+	"eth_getBlockTransactionCountByHash":      true,
+	"eth_getBlockTransactionCountByNumber":    true,
+	"eth_getUncleByBlockHashAndIndex":         true,
+	"eth_getUncleByBlockNumberAndIndex":       true,
+	"eth_getUncleCountByBlockHash":            true,
+	"eth_getUncleCountByBlockNumber":          true,
+	"eth_getTransactionByHash":                true,
+	"eth_getTransactionByBlockHashAndIndex":   true,
+	"eth_getTransactionByBlockNumberAndIndex": true,
+	"eth_getRawTransactionByHash":             true,
+	"eth_getTransactionReceipt":               true,
+	"eth_getLogs":                             true,
+	"debug_traceTransaction":                  true,
+	"debug_traceCall":                         true,
+	"debug_traceBlockByNumber":                true,
+	"debug_traceBlockByHash":                  true,
+}
+
+// response is the outcome of a single replayed call.
+type response struct {
+	result json.RawMessage
+	code   int // error code, zero on success
+	msg    string
+}
+
+// replayer replays audit records against a target node, comparing the responses
+// either to the ones of a reference node or to the audit records themselves.
+type replayer struct {
+	target    *rpc.Client
+	reference *rpc.Client     // optional, compare against the recorded outcome if nil
+	methods   map[string]bool // methods to replay, the read-only ones if empty
+	out       io.Writer
+
+	replayed   int
+	skipped    int
+	mismatched int
+}
+
+// replay replays a single audit record, reporting any mismatch.
+func (r *replayer) replay(ctx context.Context, rec *rpc.AuditRecord) error {
+	if !r.replayable(rec) {
+		r.skipped++
+		return nil
+	}
+	args, err := decodeParams(rec.Params)
+	if err != nil {
+		r.skipped++
+		return nil
+	}
+	have, err := call(ctx, r.target, rec.Method, args)
+	if err != nil {
+		return fmt.Errorf("target failed to serve %s: %v", rec.Method, err)
+	}
+	r.replayed++
+
+	var want string
+	if r.reference != nil {
+		ref, err := call(ctx, r.reference, rec.Method, args)
+		if err != nil {
+			return fmt.Errorf("reference failed to serve %s: %v", rec.Method, err)
+		}
+		if ref.code == have.code && (ref.code != 0 || jsonEqual(ref.result, have.result)) {
+			return nil
+		}
+		want = describe(ref)
+	} else {
+		switch {
+		case rec.ErrorCode != have.code:
+			want = fmt.Sprintf("error code %d", rec.ErrorCode)
+			if rec.ErrorCode == 0 {
+				want = "success"
+			}
+		case have.code == 0 && rec.ResponseHash != nil && *rec.ResponseHash != rpc.AuditResponseHash(have.result):
+			want = fmt.Sprintf("result hash %x", *rec.ResponseHash)
+		default:
+			return nil
+		}
+	}
+	r.mismatched++
+	fmt.Fprintf(r.out, "MISMATCH %s %s\n  want: %s\n  have: %s\n", rec.Method, truncate(string(rec.Params)), want, describe(have))
+	return nil
+}
+
+// replayable reports whether a record can be replayed. Subscriptions are tied
+// to the connection they were created on and the params of redacted records
+// are unknown, so they are skipped.
+func (r *replayer) replayable(rec *rpc.AuditRecord) bool {
+	if rec.Redacted {
+		return false
+	}
+	if strings.HasSuffix(rec.Method, "_subscribe") || strings.HasSuffix(rec.Method, "_unsubscribe") {
+		return false
+	}
+	if len(r.methods) == 0 {
+		return readOnlyMethods[rec.Method]
+	}
+	return r.methods[rec.Method]
+}
+
+// decodeParams splits the positional parameters of a call.
+func decodeParams(params json.RawMessage) ([]interface{}, error) {
+	if len(bytes.TrimSpace(params)) == 0 || string(params) == "null" {
+		return nil, nil
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(params, &raw); err != nil {
+		return nil, err
+	}
+	args := make([]interface{}, len(raw))
+	for i := range raw {
+		args[i] = raw[i]
+	}
+	return args, nil
+}
+
+// call executes a call, returning RPC errors as part of the response and only
+// failing on transport errors.
+func call(ctx context.Context, client *rpc.Client, method string, args []interface{}) (*response, error) {
+	var result json.RawMessage
+	err := client.CallContext(ctx, &result, method, args...)
+	if err == nil {
+		return &response{result: result}, nil
+	}
+	if rpcErr, ok := err.(rpc.Error); ok {
+		return &response{code: rpcErr.ErrorCode(), msg: err.Error()}, nil
+	}
+	return nil, err
+}
+
+// jsonEqual reports whether two JSON values are semantically equal.
+func jsonEqual(a, b json.RawMessage) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// describe formats a response for the mismatch report.
+func describe(resp *response) string {
+	if resp.code != 0 {
+		return fmt.Sprintf("error code %d (%s)", resp.code, truncate(resp.msg))
+	}
+	return truncate(string(resp.result))
+}
+
+func truncate(s string) string {
+	if len(s) > maxPrintLength {
+		return s[:maxPrintLength] + "..."
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/ethdb/memorydb"
+	"github.com/Altcoinchain/go-altcoinchain/rpc"
+)
+
+// testService answers with values depending on the node version it simulates.
+type testService struct{ version int }
+
+func (s *testService) Add(a, b int) int { return a + b }
+
+func (s *testService) Version() int { return s.version }
+
+func (s *testService) Fail() error { return errors.New("failure") }
+
+func newTestServer(t *testing.T, version int) *rpc.Server {
+	server := rpc.NewServer()
+	if err := server.RegisterName("test", &testService{version}); err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+func TestReplay(t *testing.T) {
+	// Record a few calls served by the original node
+	var (
+		db       = memorydb.New()
+		original = newTestServer(t, 1)
+	)
+	original.SetAuditLog(rpc.NewAuditDatabase(db), true)
+	client := rpc.DialInProc(original)
+	client.Call(nil, "test_add", 1, 2)
+	client.Call(nil, "test_version")
+	client.Call(nil, "test_fail")
+	client.Call(nil, "test_missing")
+	client.Close()
+
+	replay := func(target, reference *rpc.Server, methods ...string) (*replayer, string) {
+		var out bytes.Buffer
+		r := &replayer{target: rpc.DialInProc(target), methods: make(map[string]bool), out: &out}
+		if reference != nil {
+			r.reference = rpc.DialInProc(reference)
+		}
+		for _, method := range methods {
+			r.methods[method] = true
+		}
+		if err := rpc.ReadAuditDatabase(db, func(rec *rpc.AuditRecord) error {
+			return r.replay(context.Background(), rec)
+		}); err != nil {
+			t.Fatalf("replay failed: %v", err)
+		}
+		return r, out.String()
+	}
+	// Only read-only methods are replayed by default
+	if r, _ := replay(newTestServer(t, 1), nil); r.replayed != 0 || r.skipped != 4 {
+		t.Errorf("default methods: replayed %d, skipped %d", r.replayed, r.skipped)
+	}
+	all := []string{"test_add", "test_version", "test_fail", "test_missing"}
+
+	// Replaying against the same version matches the recorded outcomes
+	if r, out := replay(newTestServer(t, 1), nil, all...); r.replayed != 4 || r.mismatched != 0 {
+		t.Errorf("same version: replayed %d, mismatched %d\n%s", r.replayed, r.mismatched, out)
+	}
+	// Replaying against an upgraded version reports the changed response
+	r, out := replay(newTestServer(t, 2), nil, all...)
+	if r.replayed != 4 || r.mismatched != 1 || !strings.Contains(out, "MISMATCH test_version") {
+		t.Errorf("upgraded version: replayed %d, mismatched %d\n%s", r.replayed, r.mismatched, out)
+	}
+	// Comparing two nodes ignores the recorded outcome
+	if r, out := replay(newTestServer(t, 2), newTestServer(t, 2), all...); r.mismatched != 0 {
+		t.Errorf("reference: mismatched %d\n%s", r.mismatched, out)
+	}
+	// Methods not selected are skipped
+	if r, _ := replay(newTestServer(t, 2), nil, "test_add"); r.replayed != 1 || r.skipped != 3 || r.mismatched != 0 {
+		t.Errorf("method filter: replayed %d, skipped %d, mismatched %d", r.replayed, r.skipped, r.mismatched)
+	}
+}
@@ -20,11 +20,16 @@ package utils
 import (
 	"bufio"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"math/big"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"syscall"
@@ -38,6 +43,7 @@ import (
 	"github.com/Altcoinchain/go-altcoinchain/eth/ethconfig"
 	"github.com/Altcoinchain/go-altcoinchain/ethdb"
 	"github.com/Altcoinchain/go-altcoinchain/internal/debug"
+	"github.com/Altcoinchain/go-altcoinchain/internal/era"
 	"github.com/Altcoinchain/go-altcoinchain/log"
 	"github.com/Altcoinchain/go-altcoinchain/node"
 	"github.com/Altcoinchain/go-altcoinchain/rlp"
@@ -287,6 +293,214 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 	return nil
 }
 
+// historyChecksums is the name of the file listing the sha256 checksums of the
+// era files in a history directory.
+const historyChecksums = "checksums.txt"
+
+// ExportHistory exports the block history in the range [first, last] into era
+// files in the specified directory, one file per epoch. The checksums of the
+// files are written to a checksums.txt file next to them.
+func ExportHistory(bc *core.BlockChain, dir string, network string, first, last uint64) error {
+	log.Info("Exporting blockchain history", "dir", dir)
+	if head := bc.CurrentBlock().NumberU64(); head < last {
+		log.Warn("Last block beyond head, setting last = head", "head", head, "last", last)
+		last = head
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	var (
+		start     = time.Now()
+		reported  = time.Now()
+		checksums []string
+	)
+	for epoch := first / era.MaxEraSize; epoch <= last/era.MaxEraSize; epoch++ {
+		from, to := epoch*era.MaxEraSize, (epoch+1)*era.MaxEraSize-1
+		if from < first {
+			from = first
+		}
+		if to > last {
+			to = last
+		}
+		// Write into a temporary file, the final name contains the accumulator root
+		tmp := filepath.Join(dir, fmt.Sprintf(".%s-%05d.era1.tmp", network, epoch))
+		f, err := os.Create(tmp)
+		if err != nil {
+			return err
+		}
+		var (
+			hasher  = sha256.New()
+			buf     = bufio.NewWriter(f)
+			builder = era.NewBuilder(io.MultiWriter(buf, hasher))
+		)
+		for n := from; n <= to; n++ {
+			block := bc.GetBlockByNumber(n)
+			if block == nil {
+				f.Close()
+				return fmt.Errorf("export failed on #%d: not found", n)
+			}
+			receipts := bc.GetReceiptsByHash(block.Hash())
+			td := bc.GetTd(block.Hash(), n)
+			if receipts == nil && len(block.Transactions()) > 0 || td == nil {
+				f.Close()
+				return fmt.Errorf("export failed on #%d: history unavailable", n)
+			}
+			if err := builder.Add(block, receipts, td); err != nil {
+				f.Close()
+				return err
+			}
+			if time.Since(reported) > 8*time.Second {
+				log.Info("Exporting blocks", "exported", n-first, "elapsed", common.PrettyDuration(time.Since(start)))
+				reported = time.Now()
+			}
+		}
+		root, err := builder.Finalize()
+		if err == nil {
+			err = buf.Flush()
+		}
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		name := era.Filename(network, int(epoch), root)
+		if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+			return err
+		}
+		checksums = append(checksums, fmt.Sprintf("%x  %s", hasher.Sum(nil), name))
+	}
+	if err := os.WriteFile(filepath.Join(dir, historyChecksums), []byte(strings.Join(checksums, "\n")+"\n"), 0644); err != nil {
+		return err
+	}
+	log.Info("Exported blockchain history", "dir", dir, "files", len(checksums), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// ImportHistory imports the era files of the given network in the specified
+// directory into the freezer. Every file is checked against its checksum and
+// its accumulator before any of its blocks are written.
+func ImportHistory(chain *core.BlockChain, db ethdb.Database, dir string, network string) error {
+	files, err := era.ReadDir(dir, network)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no era files found for network %s in %s", network, dir)
+	}
+	checksums, err := readHistoryChecksums(filepath.Join(dir, historyChecksums))
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	for _, name := range files {
+		if err := importEra(chain, db, filepath.Join(dir, name), network, checksums); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		log.Info("Imported era file", "file", name, "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	log.Info("Imported blockchain history", "dir", dir, "files", len(files), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// readHistoryChecksums parses a checksums file into a map from file name to
+// sha256 checksum. A missing file yields an empty map.
+func readHistoryChecksums(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.Warn("No checksums found for era files", "path", path)
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	checksums := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("malformed checksum line %q", line)
+		}
+		checksums[fields[1]] = fields[0]
+	}
+	return checksums, nil
+}
+
+// importEra verifies a single era file and writes its blocks to the freezer,
+// skipping the ones already present.
+func importEra(chain *core.BlockChain, db ethdb.Database, path string, network string, checksums map[string]string) error {
+	name := filepath.Base(path)
+	if want, ok := checksums[name]; ok {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		hasher := sha256.New()
+		_, err = io.Copy(hasher, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if have := hex.EncodeToString(hasher.Sum(nil)); have != want {
+			return fmt.Errorf("checksum mismatch: have %s, want %s", have, want)
+		}
+	}
+	e, err := era.Open(path)
+	if err != nil {
+		return err
+	}
+	defer e.Close()
+
+	root, err := e.Verify()
+	if err != nil {
+		return err
+	}
+	if want := era.Filename(network, int(e.Start()/era.MaxEraSize), root); name != want {
+		return fmt.Errorf("file name does not match accumulator, want %s", want)
+	}
+	frozen, err := db.Ancients()
+	if err != nil {
+		return err
+	}
+	var (
+		it       = era.NewIterator(e)
+		headers  []*types.Header
+		blocks   []*types.Block
+		receipts []types.Receipts
+		last     *big.Int
+	)
+	for it.Next() {
+		block := it.Block()
+		if block.NumberU64() == 0 {
+			if block.Hash() != chain.Genesis().Hash() {
+				return fmt.Errorf("genesis mismatch: have %x, want %x", block.Hash(), chain.Genesis().Hash())
+			}
+			continue
+		}
+		if block.NumberU64() < frozen {
+			continue
+		}
+		headers, blocks = append(headers, block.Header()), append(blocks, block)
+		receipts, last = append(receipts, it.Receipts()), it.TotalDifficulty()
+	}
+	if it.Error() != nil {
+		return it.Error()
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+	if _, err := chain.InsertHeaderChain(headers, 100); err != nil {
+		return fmt.Errorf("invalid header chain: %w", err)
+	}
+	head := blocks[len(blocks)-1]
+	if td := chain.GetTd(head.Hash(), head.NumberU64()); td == nil || td.Cmp(last) != 0 {
+		return fmt.Errorf("total difficulty mismatch at #%d: have %v, want %v", head.NumberU64(), td, last)
+	}
+	if _, err := chain.InsertReceiptChain(blocks, receipts, math.MaxUint64); err != nil {
+		return fmt.Errorf("failed to write blocks: %w", err)
+	}
+	return nil
+}
+
 // ImportPreimages imports a batch of exported hash preimages into the database.
 // It's a part of the deprecated functionality, should be removed in the future.
 func ImportPreimages(db ethdb.Database, fn string) error {
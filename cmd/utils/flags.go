@@ -59,6 +59,8 @@ import (
 	"github.com/Altcoinchain/go-altcoinchain/metrics/exp"
 	"github.com/Altcoinchain/go-altcoinchain/metrics/influxdb"
 	"github.com/Altcoinchain/go-altcoinchain/miner"
+	"github.com/Altcoinchain/go-altcoinchain/miner/dagshare"
+	"github.com/Altcoinchain/go-altcoinchain/miner/pool"
 	"github.com/Altcoinchain/go-altcoinchain/node"
 	"github.com/Altcoinchain/go-altcoinchain/p2p"
 	"github.com/Altcoinchain/go-altcoinchain/p2p/enode"
@@ -66,6 +68,7 @@ import (
 	"github.com/Altcoinchain/go-altcoinchain/p2p/netutil"
 	"github.com/Altcoinchain/go-altcoinchain/params"
 	"github.com/Altcoinchain/go-altcoinchain/rpc"
+	"github.com/Altcoinchain/go-altcoinchain/trie"
 	pcsclite "github.com/gballet/go-libpcsclite"
 	gopsutil "github.com/shirou/gopsutil/mem"
 	"github.com/urfave/cli/v2"
@@ -96,6 +99,17 @@ var (
 		Usage:    "Root directory for ancient data (default = inside chaindata)",
 		Category: flags.EthCategory,
 	}
+	DBEngineFlag = &cli.StringFlag{
+		Name:     "db.engine",
+		Usage:    "Backing database implementation to use ('leveldb' or 'pebble')",
+		Value:    "leveldb",
+		Category: flags.EthCategory,
+	}
+	DBSecondaryFlag = &cli.BoolFlag{
+		Name:     "db.secondary",
+		Usage:    "Open the database read-only and follow the node writing to it (serves RPC only, no networking)",
+		Category: flags.EthCategory,
+	}
 	MinFreeDiskSpaceFlag = &flags.DirectoryFlag{
 		Name:     "datadir.minfreedisk",
 		Usage:    "Minimum free disk space in MB, once reached triggers auto shut down (default = --cache.gc converted to MB, 0 = disabled)",
@@ -217,6 +231,15 @@ var (
 		Usage: "Max number of elements (0 = no limit)",
 		Value: 0,
 	}
+	ExportChunkSizeFlag = &cli.Uint64Flag{
+		Name:  "chunksize",
+		Usage: "Size in megabytes at which snapshot export files are split",
+		Value: 128,
+	}
+	StorageLayoutFlag = &cli.StringFlag{
+		Name:  "layout",
+		Usage: "Storage layout JSON file of the contract, as emitted by solc",
+	}
 
 	defaultSyncMode = ethconfig.Defaults.SyncMode
 	SyncModeFlag    = &flags.TextMarshalerFlag{
@@ -243,6 +266,36 @@ var (
 		Value:    ethconfig.Defaults.TxLookupLimit,
 		Category: flags.EthCategory,
 	}
+	HistoryRetainFlag = &cli.Uint64Flag{
+		Name:     "history.retain",
+		Usage:    "Number of recent blocks to keep bodies and receipts for (0 = entire chain)",
+		Category: flags.EthCategory,
+	}
+	StateSchemeFlag = &cli.StringFlag{
+		Name:     "state.scheme",
+		Usage:    `Scheme to store the state trie nodes with ("hash", "path"), fixed when the database is created`,
+		Category: flags.EthCategory,
+	}
+	StateDiffsFlag = &cli.BoolFlag{
+		Name:     "state.diffs",
+		Usage:    "Store the state changes made by every imported block",
+		Category: flags.EthCategory,
+	}
+	StateDiffRetainFlag = &cli.Uint64Flag{
+		Name:     "state.diffs.retain",
+		Usage:    "Number of recent blocks to keep state diffs for (0 = entire chain)",
+		Category: flags.EthCategory,
+	}
+	StateHistoryFlag = &cli.Uint64Flag{
+		Name:     "state.history",
+		Usage:    "Number of recent blocks to serve state proofs for, rebuilding pruned states from the state diffs (0 = disabled)",
+		Category: flags.EthCategory,
+	}
+	TxWorkersFlag = &cli.IntFlag{
+		Name:     "parallel.txworkers",
+		Usage:    "Number of goroutines executing block transactions speculatively in parallel during import (0 = sequential)",
+		Category: flags.EthCategory,
+	}
 	LightKDFFlag = &cli.BoolFlag{
 		Name:     "lightkdf",
 		Usage:    "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
@@ -545,6 +598,11 @@ var (
 		Value:    "0",
 		Category: flags.MinerCategory,
 	}
+	MinerBeneficiariesFlag = &cli.StringFlag{
+		Name:     "miner.beneficiaries",
+		Usage:    "Comma separated address:weight list splitting the block reward after the reward split fork",
+		Category: flags.MinerCategory,
+	}
 	MinerExtraDataFlag = &cli.StringFlag{
 		Name:     "miner.extradata",
 		Usage:    "Block extra data set by the miner (default = client version)",
@@ -561,6 +619,66 @@ var (
 		Usage:    "Disable remote sealing verification",
 		Category: flags.MinerCategory,
 	}
+	PoolEnabledFlag = &cli.BoolFlag{
+		Name:     "pool",
+		Usage:    "Enable solo pool share accounting with PPLNS payouts on the remote sealer",
+		Category: flags.MinerCategory,
+	}
+	PoolWindowFlag = &cli.Uint64Flag{
+		Name:     "pool.window",
+		Usage:    "Number of last shares to split block rewards across (PPLNS window)",
+		Value:    pool.DefaultConfig.Window,
+		Category: flags.MinerCategory,
+	}
+	PoolShareDifficultyFlag = &cli.Uint64Flag{
+		Name:     "pool.sharediff",
+		Usage:    "Difficulty a submitted share must satisfy",
+		Value:    pool.DefaultConfig.ShareDifficulty,
+		Category: flags.MinerCategory,
+	}
+	PoolFeeFlag = &cli.Uint64Flag{
+		Name:     "pool.fee",
+		Usage:    "Pool operator fee retained from every block reward, in basis points",
+		Value:    pool.DefaultConfig.Fee,
+		Category: flags.MinerCategory,
+	}
+	PoolPayoutsFlag = &cli.BoolFlag{
+		Name:     "pool.payouts",
+		Usage:    "Automatically send payout transactions from the (unlocked) etherbase",
+		Category: flags.MinerCategory,
+	}
+	PoolPayoutThresholdFlag = &flags.BigFlag{
+		Name:     "pool.payout.threshold",
+		Usage:    "Minimum owed balance (in wei) before a payout is sent",
+		Value:    pool.DefaultConfig.PayoutThreshold,
+		Category: flags.MinerCategory,
+	}
+	DAGShareEnabledFlag = &cli.BoolFlag{
+		Name:     "dagshare",
+		Usage:    "Enable background ethash DAG pre-generation and sharing across farm nodes",
+		Category: flags.MinerCategory,
+	}
+	DAGShareAddrFlag = &cli.StringFlag{
+		Name:     "dagshare.addr",
+		Usage:    "HTTP listening address to serve the generated DAG files on (empty = don't serve)",
+		Category: flags.MinerCategory,
+	}
+	DAGShareSecretFlag = &cli.StringFlag{
+		Name:     "dagshare.secret",
+		Usage:    "Path to a file holding the secret shared by the farm nodes",
+		Category: flags.MinerCategory,
+	}
+	DAGSharePeersFlag = &cli.StringFlag{
+		Name:     "dagshare.peers",
+		Usage:    "Comma separated URLs of farm nodes to fetch DAG files from before generating them",
+		Category: flags.MinerCategory,
+	}
+	DAGShareDistanceFlag = &cli.Uint64Flag{
+		Name:     "dagshare.distance",
+		Usage:    "Number of blocks before the epoch boundary to generate missing DAGs locally",
+		Value:    dagshare.DefaultConfig.GenerateDistance,
+		Category: flags.MinerCategory,
+	}
 
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
@@ -613,6 +731,39 @@ var (
 		Value:    ethconfig.Defaults.RPCTxFeeCap,
 		Category: flags.APICategory,
 	}
+	BatchRequestLimitFlag = &cli.IntFlag{
+		Name:     "rpc.batch-request-limit",
+		Usage:    "Maximum number of requests in a batch (0 = no limit)",
+		Value:    node.DefaultConfig.BatchRequestLimit,
+		Category: flags.APICategory,
+	}
+	BatchResponseMaxSizeFlag = &cli.IntFlag{
+		Name:     "rpc.batch-response-max-size",
+		Usage:    "Maximum number of bytes returned from a batched call (0 = no limit)",
+		Value:    node.DefaultConfig.BatchResponseMaxSize,
+		Category: flags.APICategory,
+	}
+	RPCAccessPolicyFlag = &cli.StringFlag{
+		Name:     "rpc.policy",
+		Usage:    "JSON file restricting the HTTP and WS-RPC methods and assigning per API key quotas",
+		Category: flags.APICategory,
+	}
+	RPCAuditLogFlag = &cli.StringFlag{
+		Name:     "rpc.audit",
+		Usage:    "Location of the audit log recording all calls served over IPC, HTTP and WS-RPC (disabled if empty)",
+		Category: flags.APICategory,
+	}
+	RPCAuditBackendFlag = &cli.StringFlag{
+		Name:     "rpc.audit.backend",
+		Usage:    `Storage of the RPC audit log ("file" or "db")`,
+		Value:    "file",
+		Category: flags.APICategory,
+	}
+	RPCAuditResponseHashFlag = &cli.BoolFlag{
+		Name:     "rpc.audit.hash",
+		Usage:    "Record the hash of every response in the RPC audit log",
+		Category: flags.APICategory,
+	}
 	// Authenticated RPC HTTP settings
 	AuthListenFlag = &cli.StringFlag{
 		Name:     "authrpc.addr",
@@ -998,6 +1149,8 @@ var (
 		DataDirFlag,
 		AncientFlag,
 		RemoteDBFlag,
+		DBEngineFlag,
+		DBSecondaryFlag,
 	}
 )
 
@@ -1201,6 +1354,24 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(AllowUnprotectedTxs.Name) {
 		cfg.AllowUnprotectedTxs = ctx.Bool(AllowUnprotectedTxs.Name)
 	}
+	if ctx.IsSet(BatchRequestLimitFlag.Name) {
+		cfg.BatchRequestLimit = ctx.Int(BatchRequestLimitFlag.Name)
+	}
+	if ctx.IsSet(BatchResponseMaxSizeFlag.Name) {
+		cfg.BatchResponseMaxSize = ctx.Int(BatchResponseMaxSizeFlag.Name)
+	}
+	if ctx.IsSet(RPCAccessPolicyFlag.Name) {
+		cfg.RPCAccessPolicy = ctx.String(RPCAccessPolicyFlag.Name)
+	}
+	if ctx.IsSet(RPCAuditLogFlag.Name) {
+		cfg.RPCAuditLog = ctx.String(RPCAuditLogFlag.Name)
+	}
+	if ctx.IsSet(RPCAuditBackendFlag.Name) {
+		cfg.RPCAuditBackend = ctx.String(RPCAuditBackendFlag.Name)
+	}
+	if ctx.IsSet(RPCAuditResponseHashFlag.Name) {
+		cfg.RPCAuditResponseHash = ctx.Bool(RPCAuditResponseHashFlag.Name)
+	}
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1474,6 +1645,25 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(KeyStoreDirFlag.Name) {
 		cfg.KeyStoreDir = ctx.String(KeyStoreDirFlag.Name)
 	}
+	if ctx.IsSet(DBEngineFlag.Name) {
+		dbEngine := ctx.String(DBEngineFlag.Name)
+		if dbEngine != rawdb.DBLeveldb && dbEngine != rawdb.DBPebble {
+			Fatalf("Invalid choice for db.engine '%s', allowed 'leveldb' or 'pebble'", dbEngine)
+		}
+		cfg.DBEngine = dbEngine
+	}
+	if ctx.Bool(DBSecondaryFlag.Name) {
+		cfg.DBSecondary = true
+
+		// The primary node owns the network identity and the default IPC
+		// endpoint, a secondary only serves the explicitly configured RPC.
+		cfg.P2P.MaxPeers = 0
+		cfg.P2P.NoDiscovery = true
+		cfg.P2P.ListenAddr = ""
+		if !ctx.IsSet(IPCPathFlag.Name) {
+			cfg.IPCPath = ""
+		}
+	}
 	if ctx.IsSet(DeveloperFlag.Name) {
 		cfg.UseLightweightKDF = true
 	}
@@ -1650,11 +1840,72 @@ func setMiner(ctx *cli.Context, cfg *miner.Config) {
 	if ctx.IsSet(MinerNoVerifyFlag.Name) {
 		cfg.Noverify = ctx.Bool(MinerNoVerifyFlag.Name)
 	}
+	if ctx.IsSet(MinerBeneficiariesFlag.Name) {
+		cfg.Beneficiaries = nil
+		for _, entry := range strings.Split(ctx.String(MinerBeneficiariesFlag.Name), ",") {
+			parts := strings.Split(strings.TrimSpace(entry), ":")
+			if len(parts) != 2 || !common.IsHexAddress(parts[0]) {
+				Fatalf("Invalid miner beneficiary %q, want address:weight", entry)
+			}
+			weight, err := strconv.ParseUint(parts[1], 10, 64)
+			if err != nil || weight == 0 {
+				Fatalf("Invalid miner beneficiary weight %q", parts[1])
+			}
+			cfg.Beneficiaries = append(cfg.Beneficiaries, ethash.RewardSplit{
+				Address: common.HexToAddress(parts[0]),
+				Weight:  weight,
+			})
+		}
+	}
 	if ctx.IsSet(LegacyMinerGasTargetFlag.Name) {
 		log.Warn("The generic --miner.gastarget flag is deprecated and will be removed in the future!")
 	}
 }
 
+// SetPoolConfig applies solo pool related command line flags to the config.
+func SetPoolConfig(ctx *cli.Context, cfg *pool.Config) {
+	if ctx.IsSet(PoolEnabledFlag.Name) {
+		cfg.Enabled = ctx.Bool(PoolEnabledFlag.Name)
+	}
+	if ctx.IsSet(PoolWindowFlag.Name) {
+		cfg.Window = ctx.Uint64(PoolWindowFlag.Name)
+	}
+	if ctx.IsSet(PoolShareDifficultyFlag.Name) {
+		cfg.ShareDifficulty = ctx.Uint64(PoolShareDifficultyFlag.Name)
+	}
+	if ctx.IsSet(PoolFeeFlag.Name) {
+		cfg.Fee = ctx.Uint64(PoolFeeFlag.Name)
+	}
+	if ctx.IsSet(PoolPayoutsFlag.Name) {
+		cfg.Payouts = ctx.Bool(PoolPayoutsFlag.Name)
+	}
+	if ctx.IsSet(PoolPayoutThresholdFlag.Name) {
+		cfg.PayoutThreshold = flags.GlobalBig(ctx, PoolPayoutThresholdFlag.Name)
+	}
+	if cfg.Fee > 10000 {
+		Fatalf("Pool fee %d exceeds 10000 basis points", cfg.Fee)
+	}
+}
+
+// SetDAGShareConfig applies DAG sharing related command line flags to the config.
+func SetDAGShareConfig(ctx *cli.Context, cfg *dagshare.Config) {
+	if ctx.IsSet(DAGShareEnabledFlag.Name) {
+		cfg.Enabled = ctx.Bool(DAGShareEnabledFlag.Name)
+	}
+	if ctx.IsSet(DAGShareAddrFlag.Name) {
+		cfg.ListenAddr = ctx.String(DAGShareAddrFlag.Name)
+	}
+	if ctx.IsSet(DAGShareSecretFlag.Name) {
+		cfg.SecretFile = ctx.String(DAGShareSecretFlag.Name)
+	}
+	if ctx.IsSet(DAGSharePeersFlag.Name) {
+		cfg.Peers = SplitAndTrim(ctx.String(DAGSharePeersFlag.Name))
+	}
+	if ctx.IsSet(DAGShareDistanceFlag.Name) {
+		cfg.GenerateDistance = ctx.Uint64(DAGShareDistanceFlag.Name)
+	}
+}
+
 func setRequiredBlocks(ctx *cli.Context, cfg *ethconfig.Config) {
 	requiredBlocks := ctx.String(EthRequiredBlocksFlag.Name)
 	if requiredBlocks == "" {
@@ -1737,6 +1988,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(LightServeFlag.Name) && ctx.Uint64(TxLookupLimitFlag.Name) != 0 {
 		log.Warn("LES server cannot serve old transaction status and cannot connect below les/4 protocol version if transaction lookup index is limited")
 	}
+	if ctx.String(GCModeFlag.Name) == "archive" && ctx.Uint64(HistoryRetainFlag.Name) != 0 {
+		Fatalf("--%s cannot be used with --%s=archive", HistoryRetainFlag.Name, GCModeFlag.Name)
+	}
+	if ctx.String(GCModeFlag.Name) == "archive" && ctx.String(StateSchemeFlag.Name) == rawdb.PathScheme {
+		Fatalf("--%s=%s cannot be used with --%s=archive", StateSchemeFlag.Name, rawdb.PathScheme, GCModeFlag.Name)
+	}
 	var ks *keystore.KeyStore
 	if keystores := stack.AccountManager().Backends(keystore.KeyStoreType); len(keystores) > 0 {
 		ks = keystores[0].(*keystore.KeyStore)
@@ -1802,6 +2059,24 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(TxLookupLimitFlag.Name) {
 		cfg.TxLookupLimit = ctx.Uint64(TxLookupLimitFlag.Name)
 	}
+	if ctx.IsSet(HistoryRetainFlag.Name) {
+		cfg.HistoryRetain = ctx.Uint64(HistoryRetainFlag.Name)
+	}
+	if ctx.IsSet(StateSchemeFlag.Name) {
+		cfg.StateScheme = ctx.String(StateSchemeFlag.Name)
+	}
+	if ctx.IsSet(StateDiffsFlag.Name) {
+		cfg.StateDiffs = ctx.Bool(StateDiffsFlag.Name)
+	}
+	if ctx.IsSet(StateDiffRetainFlag.Name) {
+		cfg.StateDiffRetain = ctx.Uint64(StateDiffRetainFlag.Name)
+	}
+	if ctx.IsSet(StateHistoryFlag.Name) {
+		cfg.StateHistory = ctx.Uint64(StateHistoryFlag.Name)
+	}
+	if ctx.IsSet(TxWorkersFlag.Name) {
+		cfg.TxWorkers = ctx.Int(TxWorkersFlag.Name)
+	}
 	if ctx.IsSet(CacheFlag.Name) || ctx.IsSet(CacheTrieFlag.Name) {
 		cfg.TrieCleanCache = ctx.Int(CacheFlag.Name) * ctx.Int(CacheTrieFlag.Name) / 100
 	}
@@ -1990,6 +2265,9 @@ func SetDNSDiscoveryDefaults(cfg *ethconfig.Config, genesis common.Hash) {
 // node is running as a light client.
 func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (ethapi.Backend, *eth.Ethereum) {
 	if cfg.SyncMode == downloader.LightSync {
+		if stack.Config().DBSecondary {
+			Fatalf("Light client can't run as a secondary node")
+		}
 		backend, err := les.New(stack, cfg)
 		if err != nil {
 			Fatalf("Failed to register the Ethereum service: %v", err)
@@ -2010,8 +2288,11 @@ func RegisterEthService(stack *node.Node, cfg *ethconfig.Config) (ethapi.Backend
 			Fatalf("Failed to create the LES server: %v", err)
 		}
 	}
-	if err := ethcatalyst.Register(stack, backend); err != nil {
-		Fatalf("Failed to register the Engine API service: %v", err)
+	// A secondary node can't import blocks, so it has no use for the Engine API
+	if !stack.Config().DBSecondary {
+		if err := ethcatalyst.Register(stack, backend); err != nil {
+			Fatalf("Failed to register the Engine API service: %v", err)
+		}
 	}
 	stack.RegisterAPIs(tracers.APIs(backend.APIBackend))
 	return backend.APIBackend, backend
@@ -2024,6 +2305,28 @@ func RegisterEthStatsService(stack *node.Node, backend ethapi.Backend, url strin
 	}
 }
 
+// RegisterPoolService configures the solo pool share ledger and adds it to the node.
+func RegisterPoolService(stack *node.Node, backend ethapi.Backend, eth *eth.Ethereum, cfg *pool.Config) {
+	if eth == nil {
+		Fatalf("Solo pool requires a full node")
+	}
+	etherbase, err := eth.Etherbase()
+	if err != nil {
+		Fatalf("Solo pool requires an etherbase: %v", err)
+	}
+	cfg.Etherbase = etherbase
+	if _, err := pool.New(stack, backend, *cfg); err != nil {
+		Fatalf("Failed to register the solo pool service: %v", err)
+	}
+}
+
+// RegisterDAGShareService configures the ethash DAG sharing service and adds it to the node.
+func RegisterDAGShareService(stack *node.Node, backend ethapi.Backend, cfg *dagshare.Config) {
+	if _, err := dagshare.New(stack, backend, *cfg); err != nil {
+		Fatalf("Failed to register the DAG sharing service: %v", err)
+	}
+}
+
 // RegisterGraphQLService adds the GraphQL API to the node.
 func RegisterGraphQLService(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cfg *node.Config) {
 	err := graphql.New(stack, backend, filterSystem, cfg.GraphQLCors, cfg.GraphQLVirtualHosts)
@@ -2145,6 +2448,28 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node, readonly bool) ethdb.
 	return chainDb
 }
 
+// MakeStateScheme resolves the state scheme requested by the flags against the
+// one of the given database, recording it if the database is fresh.
+func MakeStateScheme(ctx *cli.Context, db ethdb.Database) string {
+	scheme, err := rawdb.ParseStateScheme(ctx.String(StateSchemeFlag.Name), db)
+	if err != nil {
+		Fatalf("%v", err)
+	}
+	if rawdb.ReadStateScheme(db) == "" {
+		rawdb.WriteStateScheme(db, scheme)
+	}
+	return scheme
+}
+
+// MakeTrieDatabase creates a trie database for the state of the given chain
+// database, using the scheme its state is stored with.
+func MakeTrieDatabase(db ethdb.Database, preimages bool) *trie.Database {
+	return trie.NewDatabaseWithConfig(db, &trie.Config{
+		Preimages: preimages,
+		Scheme:    rawdb.ReadStateScheme(db),
+	})
+}
+
 func MakeGenesis(ctx *cli.Context) *core.Genesis {
 	var genesis *core.Genesis
 	switch {
@@ -2170,6 +2495,7 @@ func MakeGenesis(ctx *cli.Context) *core.Genesis {
 func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chainDb ethdb.Database) {
 	var err error
 	chainDb = MakeChainDatabase(ctx, stack, false) // TODO(rjl493456442) support read-only database
+	scheme := MakeStateScheme(ctx, chainDb)
 	config, _, err := core.SetupGenesisBlock(chainDb, MakeGenesis(ctx))
 	if err != nil {
 		Fatalf("%v", err)
@@ -2192,6 +2518,7 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 		TrieTimeLimit:       ethconfig.Defaults.TrieTimeout,
 		SnapshotLimit:       ethconfig.Defaults.SnapshotCache,
 		Preimages:           ctx.Bool(CachePreimagesFlag.Name),
+		StateScheme:         scheme,
 	}
 	if cache.TrieDirtyDisabled && !cache.Preimages {
 		cache.Preimages = true
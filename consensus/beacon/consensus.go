@@ -237,6 +237,18 @@ func (beacon *Beacon) VerifyUncles(chain consensus.ChainReader, block *types.Blo
 	return nil
 }
 
+// VerifyRewards implements consensus.RewardVerifier, delegating the check of
+// pre-merge blocks to the eth1 engine if it commits to the block rewards.
+func (beacon *Beacon) VerifyRewards(chain consensus.ChainHeaderReader, block *types.Block) error {
+	if beacon.IsPoSHeader(block.Header()) {
+		return nil
+	}
+	if verifier, ok := beacon.ethone.(consensus.RewardVerifier); ok {
+		return verifier.VerifyRewards(chain, block)
+	}
+	return nil
+}
+
 // verifyHeader checks whether a header conforms to the consensus rules of the
 // stock Ethereum consensus engine. The difference between the beacon and classic is
 // (a) The following fields are expected to be constants:
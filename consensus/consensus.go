@@ -128,3 +128,11 @@ type PoW interface {
 	// Hashrate returns the current mining hashrate of a PoW consensus engine.
 	Hashrate() float64
 }
+
+// RewardVerifier is a consensus engine committing to the rewards credited when
+// finalizing a block in its header.
+type RewardVerifier interface {
+	// VerifyRewards checks whether the reward commitment of a block's header
+	// matches the rewards credited when finalizing the block.
+	VerifyRewards(chain ChainHeaderReader, block *types.Block) error
+}
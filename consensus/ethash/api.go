@@ -41,30 +41,31 @@ func NewAPI(ethash *EthashLachesis) *API {
 
 // GetWork returns a work package for external miner.
 //
-// The work package consists of 3 strings:
+// The work package consists of 4 or 5 strings:
 //   result[0] - 32 bytes hex encoded current block header pow-hash
 //   result[1] - 32 bytes hex encoded seed hash used for DAG
 //   result[2] - 32 bytes hex encoded boundary condition ("target"), 2^256/difficulty
 //   result[3] - hex encoded block number
-func (api *API) GetWork() ([4]string, error) {
+//   result[4] - hex encoded expected coinbase reward (optional)
+func (api *API) GetWork() ([]string, error) {
 	if api.ethash.ethash.remote == nil {
-		return [4]string{}, errors.New("not supported")
+		return nil, errors.New("not supported")
 	}
 
 	var (
-		workCh = make(chan [4]string, 1)
+		workCh = make(chan []string, 1)
 		errc   = make(chan error, 1)
 	)
 	select {
 	case api.ethash.ethash.remote.fetchWorkCh <- &sealWork{errc: errc, res: workCh}:
 	case <-api.ethash.ethash.remote.exitCh:
-		return [4]string{}, errEthashStopped
+		return nil, errEthashStopped
 	}
 	select {
 	case work := <-workCh:
 		return work, nil
 	case err := <-errc:
-		return [4]string{}, err
+		return nil, err
 	}
 }
 
@@ -91,6 +92,33 @@ func (api *API) SubmitWork(nonce types.BlockNonce, hash, digest common.Hash) boo
 	return err == nil
 }
 
+// SubmitShare can be used by external miners of a solo pool to submit a share,
+// a POW solution that satisfies the pool's share difficulty, on behalf of the
+// given miner address. Shares that also satisfy the block difficulty are sealed
+// as usual. It returns an error if the share was rejected.
+func (api *API) SubmitShare(nonce types.BlockNonce, hash, digest common.Hash, miner common.Address) (bool, error) {
+	if api.ethash.ethash.remote == nil {
+		return false, errors.New("not supported")
+	}
+
+	var errc = make(chan error, 1)
+	select {
+	case api.ethash.ethash.remote.submitWorkCh <- &mineResult{
+		nonce:     nonce,
+		mixDigest: digest,
+		hash:      hash,
+		miner:     &miner,
+		errc:      errc,
+	}:
+	case <-api.ethash.ethash.remote.exitCh:
+		return false, errEthashStopped
+	}
+	if err := <-errc; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // SubmitHashrate can be used for remote miners to submit their hash rate.
 // This enables the node to report the combined hash rate of all miners
 // which submit work through this node.
@@ -41,6 +41,7 @@ var (
     FrontierBlockReward           = big.NewInt(5e+18) // Block reward in wei for successfully mining a block
     ByzantiumBlockReward          = big.NewInt(3e+18) // Block reward in wei for successfully mining a block upward from Byzantium
     ConstantinopleBlockReward     = big.NewInt(2e+18) // Block reward in wei for successfully mining a block upward from Constantinople
+    AltcoinBlockReward            = big.NewInt(1e+18) // Block reward in wei for successfully mining an Altcoinchain block
     maxUncles                     = 2                 // Maximum number of uncles allowed in a single block
     allowedFutureBlockTimeSeconds = int64(15)         // Max seconds from current time allowed for blocks, before they're considered future blocks
 
@@ -286,8 +287,8 @@ func (ethash *Ethash) VerifyUncles(chain consensus.ChainReader, block *types.Blo
 }
 
 func (ethash *Ethash) verifyHeader(chain consensus.ChainHeaderReader, header, parent *types.Header, uncle bool, seal bool, unixNow int64) error {
-    if uint64(len(header.Extra)) > params.MaximumExtraDataSize {
-        return fmt.Errorf("extra-data too long: %d > %d", len(header.Extra), params.MaximumExtraDataSize)
+    if err := verifyExtra(chain.Config(), header); err != nil {
+        return err
     }
 
     if !uncle {
@@ -379,40 +380,62 @@ var (
 // reward. The total reward consists of the static block reward and rewards for
 // included uncles. The coinbase of each uncle block is also rewarded.
 func accumulateRewards(config *params.ChainConfig, state *state.StateDB, header *types.Header, uncles []*types.Header) {
+    for _, reward := range BlockRewardList(config, header, uncles) {
+        state.AddBalance(reward.Address, reward.Amount)
+    }
+}
+
+// BlockRewardList returns every balance credit made when finalizing the given
+// block, in the order they are applied to the state.
+func BlockRewardList(config *params.ChainConfig, header *types.Header, uncles []*types.Header) []Reward {
     // Select the correct block reward based on chain progression
-    blockReward := big.NewInt(1e+18) // 1 ALT in wei (adjust according to your token's decimals)
+    blockReward := AltcoinBlockReward
 
     // Calculate PoW and PoS + PoT + PoT rewards
     powReward := new(big.Int).Set(blockReward) // 1 ALT for PoW Ethash miners
     posPotReward := new(big.Int).Set(blockReward) // 1 ALT for PoW + PoS + PoT + PoT participants
 
     // Distribute rewards to PoW miners (coinbase)
+    var rewards []Reward
     reward := new(big.Int).Set(powReward)
-    r := new(big.Int)
     for _, uncle := range uncles {
-        r.Add(uncle.Number, big8)
+        r := new(big.Int).Add(uncle.Number, big8)
         r.Sub(r, header.Number)
         r.Mul(r, blockReward)
         r.Div(r, big8)
-        state.AddBalance(uncle.Coinbase, r)
+        rewards = append(rewards, Reward{Address: uncle.Coinbase, Kind: RewardUncle, Amount: r})
 
-        r.Div(powReward, big32)
-        reward.Add(reward, r)
+        reward.Add(reward, new(big.Int).Div(powReward, big32))
+    }
+    // PoW reward to miner, or its weighted recipients
+    for _, share := range coinbaseShares(config, header, reward) {
+        rewards = append(rewards, Reward{Address: share.address, Kind: RewardMiner, Amount: share.amount})
     }
-    state.AddBalance(header.Coinbase, reward) // PoW reward to miner
-
     // Distribute rewards to PoS + PoT + PoT participants
-    distributePoSPoTRewards(state, header, posPotReward)
+    return append(rewards, distributePoSPoTRewards(header, posPotReward)...)
+}
+
+// MinerReward returns the static block reward plus the uncle inclusion rewards
+// credited to the coinbase of a block, excluding any transaction fees.
+func MinerReward(header *types.Header, uncles []*types.Header) *big.Int {
+    reward := new(big.Int).Set(AltcoinBlockReward)
+    inclusion := new(big.Int).Div(AltcoinBlockReward, big32)
+    for range uncles {
+        reward.Add(reward, inclusion)
+    }
+    return reward
 }
 
 // Custom function to distribute PoS + PoT + PoT rewards
-func distributePoSPoTRewards(state *state.StateDB, header *types.Header, reward *big.Int) {
+func distributePoSPoTRewards(header *types.Header, reward *big.Int) []Reward {
     // Logic to identify PoS validators, PoT participants, and PoT (Proof of Trust)
+    var rewards []Reward
     for _, participant := range getPoSAndPoTParticipants() {
         // Calculate individual reward based on their contribution to PoW, PoS, PoT, and uptime
         individualReward := calculateIndividualReward(participant, reward)
-        state.AddBalance(participant.Address, individualReward)
+        rewards = append(rewards, Reward{Address: participant.Address, Kind: RewardParticipant, Amount: individualReward})
     }
+    return rewards
 }
 
 // Calculate individual rewards for PoS + PoT + PoT participants
@@ -712,6 +735,9 @@ func (ethash *Ethash) Finalize(chain consensus.ChainHeaderReader, header *types.
 // uncle rewards, setting the final state and assembling the block.
 func (ethash *Ethash) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB, txs []*types.Transaction, uncles []*types.Header, receipts []*types.Receipt) (*types.Block, error) {
     ethash.Finalize(chain, header, state, txs, uncles)
+    if chain.Config().IsRewardCommit(header.Number) {
+        commitRewards(chain.Config(), header, uncles)
+    }
     return types.NewBlock(header, txs, uncles, receipts, trie.NewStackTrie(nil)), nil
 }
 
@@ -0,0 +1,108 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// EpochLength is the number of blocks sharing the same verification cache and
+// mining dataset.
+const EpochLength = epochLength
+
+// Kinds of ethash files stored on disk, doubling as their file name prefixes.
+const (
+	DAGCache   = "cache" // Verification cache
+	DAGDataset = "full"  // Mining dataset
+)
+
+// dagFileName returns the name of the file the verification cache or mining
+// dataset of the given epoch is stored in.
+func dagFileName(kind string, epoch uint64) string {
+	var endian string
+	if !isLittleEndian() {
+		endian = ".be"
+	}
+	seed := seedHash(epoch*epochLength + 1)
+	return fmt.Sprintf("%s-R%d-%x%s", kind, algorithmRevision, seed[:8], endian)
+}
+
+// DAGFile is a fully generated verification cache or mining dataset on disk.
+type DAGFile struct {
+	Kind    string    // Kind of the file, either DAGCache or DAGDataset
+	Epoch   uint64    // Epoch the file belongs to
+	Name    string    // Name of the file within its directory
+	Path    string    // Full path of the file
+	Size    int64     // Size of the file in bytes
+	ModTime time.Time // Last modification time of the file
+}
+
+// DAGPath returns the path the verification cache or mining dataset of the
+// given epoch is loaded from, or an empty string if it is not kept on disk.
+func (ethash *Ethash) DAGPath(kind string, epoch uint64) string {
+	var dir string
+	switch kind {
+	case DAGCache:
+		dir = ethash.config.CacheDir
+	case DAGDataset:
+		dir = ethash.config.DatasetDir
+	}
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, dagFileName(kind, epoch))
+}
+
+// DAGFiles returns the verification cache and mining dataset files of the given
+// epoch which are fully generated on disk. Files being generated are written to
+// temporary files first, so they are never returned.
+func (ethash *Ethash) DAGFiles(epoch uint64) []DAGFile {
+	var files []DAGFile
+	for _, kind := range []string{DAGCache, DAGDataset} {
+		path := ethash.DAGPath(kind, epoch)
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, DAGFile{
+			Kind:    kind,
+			Epoch:   epoch,
+			Name:    info.Name(),
+			Path:    path,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+	return files
+}
+
+// Pregenerate ensures the verification cache and mining dataset of the epoch
+// of the given block are available and starts generating the ones of the next
+// epoch in the background, so mining does not stall when the epoch turns over.
+func (ethash *Ethash) Pregenerate(block uint64) {
+	if ethash.config.PowMode == ModeFake || ethash.config.PowMode == ModeFullFake {
+		return
+	}
+	ethash.cache(block)
+	ethash.dataset(block, true)
+}
@@ -19,7 +19,6 @@ package ethash
 
 import (
 	"errors"
-	"fmt"
 	"math"
 	"math/big"
 	"math/rand"
@@ -317,11 +316,7 @@ func (c *cache) generate(dir string, limit int, lock bool, test bool) {
 			return
 		}
 		// Disk storage is needed, this will get fancy
-		var endian string
-		if !isLittleEndian() {
-			endian = ".be"
-		}
-		path := filepath.Join(dir, fmt.Sprintf("cache-R%d-%x%s", algorithmRevision, seed[:8], endian))
+		path := filepath.Join(dir, dagFileName(DAGCache, c.epoch))
 		logger := log.New("epoch", c.epoch)
 
 		// We're about to mmap the file, ensure that the mapping is cleaned up when the
@@ -347,8 +342,7 @@ func (c *cache) generate(dir string, limit int, lock bool, test bool) {
 		}
 		// Iterate over all previous instances and delete old ones
 		for ep := int(c.epoch) - limit; ep >= 0; ep-- {
-			seed := seedHash(uint64(ep)*epochLength + 1)
-			path := filepath.Join(dir, fmt.Sprintf("cache-R%d-%x%s*", algorithmRevision, seed[:8], endian))
+			path := filepath.Join(dir, dagFileName(DAGCache, uint64(ep))+"*")
 			files, _ := filepath.Glob(path) // find also the temp files that are generated.
 			for _, file := range files {
 				os.Remove(file)
@@ -406,11 +400,7 @@ func (d *dataset) generate(dir string, limit int, lock bool, test bool) {
 			return
 		}
 		// Disk storage is needed, this will get fancy
-		var endian string
-		if !isLittleEndian() {
-			endian = ".be"
-		}
-		path := filepath.Join(dir, fmt.Sprintf("full-R%d-%x%s", algorithmRevision, seed[:8], endian))
+		path := filepath.Join(dir, dagFileName(DAGDataset, d.epoch))
 		logger := log.New("epoch", d.epoch)
 
 		// We're about to mmap the file, ensure that the mapping is cleaned up when the
@@ -439,8 +429,7 @@ func (d *dataset) generate(dir string, limit int, lock bool, test bool) {
 		}
 		// Iterate over all previous instances and delete old ones
 		for ep := int(d.epoch) - limit; ep >= 0; ep-- {
-			seed := seedHash(uint64(ep)*epochLength + 1)
-			path := filepath.Join(dir, fmt.Sprintf("full-R%d-%x%s", algorithmRevision, seed[:8], endian))
+			path := filepath.Join(dir, dagFileName(DAGDataset, uint64(ep)))
 			os.Remove(path)
 		}
 	})
@@ -519,6 +508,10 @@ type Ethash struct {
 	hashrate metrics.Meter // Meter tracking the average hashrate
 	remote   *remoteSealer
 
+	// Remote share accounting, enabled when running as a solo pool
+	shares    ShareRecorder // Recorder notified of all accepted shares
+	shareDiff *big.Int      // Difficulty a submitted share must satisfy
+
 	// The fields below are hooks for testing
 	shared    *Ethash       // Shared PoW verifier to avoid cache regeneration
 	fakeFail  uint64        // Block number which fails PoW check even in fake mode
@@ -0,0 +1,189 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/consensus"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+	"github.com/Altcoinchain/go-altcoinchain/rlp"
+	"github.com/Altcoinchain/go-altcoinchain/trie"
+)
+
+// Kinds of balance credits made when finalizing a block.
+const (
+	RewardMiner       uint8 = iota // Block and uncle inclusion reward of the coinbase or its split recipients
+	RewardUncle                    // Reward of an included uncle's coinbase
+	RewardParticipant              // Reward of a PoS or PoT participant
+)
+
+// rewardCommitLength is the length of the reward commitment suffix of the
+// extra-data: the magic marker followed by the reward trie root.
+const rewardCommitLength = 4 + common.HashLength
+
+var (
+	// rewardCommitMagic marks the reward commitment at the end of the extra-data.
+	rewardCommitMagic = []byte("RWDC")
+
+	errMissingRewardCommit = errors.New("missing reward commitment")
+	errInvalidRewardCommit = errors.New("invalid reward commitment")
+)
+
+// Reward is a single balance credit made when finalizing a block.
+type Reward struct {
+	Address common.Address
+	Kind    uint8
+	Amount  *big.Int
+}
+
+// RewardEntry is a single balance credit of an address, as stored in the reward
+// trie of a block.
+type RewardEntry struct {
+	Kind   uint8
+	Amount *big.Int
+}
+
+// RewardTrie builds the trie committing to the rewards of a block. It is keyed
+// by the credited addresses, every value being the RLP encoded list of credits
+// of that address in the order they are applied.
+func RewardTrie(rewards []Reward) (*trie.Trie, error) {
+	var (
+		order   []common.Address
+		entries = make(map[common.Address][]RewardEntry)
+	)
+	for _, reward := range rewards {
+		if _, ok := entries[reward.Address]; !ok {
+			order = append(order, reward.Address)
+		}
+		entries[reward.Address] = append(entries[reward.Address], RewardEntry{Kind: reward.Kind, Amount: reward.Amount})
+	}
+	tr := trie.NewEmpty(trie.NewDatabase(rawdb.NewMemoryDatabase()))
+	for _, addr := range order {
+		blob, err := rlp.EncodeToBytes(entries[addr])
+		if err != nil {
+			return nil, err
+		}
+		if err := tr.TryUpdate(addr.Bytes(), blob); err != nil {
+			return nil, err
+		}
+	}
+	return tr, nil
+}
+
+// RewardsRoot returns the root hash of the reward trie of a block.
+func RewardsRoot(rewards []Reward) common.Hash {
+	tr, err := RewardTrie(rewards)
+	if err != nil {
+		// Encoding big integers and in-memory insertions cannot fail
+		panic(fmt.Sprintf("failed to build reward trie: %v", err))
+	}
+	return tr.Hash()
+}
+
+// HeaderRewardsRoot returns the reward trie root committed to by a header, or
+// false if the header does not carry a reward commitment.
+func HeaderRewardsRoot(config *params.ChainConfig, header *types.Header) (common.Hash, bool) {
+	if !config.IsRewardCommit(header.Number) {
+		return common.Hash{}, false
+	}
+	return decodeRewardCommit(header.Extra)
+}
+
+// VerifyRewardsProof checks a merkle proof of the rewards of an address against
+// a reward trie root, returning the credits of the address. A valid proof of
+// absence yields no credits.
+func VerifyRewardsProof(root common.Hash, addr common.Address, proofDb ethdb.KeyValueReader) ([]RewardEntry, error) {
+	blob, err := trie.VerifyProof(root, addr.Bytes(), proofDb)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) == 0 {
+		return nil, nil
+	}
+	var entries []RewardEntry
+	if err := rlp.DecodeBytes(blob, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// decodeRewardCommit extracts the reward trie root from the end of an extra-data
+// field, returning false if there is none.
+func decodeRewardCommit(extra []byte) (common.Hash, bool) {
+	if len(extra) < rewardCommitLength {
+		return common.Hash{}, false
+	}
+	suffix := extra[len(extra)-rewardCommitLength:]
+	if !bytes.HasPrefix(suffix, rewardCommitMagic) {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(suffix[len(rewardCommitMagic):]), true
+}
+
+// extraBody returns the extra-data of a header without its reward commitment.
+func extraBody(config *params.ChainConfig, header *types.Header) []byte {
+	if !config.IsRewardCommit(header.Number) {
+		return header.Extra
+	}
+	if _, ok := decodeRewardCommit(header.Extra); !ok {
+		return header.Extra
+	}
+	return header.Extra[:len(header.Extra)-rewardCommitLength]
+}
+
+// commitRewards appends the commitment to the rewards of a block to the
+// extra-data of its header, replacing any previous commitment.
+func commitRewards(config *params.ChainConfig, header *types.Header, uncles []*types.Header) {
+	root := RewardsRoot(BlockRewardList(config, header, uncles))
+
+	extra := common.CopyBytes(extraBody(config, header))
+	extra = append(extra, rewardCommitMagic...)
+	header.Extra = append(extra, root.Bytes()...)
+}
+
+// verifyRewardCommit checks that the reward commitment of a header matches the
+// rewards credited when finalizing the block.
+func verifyRewardCommit(config *params.ChainConfig, header *types.Header, uncles []*types.Header) error {
+	if !config.IsRewardCommit(header.Number) {
+		return nil
+	}
+	root, ok := decodeRewardCommit(header.Extra)
+	if !ok {
+		return errMissingRewardCommit
+	}
+	if want := RewardsRoot(BlockRewardList(config, header, uncles)); root != want {
+		return fmt.Errorf("%w: have %x, want %x", errInvalidRewardCommit, root, want)
+	}
+	return nil
+}
+
+// VerifyRewards implements consensus.RewardVerifier, checking that the reward
+// commitment of a block's header matches the rewards credited when finalizing it.
+func (ethash *Ethash) VerifyRewards(chain consensus.ChainHeaderReader, block *types.Block) error {
+	if ethash.config.PowMode == ModeFullFake {
+		return nil
+	}
+	return verifyRewardCommit(chain.Config(), block.Header(), block.Uncles())
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb/memorydb"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+)
+
+// Tests that reward commitments are only required after the fork, and that they
+// are checked against the rewards credited by the block.
+func TestRewardCommitVerify(t *testing.T) {
+	config := *params.TestChainConfig
+	config.RewardCommitBlock = big.NewInt(10)
+	config.RewardSplitBlock = big.NewInt(10)
+
+	extra, _ := EncodeRewardSplit([]RewardSplit{{Address: common.HexToAddress("0x01"), Weight: 1}}, []byte("vanity"))
+	header := &types.Header{Number: big.NewInt(10), Coinbase: common.HexToAddress("0xc0"), Extra: extra}
+	uncles := []*types.Header{{Number: big.NewInt(9), Coinbase: common.HexToAddress("0x02")}}
+
+	if err := verifyExtra(&config, header); err != errMissingRewardCommit {
+		t.Errorf("uncommitted header error mismatch: have %v, want %v", err, errMissingRewardCommit)
+	}
+	commitRewards(&config, header, uncles)
+	if err := verifyExtra(&config, header); err != nil {
+		t.Errorf("committed header rejected: %v", err)
+	}
+	if err := verifyRewardCommit(&config, header, uncles); err != nil {
+		t.Errorf("valid commitment rejected: %v", err)
+	}
+	if err := verifyRewardCommit(&config, header, nil); !errors.Is(err, errInvalidRewardCommit) {
+		t.Errorf("mismatching commitment error mismatch: have %v, want %v", err, errInvalidRewardCommit)
+	}
+	// Recommitting must replace the commitment instead of stacking another one
+	commitRewards(&config, header, nil)
+	if len(header.Extra) != len(extra)+rewardCommitLength {
+		t.Errorf("extra-data length mismatch: have %d, want %d", len(header.Extra), len(extra)+rewardCommitLength)
+	}
+	if err := verifyRewardCommit(&config, header, nil); err != nil {
+		t.Errorf("replaced commitment rejected: %v", err)
+	}
+	// Headers before the fork are neither required nor allowed to exceed the cap
+	header = &types.Header{Number: big.NewInt(9), Extra: []byte("vanity")}
+	if err := verifyExtra(&config, header); err != nil {
+		t.Errorf("pre-fork header rejected: %v", err)
+	}
+	if err := verifyRewardCommit(&config, header, uncles); err != nil {
+		t.Errorf("pre-fork header commitment checked: %v", err)
+	}
+	if _, ok := HeaderRewardsRoot(&config, header); ok {
+		t.Errorf("pre-fork header reports a reward commitment")
+	}
+}
+
+// Tests that the rewards of an address can be proven against the commitment of
+// the header, including addresses not rewarded at all.
+func TestRewardCommitProof(t *testing.T) {
+	config := *params.TestChainConfig
+	config.RewardCommitBlock = big.NewInt(0)
+
+	var (
+		coinbase = common.HexToAddress("0xc0")
+		uncle    = common.HexToAddress("0x02")
+	)
+	header := &types.Header{Number: big.NewInt(10), Coinbase: coinbase}
+	uncles := []*types.Header{
+		{Number: big.NewInt(9), Coinbase: uncle},
+		{Number: big.NewInt(8), Coinbase: uncle},
+	}
+	commitRewards(&config, header, uncles)
+
+	root, ok := HeaderRewardsRoot(&config, header)
+	if !ok {
+		t.Fatalf("committed header reports no reward commitment")
+	}
+	tr, err := RewardTrie(BlockRewardList(&config, header, uncles))
+	if err != nil {
+		t.Fatalf("failed to build reward trie: %v", err)
+	}
+	want := map[common.Address][]RewardEntry{
+		coinbase: {{Kind: RewardMiner, Amount: MinerReward(header, uncles)}},
+		uncle: {
+			{Kind: RewardUncle, Amount: new(big.Int).Div(new(big.Int).Mul(AltcoinBlockReward, big.NewInt(7)), big8)},
+			{Kind: RewardUncle, Amount: new(big.Int).Div(new(big.Int).Mul(AltcoinBlockReward, big.NewInt(6)), big8)},
+		},
+		common.HexToAddress("0x03"): nil,
+	}
+	for addr, entries := range want {
+		proof := memorydb.New()
+		if err := tr.Prove(addr.Bytes(), 0, proof); err != nil {
+			t.Fatalf("%x: failed to prove rewards: %v", addr, err)
+		}
+		have, err := VerifyRewardsProof(root, addr, proof)
+		if err != nil {
+			t.Fatalf("%x: failed to verify proof: %v", addr, err)
+		}
+		if len(have) != len(entries) {
+			t.Fatalf("%x: reward count mismatch: have %d, want %d", addr, len(have), len(entries))
+		}
+		for i := range have {
+			if have[i].Kind != entries[i].Kind || have[i].Amount.Cmp(entries[i].Amount) != 0 {
+				t.Errorf("%x: reward %d mismatch: have %v, want %v", addr, i, have[i], entries[i])
+			}
+		}
+		if _, err := VerifyRewardsProof(common.Hash{0x01}, addr, proof); err == nil {
+			t.Errorf("%x: proof verified against wrong root", addr)
+		}
+	}
+}
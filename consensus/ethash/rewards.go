@@ -0,0 +1,103 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"math/big"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+)
+
+// BlockRewards is the expected reward breakdown of a block, as credited by
+// accumulateRewards once the block is sealed.
+type BlockRewards struct {
+	Subsidy        *big.Int // Static block reward credited to the coinbase
+	UncleInclusion *big.Int // Reward credited to the coinbase for including uncles
+	Fees           *big.Int // Priority fees paid by the included transactions
+	Split          *big.Int // Part of the subsidy and uncle inclusion reward credited to the split recipients instead
+	Treasury       *big.Int // Part of the PoS and PoT allocation not distributed to any participant
+}
+
+// Total returns the amount credited to the coinbase of the block.
+func (r *BlockRewards) Total() *big.Int {
+	total := new(big.Int).Add(r.Subsidy, r.UncleInclusion)
+	total.Add(total, r.Fees)
+	return total.Sub(total, r.Split)
+}
+
+// ExpectedRewards returns the reward breakdown of a block with the given uncles
+// and transaction fees. The amounts are taken from the balance credits made by
+// the finalizer, so a reward split in the header is accounted for.
+func ExpectedRewards(config *params.ChainConfig, header *types.Header, uncles []*types.Header, fees *big.Int) *BlockRewards {
+	rewards := &BlockRewards{
+		Subsidy:        new(big.Int).Set(AltcoinBlockReward),
+		UncleInclusion: new(big.Int),
+		Fees:           new(big.Int),
+		Split:          new(big.Int),
+		Treasury:       new(big.Int).Set(AltcoinBlockReward),
+	}
+	// The miner credits add up to the subsidy and the uncle inclusion reward
+	miner := new(big.Int)
+	for _, reward := range BlockRewardList(config, header, uncles) {
+		switch reward.Kind {
+		case RewardMiner:
+			miner.Add(miner, reward.Amount)
+			if reward.Address != header.Coinbase {
+				rewards.Split.Add(rewards.Split, reward.Amount)
+			}
+		case RewardParticipant:
+			rewards.Treasury.Sub(rewards.Treasury, reward.Amount)
+		}
+	}
+	rewards.UncleInclusion.Sub(miner, rewards.Subsidy)
+
+	if fees != nil {
+		rewards.Fees.Set(fees)
+	}
+	if rewards.Treasury.Sign() < 0 {
+		rewards.Treasury.SetUint64(0)
+	}
+	return rewards
+}
+
+// workRewards is the expected reward breakdown of a work package, reported by
+// the block producer before the package is handed out for sealing.
+type workRewards struct {
+	sealhash common.Hash
+	number   uint64
+	rewards  *BlockRewards
+}
+
+// ReportWorkRewards attaches the expected reward breakdown to the work package
+// of the given header. It must be called before the block is sealed for the
+// remote work package to include it.
+func (ethash *Ethash) ReportWorkRewards(header *types.Header, rewards *BlockRewards) {
+	if ethash.remote == nil {
+		return
+	}
+	work := &workRewards{
+		sealhash: ethash.SealHash(header),
+		number:   header.Number.Uint64(),
+		rewards:  rewards,
+	}
+	select {
+	case ethash.remote.rewardsCh <- work:
+	case <-ethash.remote.exitCh:
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+)
+
+// Tests that the expected reward breakdown accounts for uncles and fees.
+func TestExpectedRewards(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(10)}
+	uncles := []*types.Header{{Number: big.NewInt(9)}, {Number: big.NewInt(8)}}
+
+	rewards := ExpectedRewards(params.TestChainConfig, header, uncles, big.NewInt(1000))
+	if rewards.Subsidy.Cmp(AltcoinBlockReward) != 0 {
+		t.Errorf("subsidy mismatch: have %v, want %v", rewards.Subsidy, AltcoinBlockReward)
+	}
+	inclusion := new(big.Int).Div(AltcoinBlockReward, big.NewInt(16))
+	if rewards.UncleInclusion.Cmp(inclusion) != 0 {
+		t.Errorf("uncle inclusion mismatch: have %v, want %v", rewards.UncleInclusion, inclusion)
+	}
+	if rewards.Fees.Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("fees mismatch: have %v, want %v", rewards.Fees, 1000)
+	}
+	if rewards.Split.Sign() != 0 {
+		t.Errorf("split mismatch: have %v, want 0", rewards.Split)
+	}
+	want := new(big.Int).Add(AltcoinBlockReward, inclusion)
+	want.Add(want, big.NewInt(1000))
+	if total := rewards.Total(); total.Cmp(want) != 0 {
+		t.Errorf("total mismatch: have %v, want %v", total, want)
+	}
+	if reward := MinerReward(header, uncles); reward.Cmp(new(big.Int).Add(AltcoinBlockReward, inclusion)) != 0 {
+		t.Errorf("miner reward mismatch: have %v, want %v", reward, new(big.Int).Add(AltcoinBlockReward, inclusion))
+	}
+}
+
+// Tests that the expected coinbase reward excludes the shares of the reward
+// split recipients.
+func TestExpectedRewardsSplit(t *testing.T) {
+	config := *params.TestChainConfig
+	config.RewardSplitBlock = big.NewInt(1)
+
+	coinbase := common.HexToAddress("0xc0")
+	extra, _ := EncodeRewardSplit([]RewardSplit{{Address: common.HexToAddress("0x01"), Weight: 1}, {Address: coinbase, Weight: 1}}, nil)
+	header := &types.Header{Number: big.NewInt(10), Coinbase: coinbase, Extra: extra}
+	uncles := []*types.Header{{Number: big.NewInt(9)}}
+
+	rewards := ExpectedRewards(&config, header, uncles, big.NewInt(1000))
+	inclusion := new(big.Int).Div(AltcoinBlockReward, big.NewInt(32))
+	if rewards.UncleInclusion.Cmp(inclusion) != 0 {
+		t.Errorf("uncle inclusion mismatch: have %v, want %v", rewards.UncleInclusion, inclusion)
+	}
+	split := new(big.Int).Add(AltcoinBlockReward, inclusion)
+	split.Div(split, big.NewInt(2))
+	if rewards.Split.Cmp(split) != 0 {
+		t.Errorf("split mismatch: have %v, want %v", rewards.Split, split)
+	}
+	want := new(big.Int).Add(AltcoinBlockReward, inclusion)
+	want.Sub(want, split)
+	want.Add(want, big.NewInt(1000))
+	if total := rewards.Total(); total.Cmp(want) != 0 {
+		t.Errorf("total mismatch: have %v, want %v", total, want)
+	}
+}
+
+// Tests that reported rewards are attached to the remote work package.
+func TestRemoteWorkRewards(t *testing.T) {
+	ethash := NewTester(nil, false)
+	defer ethash.Close()
+
+	getWork := func() []string {
+		var (
+			workCh = make(chan []string, 1)
+			errc   = make(chan error, 1)
+		)
+		ethash.remote.fetchWorkCh <- &sealWork{errc: errc, res: workCh}
+		select {
+		case work := <-workCh:
+			return work
+		case err := <-errc:
+			t.Fatalf("failed to fetch work: %v", err)
+		}
+		return nil
+	}
+	results := make(chan *types.Block)
+
+	// Work without reported rewards should only have the legacy fields
+	header := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(100)}
+	ethash.Seal(nil, types.NewBlockWithHeader(header), results, nil)
+	if work := getWork(); len(work) != 4 {
+		t.Fatalf("work package length mismatch: have %d, want 4", len(work))
+	}
+	// Work with reported rewards should carry the expected total
+	header = &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1000)}
+	rewards := ExpectedRewards(params.TestChainConfig, header, nil, big.NewInt(1))
+	ethash.ReportWorkRewards(header, rewards)
+	ethash.Seal(nil, types.NewBlockWithHeader(header), results, nil)
+
+	work := getWork()
+	if len(work) != 5 {
+		t.Fatalf("work package length mismatch: have %d, want 5", len(work))
+	}
+	if want := hexutil.EncodeBig(rewards.Total()); work[4] != want {
+		t.Errorf("work reward mismatch: have %s, want %s", work[4], want)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+	"github.com/Altcoinchain/go-altcoinchain/rlp"
+)
+
+const (
+	// maxRewardSplits is the maximum number of reward recipients a single block
+	// header may encode in its extra-data.
+	maxRewardSplits = 16
+)
+
+var (
+	// rewardSplitMagic prefixes the extra-data of headers carrying a reward split.
+	rewardSplitMagic = []byte("RSPL")
+
+	errRewardSplitEmpty     = errors.New("empty reward split")
+	errRewardSplitTooLong   = errors.New("too many reward split recipients")
+	errRewardSplitWeight    = errors.New("zero reward split weight")
+	errRewardSplitDuplicate = errors.New("duplicate reward split recipient")
+)
+
+// RewardSplit is a single recipient of a weighted coinbase reward split.
+type RewardSplit struct {
+	Address common.Address `toml:",omitempty"`
+	Weight  uint64         `toml:",omitempty"`
+}
+
+// rewardSplitExtra is the RLP layout of the extra-data following the magic
+// prefix when a header carries a reward split.
+type rewardSplitExtra struct {
+	Vanity []byte
+	Splits []RewardSplit
+}
+
+// EncodeRewardSplit packs the given reward split and vanity data into a header
+// extra-data field.
+func EncodeRewardSplit(splits []RewardSplit, vanity []byte) ([]byte, error) {
+	if err := validateRewardSplit(splits); err != nil {
+		return nil, err
+	}
+	blob, err := rlp.EncodeToBytes(&rewardSplitExtra{Vanity: vanity, Splits: splits})
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte{}, rewardSplitMagic...), blob...), nil
+}
+
+// DecodeRewardSplit extracts the reward split from a header extra-data field,
+// returning nil if the extra-data does not carry one.
+func DecodeRewardSplit(extra []byte) ([]RewardSplit, error) {
+	if !bytes.HasPrefix(extra, rewardSplitMagic) {
+		return nil, nil
+	}
+	var dec rewardSplitExtra
+	if err := rlp.DecodeBytes(extra[len(rewardSplitMagic):], &dec); err != nil {
+		return nil, fmt.Errorf("invalid reward split: %v", err)
+	}
+	if uint64(len(dec.Vanity)) > params.MaximumExtraDataSize {
+		return nil, fmt.Errorf("reward split vanity too long: %d > %d", len(dec.Vanity), params.MaximumExtraDataSize)
+	}
+	if err := validateRewardSplit(dec.Splits); err != nil {
+		return nil, err
+	}
+	return dec.Splits, nil
+}
+
+// validateRewardSplit checks that a reward split is well formed.
+func validateRewardSplit(splits []RewardSplit) error {
+	if len(splits) == 0 {
+		return errRewardSplitEmpty
+	}
+	if len(splits) > maxRewardSplits {
+		return errRewardSplitTooLong
+	}
+	seen := make(map[common.Address]struct{})
+	for _, split := range splits {
+		if split.Weight == 0 {
+			return errRewardSplitWeight
+		}
+		if _, ok := seen[split.Address]; ok {
+			return errRewardSplitDuplicate
+		}
+		seen[split.Address] = struct{}{}
+	}
+	return nil
+}
+
+// verifyExtra checks the extra-data of a header. Before the reward split fork it
+// is free form but capped in size, afterwards it may also carry a reward split,
+// whose vanity part is subject to the same cap. After the reward commitment fork
+// the extra-data must end with a reward commitment, which is not counted towards
+// the cap.
+func verifyExtra(config *params.ChainConfig, header *types.Header) error {
+	extra := header.Extra
+	if config.IsRewardCommit(header.Number) {
+		if _, ok := decodeRewardCommit(extra); !ok {
+			return errMissingRewardCommit
+		}
+		extra = extra[:len(extra)-rewardCommitLength]
+	}
+	if config.IsRewardSplit(header.Number) && bytes.HasPrefix(extra, rewardSplitMagic) {
+		_, err := DecodeRewardSplit(extra)
+		return err
+	}
+	if uint64(len(extra)) > params.MaximumExtraDataSize {
+		return fmt.Errorf("extra-data too long: %d > %d", len(extra), params.MaximumExtraDataSize)
+	}
+	return nil
+}
+
+// coinbaseShares splits the miner reward of a block between its coinbase or,
+// after the reward split fork, the weighted recipients encoded in the extra-data.
+// Any rounding remainder of the split is credited to the coinbase.
+func coinbaseShares(config *params.ChainConfig, header *types.Header, reward *big.Int) []rewardShare {
+	if !config.IsRewardSplit(header.Number) {
+		return []rewardShare{{address: header.Coinbase, amount: reward}}
+	}
+	splits, err := DecodeRewardSplit(extraBody(config, header))
+	if err != nil || len(splits) == 0 {
+		return []rewardShare{{address: header.Coinbase, amount: reward}}
+	}
+	shares := splitReward(splits, reward)
+
+	remainder := new(big.Int).Set(reward)
+	for _, share := range shares {
+		remainder.Sub(remainder, share.amount)
+	}
+	if remainder.Sign() > 0 {
+		shares = append(shares, rewardShare{address: header.Coinbase, amount: remainder})
+	}
+	return shares
+}
+
+// rewardShare is the amount of a split reward credited to a single recipient.
+type rewardShare struct {
+	address common.Address
+	amount  *big.Int
+}
+
+// splitReward divides a reward proportionally to the weights of the splits,
+// rounding each share down.
+func splitReward(splits []RewardSplit, reward *big.Int) []rewardShare {
+	total := new(big.Int)
+	for _, split := range splits {
+		total.Add(total, new(big.Int).SetUint64(split.Weight))
+	}
+	shares := make([]rewardShare, 0, len(splits))
+	for _, split := range splits {
+		amount := new(big.Int).Mul(reward, new(big.Int).SetUint64(split.Weight))
+		amount.Div(amount, total)
+		shares = append(shares, rewardShare{address: split.Address, amount: amount})
+	}
+	return shares
+}
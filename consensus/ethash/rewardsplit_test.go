@@ -0,0 +1,131 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/core/state"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+)
+
+// Tests that reward splits survive an extra-data round trip and that malformed
+// splits are rejected.
+func TestRewardSplitEncoding(t *testing.T) {
+	splits := []RewardSplit{
+		{Address: common.HexToAddress("0x01"), Weight: 1},
+		{Address: common.HexToAddress("0x02"), Weight: 3},
+	}
+	extra, err := EncodeRewardSplit(splits, []byte("vanity"))
+	if err != nil {
+		t.Fatalf("failed to encode reward split: %v", err)
+	}
+	decoded, err := DecodeRewardSplit(extra)
+	if err != nil {
+		t.Fatalf("failed to decode reward split: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, splits) {
+		t.Errorf("reward split mismatch: have %v, want %v", decoded, splits)
+	}
+	if decoded, err := DecodeRewardSplit([]byte("plain vanity")); decoded != nil || err != nil {
+		t.Errorf("plain extra-data decoded as split: %v, %v", decoded, err)
+	}
+	if _, err := EncodeRewardSplit(nil, nil); err != errRewardSplitEmpty {
+		t.Errorf("empty split error mismatch: have %v, want %v", err, errRewardSplitEmpty)
+	}
+	if _, err := EncodeRewardSplit([]RewardSplit{{Weight: 0}}, nil); err != errRewardSplitWeight {
+		t.Errorf("zero weight error mismatch: have %v, want %v", err, errRewardSplitWeight)
+	}
+	if _, err := EncodeRewardSplit([]RewardSplit{splits[0], splits[0]}, nil); err != errRewardSplitDuplicate {
+		t.Errorf("duplicate error mismatch: have %v, want %v", err, errRewardSplitDuplicate)
+	}
+	if _, err := EncodeRewardSplit(make([]RewardSplit, maxRewardSplits+1), nil); err != errRewardSplitTooLong {
+		t.Errorf("oversized split error mismatch: have %v, want %v", err, errRewardSplitTooLong)
+	}
+	if _, err := DecodeRewardSplit(append(extra, 0x00)); err == nil {
+		t.Errorf("trailing garbage accepted")
+	}
+}
+
+// Tests that oversized extra-data is only accepted after the fork, and only if
+// it carries a valid reward split.
+func TestRewardSplitVerifyExtra(t *testing.T) {
+	config := *params.TestChainConfig
+	config.RewardSplitBlock = big.NewInt(10)
+
+	splits := make([]RewardSplit, maxRewardSplits)
+	for i := range splits {
+		splits[i] = RewardSplit{Address: common.BytesToAddress([]byte{byte(i + 1)}), Weight: uint64(i + 1)}
+	}
+	extra, _ := EncodeRewardSplit(splits, bytes.Repeat([]byte{0xff}, int(params.MaximumExtraDataSize)))
+
+	tests := []struct {
+		number uint64
+		extra  []byte
+		fail   bool
+	}{
+		{9, bytes.Repeat([]byte{0x01}, int(params.MaximumExtraDataSize)), false},
+		{9, extra, true},
+		{10, extra, false},
+		{10, bytes.Repeat([]byte{0x01}, int(params.MaximumExtraDataSize)+1), true},
+		{10, append(append([]byte{}, rewardSplitMagic...), 0xc0), true},
+	}
+	for i, tt := range tests {
+		header := &types.Header{Number: new(big.Int).SetUint64(tt.number), Extra: tt.extra}
+		if err := verifyExtra(&config, header); (err != nil) != tt.fail {
+			t.Errorf("test %d: failure mismatch: have %v, want fail %v", i, err, tt.fail)
+		}
+	}
+}
+
+// Tests that the coinbase reward is split by weight, with the rounding remainder
+// credited to the coinbase.
+func TestRewardSplitCredit(t *testing.T) {
+	config := *params.TestChainConfig
+	config.RewardSplitBlock = big.NewInt(1)
+
+	var (
+		coinbase = common.HexToAddress("0xc0")
+		first    = common.HexToAddress("0x01")
+		second   = common.HexToAddress("0x02")
+	)
+	extra, _ := EncodeRewardSplit([]RewardSplit{{Address: first, Weight: 1}, {Address: second, Weight: 2}}, nil)
+
+	for _, number := range []int64{0, 1} {
+		statedb, _ := state.New(common.Hash{}, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		header := &types.Header{Number: big.NewInt(number), Coinbase: coinbase, Extra: extra}
+		for _, share := range coinbaseShares(&config, header, big.NewInt(100)) {
+			statedb.AddBalance(share.address, share.amount)
+		}
+
+		want := map[common.Address]int64{coinbase: 100}
+		if number > 0 {
+			want = map[common.Address]int64{coinbase: 1, first: 33, second: 66}
+		}
+		for addr, amount := range want {
+			if have := statedb.GetBalance(addr); have.Cmp(big.NewInt(amount)) != 0 {
+				t.Errorf("block %d: balance of %x mismatch: have %v, want %v", number, addr, have, amount)
+			}
+		}
+	}
+}
@@ -192,9 +192,11 @@ const remoteSealerTimeout = 1 * time.Second
 
 type remoteSealer struct {
 	works        map[common.Hash]*types.Block
+	shares       map[common.Hash]map[types.BlockNonce]struct{} // Nonces of accepted shares per work package
+	rewards      map[common.Hash]*workRewards
 	rates        map[common.Hash]hashrate
 	currentBlock *types.Block
-	currentWork  [4]string
+	currentWork  []string
 	notifyCtx    context.Context
 	cancelNotify context.CancelFunc // cancels all notification requests
 	reqWG        sync.WaitGroup     // tracks notification request goroutines
@@ -203,11 +205,12 @@ type remoteSealer struct {
 	noverify     bool
 	notifyURLs   []string
 	results      chan<- *types.Block
-	workCh       chan *sealTask   // Notification channel to push new work and relative result channel to remote sealer
-	fetchWorkCh  chan *sealWork   // Channel used for remote sealer to fetch mining work
-	submitWorkCh chan *mineResult // Channel used for remote sealer to submit their mining result
-	fetchRateCh  chan chan uint64 // Channel used to gather submitted hash rate for local or remote sealer.
-	submitRateCh chan *hashrate   // Channel used for remote sealer to submit their mining hashrate
+	workCh       chan *sealTask    // Notification channel to push new work and relative result channel to remote sealer
+	rewardsCh    chan *workRewards // Channel used to attach the expected rewards to upcoming work
+	fetchWorkCh  chan *sealWork    // Channel used for remote sealer to fetch mining work
+	submitWorkCh chan *mineResult  // Channel used for remote sealer to submit their mining result
+	fetchRateCh  chan chan uint64  // Channel used to gather submitted hash rate for local or remote sealer.
+	submitRateCh chan *hashrate    // Channel used for remote sealer to submit their mining hashrate
 	requestExit  chan struct{}
 	exitCh       chan struct{}
 }
@@ -223,6 +226,7 @@ type mineResult struct {
 	nonce     types.BlockNonce
 	mixDigest common.Hash
 	hash      common.Hash
+	miner     *common.Address // Share submitter, nil for plain work submissions
 
 	errc chan error
 }
@@ -239,7 +243,7 @@ type hashrate struct {
 // sealWork wraps a seal work package for remote sealer.
 type sealWork struct {
 	errc chan error
-	res  chan [4]string
+	res  chan []string
 }
 
 func startRemoteSealer(ethash *Ethash, urls []string, noverify bool) *remoteSealer {
@@ -251,8 +255,11 @@ func startRemoteSealer(ethash *Ethash, urls []string, noverify bool) *remoteSeal
 		notifyCtx:    ctx,
 		cancelNotify: cancel,
 		works:        make(map[common.Hash]*types.Block),
+		shares:       make(map[common.Hash]map[types.BlockNonce]struct{}),
+		rewards:      make(map[common.Hash]*workRewards),
 		rates:        make(map[common.Hash]hashrate),
 		workCh:       make(chan *sealTask),
+		rewardsCh:    make(chan *workRewards),
 		fetchWorkCh:  make(chan *sealWork),
 		submitWorkCh: make(chan *mineResult),
 		fetchRateCh:  make(chan chan uint64),
@@ -284,6 +291,10 @@ func (s *remoteSealer) loop() {
 			s.makeWork(work.block)
 			s.notifyWork()
 
+		case work := <-s.rewardsCh:
+			// Track the expected rewards of work about to be sealed.
+			s.rewards[work.sealhash] = work
+
 		case work := <-s.fetchWorkCh:
 			// Return current mining work to remote miner.
 			if s.currentBlock == nil {
@@ -293,6 +304,11 @@ func (s *remoteSealer) loop() {
 			}
 
 		case result := <-s.submitWorkCh:
+			// Shares are accounted separately, they might not be blocks at all.
+			if result.miner != nil {
+				result.errc <- s.submitShare(*result.miner, result.nonce, result.mixDigest, result.hash)
+				continue
+			}
 			// Verify submitted PoW solution based on maintained mining blocks.
 			if s.submitWork(result.nonce, result.mixDigest, result.hash) {
 				result.errc <- nil
@@ -326,6 +342,12 @@ func (s *remoteSealer) loop() {
 				for hash, block := range s.works {
 					if block.NumberU64()+staleThreshold <= s.currentBlock.NumberU64() {
 						delete(s.works, hash)
+						delete(s.shares, hash)
+					}
+				}
+				for hash, work := range s.rewards {
+					if work.number+staleThreshold <= s.currentBlock.NumberU64() {
+						delete(s.rewards, hash)
 					}
 				}
 			}
@@ -338,17 +360,23 @@ func (s *remoteSealer) loop() {
 
 // makeWork creates a work package for external miner.
 //
-// The work package consists of 3 strings:
+// The work package consists of 4 or 5 strings:
 //   result[0], 32 bytes hex encoded current block header pow-hash
 //   result[1], 32 bytes hex encoded seed hash used for DAG
 //   result[2], 32 bytes hex encoded boundary condition ("target"), 2^256/difficulty
 //   result[3], hex encoded block number
+//   result[4], hex encoded expected coinbase reward, if reported by the producer
 func (s *remoteSealer) makeWork(block *types.Block) {
 	hash := s.ethash.SealHash(block.Header())
-	s.currentWork[0] = hash.Hex()
-	s.currentWork[1] = common.BytesToHash(SeedHash(block.NumberU64())).Hex()
-	s.currentWork[2] = common.BytesToHash(new(big.Int).Div(two256, block.Difficulty()).Bytes()).Hex()
-	s.currentWork[3] = hexutil.EncodeBig(block.Number())
+	s.currentWork = []string{
+		hash.Hex(),
+		common.BytesToHash(SeedHash(block.NumberU64())).Hex(),
+		common.BytesToHash(new(big.Int).Div(two256, block.Difficulty()).Bytes()).Hex(),
+		hexutil.EncodeBig(block.Number()),
+	}
+	if work := s.rewards[hash]; work != nil {
+		s.currentWork = append(s.currentWork, hexutil.EncodeBig(work.rewards.Total()))
+	}
 
 	// Trace the seal work fetched by remote sealer.
 	s.currentBlock = block
@@ -375,7 +403,7 @@ func (s *remoteSealer) notifyWork() {
 	}
 }
 
-func (s *remoteSealer) sendNotification(ctx context.Context, url string, json []byte, work [4]string) {
+func (s *remoteSealer) sendNotification(ctx context.Context, url string, json []byte, work []string) {
 	defer s.reqWG.Done()
 
 	req, err := http.NewRequest("POST", url, bytes.NewReader(json))
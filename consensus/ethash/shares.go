@@ -0,0 +1,135 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"runtime"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+)
+
+var (
+	errNoShareRecorder = errors.New("share accounting disabled")
+	errLowShare        = errors.New("share below pool difficulty")
+	errDuplicateShare  = errors.New("duplicate share")
+)
+
+// ShareRecorder is the interface through which the remote sealer reports every
+// accepted low-difficulty share, e.g. to the payout ledger of a solo pool.
+type ShareRecorder interface {
+	// RecordShare accounts a share of the given difficulty, found by miner for
+	// the work package of the given block number.
+	RecordShare(miner common.Address, number uint64, difficulty *big.Int) error
+}
+
+// SetShareRecorder enables share accounting on the remote sealer. Shares
+// submitted through ethash_submitShare must satisfy the given difficulty, which
+// should be well below the block difficulty for shares to be meaningful.
+func (ethash *Ethash) SetShareRecorder(recorder ShareRecorder, difficulty *big.Int) {
+	ethash.lock.Lock()
+	defer ethash.lock.Unlock()
+
+	ethash.shares = recorder
+	ethash.shareDiff = new(big.Int).Set(difficulty)
+}
+
+// shareRecorder returns the currently configured share recorder and share
+// difficulty, if any.
+func (ethash *Ethash) shareRecorder() (ShareRecorder, *big.Int) {
+	ethash.lock.Lock()
+	defer ethash.lock.Unlock()
+
+	return ethash.shares, ethash.shareDiff
+}
+
+// powResult runs the hashimoto function over the given header, returning the
+// mix digest and the final pow value without comparing it to any target. Like
+// header verification, it only uses the verification cache, so accepting shares
+// never triggers the generation of a full dataset.
+func (ethash *Ethash) powResult(header *types.Header) ([]byte, []byte) {
+	if ethash.config.PowMode == ModeFake || ethash.config.PowMode == ModeFullFake {
+		return header.MixDigest[:], make([]byte, common.HashLength)
+	}
+	number := header.Number.Uint64()
+	cache := ethash.cache(number)
+
+	size := datasetSize(number)
+	if ethash.config.PowMode == ModeTest {
+		size = 32 * 1024
+	}
+	digest, result := hashimotoLight(size, cache.cache, ethash.SealHash(header).Bytes(), header.Nonce.Uint64())
+	runtime.KeepAlive(cache)
+	return digest, result
+}
+
+// submitShare verifies a share submitted by a remote miner against the share
+// difficulty and reports it to the share recorder. If the share happens to also
+// satisfy the block difficulty, it's forwarded as a regular sealing result.
+func (s *remoteSealer) submitShare(miner common.Address, nonce types.BlockNonce, mixDigest common.Hash, sealhash common.Hash) error {
+	recorder, shareDiff := s.ethash.shareRecorder()
+	if recorder == nil {
+		return errNoShareRecorder
+	}
+	if s.currentBlock == nil {
+		return errNoMiningWork
+	}
+	block := s.works[sealhash]
+	if block == nil {
+		s.ethash.config.Log.Debug("Share submitted but no work pending", "sealhash", sealhash, "miner", miner)
+		return errInvalidSealResult
+	}
+	if _, ok := s.shares[sealhash][nonce]; ok {
+		s.ethash.config.Log.Debug("Duplicate share submitted", "sealhash", sealhash, "miner", miner, "nonce", nonce)
+		return errDuplicateShare
+	}
+	header := block.Header()
+	header.Nonce = nonce
+	header.MixDigest = mixDigest
+
+	// Shares are always verified, regardless of the noverify setting, otherwise
+	// anyone could claim arbitrary portions of the pool rewards.
+	start := time.Now()
+	digest, result := s.ethash.powResult(header)
+	if !bytes.Equal(header.MixDigest[:], digest) {
+		s.ethash.config.Log.Debug("Invalid share mix digest", "sealhash", sealhash, "miner", miner)
+		return errInvalidSealResult
+	}
+	value := new(big.Int).SetBytes(result)
+	if value.Cmp(new(big.Int).Div(two256, shareDiff)) > 0 {
+		return errLowShare
+	}
+	if err := recorder.RecordShare(miner, block.NumberU64(), shareDiff); err != nil {
+		s.ethash.config.Log.Warn("Failed to record share", "sealhash", sealhash, "miner", miner, "err", err)
+		return err
+	}
+	if s.shares[sealhash] == nil {
+		s.shares[sealhash] = make(map[types.BlockNonce]struct{})
+	}
+	s.shares[sealhash][nonce] = struct{}{}
+	s.ethash.config.Log.Trace("Accepted share", "sealhash", sealhash, "miner", miner, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	// If the share is also a valid block, hand it over to the miner
+	if value.Cmp(new(big.Int).Div(two256, block.Difficulty())) <= 0 {
+		s.submitWork(nonce, mixDigest, sealhash)
+	}
+	return nil
+}
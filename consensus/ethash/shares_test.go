@@ -0,0 +1,105 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethash
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+)
+
+type testShare struct {
+	miner      common.Address
+	number     uint64
+	difficulty *big.Int
+}
+
+type testShareRecorder struct {
+	shares []testShare
+	lock   sync.Mutex
+}
+
+func (r *testShareRecorder) RecordShare(miner common.Address, number uint64, difficulty *big.Int) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.shares = append(r.shares, testShare{miner, number, difficulty})
+	return nil
+}
+
+// Tests that shares are verified against the share difficulty and accounted to
+// the submitting miner.
+func TestRemoteShareSubmission(t *testing.T) {
+	ethash := NewTester(nil, false)
+	ethash.SetThreads(-1)
+	defer ethash.Close()
+
+	submit := func(miner common.Address, nonce types.BlockNonce, digest, sealhash common.Hash) error {
+		errc := make(chan error, 1)
+		ethash.remote.submitWorkCh <- &mineResult{nonce: nonce, mixDigest: digest, hash: sealhash, miner: &miner, errc: errc}
+		return <-errc
+	}
+	// Shares must be rejected until share accounting is enabled
+	header := &types.Header{Number: big.NewInt(1), Difficulty: new(big.Int).Rsh(two256, 1)}
+	ethash.Seal(nil, types.NewBlockWithHeader(header), make(chan *types.Block, 1), nil)
+
+	miner := common.HexToAddress("0x1234")
+	if err := submit(miner, types.BlockNonce{}, common.Hash{}, ethash.SealHash(header)); err != errNoShareRecorder {
+		t.Fatalf("share accepted without recorder: %v", err)
+	}
+	recorder := new(testShareRecorder)
+	ethash.SetShareRecorder(recorder, big.NewInt(1))
+
+	// Compute the real mix digest for the nonce and submit a valid share
+	header.Nonce = types.EncodeNonce(42)
+	digest, _ := ethash.powResult(header)
+	if err := submit(miner, header.Nonce, common.BytesToHash(digest), ethash.SealHash(header)); err != nil {
+		t.Fatalf("failed to submit valid share: %v", err)
+	}
+	// Resubmitting the same nonce for the same work must not be accounted again
+	if err := submit(common.HexToAddress("0x5678"), header.Nonce, common.BytesToHash(digest), ethash.SealHash(header)); err != errDuplicateShare {
+		t.Errorf("duplicate share error mismatch: have %v, want %v", err, errDuplicateShare)
+	}
+	// Invalid digests and unknown work must be rejected
+	other := *header
+	other.Nonce = types.EncodeNonce(43)
+	otherDigest, _ := ethash.powResult(&other)
+
+	if err := submit(miner, other.Nonce, common.Hash{0x01}, ethash.SealHash(header)); err != errInvalidSealResult {
+		t.Errorf("invalid digest error mismatch: have %v, want %v", err, errInvalidSealResult)
+	}
+	if err := submit(miner, header.Nonce, common.BytesToHash(digest), common.Hash{0x02}); err != errInvalidSealResult {
+		t.Errorf("unknown work error mismatch: have %v, want %v", err, errInvalidSealResult)
+	}
+	// Raise the share difficulty to the block's and ensure the share is too low
+	ethash.SetShareRecorder(recorder, header.Difficulty)
+	if err := submit(miner, other.Nonce, common.BytesToHash(otherDigest), ethash.SealHash(header)); err != errLowShare {
+		t.Errorf("low share error mismatch: have %v, want %v", err, errLowShare)
+	}
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+
+	if len(recorder.shares) != 1 {
+		t.Fatalf("recorded share count mismatch: have %d, want 1", len(recorder.shares))
+	}
+	if share := recorder.shares[0]; share.miner != miner || share.number != 1 || share.difficulty.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("recorded share mismatch: have %+v", share)
+	}
+}
@@ -59,6 +59,11 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	if err := v.engine.VerifyUncles(v.bc, block); err != nil {
 		return err
 	}
+	if verifier, ok := v.engine.(consensus.RewardVerifier); ok {
+		if err := verifier.VerifyRewards(v.bc, block); err != nil {
+			return err
+		}
+	}
 	if hash := types.CalcUncleHash(block.Uncles()); hash != header.UncleHash {
 		return fmt.Errorf("uncle root hash mismatch: have %x, want %x", hash, header.UncleHash)
 	}
@@ -131,6 +131,13 @@ type CacheConfig struct {
 	TrieTimeLimit       time.Duration // Time limit after which to flush the current in-memory trie to disk
 	SnapshotLimit       int           // Memory allowance (MB) to use for caching snapshot entries in memory
 	Preimages           bool          // Whether to store preimage of trie key to the disk
+	StateScheme         string        // Scheme used to store the state trie nodes (hash or path)
+	HistoryRetain       uint64        // Number of recent blocks to keep bodies and receipts for (0 = all)
+	StateDiffs          bool          // Whether to store the state changes made by every block
+	StateDiffRetain     uint64        // Number of recent blocks to keep state diffs for (0 = all)
+	StateHistory        uint64        // Number of recent blocks whose state can be rebuilt from the state diffs (0 = disabled)
+	Secondary           bool          // Whether to only follow the chain written to the database by another node
+	TxWorkers           int           // Number of goroutines executing transactions speculatively during import (0 = sequential)
 
 	SnapshotWait bool // Wait for snapshot construction on startup. TODO(karalabe): This is a dirty hack for testing, nuke it
 }
@@ -182,6 +189,7 @@ type BlockChain struct {
 	chainHeadFeed event.Feed
 	logsFeed      event.Feed
 	blockProcFeed event.Feed
+	stateDiffFeed event.Feed
 	scope         event.SubscriptionScope
 	genesisBlock  *types.Block
 
@@ -238,6 +246,7 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 			Cache:     cacheConfig.TrieCleanLimit,
 			Journal:   cacheConfig.TrieCleanJournal,
 			Preimages: cacheConfig.Preimages,
+			Scheme:    cacheConfig.StateScheme,
 		}),
 		quit:          make(chan struct{}),
 		chainmu:       syncx.NewClosableMutex(),
@@ -271,6 +280,17 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	bc.currentFinalizedBlock.Store(nilBlock)
 	bc.currentSafeBlock.Store(nilBlock)
 
+	// A secondary chain doesn't write to the database, it only follows the head
+	// written by the primary node.
+	if cacheConfig.Secondary {
+		if err := bc.loadSecondaryState(); err != nil {
+			return nil, err
+		}
+		bc.wg.Add(1)
+		go bc.followPrimary()
+		return bc, nil
+	}
+
 	// Initialize the chain with ancient data if it isn't empty.
 	var txIndexBlock uint64
 
@@ -392,10 +412,22 @@ func NewBlockChain(db ethdb.Database, cacheConfig *CacheConfig, chainConfig *par
 	if txLookupLimit != nil {
 		bc.txLookupLimit = *txLookupLimit
 
+		// Transactions can't be indexed beyond the retained history
+		if retain := bc.cacheConfig.HistoryRetain; retain != 0 && (bc.txLookupLimit == 0 || bc.txLookupLimit > retain) {
+			log.Info("Limiting transaction index to retained history", "txlookuplimit", retain)
+			bc.txLookupLimit = retain
+		}
+
 		bc.wg.Add(1)
 		go bc.maintainTxIndex(txIndexBlock)
 	}
 
+	// Start history pruner.
+	if bc.cacheConfig.HistoryRetain > 0 {
+		bc.wg.Add(1)
+		go bc.maintainHistory()
+	}
+
 	// If periodic cache journal is required, spin it up.
 	if bc.cacheConfig.TrieCleanRejournal > 0 {
 		if bc.cacheConfig.TrieCleanRejournal < time.Minute {
@@ -504,6 +536,9 @@ func (bc *BlockChain) loadLastState() error {
 // was fast synced or full synced and in which state, the method will try to
 // delete minimal data from disk whilst retaining chain consistency.
 func (bc *BlockChain) SetHead(head uint64) error {
+	if bc.cacheConfig.Secondary {
+		return ErrSecondaryChain
+	}
 	_, err := bc.setHeadBeyondRoot(head, common.Hash{}, false)
 	return err
 }
@@ -572,7 +607,16 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, root common.Hash, repair bo
 					if root != (common.Hash{}) && !beyondRoot && newHeadBlock.Root() == root {
 						beyondRoot, rootNumber = true, newHeadBlock.NumberU64()
 					}
-					if _, err := state.New(newHeadBlock.Root(), bc.stateCache, bc.snaps); err != nil {
+					_, err := state.New(newHeadBlock.Root(), bc.stateCache, bc.snaps)
+					if err != nil && bc.stateCache.TrieDB().Recoverable(newHeadBlock.Root()) {
+						// The path scheme can rewind the persisted state with the reverse diffs
+						if err = bc.stateCache.TrieDB().Recover(newHeadBlock.Root()); err != nil {
+							log.Error("Failed to recover block state", "number", newHeadBlock.NumberU64(), "hash", newHeadBlock.Hash(), "err", err)
+						} else {
+							log.Debug("Recovered block state", "number", newHeadBlock.NumberU64(), "hash", newHeadBlock.Hash())
+						}
+					}
+					if err != nil {
 						log.Trace("Block state missing, rewinding further", "number", newHeadBlock.NumberU64(), "hash", newHeadBlock.Hash())
 						if pivot == nil || newHeadBlock.NumberU64() > *pivot {
 							parent := bc.GetBlock(newHeadBlock.ParentHash(), newHeadBlock.NumberU64()-1)
@@ -853,6 +897,12 @@ func (bc *BlockChain) Stop() {
 	bc.chainmu.Close()
 	bc.wg.Wait()
 
+	// A secondary chain has nothing to persist.
+	if bc.cacheConfig.Secondary {
+		log.Info("Blockchain stopped")
+		return
+	}
+
 	// Ensure that the entirety of the state snapshot is journalled to disk.
 	var snapBase common.Hash
 	if bc.snaps != nil {
@@ -867,7 +917,17 @@ func (bc *BlockChain) Stop() {
 	//  - HEAD:     So we don't need to reprocess any blocks in the general case
 	//  - HEAD-1:   So we don't do large reorgs if our HEAD becomes an uncle
 	//  - HEAD-127: So we have a hard limit on the number of blocks reexecuted
-	if !bc.cacheConfig.TrieDirtyDisabled {
+	//
+	// The path scheme only persists a single state, the older ones are rewound
+	// with the reverse diffs if needed, so only the head state is written.
+	if triedb := bc.stateCache.TrieDB(); triedb.Scheme() == rawdb.PathScheme {
+		recent := bc.CurrentBlock()
+
+		log.Info("Writing cached state to disk", "block", recent.Number(), "hash", recent.Hash(), "root", recent.Root())
+		if err := triedb.Commit(recent.Root(), true, nil); err != nil {
+			log.Error("Failed to commit recent state trie", "err", err)
+		}
+	} else if !bc.cacheConfig.TrieDirtyDisabled {
 		triedb := bc.stateCache.TrieDB()
 
 		for _, offset := range []uint64{0, 1, TriesInMemory - 1} {
@@ -948,6 +1008,9 @@ const (
 // InsertReceiptChain attempts to complete an already existing header chain with
 // transaction and receipt data.
 func (bc *BlockChain) InsertReceiptChain(blockChain types.Blocks, receiptChain []types.Receipts, ancientLimit uint64) (int, error) {
+	if bc.cacheConfig.Secondary {
+		return 0, ErrSecondaryChain
+	}
 	// We don't require the chainMu here since we want to maximize the
 	// concurrency of header insertion and receipt insertion.
 	bc.wg.Add(1)
@@ -1257,25 +1320,43 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	// Make sure no inconsistent state is leaked during insertion
 	externTd := new(big.Int).Add(block.Difficulty(), ptd)
 
+	// Commit all cached state changes into underlying memory database.
+	root, err := state.Commit(bc.chainConfig.IsEIP158(block.Number()))
+	if err != nil {
+		return err
+	}
 	// Irrelevant of the canonical status, write the block itself to the database.
 	//
-	// Note all the components of block(td, hash->number map, header, body, receipts)
-	// should be written atomically. BlockBatch is used for containing all components.
+	// Note all the components of block(td, hash->number map, header, body, receipts,
+	// state diff) should be written atomically. BlockBatch is used for containing
+	// all components.
 	blockBatch := bc.db.NewBatch()
 	rawdb.WriteTd(blockBatch, block.Hash(), block.NumberU64(), externTd)
 	rawdb.WriteBlock(blockBatch, block)
 	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
 	rawdb.WritePreimages(blockBatch, state.Preimages())
+	if diff := state.StateDiff(); bc.cacheConfig.StateDiffs && diff != nil {
+		rawdb.WriteStateDiff(blockBatch, block.Hash(), block.NumberU64(), diff)
+
+		// Drop the diffs of all the blocks falling out of the retained window
+		if retain := bc.cacheConfig.StateDiffRetain; retain > 0 && block.NumberU64() > retain {
+			rawdb.DeleteStateDiffs(bc.db, blockBatch, 0, block.NumberU64()-retain+1)
+		}
+	}
 	if err := blockBatch.Write(); err != nil {
 		log.Crit("Failed to write block into disk", "err", err)
 	}
-	// Commit all cached state changes into underlying memory database.
-	root, err := state.Commit(bc.chainConfig.IsEIP158(block.Number()))
-	if err != nil {
-		return err
-	}
 	triedb := bc.stateCache.TrieDB()
 
+	// The path scheme keeps the recent states in memory and flushes the older
+	// ones by itself, only its memory allowance needs to be enforced.
+	if triedb.Scheme() == rawdb.PathScheme {
+		limit := common.StorageSize(bc.cacheConfig.TrieDirtyLimit) * 1024 * 1024
+		if nodes, imgs := triedb.Size(); nodes > limit || imgs > 4*1024*1024 {
+			return triedb.Cap(limit - ethdb.IdealBatchSize)
+		}
+		return nil
+	}
 	// If we're running an archive node, always flush
 	if bc.cacheConfig.TrieDirtyDisabled {
 		return triedb.Commit(root, false, nil)
@@ -1373,6 +1454,10 @@ func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types
 		if len(logs) > 0 {
 			bc.logsFeed.Send(logs)
 		}
+		if diff := state.StateDiff(); diff != nil {
+			bc.stateDiffFeed.Send(StateDiffEvent{Block: block, Diff: diff})
+		}
+
 		// In theory we should fire a ChainHeadEvent when we inject
 		// a canonical block, but sometimes we can insert a batch of
 		// canonical blocks. Avoid firing too many ChainHeadEvents,
@@ -1415,6 +1500,9 @@ func (bc *BlockChain) InsertChain(chain types.Blocks) (int, error) {
 	if len(chain) == 0 {
 		return 0, nil
 	}
+	if bc.cacheConfig.Secondary {
+		return 0, ErrSecondaryChain
+	}
 	bc.blockProcFeed.Send(true)
 	defer bc.blockProcFeed.Send(false)
 
@@ -1643,6 +1731,9 @@ func (bc *BlockChain) insertChain(chain types.Blocks, verifySeals, setHead bool)
 		if err != nil {
 			return it.index, err
 		}
+		if bc.cacheConfig.StateDiffs {
+			statedb.TrackStateDiff()
+		}
 
 		// Enable prefetching to pull in trie node paths while processing transactions
 		statedb.StartPrefetcher("chain")
@@ -2366,6 +2457,49 @@ func (bc *BlockChain) maintainTxIndex(ancients uint64) {
 	}
 }
 
+// maintainHistory is responsible for expiring the bodies and receipts of
+// blocks falling out of the retained history window.
+//
+// User can use flag `history.retain` to specify the number of recent blocks
+// to keep the history of. Only ancient blocks get pruned, so this is checked
+// whenever the chain progresses.
+func (bc *BlockChain) maintainHistory() {
+	defer bc.wg.Done()
+
+	var (
+		done   chan struct{}                  // Non-nil if background pruning routine is active.
+		headCh = make(chan ChainHeadEvent, 1) // Buffered to avoid locking up the event feed
+	)
+	sub := bc.SubscribeChainHeadEvent(headCh)
+	if sub == nil {
+		return
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-headCh:
+			if done == nil {
+				done = make(chan struct{})
+				go func() {
+					defer close(done)
+					if _, err := rawdb.PruneHistory(bc.db, bc.cacheConfig.HistoryRetain, bc.quit); err != nil {
+						log.Error("Failed to prune block history", "err", err)
+					}
+				}()
+			}
+		case <-done:
+			done = nil
+		case <-bc.quit:
+			if done != nil {
+				log.Info("Waiting background history pruner to exit")
+				<-done
+			}
+			return
+		}
+	}
+}
+
 // reportBlock logs a bad block error.
 func (bc *BlockChain) reportBlock(block *types.Block, receipts types.Receipts, err error) {
 	rawdb.WriteBadBlock(bc.db, block)
@@ -2401,6 +2535,9 @@ func (bc *BlockChain) InsertHeaderChain(chain []*types.Header, checkFreq int) (i
 	if len(chain) == 0 {
 		return 0, nil
 	}
+	if bc.cacheConfig.Secondary {
+		return 0, ErrSecondaryChain
+	}
 	start := time.Now()
 	if i, err := bc.hc.ValidateHeaderChain(chain, checkFreq); err != nil {
 		return i, err
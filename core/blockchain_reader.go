@@ -17,6 +17,8 @@
 package core
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/Altcoinchain/go-altcoinchain/common"
@@ -225,6 +227,12 @@ func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
 	return receipts
 }
 
+// GetStateDiff retrieves the state changes made by a block, nil if they were
+// not recorded.
+func (bc *BlockChain) GetStateDiff(hash common.Hash, number uint64) *types.StateDiff {
+	return rawdb.ReadStateDiff(bc.db, hash, number)
+}
+
 // GetUnclesInChain retrieves all the uncles from a given block backwards until
 // a specific distance is reached.
 func (bc *BlockChain) GetUnclesInChain(block *types.Block, length int) []*types.Header {
@@ -323,6 +331,48 @@ func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
 	return state.New(root, bc.stateCache, bc.snaps)
 }
 
+// HistoricStateAt returns the state of a canonical block, rebuilding it from a
+// newer state by reverting the state diffs of the blocks in between if it's not
+// available anymore. States can only be rebuilt within the configured history
+// window from the chain head.
+func (bc *BlockChain) HistoricStateAt(header *types.Header) (*state.StateDB, error) {
+	if statedb, err := bc.StateAt(header.Root); err == nil {
+		return statedb, nil
+	}
+	window := bc.cacheConfig.StateHistory
+	if window == 0 {
+		return nil, errors.New("state history disabled")
+	}
+	number, head := header.Number.Uint64(), bc.CurrentBlock().NumberU64()
+	if number+window < head {
+		return nil, fmt.Errorf("block %d is older than the state history of %d blocks", number, window)
+	}
+	if bc.GetCanonicalHash(number) != header.Hash() {
+		return nil, fmt.Errorf("state of non-canonical block %d not available", number)
+	}
+	// Gather the diffs of the following blocks up to the first available state
+	var diffs []*types.StateDiff
+	for n := number + 1; n <= head; n++ {
+		next := bc.GetHeaderByNumber(n)
+		if next == nil {
+			return nil, fmt.Errorf("missing header %d", n)
+		}
+		diff := bc.GetStateDiff(next.Hash(), n)
+		if diff == nil {
+			return nil, fmt.Errorf("state diff of block %d not available", n)
+		}
+		diffs = append(diffs, diff)
+
+		if bc.HasState(next.Root) {
+			for i, j := 0, len(diffs)-1; i < j; i, j = i+1, j-1 {
+				diffs[i], diffs[j] = diffs[j], diffs[i]
+			}
+			return state.NewHistoricState(header.Root, bc.stateCache, next.Root, diffs)
+		}
+	}
+	return nil, fmt.Errorf("no state available after block %d", number)
+}
+
 // Config retrieves the chain's fork configuration.
 func (bc *BlockChain) Config() *params.ChainConfig { return bc.chainConfig }
 
@@ -376,6 +426,12 @@ func (bc *BlockChain) TxLookupLimit() uint64 {
 	return bc.txLookupLimit
 }
 
+// StateDiffsEnabled reports whether the state changes made by every block are
+// recorded.
+func (bc *BlockChain) StateDiffsEnabled() bool {
+	return bc.cacheConfig.StateDiffs
+}
+
 // SubscribeRemovedLogsEvent registers a subscription of RemovedLogsEvent.
 func (bc *BlockChain) SubscribeRemovedLogsEvent(ch chan<- RemovedLogsEvent) event.Subscription {
 	return bc.scope.Track(bc.rmLogsFeed.Subscribe(ch))
@@ -401,6 +457,11 @@ func (bc *BlockChain) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscript
 	return bc.scope.Track(bc.logsFeed.Subscribe(ch))
 }
 
+// SubscribeStateDiffEvent registers a subscription of StateDiffEvent.
+func (bc *BlockChain) SubscribeStateDiffEvent(ch chan<- StateDiffEvent) event.Subscription {
+	return bc.scope.Track(bc.stateDiffFeed.Subscribe(ch))
+}
+
 // SubscribeBlockProcessingEvent registers a subscription of bool where true means
 // block processing has started while false means it has stopped.
 func (bc *BlockChain) SubscribeBlockProcessingEvent(ch chan<- bool) event.Subscription {
@@ -0,0 +1,156 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/log"
+)
+
+const (
+	// secondaryRefreshInterval is the interval at which a secondary chain checks
+	// for updates of the primary.
+	secondaryRefreshInterval = time.Second
+
+	// maxFollowedBlocks is the maximum number of blocks a secondary chain emits
+	// events for when catching up with the primary. Beyond it, only the head
+	// event is sent.
+	maxFollowedBlocks = 128
+)
+
+// loadSecondaryState loads the chain head written by the primary. Unlike
+// loadLastState on its own, a missing head is an error instead of resetting
+// the chain.
+func (bc *BlockChain) loadSecondaryState() error {
+	head := rawdb.ReadHeadBlockHash(bc.db)
+	if head == (common.Hash{}) || bc.GetBlockByHash(head) == nil {
+		return fmt.Errorf("head block %x missing from secondary database", head)
+	}
+	return bc.loadLastState()
+}
+
+// followPrimary periodically refreshes the database of a secondary chain, and
+// moves the chain head to the one written by the primary.
+func (bc *BlockChain) followPrimary() {
+	defer bc.wg.Done()
+
+	ticker := time.NewTicker(secondaryRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := rawdb.Refresh(bc.db); err != nil {
+				log.Warn("Failed to refresh secondary database", "err", err)
+				continue
+			}
+			bc.followHead()
+
+		case <-bc.quit:
+			return
+		}
+	}
+}
+
+// followHead loads the head markers written by the primary, emitting the events
+// of the blocks which became canonical or were reorged out since the last call.
+func (bc *BlockChain) followHead() {
+	current := bc.CurrentBlock()
+	if hash := rawdb.ReadHeadBlockHash(bc.db); hash != current.Hash() {
+		head := bc.GetBlockByHash(hash)
+		if head == nil {
+			log.Warn("Head block of primary missing", "hash", hash)
+			return
+		}
+		// Find the blocks on either side of the common ancestor
+		var (
+			oldChain, newChain []*types.Block
+			oldBlock, newBlock = current, head
+			truncated          bool
+		)
+		for oldBlock != nil && newBlock != nil && oldBlock.Hash() != newBlock.Hash() {
+			if len(oldChain)+len(newChain) >= maxFollowedBlocks {
+				truncated = true
+				break
+			}
+			if newBlock.NumberU64() >= oldBlock.NumberU64() {
+				newChain = append(newChain, newBlock)
+				newBlock = bc.GetBlock(newBlock.ParentHash(), newBlock.NumberU64()-1)
+			} else {
+				oldChain = append(oldChain, oldBlock)
+				oldBlock = bc.GetBlock(oldBlock.ParentHash(), oldBlock.NumberU64()-1)
+			}
+		}
+		if len(oldChain) > 0 || truncated {
+			bc.txLookupCache.Purge()
+		}
+		bc.currentBlock.Store(head)
+		headBlockGauge.Update(int64(head.NumberU64()))
+
+		if !truncated {
+			var deletedLogs [][]*types.Log
+			for _, block := range oldChain {
+				if logs := bc.collectLogs(block.Hash(), true); len(logs) > 0 {
+					deletedLogs = append(deletedLogs, logs)
+				}
+			}
+			if len(deletedLogs) > 0 {
+				bc.rmLogsFeed.Send(RemovedLogsEvent{mergeLogs(deletedLogs, true)})
+			}
+			for i := len(oldChain) - 1; i >= 0; i-- {
+				bc.chainSideFeed.Send(ChainSideEvent{Block: oldChain[i]})
+			}
+			for i := len(newChain) - 1; i >= 0; i-- {
+				block := newChain[i]
+				logs := bc.collectLogs(block.Hash(), false)
+				bc.chainFeed.Send(ChainEvent{Block: block, Hash: block.Hash(), Logs: logs})
+				if len(logs) > 0 {
+					bc.logsFeed.Send(logs)
+				}
+			}
+		}
+		bc.chainHeadFeed.Send(ChainHeadEvent{Block: head})
+		log.Debug("Followed primary chain head", "number", head.Number(), "hash", head.Hash(), "reorged", len(oldChain))
+	}
+	// Update the remaining markers, which may move without the head block
+	if hash := rawdb.ReadHeadHeaderHash(bc.db); hash != bc.CurrentHeader().Hash() {
+		if header := bc.GetHeaderByHash(hash); header != nil {
+			bc.hc.SetCurrentHeader(header)
+		}
+	}
+	if hash := rawdb.ReadHeadFastBlockHash(bc.db); hash != bc.CurrentFastBlock().Hash() {
+		if block := bc.GetBlockByHash(hash); block != nil {
+			bc.currentFastBlock.Store(block)
+			headFastBlockGauge.Update(int64(block.NumberU64()))
+		}
+	}
+	if hash := rawdb.ReadFinalizedBlockHash(bc.db); hash != (common.Hash{}) {
+		if finalized := bc.CurrentFinalizedBlock(); finalized == nil || finalized.Hash() != hash {
+			if block := bc.GetBlockByHash(hash); block != nil {
+				bc.currentFinalizedBlock.Store(block)
+				headFinalizedBlockGauge.Update(int64(block.NumberU64()))
+				bc.currentSafeBlock.Store(block)
+				headSafeBlockGauge.Update(int64(block.NumberU64()))
+			}
+		}
+	}
+}
@@ -32,6 +32,10 @@ var (
 	// ErrNoGenesis is returned when there is no Genesis Block.
 	ErrNoGenesis = errors.New("genesis not found in chain")
 
+	// ErrSecondaryChain is returned when modifying a chain which follows the
+	// database of another node.
+	ErrSecondaryChain = errors.New("read-only secondary chain")
+
 	errSideChainReceipts = errors.New("side blocks can't be accepted as ancient chain data")
 )
 
@@ -41,3 +41,10 @@ type ChainSideEvent struct {
 }
 
 type ChainHeadEvent struct{ Block *types.Block }
+
+// StateDiffEvent is posted when a block is inserted as the new head, with the
+// state changes it made.
+type StateDiffEvent struct {
+	Block *types.Block
+	Diff  *types.StateDiff
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Altcoinchain/go-altcoinchain/core/state"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/core/vm"
+	"github.com/Altcoinchain/go-altcoinchain/metrics"
+)
+
+var (
+	parallelAppliedMeter   = metrics.NewRegisteredMeter("chain/parallel/applied", nil)
+	parallelReexecuteMeter = metrics.NewRegisteredMeter("chain/parallel/reexecuted", nil)
+)
+
+// errUnprotectedTx is returned by the speculative execution of a transaction
+// which is not allowed on the chain and needs to be rejected sequentially.
+var errUnprotectedTx = errors.New("unprotected transaction")
+
+// speculation is the outcome of executing a transaction speculatively against
+// the state at the beginning of the block.
+type speculation struct {
+	state  *state.StateDB   // Copy of the pre-block state the transaction executed on
+	result *ExecutionResult // Result of the execution, nil if it failed
+	err    error            // Error encountered during execution
+}
+
+// applyParallel executes the transactions of a block using optimistic
+// concurrency control:
+//
+//  1. Every transaction is executed speculatively on its own copy of the
+//     pre-block state, tracking the accounts and storage slots it reads and
+//     the ones it changes.
+//  2. The speculative results are committed in transaction order. If any of
+//     the reads of a transaction was changed by an earlier one in the block,
+//     or its speculative execution failed, the transaction is re-executed on
+//     the real state instead.
+//
+// The produced receipts and state are identical to executing the transactions
+// sequentially, as every committed transaction observed exactly the state it
+// would have seen in order.
+func (p *StateProcessor) applyParallel(block *types.Block, statedb *state.StateDB, cfg vm.Config, vmenv *vm.EVM, gp *GasPool, usedGas *uint64) (types.Receipts, error) {
+	var (
+		txs         = block.Transactions()
+		header      = block.Header()
+		blockHash   = block.Hash()
+		blockNumber = block.Number()
+		signer      = types.MakeSigner(p.config, header.Number)
+
+		msgs    = make([]types.Message, len(txs))
+		errs    = make([]error, len(txs))
+		specs   = make([]*speculation, len(txs))
+		pending = make(chan int, len(txs))
+	)
+	for i, tx := range txs {
+		if msgs[i], errs[i] = tx.AsMessage(signer, header.BaseFee); errs[i] != nil {
+			continue
+		}
+		// Copies are made upfront, the pre-block state must not be accessed
+		// concurrently with the workers
+		specs[i] = &speculation{state: statedb.Copy()}
+		pending <- i
+	}
+	close(pending)
+
+	// Execute all the transactions speculatively
+	var wg sync.WaitGroup
+	for n := 0; n < p.workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// The block context caches hashes, so every worker needs its own
+			blockContext := NewEVMBlockContext(header, p.bc, nil)
+			for i := range pending {
+				p.speculate(specs[i], msgs[i], txs[i], i, blockContext, cfg)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Commit the speculative results in order, re-executing the invalid ones
+	var (
+		receipts = make(types.Receipts, 0, len(txs))
+		written  = state.NewAccessSet()
+	)
+	for i, tx := range txs {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), errs[i])
+		}
+		statedb.Prepare(tx.Hash(), i)
+
+		spec := specs[i]
+		if spec.err == nil && gp.Gas() >= msgs[i].Gas() && !spec.state.TrackedReads().Overlaps(written) {
+			// Mirror the gas pool accounting of the state transition
+			gp.SubGas(msgs[i].Gas())
+			gp.AddGas(msgs[i].Gas() - spec.result.UsedGas)
+
+			statedb.ApplyTracked(spec.state)
+			statedb.Finalise(true)
+			*usedGas += spec.result.UsedGas
+
+			receipts = append(receipts, newReceipt(tx, msgs[i], spec.result, statedb, nil, *usedGas, blockNumber, blockHash))
+			written.Merge(spec.state.TrackedWrites())
+			parallelAppliedMeter.Mark(1)
+			continue
+		}
+		statedb.TrackAccesses()
+		receipt, err := applyTransaction(msgs[i], p.config, nil, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv)
+		if err != nil {
+			statedb.StopTrackingAccesses()
+			return nil, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		receipts = append(receipts, receipt)
+		written.Merge(statedb.TrackedWrites())
+		statedb.StopTrackingAccesses()
+		parallelReexecuteMeter.Mark(1)
+	}
+	return receipts, nil
+}
+
+// speculate executes a transaction on the speculation's copy of the pre-block
+// state, tracking its accesses.
+func (p *StateProcessor) speculate(spec *speculation, msg types.Message, tx *types.Transaction, index int, blockContext vm.BlockContext, cfg vm.Config) {
+	// Leave rejecting unprotected transactions to the sequential path
+	if p.config.IsEthPoWFork(blockContext.BlockNumber) && !tx.Protected() {
+		spec.err = errUnprotectedTx
+		return
+	}
+	statedb := spec.state
+	statedb.TrackAccesses()
+	statedb.Prepare(tx.Hash(), index)
+
+	evm := vm.NewEVM(blockContext, NewEVMTxContext(msg), statedb, p.config, cfg)
+	result, err := ApplyMessage(evm, msg, new(GasPool).AddGas(blockContext.GasLimit))
+	if err != nil {
+		spec.err = err
+		return
+	}
+	statedb.Finalise(true)
+
+	// A database failure makes the tracked accesses unreliable
+	if err := statedb.Error(); err != nil {
+		spec.err = err
+		return
+	}
+	spec.result = result
+}
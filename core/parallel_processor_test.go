@@ -0,0 +1,198 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/core/vm"
+	"github.com/Altcoinchain/go-altcoinchain/crypto"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+)
+
+// Tests that executing the transactions of a chain in parallel produces the
+// exact same receipts, logs and state as executing them sequentially.
+func TestParallelProcessing(t *testing.T) {
+	var (
+		engine = ethash.NewFaker()
+		db     = rawdb.NewMemoryDatabase()
+		config = params.TestChainConfig
+		signer = types.LatestSigner(config)
+
+		keys  []*ecdsa.PrivateKey
+		funds = big.NewInt(params.Ether)
+		alloc = make(GenesisAlloc)
+
+		// counter increments the slot given in the calldata and logs
+		counter     = common.Address{0xcc}
+		counterCode = []byte{
+			byte(vm.PUSH1), 0x00, byte(vm.CALLDATALOAD), byte(vm.DUP1), byte(vm.SLOAD),
+			byte(vm.PUSH1), 0x01, byte(vm.ADD), byte(vm.SWAP1), byte(vm.SSTORE),
+			byte(vm.PUSH1), 0x00, byte(vm.DUP1), byte(vm.LOG0), byte(vm.STOP),
+		}
+		// reader stores the balance of the coinbase, depending on all the fees
+		reader     = common.Address{0xdd}
+		readerCode = []byte{
+			byte(vm.COINBASE), byte(vm.BALANCE), byte(vm.PUSH1), 0x00, byte(vm.SSTORE), byte(vm.STOP),
+		}
+		// doomed selfdestructs to the caller
+		doomed     = common.Address{0xee}
+		doomedCode = []byte{byte(vm.CALLER), byte(vm.SELFDESTRUCT)}
+
+		initCode = []byte{byte(vm.PUSH1), 0x01, byte(vm.PUSH1), 0x00, byte(vm.SSTORE), byte(vm.STOP)}
+	)
+	for i := 0; i < 8; i++ {
+		key, _ := crypto.ToECDSA(common.LeftPadBytes([]byte{byte(i + 1)}, 32))
+		keys = append(keys, key)
+		alloc[crypto.PubkeyToAddress(key.PublicKey)] = GenesisAccount{Balance: funds}
+	}
+	alloc[counter] = GenesisAccount{Code: counterCode, Balance: new(big.Int)}
+	alloc[reader] = GenesisAccount{Code: readerCode, Balance: new(big.Int)}
+	alloc[doomed] = GenesisAccount{Code: doomedCode, Balance: big.NewInt(1000), Storage: map[common.Hash]common.Hash{{0x01}: {0x01}}}
+
+	gspec := &Genesis{Config: config, Alloc: alloc}
+	genesis := gspec.MustCommit(db)
+
+	blocks, _ := GenerateChain(config, genesis, engine, db, 8, func(n int, b *BlockGen) {
+		b.SetCoinbase(common.Address{0xc0, byte(n % 2)})
+
+		nonces := make([]uint64, len(keys))
+		send := func(i int, to *common.Address, value int64, gas uint64, data []byte) {
+			from := crypto.PubkeyToAddress(keys[i].PublicKey)
+			if nonces[i] == 0 {
+				nonces[i] = b.TxNonce(from)
+			}
+			price := new(big.Int).Mul(b.header.BaseFee, big.NewInt(2))
+
+			var tx *types.Transaction
+			if to == nil {
+				tx = types.NewContractCreation(nonces[i], big.NewInt(value), gas, price, data)
+			} else {
+				tx = types.NewTransaction(nonces[i], *to, big.NewInt(value), gas, price, data)
+			}
+			tx, _ = types.SignTx(tx, signer, keys[i])
+			b.AddTx(tx)
+			nonces[i]++
+		}
+		// The first transactions of the senders are executed speculatively: some
+		// increment the same storage slots, read the fees of the coinbase or pay
+		// an account sending funds itself, others transfer to fresh accounts
+		for i := range keys {
+			switch {
+			case i < 4:
+				send(i, &counter, 0, 100000, common.LeftPadBytes([]byte{byte(i % 2)}, 32))
+			case i == 6:
+				sender := crypto.PubkeyToAddress(keys[5].PublicKey)
+				send(i, &sender, 1000, params.TxGas, nil)
+			case i == 7 && n%2 == 0:
+				send(i, &reader, 0, 100000, nil)
+			default:
+				send(i, &common.Address{0xf0, byte(n), byte(i)}, 1000, params.TxGas, nil)
+			}
+		}
+		// Follow-up transactions of the same senders
+		send(0, &common.Address{0xf1, byte(n)}, 1000, params.TxGas, nil)
+		send(1, &common.Address{0xf0, byte(n), 5}, 1000, params.TxGas, nil)
+		for i := range keys {
+			send(i, &counter, 0, 100000, common.LeftPadBytes([]byte{byte(i % 3)}, 32))
+		}
+		// An increment running out of gas
+		send(2, &counter, 0, 25000, common.LeftPadBytes([]byte{0x01}, 32))
+
+		switch n {
+		case 1:
+			send(3, nil, 0, 100000, initCode)
+		case 2:
+			send(4, &doomed, 0, 100000, nil)
+			send(5, &doomed, 10, params.TxGas, nil) // Resurrect the destructed account
+		case 3:
+			send(6, &common.Address{0xf0, byte(n - 1), 7}, 0, params.TxGas, nil) // Touch, but not empty
+		}
+	})
+	// Import the chain sequentially and execute every block in both modes
+	diskdb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(diskdb)
+	chain, err := NewBlockChain(diskdb, nil, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if n, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into chain: %v", n, err)
+	}
+	sequential := NewStateProcessor(config, chain, engine)
+	parallel := NewStateProcessor(config, chain, engine)
+	parallel.workers = 4
+
+	for _, block := range blocks {
+		parent := chain.GetHeaderByHash(block.ParentHash())
+
+		seqstate, _ := chain.StateAt(parent.Root)
+		seqReceipts, seqLogs, seqGas, err := sequential.Process(block, seqstate, vm.Config{})
+		if err != nil {
+			t.Fatalf("block %d: sequential processing failed: %v", block.NumberU64(), err)
+		}
+		parstate, _ := chain.StateAt(parent.Root)
+		parReceipts, parLogs, parGas, err := parallel.Process(block, parstate, vm.Config{})
+		if err != nil {
+			t.Fatalf("block %d: parallel processing failed: %v", block.NumberU64(), err)
+		}
+		if !reflect.DeepEqual(parReceipts, seqReceipts) {
+			t.Errorf("block %d: receipts mismatch", block.NumberU64())
+		}
+		if !reflect.DeepEqual(parLogs, seqLogs) {
+			t.Errorf("block %d: logs mismatch", block.NumberU64())
+		}
+		if parGas != seqGas {
+			t.Errorf("block %d: gas used mismatch: have %d, want %d", block.NumberU64(), parGas, seqGas)
+		}
+		if have, want := parstate.IntermediateRoot(true), seqstate.IntermediateRoot(true); have != want {
+			t.Errorf("block %d: state root mismatch: have %x, want %x", block.NumberU64(), have, want)
+		}
+		if have := parstate.IntermediateRoot(true); have != block.Root() {
+			t.Errorf("block %d: state root mismatch with block: have %x, want %x", block.NumberU64(), have, block.Root())
+		}
+	}
+	// Import the chain with parallel execution enabled too
+	paralleldb := rawdb.NewMemoryDatabase()
+	gspec.MustCommit(paralleldb)
+
+	cacheConfig := *defaultCacheConfig
+	cacheConfig.TxWorkers = 4
+	pchain, err := NewBlockChain(paralleldb, &cacheConfig, config, engine, vm.Config{}, nil, nil)
+	if err != nil {
+		t.Fatalf("failed to create tester chain: %v", err)
+	}
+	defer pchain.Stop()
+
+	if n, err := pchain.InsertChain(blocks); err != nil {
+		t.Fatalf("block %d: failed to insert into parallel chain: %v", n, err)
+	}
+	for _, block := range blocks {
+		if have, want := pchain.GetReceiptsByHash(block.Hash()), chain.GetReceiptsByHash(block.Hash()); !reflect.DeepEqual(have, want) {
+			t.Errorf("block %d: stored receipts mismatch", block.NumberU64())
+		}
+	}
+}
@@ -278,6 +278,16 @@ func WriteTxIndexTail(db ethdb.KeyValueWriter, number uint64) {
 	}
 }
 
+// ReadHistoryTail retrieves the number of the oldest block whose body and
+// receipts are still available. Anything below it has been pruned.
+func ReadHistoryTail(db ethdb.AncientReaderOp) uint64 {
+	tail, err := db.Tail()
+	if err != nil {
+		return 0
+	}
+	return tail
+}
+
 // ReadFastTxLookupLimit retrieves the tx lookup limit used in fast sync.
 func ReadFastTxLookupLimit(db ethdb.KeyValueReader) *uint64 {
 	data, _ := db.Get(fastTxLookupLimitKey)
@@ -445,7 +455,11 @@ func ReadBodyRLP(db ethdb.Reader, hash common.Hash, number uint64) rlp.RawValue
 		// Check if the data is in ancients
 		if isCanon(reader, number, hash) {
 			data, _ = reader.Ancient(chainFreezerBodiesTable, number)
-			return nil
+			if len(data) > 0 {
+				return nil
+			}
+			// The history might be pruned, but the genesis body is
+			// always kept in leveldb
 		}
 		// If not, try reading from leveldb
 		data, _ = db.Get(blockBodyKey(number, hash))
@@ -482,7 +496,9 @@ func WriteBodyRLP(db ethdb.KeyValueWriter, hash common.Hash, number uint64, rlp
 
 // HasBody verifies the existence of a block body corresponding to the hash.
 func HasBody(db ethdb.Reader, hash common.Hash, number uint64) bool {
-	if isCanon(db, number, hash) {
+	// Frozen data below the history tail was pruned, but the genesis block
+	// might still be in the key-value store
+	if isCanon(db, number, hash) && number >= ReadHistoryTail(db) {
 		return true
 	}
 	if has, err := db.Has(blockBodyKey(number, hash)); !has || err != nil {
@@ -572,7 +588,9 @@ func DeleteTd(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 // HasReceipts verifies the existence of all the transaction receipts belonging
 // to a block.
 func HasReceipts(db ethdb.Reader, hash common.Hash, number uint64) bool {
-	if isCanon(db, number, hash) {
+	// Frozen data below the history tail was pruned, but the genesis block
+	// might still be in the key-value store
+	if isCanon(db, number, hash) && number >= ReadHistoryTail(db) {
 		return true
 	}
 	if has, err := db.Has(blockReceiptsKey(number, hash)); !has || err != nil {
@@ -1002,3 +1020,55 @@ func ReadHeadBlock(db ethdb.Reader) *types.Block {
 	}
 	return ReadBlock(db, headBlockHash, *headBlockNumber)
 }
+
+// ReadStateDiffRLP retrieves the state diff of a block in RLP encoding.
+func ReadStateDiffRLP(db ethdb.KeyValueReader, hash common.Hash, number uint64) rlp.RawValue {
+	data, _ := db.Get(stateDiffKey(number, hash))
+	return data
+}
+
+// ReadStateDiff retrieves the state changes made by a block, nil if they were
+// not recorded.
+func ReadStateDiff(db ethdb.KeyValueReader, hash common.Hash, number uint64) *types.StateDiff {
+	data := ReadStateDiffRLP(db, hash, number)
+	if len(data) == 0 {
+		return nil
+	}
+	diff := new(types.StateDiff)
+	if err := rlp.DecodeBytes(data, diff); err != nil {
+		log.Error("Invalid state diff RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return diff
+}
+
+// WriteStateDiff stores the state changes made by a block.
+func WriteStateDiff(db ethdb.KeyValueWriter, hash common.Hash, number uint64, diff *types.StateDiff) {
+	data, err := rlp.EncodeToBytes(diff)
+	if err != nil {
+		log.Crit("Failed to encode state diff", "err", err)
+	}
+	if err := db.Put(stateDiffKey(number, hash), data); err != nil {
+		log.Crit("Failed to store state diff", "err", err)
+	}
+}
+
+// DeleteStateDiffs removes the state diffs of all blocks numbered in the range
+// [from, to) through the given batch.
+func DeleteStateDiffs(db ethdb.Iteratee, batch ethdb.KeyValueWriter, from, to uint64) {
+	it := db.NewIterator(stateDiffPrefix, encodeBlockNumber(from))
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(stateDiffPrefix)+8+common.HashLength {
+			continue
+		}
+		if binary.BigEndian.Uint64(key[len(stateDiffPrefix):]) >= to {
+			break
+		}
+		if err := batch.Delete(key); err != nil {
+			log.Crit("Failed to delete state diff", "err", err)
+		}
+	}
+}
@@ -281,6 +281,60 @@ func TestTdStorage(t *testing.T) {
 	}
 }
 
+// Tests block state diff storage, retrieval and pruning operations.
+func TestStateDiffStorage(t *testing.T) {
+	db := NewMemoryDatabase()
+
+	diff := &types.StateDiff{Accounts: []*types.AccountDiff{
+		{
+			Address: common.Address{0x01},
+			Post:    &types.StateAccount{Nonce: 1, Balance: big.NewInt(2), Root: common.Hash{0x03}, CodeHash: []byte{0x04}},
+			Storage: []*types.StorageDiff{{Key: common.Hash{0x05}, Post: common.Hash{0x06}}},
+		},
+		{
+			Address:        common.Address{0x02},
+			Pre:            &types.StateAccount{Nonce: 7, Balance: big.NewInt(8), Root: common.Hash{0x09}, CodeHash: []byte{0x0a}},
+			StorageCleared: true,
+		},
+	}}
+	if entry := ReadStateDiff(db, common.Hash{0x01}, 1); entry != nil {
+		t.Fatalf("Non existent state diff returned: %v", entry)
+	}
+	// Write and verify the diffs of two sibling blocks and of their child
+	WriteStateDiff(db, common.Hash{0x01}, 1, diff)
+	WriteStateDiff(db, common.Hash{0x02}, 1, diff)
+	WriteStateDiff(db, common.Hash{0x03}, 2, diff)
+	WriteStateDiff(db, common.Hash{0x04}, 3, diff)
+
+	want, _ := rlp.EncodeToBytes(diff)
+	if entry := ReadStateDiff(db, common.Hash{0x01}, 1); entry == nil {
+		t.Fatalf("Stored state diff not found")
+	} else if have, _ := rlp.EncodeToBytes(entry); !bytes.Equal(have, want) {
+		t.Fatalf("Retrieved state diff mismatch: have %x, want %x", have, want)
+	}
+	// Prune the first two blocks and verify only their diffs were removed,
+	// once the batch is written
+	batch := db.NewBatch()
+	DeleteStateDiffs(db, batch, 0, 3)
+	if entry := ReadStateDiff(db, common.Hash{0x01}, 1); entry == nil {
+		t.Fatalf("State diff deleted before the batch was written")
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("Failed to write batch: %v", err)
+	}
+	for number, hash := range map[uint64]common.Hash{1: {0x01}, 2: {0x03}} {
+		if entry := ReadStateDiff(db, hash, number); entry != nil {
+			t.Fatalf("Deleted state diff returned: %v", entry)
+		}
+	}
+	if entry := ReadStateDiff(db, common.Hash{0x02}, 1); entry != nil {
+		t.Fatalf("Deleted sibling state diff returned: %v", entry)
+	}
+	if entry := ReadStateDiff(db, common.Hash{0x04}, 3); entry == nil {
+		t.Fatalf("State diff past the pruned range deleted")
+	}
+}
+
 // Tests that canonical numbers can be mapped to hashes and retrieved.
 func TestCanonicalMappingStorage(t *testing.T) {
 	db := NewMemoryDatabase()
@@ -17,6 +17,7 @@
 package rawdb
 
 import (
+	"encoding/binary"
 	"encoding/json"
 	"time"
 
@@ -187,3 +188,29 @@ func WriteTransitionStatus(db ethdb.KeyValueWriter, data []byte) {
 		log.Crit("Failed to store the eth2 transition status", "err", err)
 	}
 }
+
+// ReadScrubProgress retrieves the number of the next block to be checked by an
+// interrupted database scrub.
+func ReadScrubProgress(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(scrubProgressKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteScrubProgress stores the number of the next block to be checked by the
+// database scrubber.
+func WriteScrubProgress(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(scrubProgressKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store the scrub progress", "err", err)
+	}
+}
+
+// DeleteScrubProgress removes the progress marker of a completed database scrub.
+func DeleteScrubProgress(db ethdb.KeyValueWriter) {
+	if err := db.Delete(scrubProgressKey); err != nil {
+		log.Crit("Failed to delete the scrub progress", "err", err)
+	}
+}
@@ -112,6 +112,26 @@ func DeleteCode(db ethdb.KeyValueWriter, hash common.Hash) {
 	}
 }
 
+// ReadStorageLayout retrieves the storage layout registered for a contract.
+func ReadStorageLayout(db ethdb.KeyValueReader, address common.Address) []byte {
+	data, _ := db.Get(storageLayoutKey(address))
+	return data
+}
+
+// WriteStorageLayout stores the storage layout of a contract.
+func WriteStorageLayout(db ethdb.KeyValueWriter, address common.Address, layout []byte) {
+	if err := db.Put(storageLayoutKey(address), layout); err != nil {
+		log.Crit("Failed to store storage layout", "err", err)
+	}
+}
+
+// DeleteStorageLayout removes the storage layout registered for a contract.
+func DeleteStorageLayout(db ethdb.KeyValueWriter, address common.Address) {
+	if err := db.Delete(storageLayoutKey(address)); err != nil {
+		log.Crit("Failed to delete storage layout", "err", err)
+	}
+}
+
 // DeleteTrieNode deletes the specified trie node from the database.
 func DeleteTrieNode(db ethdb.KeyValueWriter, hash common.Hash) {
 	if err := db.Delete(hash.Bytes()); err != nil {
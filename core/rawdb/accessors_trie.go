@@ -0,0 +1,193 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// The schemes the state trie nodes can be stored with.
+const (
+	// HashScheme stores the trie nodes keyed by their hash. Nodes are shared
+	// between states and only garbage collected in memory.
+	HashScheme = "hash"
+
+	// PathScheme stores the trie nodes keyed by their path in the trie. Only
+	// the latest version of every node is kept on disk, older states can be
+	// restored with reverse diffs.
+	PathScheme = "path"
+)
+
+// ReadStateScheme retrieves the scheme recorded for the state of the database,
+// an empty string if none was recorded.
+func ReadStateScheme(db ethdb.KeyValueReader) string {
+	data, _ := db.Get(stateSchemeKey)
+	return string(data)
+}
+
+// WriteStateScheme stores the scheme the state of the database is stored with.
+func WriteStateScheme(db ethdb.KeyValueWriter, scheme string) {
+	if err := db.Put(stateSchemeKey, []byte(scheme)); err != nil {
+		log.Crit("Failed to store the state scheme", "err", err)
+	}
+}
+
+// ParseStateScheme checks the requested state scheme against the one the
+// database is stored with, returning the scheme to use. The stored scheme is
+// used if none was requested, and the hash scheme for a fresh database.
+// Databases initialized before the scheme was recorded are in the hash scheme.
+func ParseStateScheme(provided string, db ethdb.Reader) (string, error) {
+	stored := ReadStateScheme(db)
+	if stored == "" && ReadCanonicalHash(db, 0) != (common.Hash{}) {
+		stored = HashScheme
+	}
+	if provided == "" {
+		if stored == "" {
+			return HashScheme, nil
+		}
+		return stored, nil
+	}
+	if provided != HashScheme && provided != PathScheme {
+		return "", fmt.Errorf("unknown state scheme %q", provided)
+	}
+	if stored != "" && stored != provided {
+		return "", fmt.Errorf("incompatible state scheme, stored: %s, provided: %s", stored, provided)
+	}
+	return provided, nil
+}
+
+// ReadAccountTrieNode retrieves the account trie node stored at the given path.
+func ReadAccountTrieNode(db ethdb.KeyValueReader, path []byte) []byte {
+	data, _ := db.Get(accountTrieNodeKey(path))
+	return data
+}
+
+// WriteAccountTrieNode writes the account trie node stored at the given path.
+func WriteAccountTrieNode(db ethdb.KeyValueWriter, path []byte, node []byte) {
+	if err := db.Put(accountTrieNodeKey(path), node); err != nil {
+		log.Crit("Failed to store account trie node", "err", err)
+	}
+}
+
+// DeleteAccountTrieNode deletes the account trie node stored at the given path.
+func DeleteAccountTrieNode(db ethdb.KeyValueWriter, path []byte) {
+	if err := db.Delete(accountTrieNodeKey(path)); err != nil {
+		log.Crit("Failed to delete account trie node", "err", err)
+	}
+}
+
+// ReadStorageTrieNode retrieves the storage trie node of an account stored at
+// the given path.
+func ReadStorageTrieNode(db ethdb.KeyValueReader, accountHash common.Hash, path []byte) []byte {
+	data, _ := db.Get(storageTrieNodeKey(accountHash, path))
+	return data
+}
+
+// WriteStorageTrieNode writes the storage trie node of an account stored at
+// the given path.
+func WriteStorageTrieNode(db ethdb.KeyValueWriter, accountHash common.Hash, path []byte, node []byte) {
+	if err := db.Put(storageTrieNodeKey(accountHash, path), node); err != nil {
+		log.Crit("Failed to store storage trie node", "err", err)
+	}
+}
+
+// DeleteStorageTrieNode deletes the storage trie node of an account stored at
+// the given path.
+func DeleteStorageTrieNode(db ethdb.KeyValueWriter, accountHash common.Hash, path []byte) {
+	if err := db.Delete(storageTrieNodeKey(accountHash, path)); err != nil {
+		log.Crit("Failed to delete storage trie node", "err", err)
+	}
+}
+
+// IsPathTrieNodeKey reports whether the key belongs to a trie node stored in the
+// path scheme.
+func IsPathTrieNodeKey(key []byte) bool {
+	switch {
+	case bytes.HasPrefix(key, trieNodeAccountPrefix):
+		// The hex path of an account trie node has at most 64 nibbles
+		return len(key) <= len(trieNodeAccountPrefix)+2*common.HashLength
+	case bytes.HasPrefix(key, trieNodeStoragePrefix):
+		return len(key) >= len(trieNodeStoragePrefix)+common.HashLength && len(key) <= len(trieNodeStoragePrefix)+3*common.HashLength
+	}
+	return false
+}
+
+// ReadReverseDiff retrieves the reverse diff with the given id.
+func ReadReverseDiff(db ethdb.KeyValueReader, id uint64) []byte {
+	data, _ := db.Get(reverseDiffKey(id))
+	return data
+}
+
+// WriteReverseDiff stores a reverse diff along with the lookup from the state
+// root it reverts to its id.
+func WriteReverseDiff(db ethdb.KeyValueWriter, id uint64, root common.Hash, blob []byte) {
+	if err := db.Put(reverseDiffKey(id), blob); err != nil {
+		log.Crit("Failed to store reverse diff", "err", err)
+	}
+	if err := db.Put(reverseDiffLookupKey(root), encodeBlockNumber(id)); err != nil {
+		log.Crit("Failed to store reverse diff lookup", "err", err)
+	}
+}
+
+// DeleteReverseDiff removes the reverse diff with the given id.
+func DeleteReverseDiff(db ethdb.KeyValueWriter, id uint64) {
+	if err := db.Delete(reverseDiffKey(id)); err != nil {
+		log.Crit("Failed to delete reverse diff", "err", err)
+	}
+}
+
+// DeleteReverseDiffLookup removes the lookup from a state root to the reverse
+// diff reverting to it.
+func DeleteReverseDiffLookup(db ethdb.KeyValueWriter, root common.Hash) {
+	if err := db.Delete(reverseDiffLookupKey(root)); err != nil {
+		log.Crit("Failed to delete reverse diff lookup", "err", err)
+	}
+}
+
+// ReadReverseDiffLookup retrieves the id of the reverse diff which reverts the
+// path-based state to the given state root.
+func ReadReverseDiffLookup(db ethdb.KeyValueReader, root common.Hash) *uint64 {
+	data, _ := db.Get(reverseDiffLookupKey(root))
+	if len(data) != 8 {
+		return nil
+	}
+	id := binary.BigEndian.Uint64(data)
+	return &id
+}
+
+// ReadReverseDiffHead retrieves the id of the latest reverse diff, zero if none
+// was stored yet.
+func ReadReverseDiffHead(db ethdb.KeyValueReader) uint64 {
+	data, _ := db.Get(reverseDiffHeadKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteReverseDiffHead stores the id of the latest reverse diff.
+func WriteReverseDiffHead(db ethdb.KeyValueWriter, id uint64) {
+	if err := db.Put(reverseDiffHeadKey, encodeBlockNumber(id)); err != nil {
+		log.Crit("Failed to store the reverse diff head", "err", err)
+	}
+}
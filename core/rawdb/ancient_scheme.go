@@ -46,6 +46,14 @@ var chainFreezerNoSnappy = map[string]bool{
 	chainFreezerDifficultyTable: true,
 }
 
+// chainFreezerPrunable lists the ancient-tables holding block history, which may
+// be expired below a horizon. Headers, hashes and difficulties are always kept
+// so that the chain remains verifiable.
+var chainFreezerPrunable = map[string]bool{
+	chainFreezerBodiesTable:  true,
+	chainFreezerReceiptTable: true,
+}
+
 // The list of identifiers of ancient stores.
 var (
 	chainFreezerName = "chain" // the folder name of chain segment ancient store.
@@ -0,0 +1,423 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// backupVersion is the version of the backup layout and manifest format.
+	backupVersion = 1
+
+	// backupManifestName is the name of the manifest file in a backup directory.
+	backupManifestName = "manifest.json"
+
+	// backupKeyValueDir is the directory of the key-value store in a backup. The
+	// chain freezer is located in its default place inside.
+	backupKeyValueDir = "chaindata"
+)
+
+// BackupManifest describes the content of a database backup.
+type BackupManifest struct {
+	Version     uint64            `json:"version"`
+	Time        time.Time         `json:"time"`
+	Engine      string            `json:"engine"`      // Engine of the key-value store
+	HeadHash    common.Hash       `json:"headHash"`    // Head block at the time of the snapshot
+	HeadNumber  uint64            `json:"headNumber"`  // Number of the head block
+	StateRoot   common.Hash       `json:"stateRoot"`   // Most recent persisted state, the chain is rewound to it on startup
+	StateNumber uint64            `json:"stateNumber"` // Number of the block of the persisted state
+	Entries     uint64            `json:"entries"`     // Number of key-value entries
+	Ancients    uint64            `json:"ancients"`    // Number of items in the chain freezer
+	Files       map[string]string `json:"files"`       // Sha256 checksums of the freezer files
+}
+
+// Backup writes a consistent copy of the database into dir, which must not
+// exist yet. The key-value store is copied from a snapshot into a new database
+// of the given engine, then the freezer files are copied while freezing is
+// paused. As data only leaves the key-value store after it's been frozen,
+// anything missing from the snapshot is guaranteed to be part of the copied
+// ancients.
+func Backup(db ethdb.Database, dir string, engine string) (*BackupManifest, error) {
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("backup directory %s already exists", dir)
+	}
+	if engine == "" {
+		engine = DBLeveldb
+	}
+	snap, err := db.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	defer snap.Release()
+
+	var (
+		start    = time.Now()
+		kvdir    = filepath.Join(dir, backupKeyValueDir)
+		manifest = &BackupManifest{
+			Version:  backupVersion,
+			Time:     start.UTC(),
+			Engine:   engine,
+			HeadHash: ReadHeadBlockHash(snap),
+			Files:    make(map[string]string),
+		}
+	)
+	number := ReadHeaderNumber(snap, manifest.HeadHash)
+	if number == nil {
+		return nil, errors.New("head block unavailable")
+	}
+	manifest.HeadNumber = *number
+
+	// The node usually holds the recent states in memory only, find the most
+	// recent one in the snapshot
+	if manifest.StateNumber, manifest.StateRoot, err = persistedState(db, snap, manifest.HeadHash, manifest.HeadNumber); err != nil {
+		return nil, err
+	}
+	// Copy the chain freezer, holding its lock to keep the files unchanged
+	if ancient, err := db.AncientDatadir(); err == nil {
+		var (
+			src = resolveChainFreezerDir(ancient)
+			dst = filepath.Join(kvdir, "ancient", chainFreezerName)
+		)
+		err := db.ReadAncients(func(reader ethdb.AncientReaderOp) error {
+			ancients, err := reader.Ancients()
+			if err != nil {
+				return err
+			}
+			manifest.Ancients = ancients
+			return copyFreezerFiles(src, dst, manifest.Files)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy ancients: %w", err)
+		}
+	}
+	// Copy the key-value store from the snapshot
+	kvdb, err := openKeyValueDatabase(OpenOptions{Type: engine, Directory: kvdir, Cache: 16, Handles: 16})
+	if err != nil {
+		return nil, err
+	}
+	var (
+		it     = snap.NewIterator(nil, nil)
+		batch  = kvdb.NewBatch()
+		logged = time.Now()
+	)
+	for it.Next() {
+		if err = batch.Put(it.Key(), it.Value()); err != nil {
+			break
+		}
+		manifest.Entries++
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err = batch.Write(); err != nil {
+				break
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Backing up key-value store", "entries", manifest.Entries, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	it.Release()
+	if err == nil {
+		err = it.Error()
+	}
+	if err == nil {
+		err = batch.Write()
+	}
+	if cerr := kvdb.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy key-value store: %w", err)
+	}
+	if err := writeBackupManifest(dir, manifest); err != nil {
+		return nil, err
+	}
+	log.Info("Backed up database", "dir", dir, "head", manifest.HeadNumber, "entries", manifest.Entries,
+		"ancients", manifest.Ancients, "elapsed", common.PrettyDuration(time.Since(start)))
+	return manifest, nil
+}
+
+// Restore validates the backup in dir and copies it into kvdir, the directory
+// of the key-value store, which must not exist yet. The ancients are restored
+// into their default location inside it.
+func Restore(dir string, kvdir string) (*BackupManifest, error) {
+	if _, err := os.Stat(kvdir); err == nil {
+		return nil, fmt.Errorf("database directory %s already exists", kvdir)
+	}
+	manifest, err := ReadBackupManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyBackup(dir, manifest); err != nil {
+		return nil, err
+	}
+	// Copy into a temporary location first so that a failure doesn't leave a
+	// partially restored database behind
+	tmp := kvdir + ".restore"
+	if err := os.RemoveAll(tmp); err != nil {
+		return nil, err
+	}
+	if err := copyTree(filepath.Join(dir, backupKeyValueDir), tmp); err != nil {
+		os.RemoveAll(tmp)
+		return nil, err
+	}
+	if err := os.Rename(tmp, kvdir); err != nil {
+		return nil, err
+	}
+	log.Info("Restored database", "dir", kvdir, "head", manifest.HeadNumber, "hash", manifest.HeadHash)
+	return manifest, nil
+}
+
+// ReadBackupManifest reads the manifest of the backup in dir.
+func ReadBackupManifest(dir string) (*BackupManifest, error) {
+	blob, err := os.ReadFile(filepath.Join(dir, backupManifestName))
+	if err != nil {
+		return nil, err
+	}
+	manifest := new(BackupManifest)
+	if err := json.Unmarshal(blob, manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	if manifest.Version != backupVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", manifest.Version)
+	}
+	return manifest, nil
+}
+
+// VerifyBackup checks the freezer files of the backup in dir against the
+// checksums of the manifest, and the consistency of the recorded head block
+// and persisted state.
+func VerifyBackup(dir string, manifest *BackupManifest) error {
+	var (
+		kvdir   = filepath.Join(dir, backupKeyValueDir)
+		ancient = filepath.Join(kvdir, "ancient")
+		files   = make(map[string]string)
+	)
+	if err := hashFreezerFiles(filepath.Join(ancient, chainFreezerName), files); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for name, want := range manifest.Files {
+		if have, ok := files[name]; !ok {
+			return fmt.Errorf("missing freezer file %s", name)
+		} else if have != want {
+			return fmt.Errorf("freezer file %s checksum mismatch: have %s, want %s", name, have, want)
+		}
+	}
+	if len(files) != len(manifest.Files) {
+		return fmt.Errorf("unexpected freezer files: have %d, want %d", len(files), len(manifest.Files))
+	}
+	// Open the backup to check its head block is complete
+	o := OpenOptions{Type: manifest.Engine, Directory: kvdir, Cache: 16, Handles: 16, ReadOnly: true}
+	if len(manifest.Files) > 0 {
+		o.AncientsDirectory = ancient
+	}
+	db, err := Open(o)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if len(manifest.Files) > 0 {
+		if ancients, err := db.Ancients(); err != nil {
+			return err
+		} else if ancients != manifest.Ancients {
+			return fmt.Errorf("ancient count mismatch: have %d, want %d", ancients, manifest.Ancients)
+		}
+	}
+	if hash := ReadHeadBlockHash(db); hash != manifest.HeadHash {
+		return fmt.Errorf("head block mismatch: have %x, want %x", hash, manifest.HeadHash)
+	}
+	header := ReadHeader(db, manifest.HeadHash, manifest.HeadNumber)
+	if header == nil {
+		return fmt.Errorf("head header #%d missing", manifest.HeadNumber)
+	}
+	if ReadCanonicalHash(db, manifest.HeadNumber) != manifest.HeadHash {
+		return fmt.Errorf("head block #%d not canonical", manifest.HeadNumber)
+	}
+	if !HasBody(db, manifest.HeadHash, manifest.HeadNumber) {
+		return fmt.Errorf("head block #%d body missing", manifest.HeadNumber)
+	}
+	if manifest.StateNumber > manifest.HeadNumber {
+		return fmt.Errorf("state block #%d beyond head #%d", manifest.StateNumber, manifest.HeadNumber)
+	}
+	state := ReadHeader(db, ReadCanonicalHash(db, manifest.StateNumber), manifest.StateNumber)
+	if state == nil || state.Root != manifest.StateRoot {
+		return fmt.Errorf("state block #%d mismatch", manifest.StateNumber)
+	}
+	if !hasStateRoot(db, manifest.StateRoot) {
+		return fmt.Errorf("state %x of block #%d missing", manifest.StateRoot, manifest.StateNumber)
+	}
+	return nil
+}
+
+// persistedState returns the number and state root of the most recent block,
+// going back from the given one, whose state is present in the snapshot.
+func persistedState(db ethdb.Reader, snap ethdb.KeyValueReader, hash common.Hash, number uint64) (uint64, common.Hash, error) {
+	for {
+		header := ReadHeader(db, hash, number)
+		if header == nil {
+			return 0, common.Hash{}, fmt.Errorf("header #%d missing", number)
+		}
+		if hasStateRoot(snap, header.Root) {
+			return number, header.Root, nil
+		}
+		if number == 0 {
+			return 0, common.Hash{}, errors.New("no persisted state")
+		}
+		hash, number = header.ParentHash, number-1
+	}
+}
+
+// hasStateRoot reports whether the root node of the given state is persisted.
+// With the path scheme, only the root of the latest persisted state is present.
+func hasStateRoot(db ethdb.KeyValueReader, root common.Hash) bool {
+	if root == types.EmptyRootHash {
+		return true
+	}
+	if ReadStateScheme(db) == PathScheme {
+		node := ReadAccountTrieNode(db, nil)
+		return len(node) > 0 && crypto.Keccak256Hash(node) == root
+	}
+	return HasTrieNode(db, root)
+}
+
+// writeBackupManifest stores the manifest in the backup directory.
+func writeBackupManifest(dir string, manifest *BackupManifest) error {
+	blob, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, backupManifestName), blob, 0644)
+}
+
+// copyFreezerFiles copies the files of the freezer in src into dst, recording
+// their checksums. The lock file is skipped.
+func copyFreezerFiles(src, dst string, checksums map[string]string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || entry.Name() == "FLOCK" {
+			continue
+		}
+		sum, err := copyFile(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name()))
+		if err != nil {
+			return err
+		}
+		checksums[entry.Name()] = sum
+	}
+	return nil
+}
+
+// hashFreezerFiles computes the checksums of the freezer files in dir.
+func hashFreezerFiles(dir string, checksums map[string]string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || entry.Name() == "FLOCK" {
+			continue
+		}
+		sum, err := copyFile(filepath.Join(dir, entry.Name()), "")
+		if err != nil {
+			return err
+		}
+		checksums[entry.Name()] = sum
+	}
+	return nil
+}
+
+// copyFile copies the file src to dst, returning the hex encoded sha256 of
+// its content. If dst is empty, the file is only hashed.
+func copyFile(src, dst string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	var (
+		hasher = sha256.New()
+		w      io.Writer
+	)
+	w = hasher
+	if dst != "" {
+		out, err := os.Create(dst)
+		if err != nil {
+			return "", err
+		}
+		defer out.Close()
+		w = io.MultiWriter(out, hasher)
+	}
+	if _, err := io.Copy(w, in); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// copyTree recursively copies the directory src into dst.
+func copyTree(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	for _, entry := range entries {
+		var (
+			from = filepath.Join(src, entry.Name())
+			to   = filepath.Join(dst, entry.Name())
+		)
+		switch {
+		case entry.IsDir():
+			if err := copyTree(from, to); err != nil {
+				return err
+			}
+		case entry.Type().IsRegular():
+			if entry.Name() == "FLOCK" || entry.Name() == "LOCK" {
+				continue
+			}
+			if _, err := copyFile(from, to); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
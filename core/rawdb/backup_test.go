@@ -0,0 +1,149 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestBackupRestore(t *testing.T) {
+	var (
+		dir    = t.TempDir()
+		kvdir  = filepath.Join(dir, "chaindata")
+		backup = filepath.Join(dir, "backup")
+	)
+	db, err := NewLevelDBDatabaseWithFreezer(kvdir, 16, 16, filepath.Join(kvdir, "ancient"), "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// Freeze blocks 0-5, keeping 6-9 in the key-value store
+	node := []byte{0xc0}
+	root := crypto.Keccak256Hash(node)
+	WriteTrieNode(db, root, node)
+
+	// The states of the last blocks are only held in memory
+	var blocks []*types.Block
+	for i := uint64(0); i < 10; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i), Root: root}
+		if i >= 8 {
+			header.Root = common.Hash{byte(i)}
+		}
+		if i > 0 {
+			header.ParentHash = blocks[i-1].Hash()
+		}
+		blocks = append(blocks, types.NewBlockWithHeader(header))
+	}
+	receipts := make([]types.Receipts, 6)
+	if _, err := WriteAncientBlocks(db, blocks[:6], receipts, big.NewInt(0)); err != nil {
+		t.Fatal(err)
+	}
+	for _, block := range blocks[6:] {
+		WriteBlock(db, block)
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		WriteHeaderNumber(db, block.Hash(), block.NumberU64())
+	}
+	WriteHeadBlockHash(db, blocks[9].Hash())
+
+	manifest, err := Backup(db, backup, "")
+	if err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+	if manifest.HeadHash != blocks[9].Hash() || manifest.HeadNumber != 9 || manifest.Ancients != 6 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	if manifest.StateNumber != 7 || manifest.StateRoot != root {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+	if len(manifest.Files) == 0 {
+		t.Fatal("no freezer files in manifest")
+	}
+	if _, err := Backup(db, backup, ""); err == nil {
+		t.Fatal("backup overwrote existing directory")
+	}
+	// Changes after the backup are not part of it
+	WriteHeadBlockHash(db, common.Hash{0x01})
+
+	restored := filepath.Join(dir, "restored")
+	if _, err := Restore(backup, restored); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	rdb, err := NewLevelDBDatabaseWithFreezer(restored, 16, 16, filepath.Join(restored, "ancient"), "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdb.Close()
+
+	if hash := ReadHeadBlockHash(rdb); hash != blocks[9].Hash() {
+		t.Fatalf("wrong restored head: have %x, want %x", hash, blocks[9].Hash())
+	}
+	for _, block := range blocks {
+		if ReadBlock(rdb, block.Hash(), block.NumberU64()) == nil {
+			t.Errorf("block %d missing", block.NumberU64())
+		}
+	}
+	if _, err := Restore(backup, restored); err == nil {
+		t.Fatal("restore overwrote existing database")
+	}
+}
+
+func TestRestoreCorrupted(t *testing.T) {
+	var (
+		dir    = t.TempDir()
+		kvdir  = filepath.Join(dir, "chaindata")
+		backup = filepath.Join(dir, "backup")
+	)
+	db, err := NewLevelDBDatabaseWithFreezer(kvdir, 16, 16, filepath.Join(kvdir, "ancient"), "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(0), Root: types.EmptyRootHash})
+	if _, err := WriteAncientBlocks(db, []*types.Block{genesis}, []types.Receipts{nil}, big.NewInt(0)); err != nil {
+		t.Fatal(err)
+	}
+	WriteHeaderNumber(db, genesis.Hash(), 0)
+	WriteHeadBlockHash(db, genesis.Hash())
+	manifest, err := Backup(db, backup, "")
+	db.Close()
+	if err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+	// Tamper with one of the freezer files
+	var name string
+	for name = range manifest.Files {
+		break
+	}
+	file := filepath.Join(backup, backupKeyValueDir, "ancient", chainFreezerName, name)
+	if err := os.WriteFile(file, []byte{0xff}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	restored := filepath.Join(dir, "restored")
+	if _, err := Restore(backup, restored); err == nil {
+		t.Fatal("corrupted backup restored")
+	}
+	if _, err := os.Stat(restored); !os.IsNotExist(err) {
+		t.Fatal("database directory created for corrupted backup")
+	}
+}
@@ -54,9 +54,10 @@ type chainFreezer struct {
 	trigger chan chan struct{} // Manual blocking freeze trigger, test determinism
 }
 
-// newChainFreezer initializes the freezer for ancient chain data.
-func newChainFreezer(datadir string, namespace string, readonly bool, maxTableSize uint32, tables map[string]bool) (*chainFreezer, error) {
-	freezer, err := NewFreezer(datadir, namespace, readonly, maxTableSize, tables)
+// newChainFreezer initializes the freezer for ancient chain data. Only the
+// block history tables can be truncated at the tail.
+func newChainFreezer(datadir string, namespace string, readonly bool, secondary bool, maxTableSize uint32, tables map[string]bool) (*chainFreezer, error) {
+	freezer, err := newFreezer(datadir, namespace, readonly, secondary, maxTableSize, tables, chainFreezerPrunable)
 	if err != nil {
 		return nil, err
 	}
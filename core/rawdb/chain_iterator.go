@@ -352,3 +352,46 @@ func UnindexTransactions(db ethdb.Database, from uint64, to uint64, interrupt ch
 func unindexTransactionsForTesting(db ethdb.Database, from uint64, to uint64, interrupt chan struct{}, hook func(uint64) bool) {
 	unindexTransactions(db, from, to, interrupt, hook)
 }
+
+// PruneHistory expires the bodies and receipts of all frozen blocks more than
+// retain blocks below the current head, keeping their headers. Transactions of
+// the expired blocks are unindexed first. Recent blocks still living in the
+// key-value store are left alone until they get frozen.
+//
+// The number of the first block with history available is returned.
+func PruneHistory(db ethdb.Database, retain uint64, interrupt chan struct{}) (uint64, error) {
+	tail, err := db.Tail()
+	if err != nil {
+		return 0, err
+	}
+	frozen, err := db.Ancients()
+	if err != nil {
+		return 0, err
+	}
+	head := ReadHeaderNumber(db, ReadHeadBlockHash(db))
+	if head == nil || *head+1 <= retain {
+		return tail, nil
+	}
+	target := *head + 1 - retain
+	if target > frozen {
+		target = frozen
+	}
+	if target <= tail {
+		return tail, nil
+	}
+	// Drop the lookup entries pointing into the expired bodies
+	if txtail := ReadTxIndexTail(db); txtail != nil && *txtail < target {
+		UnindexTransactions(db, *txtail, target, interrupt)
+		if txtail = ReadTxIndexTail(db); txtail != nil && *txtail < target {
+			target = *txtail // Unindexing was interrupted
+		}
+	}
+	if target <= tail {
+		return tail, nil
+	}
+	if err := db.TruncateTail(target); err != nil {
+		return tail, err
+	}
+	log.Info("Pruned block history", "tail", target, "head", *head)
+	return target, nil
+}
@@ -206,3 +206,75 @@ func TestIndexTransactions(t *testing.T) {
 	verify(8, 11, true, 8)
 	verify(0, 8, false, 8)
 }
+
+func TestPruneHistory(t *testing.T) {
+	db, err := NewDatabaseWithFreezer(NewMemoryDatabase(), t.TempDir(), "", false)
+	if err != nil {
+		t.Fatalf("failed to create database with ancient backend: %v", err)
+	}
+	defer db.Close()
+
+	// Freeze blocks 0-7, keeping 8-9 in the key-value store
+	to := common.BytesToAddress([]byte{0x11})
+	var (
+		blocks   []*types.Block
+		receipts []types.Receipts
+	)
+	for i := uint64(0); i < 10; i++ {
+		tx := types.NewTx(&types.LegacyTx{Nonce: i, GasPrice: big.NewInt(1), Gas: 21000, To: &to})
+		block := types.NewBlock(&types.Header{Number: new(big.Int).SetUint64(i)}, []*types.Transaction{tx}, nil, nil, newHasher())
+		blocks = append(blocks, block)
+		receipts = append(receipts, types.Receipts{{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{}}})
+	}
+	if _, err := WriteAncientBlocks(db, blocks[:8], receipts[:8], big.NewInt(0)); err != nil {
+		t.Fatal(err)
+	}
+	WriteBlock(db, blocks[0])
+	for _, block := range blocks[8:] {
+		WriteBlock(db, block)
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		WriteHeaderNumber(db, block.Hash(), block.NumberU64())
+	}
+	WriteHeadBlockHash(db, blocks[9].Hash())
+	IndexTransactions(db, 0, 10, nil)
+
+	// Nothing is pruned while the whole chain is retained
+	if tail, err := PruneHistory(db, 10, nil); err != nil || tail != 0 {
+		t.Fatalf("unexpected prune result: tail %d, err %v", tail, err)
+	}
+	if tail, err := PruneHistory(db, 5, nil); err != nil || tail != 5 {
+		t.Fatalf("unexpected prune result: tail %d, err %v", tail, err)
+	}
+	// Retaining fewer blocks than the freezer holds only prunes frozen ones
+	if tail, err := PruneHistory(db, 1, nil); err != nil || tail != 8 {
+		t.Fatalf("unexpected prune result: tail %d, err %v", tail, err)
+	}
+	if tail := ReadHistoryTail(db); tail != 8 {
+		t.Fatalf("wrong history tail: have %d, want 8", tail)
+	}
+	if tail := ReadTxIndexTail(db); tail == nil || *tail != 8 {
+		t.Fatalf("wrong transaction index tail: have %v, want 8", tail)
+	}
+	for i, block := range blocks {
+		hash, number := block.Hash(), block.NumberU64()
+		if ReadHeader(db, hash, number) == nil {
+			t.Errorf("header %d missing", i)
+		}
+		pruned := i > 0 && i < 8 // genesis is always kept in the key-value store
+		if body := ReadBody(db, hash, number); (body == nil) != pruned {
+			t.Errorf("body %d: have %v, want pruned %v", i, body, pruned)
+		}
+		if HasBody(db, hash, number) == pruned {
+			t.Errorf("body %d: have present %v, want %v", i, pruned, !pruned)
+		}
+		if pruned && ReadRawReceipts(db, hash, number) != nil {
+			t.Errorf("receipts %d not pruned", i)
+		}
+		if pruned && HasReceipts(db, hash, number) {
+			t.Errorf("receipts %d reported present after pruning", i)
+		}
+		if indexed := ReadTxLookupEntry(db, block.Transactions()[0].Hash()) != nil; indexed != (i >= 8) {
+			t.Errorf("transaction %d: have indexed %v", i, indexed)
+		}
+	}
+}
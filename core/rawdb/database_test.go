@@ -15,3 +15,42 @@
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
 package rawdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests that the engine of an existing database is detected, and that opening it
+// with another engine is refused.
+func TestDatabaseEngineMismatch(t *testing.T) {
+	dir := t.TempDir()
+	if engine := PreexistingDatabase(dir); engine != "" {
+		t.Fatalf("empty directory detected as %q", engine)
+	}
+	db, err := Open(OpenOptions{Directory: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	if engine := PreexistingDatabase(dir); engine != DBLeveldb {
+		t.Fatalf("engine mismatch: have %q, want %q", engine, DBLeveldb)
+	}
+	if _, err := Open(OpenOptions{Type: DBPebble, Directory: dir}); err == nil {
+		t.Fatal("leveldb database opened as pebble")
+	}
+	// Pebble databases are recognized by their OPTIONS file
+	dir = t.TempDir()
+	for _, name := range []string{"CURRENT", "OPTIONS-000003"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if engine := PreexistingDatabase(dir); engine != DBPebble {
+		t.Fatalf("engine mismatch: have %q, want %q", engine, DBPebble)
+	}
+	if _, err := NewLevelDBDatabase(dir, 0, 0, "", false); err == nil {
+		t.Fatal("pebble database opened as leveldb")
+	}
+}
@@ -66,7 +66,7 @@ type Freezer struct {
 	// 64-bit aligned fields can be atomic. The struct is guaranteed to be so aligned,
 	// so take advantage of that (https://golang.org/pkg/sync/atomic/#pkg-note-BUG).
 	frozen uint64 // Number of blocks already frozen
-	tail   uint64 // Number of the first stored item in the freezer
+	tail   uint64 // Number of the first stored item in the prunable tables
 
 	// This lock synchronizes writers and the truncate operation, as well as
 	// the "atomic" (batched) read operations.
@@ -75,6 +75,7 @@ type Freezer struct {
 
 	readonly     bool
 	tables       map[string]*freezerTable // Data tables for storing everything
+	prunable     map[string]bool          // Tables subject to tail truncation, nil meaning all of them
 	instanceLock fileutil.Releaser        // File-system lock to prevent double opens
 	closeOnce    sync.Once
 }
@@ -85,6 +86,16 @@ type Freezer struct {
 // The 'tables' argument defines the data tables. If the value of a map
 // entry is true, snappy compression is disabled for the table.
 func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize uint32, tables map[string]bool) (*Freezer, error) {
+	return newFreezer(datadir, namespace, readonly, false, maxTableSize, tables, nil)
+}
+
+// newFreezer creates a freezer instance where tail truncation only applies to
+// the tables in 'prunable', the rest retaining their entire history. A nil set
+// makes all tables prunable, sharing a common tail.
+//
+// A secondary freezer is opened read-only without taking the directory lock, to
+// follow the changes of another process writing to it.
+func newFreezer(datadir string, namespace string, readonly bool, secondary bool, maxTableSize uint32, tables map[string]bool, prunable map[string]bool) (*Freezer, error) {
 	// Create the initial freezer object
 	var (
 		readMeter  = metrics.NewRegisteredMeter(namespace+"ancient/read", nil)
@@ -100,31 +111,41 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 	}
 	// Leveldb uses LOCK as the filelock filename. To prevent the
 	// name collision, we use FLOCK as the lock name.
-	lock, _, err := fileutil.Flock(filepath.Join(datadir, "FLOCK"))
-	if err != nil {
+	var (
+		lock fileutil.Releaser
+		err  error
+	)
+	if secondary {
+		readonly = true
+	} else if lock, _, err = fileutil.Flock(filepath.Join(datadir, "FLOCK")); err != nil {
 		return nil, err
 	}
 	// Open all the supported data tables
 	freezer := &Freezer{
 		readonly:     readonly,
 		tables:       make(map[string]*freezerTable),
+		prunable:     prunable,
 		instanceLock: lock,
 	}
 
 	// Create the tables.
 	for name, disableSnappy := range tables {
-		table, err := newTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, disableSnappy, readonly)
+		table, err := openTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, disableSnappy, readonly, secondary)
 		if err != nil {
 			for _, table := range freezer.tables {
 				table.Close()
 			}
-			lock.Release()
+			freezer.releaseLock()
 			return nil, err
 		}
 		freezer.tables[name] = table
 	}
 
-	if freezer.readonly {
+	if secondary {
+		// The tables may be appended to concurrently, only use the
+		// items they all contain.
+		err = freezer.align()
+	} else if freezer.readonly {
 		// In readonly mode only validate, don't truncate.
 		// validate also sets `freezer.frozen`.
 		err = freezer.validate()
@@ -136,7 +157,7 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 		for _, table := range freezer.tables {
 			table.Close()
 		}
-		lock.Release()
+		freezer.releaseLock()
 		return nil, err
 	}
 
@@ -159,7 +180,7 @@ func (f *Freezer) Close() error {
 				errs = append(errs, err)
 			}
 		}
-		if err := f.instanceLock.Release(); err != nil {
+		if err := f.releaseLock(); err != nil {
 			errs = append(errs, err)
 		}
 	})
@@ -169,6 +190,14 @@ func (f *Freezer) Close() error {
 	return nil
 }
 
+// releaseLock releases the directory lock, if it was taken.
+func (f *Freezer) releaseLock() error {
+	if f.instanceLock == nil {
+		return nil
+	}
+	return f.instanceLock.Release()
+}
+
 // HasAncient returns an indicator whether the specified ancient data exists
 // in the freezer.
 func (f *Freezer) HasAncient(kind string, number uint64) (bool, error) {
@@ -285,6 +314,7 @@ func (f *Freezer) TruncateHead(items uint64) error {
 }
 
 // TruncateTail discards any recent data below the provided threshold number.
+// Only the prunable tables are affected, the others keep their full history.
 func (f *Freezer) TruncateTail(tail uint64) error {
 	if f.readonly {
 		return errReadOnly
@@ -295,7 +325,10 @@ func (f *Freezer) TruncateTail(tail uint64) error {
 	if atomic.LoadUint64(&f.tail) >= tail {
 		return nil
 	}
-	for _, table := range f.tables {
+	for kind, table := range f.tables {
+		if !f.isPrunable(kind) {
+			continue
+		}
 		if err := table.truncateTail(tail); err != nil {
 			return err
 		}
@@ -304,6 +337,23 @@ func (f *Freezer) TruncateTail(tail uint64) error {
 	return nil
 }
 
+// OverwriteAncient replaces an ancient item in place, which is only possible if
+// the replacement has the same size as the stored item. It's meant for repairing
+// corrupted data, the regular way to alter the ancients is truncating them.
+func (f *Freezer) OverwriteAncient(kind string, number uint64, blob []byte) error {
+	if f.readonly {
+		return errReadOnly
+	}
+	f.writeLock.Lock()
+	defer f.writeLock.Unlock()
+
+	table := f.tables[kind]
+	if table == nil {
+		return errUnknownTable
+	}
+	return table.overwrite(number, blob)
+}
+
 // Sync flushes all data tables to disk.
 func (f *Freezer) Sync() error {
 	var errs []error
@@ -335,36 +385,91 @@ func (f *Freezer) validate() error {
 		break
 	}
 	// Now check every table against that length
+	var tail uint64
 	for kind, table := range f.tables {
 		items := atomic.LoadUint64(&table.items)
 		if length != items {
 			return fmt.Errorf("freezer tables %s and %s have differing lengths: %d != %d", kind, name, items, length)
 		}
+		if hidden := atomic.LoadUint64(&table.itemHidden); f.isPrunable(kind) && hidden > tail {
+			tail = hidden
+		}
 	}
 	atomic.StoreUint64(&f.frozen, length)
+	atomic.StoreUint64(&f.tail, tail)
 	return nil
 }
 
-// repair truncates all data tables to the same length.
+// align sets the length of the freezer to the shortest table, and its tail to
+// the highest one among the prunable tables. Used instead of `validate` for
+// secondary freezers, whose tables may be appended to concurrently.
+func (f *Freezer) align() error {
+	var (
+		length = uint64(math.MaxUint64)
+		tail   uint64
+	)
+	for kind, table := range f.tables {
+		if items := atomic.LoadUint64(&table.items); items < length {
+			length = items
+		}
+		if hidden := atomic.LoadUint64(&table.itemHidden); f.isPrunable(kind) && hidden > tail {
+			tail = hidden
+		}
+	}
+	if len(f.tables) == 0 {
+		length = 0
+	}
+	atomic.StoreUint64(&f.frozen, length)
+	atomic.StoreUint64(&f.tail, tail)
+	return nil
+}
+
+// refresh loads the items appended or removed by the process writing to a
+// secondary freezer since it was opened.
+func (f *Freezer) refresh() error {
+	f.writeLock.Lock()
+	defer f.writeLock.Unlock()
+
+	for _, table := range f.tables {
+		if err := table.refresh(); err != nil {
+			return err
+		}
+	}
+	return f.align()
+}
+
+// isPrunable reports whether tail truncation applies to the given table.
+func (f *Freezer) isPrunable(kind string) bool {
+	return f.prunable == nil || f.prunable[kind]
+}
+
+// repair truncates all data tables to the same length, and all prunable
+// tables to the same tail.
 func (f *Freezer) repair() error {
 	var (
 		head = uint64(math.MaxUint64)
 		tail = uint64(0)
 	)
-	for _, table := range f.tables {
+	for kind, table := range f.tables {
 		items := atomic.LoadUint64(&table.items)
 		if head > items {
 			head = items
 		}
+		if !f.isPrunable(kind) {
+			continue
+		}
 		hidden := atomic.LoadUint64(&table.itemHidden)
 		if hidden > tail {
 			tail = hidden
 		}
 	}
-	for _, table := range f.tables {
+	for kind, table := range f.tables {
 		if err := table.truncateHead(head); err != nil {
 			return err
 		}
+		if !f.isPrunable(kind) {
+			continue
+		}
 		if err := table.truncateTail(tail); err != nil {
 			return err
 		}
@@ -103,6 +103,7 @@ type freezerTable struct {
 
 	noCompression bool // if true, disables snappy compression. Note: does not work retroactively
 	readonly      bool
+	secondary     bool   // if true, the table is appended to by another process
 	maxFileSize   uint32 // Max file size for data-files
 	name          string
 	path          string
@@ -132,6 +133,15 @@ func newFreezerTable(path, name string, disableSnappy, readonly bool) (*freezerT
 // non-existent. Both files are truncated to the shortest common length to ensure
 // they don't go out of sync.
 func newTable(path string, name string, readMeter metrics.Meter, writeMeter metrics.Meter, sizeGauge metrics.Gauge, maxFilesize uint32, noCompression, readonly bool) (*freezerTable, error) {
+	return openTable(path, name, readMeter, writeMeter, sizeGauge, maxFilesize, noCompression, readonly, false)
+}
+
+// openTable opens a freezer table like newTable. A secondary table is opened
+// read-only while another process may be appending to it, ignoring any data
+// which isn't indexed yet.
+func openTable(path string, name string, readMeter metrics.Meter, writeMeter metrics.Meter, sizeGauge metrics.Gauge, maxFilesize uint32, noCompression, readonly, secondary bool) (*freezerTable, error) {
+	readonly = readonly || secondary
+
 	// Ensure the containing directory exists and open the indexEntry file
 	if err := os.MkdirAll(path, 0755); err != nil {
 		return nil, err
@@ -188,6 +198,7 @@ func newTable(path string, name string, readMeter metrics.Meter, writeMeter metr
 		logger:        log.New("database", path, "table", name),
 		noCompression: noCompression,
 		readonly:      readonly,
+		secondary:     secondary,
 		maxFileSize:   maxFilesize,
 	}
 	if err := tab.repair(); err != nil {
@@ -221,15 +232,16 @@ func (t *freezerTable) repair() error {
 			return err
 		}
 	}
-	// Ensure the index is a multiple of indexEntrySize bytes
-	if overflow := stat.Size() % indexEntrySize; overflow != 0 {
+	// Ensure the index is a multiple of indexEntrySize bytes. A secondary table
+	// may be appended to by another process, so a partial entry is ignored.
+	if overflow := stat.Size() % indexEntrySize; overflow != 0 && !t.secondary {
 		truncateFreezerFile(t.index, stat.Size()-overflow) // New file can't trigger this path
 	}
 	// Retrieve the file sizes and prepare for truncation
 	if stat, err = t.index.Stat(); err != nil {
 		return err
 	}
-	offsetsSize := stat.Size()
+	offsetsSize := stat.Size() - stat.Size()%indexEntrySize
 
 	// Open the head file
 	var (
@@ -279,6 +291,10 @@ func (t *freezerTable) repair() error {
 
 	// Keep truncating both files until they come in sync
 	contentExp = int64(lastIndex.offset)
+	if t.secondary && contentExp < contentSize {
+		// Data appended by another process, but not yet indexed
+		contentSize = contentExp
+	}
 	for contentExp != contentSize {
 		// Truncate the head file to the last offset pointer
 		if contentExp < contentSize {
@@ -555,6 +571,120 @@ func (t *freezerTable) truncateTail(items uint64) error {
 	return nil
 }
 
+// overwrite replaces the content of a stored item in place, which is used to
+// repair corrupted data. As the offsets of the following items can't change,
+// the replacement must occupy exactly as many bytes as the original.
+func (t *freezerTable) overwrite(item uint64, blob []byte) error {
+	if t.readonly {
+		return errReadOnly
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.index == nil || t.head == nil {
+		return errClosed
+	}
+	if !t.has(item) {
+		return errOutOfBounds
+	}
+	indices, err := t.getIndices(item, 1)
+	if err != nil {
+		return err
+	}
+	start, end, filenum := indices[0].bounds(indices[1])
+	if !t.noCompression {
+		blob = snappy.Encode(nil, blob)
+	}
+	if uint32(len(blob)) != end-start {
+		return fmt.Errorf("item %d size mismatch: have %d bytes, want %d", item, len(blob), end-start)
+	}
+	// All but the head file are opened read-only, write through a new handle
+	file := t.head
+	if filenum != t.headId {
+		existing, ok := t.files[filenum]
+		if !ok {
+			return fmt.Errorf("missing data file %d", filenum)
+		}
+		if file, err = os.OpenFile(existing.Name(), os.O_WRONLY, 0644); err != nil {
+			return err
+		}
+		defer file.Close()
+	}
+	if _, err := file.WriteAt(blob, int64(start)); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// refresh loads the items appended or removed by the process writing to a
+// secondary table since it was opened.
+func (t *freezerTable) refresh() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !t.secondary {
+		return errors.New("refreshing non-secondary table")
+	}
+	buffer := make([]byte, indexEntrySize)
+
+	// Tail truncation replaces the index file, reopen it if it changed
+	current, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	latest, err := os.Stat(t.index.Name())
+	if err != nil {
+		return err
+	}
+	if !os.SameFile(current, latest) {
+		index, err := openFreezerFileForReadOnly(t.index.Name())
+		if err != nil {
+			return err
+		}
+		t.index.Close()
+		t.index = index
+
+		if _, err := t.index.ReadAt(buffer, 0); err != nil {
+			return err
+		}
+		var first indexEntry
+		first.unmarshalBinary(buffer)
+		t.tailId = first.filenum
+		atomic.StoreUint64(&t.itemOffset, uint64(first.offset))
+		t.releaseFilesBefore(t.tailId, false)
+	}
+	if meta, err := readMetadata(t.meta); err == nil {
+		atomic.StoreUint64(&t.itemHidden, meta.VirtualTail)
+	}
+	// Load the head, ignoring any partially written index entry
+	stat, err := t.index.Stat()
+	if err != nil {
+		return err
+	}
+	offsetsSize := stat.Size() - stat.Size()%indexEntrySize
+
+	lastIndex := indexEntry{filenum: t.tailId, offset: 0}
+	if offsetsSize > indexEntrySize {
+		if _, err := t.index.ReadAt(buffer, offsetsSize-indexEntrySize); err != nil {
+			return err
+		}
+		lastIndex.unmarshalBinary(buffer)
+	}
+	for num := t.headId + 1; num < lastIndex.filenum; num++ {
+		if _, err := t.openFile(num, openFreezerFileForReadOnly); err != nil {
+			return err
+		}
+	}
+	t.releaseFilesAfter(lastIndex.filenum, false)
+	if t.head, err = t.openFile(lastIndex.filenum, openFreezerFileForReadOnly); err != nil {
+		return err
+	}
+	t.headId = lastIndex.filenum
+	t.headBytes = int64(lastIndex.offset)
+	atomic.StoreUint64(&t.items, atomic.LoadUint64(&t.itemOffset)+uint64(offsetsSize/indexEntrySize-1))
+	return nil
+}
+
 // Close closes all opened files.
 func (t *freezerTable) Close() error {
 	t.lock.Lock()
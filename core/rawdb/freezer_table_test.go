@@ -1086,6 +1086,71 @@ func TestFreezerReadonly(t *testing.T) {
 	}
 }
 
+// TestFreezerSecondary checks that a secondary table tolerates data appended
+// concurrently, and follows the writer on refresh.
+func TestFreezerSecondary(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		name = fmt.Sprintf("secondarytest-%d", rand.Uint64())
+	)
+	f, err := newTable(dir, name, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	writeChunks(t, f, 8, 32)
+
+	// Data written before its index entry is ignored
+	if _, err := f.head.Write([]byte{1, 1}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := openTable(dir, name, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, true, false, true)
+	if err != nil {
+		t.Fatalf("failed to open secondary table: %v", err)
+	}
+	defer s.Close()
+	if items := atomic.LoadUint64(&s.items); items != 8 {
+		t.Fatalf("wrong item count: have %d, want 8", items)
+	}
+	// Items appended across data files are loaded on refresh
+	if err := f.truncateHead(8); err != nil {
+		t.Fatal(err)
+	}
+	batch := f.newBatch()
+	for i := 8; i < 16; i++ {
+		if err := batch.AppendRaw(uint64(i), getChunk(32, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := batch.commit(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.refresh(); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	if items := atomic.LoadUint64(&s.items); items != 16 {
+		t.Fatalf("wrong item count: have %d, want 16", items)
+	}
+	for i := 0; i < 16; i++ {
+		if blob, err := s.Retrieve(uint64(i)); err != nil || !bytes.Equal(blob, getChunk(32, i)) {
+			t.Errorf("item %d: have %x, err %v", i, blob, err)
+		}
+	}
+	// Tail truncation replaces the index file
+	if err := f.truncateTail(4); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.refresh(); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	if _, err := s.Retrieve(3); err == nil {
+		t.Error("retrieved item below the tail")
+	}
+	if blob, err := s.Retrieve(15); err != nil || !bytes.Equal(blob, getChunk(32, 15)) {
+		t.Errorf("item 15: have %x, err %v", blob, err)
+	}
+}
+
 // randTest performs random freezer table operations.
 // Instances of this test are created by Generate.
 type randTest []randTestStep
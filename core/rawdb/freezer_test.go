@@ -283,6 +283,57 @@ func TestFreezerReadonlyValidate(t *testing.T) {
 	}
 }
 
+func TestFreezerPrunableTail(t *testing.T) {
+	tables := map[string]bool{"a": true, "b": true}
+	dir := t.TempDir()
+	f, err := newFreezer(dir, "", false, false, 2049, tables, map[string]bool{"b": true})
+	if err != nil {
+		t.Fatal("can't open freezer", err)
+	}
+	_, err = f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for i := uint64(0); i < 10; i++ {
+			if err := op.AppendRaw("a", i, []byte{byte(i)}); err != nil {
+				return err
+			}
+			if err := op.AppendRaw("b", i, []byte{byte(i)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, f.TruncateTail(5))
+
+	check := func(f *Freezer) {
+		t.Helper()
+		if tail, _ := f.Tail(); tail != 5 {
+			t.Fatalf("wrong tail: have %d, want 5", tail)
+		}
+		if _, err := f.Ancient("a", 0); err != nil {
+			t.Fatal("non-prunable table truncated:", err)
+		}
+		if _, err := f.Ancient("b", 4); err == nil {
+			t.Fatal("prunable table not truncated")
+		}
+		if _, err := f.Ancient("b", 5); err != nil {
+			t.Fatal("prunable table truncated too far:", err)
+		}
+	}
+	check(f)
+	require.NoError(t, f.Close())
+
+	// Reopening must not realign the tails of the retained tables
+	f, err = newFreezer(dir, "", false, false, 2049, tables, map[string]bool{"b": true})
+	require.NoError(t, err)
+	check(f)
+	require.NoError(t, f.Close())
+
+	f, err = newFreezer(dir, "", true, false, 2049, tables, map[string]bool{"b": true})
+	require.NoError(t, err)
+	check(f)
+	require.NoError(t, f.Close())
+}
+
 func newFreezerForTesting(t *testing.T, tables map[string]bool) (*Freezer, string) {
 	t.Helper()
 
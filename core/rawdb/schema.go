@@ -84,6 +84,15 @@ var (
 	// transitionStatusKey tracks the eth2 transition status.
 	transitionStatusKey = []byte("eth2-transition")
 
+	// scrubProgressKey tracks the next block to be checked by the database scrubber.
+	scrubProgressKey = []byte("ScrubProgress")
+
+	// stateSchemeKey tracks the scheme the state trie nodes are stored with.
+	stateSchemeKey = []byte("StateScheme")
+
+	// reverseDiffHeadKey tracks the id of the latest reverse diff of the path-based state.
+	reverseDiffHeadKey = []byte("ReverseDiffHead")
+
 	// Data item prefixes (use single byte to avoid mixing data types, avoid `i`, used for indexes).
 	headerPrefix       = []byte("h") // headerPrefix + num (uint64 big endian) + hash -> header
 	headerTDSuffix     = []byte("t") // headerPrefix + num (uint64 big endian) + hash + headerTDSuffix -> td
@@ -99,11 +108,18 @@ var (
 	SnapshotStoragePrefix = []byte("o") // SnapshotStoragePrefix + account hash + storage hash -> storage trie value
 	CodePrefix            = []byte("c") // CodePrefix + code hash -> account code
 	skeletonHeaderPrefix  = []byte("S") // skeletonHeaderPrefix + num (uint64 big endian) -> header
+	trieNodeAccountPrefix = []byte("A") // trieNodeAccountPrefix + hexPath -> trie node (path scheme)
+	trieNodeStoragePrefix = []byte("O") // trieNodeStoragePrefix + account hash + hexPath -> trie node (path scheme)
 
 	PreimagePrefix = []byte("secure-key-")       // PreimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-")  // config prefix for the db
 	genesisPrefix  = []byte("ethereum-genesis-") // genesis state prefix for the db
 
+	reverseDiffPrefix       = []byte("reverse-diff-")   // reverseDiffPrefix + id (uint64 big endian) -> reverse diff
+	reverseDiffLookupPrefix = []byte("reverse-lookup-") // reverseDiffLookupPrefix + state root -> reverse diff id
+	stateDiffPrefix         = []byte("state-diff-")     // stateDiffPrefix + num (uint64 big endian) + hash -> block state diff
+	storageLayoutPrefix     = []byte("storage-layout-") // storageLayoutPrefix + address -> contract storage layout
+
 	// Chain index prefixes (use `i` + single byte to avoid mixing data types).
 	BloomBitsIndexPrefix = []byte("iB") // BloomBitsIndexPrefix is the data table of a chain indexer to track its progress
 
@@ -215,6 +231,36 @@ func IsCodeKey(key []byte) (bool, []byte) {
 	return false, nil
 }
 
+// accountTrieNodeKey = trieNodeAccountPrefix + hexPath
+func accountTrieNodeKey(path []byte) []byte {
+	return append(trieNodeAccountPrefix, path...)
+}
+
+// storageTrieNodeKey = trieNodeStoragePrefix + account hash + hexPath
+func storageTrieNodeKey(accountHash common.Hash, path []byte) []byte {
+	return append(append(trieNodeStoragePrefix, accountHash.Bytes()...), path...)
+}
+
+// reverseDiffKey = reverseDiffPrefix + id (uint64 big endian)
+func reverseDiffKey(id uint64) []byte {
+	return append(reverseDiffPrefix, encodeBlockNumber(id)...)
+}
+
+// reverseDiffLookupKey = reverseDiffLookupPrefix + state root
+func reverseDiffLookupKey(root common.Hash) []byte {
+	return append(reverseDiffLookupPrefix, root.Bytes()...)
+}
+
+// stateDiffKey = stateDiffPrefix + num (uint64 big endian) + hash
+func stateDiffKey(number uint64, hash common.Hash) []byte {
+	return append(append(stateDiffPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// storageLayoutKey = storageLayoutPrefix + address
+func storageLayoutKey(address common.Address) []byte {
+	return append(storageLayoutPrefix, address.Bytes()...)
+}
+
 // configKey = configPrefix + hash
 func configKey(hash common.Hash) []byte {
 	return append(configPrefix, hash.Bytes()...)
@@ -0,0 +1,123 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// errNotOverwritable is returned when repairing an ancient item of a database
+// which doesn't support replacing them.
+var errNotOverwritable = errors.New("ancient items can't be overwritten")
+
+// AncientOverwriter is implemented by databases whose ancient items can be
+// replaced in place, which is used to repair corrupted items.
+type AncientOverwriter interface {
+	OverwriteAncient(kind string, number uint64, blob []byte) error
+}
+
+// OverwriteAncient implements AncientOverwriter, replacing an item of the chain
+// freezer.
+func (frdb *freezerdb) OverwriteAncient(kind string, number uint64, blob []byte) error {
+	if freezer, ok := frdb.AncientStore.(*chainFreezer); ok {
+		return freezer.OverwriteAncient(kind, number, blob)
+	}
+	return errNotOverwritable
+}
+
+// isFrozen reports whether the data of the given block lives in the freezer.
+func isFrozen(db ethdb.AncientReaderOp, number uint64) bool {
+	frozen, err := db.Ancients()
+	return err == nil && number < frozen
+}
+
+// overwriteAncient replaces an ancient item with the RLP encoding of the given
+// value, or the value itself if it's a byte slice.
+func overwriteAncient(db ethdb.Database, kind string, number uint64, item interface{}) error {
+	overwriter, ok := db.(AncientOverwriter)
+	if !ok {
+		return errNotOverwritable
+	}
+	blob, ok := item.([]byte)
+	if !ok {
+		var err error
+		if blob, err = rlp.EncodeToBytes(item); err != nil {
+			return err
+		}
+	}
+	return overwriter.OverwriteAncient(kind, number, blob)
+}
+
+// RepairCanonicalHash replaces the canonical hash of a block, in the freezer if
+// the block is already frozen.
+func RepairCanonicalHash(db ethdb.Database, hash common.Hash, number uint64) error {
+	if isFrozen(db, number) {
+		return overwriteAncient(db, chainFreezerHashTable, number, hash.Bytes())
+	}
+	WriteCanonicalHash(db, hash, number)
+	return nil
+}
+
+// RepairHeader replaces the stored header of a canonical block, in the freezer
+// if the block is already frozen.
+func RepairHeader(db ethdb.Database, header *types.Header) error {
+	if number := header.Number.Uint64(); isFrozen(db, number) {
+		return overwriteAncient(db, chainFreezerHeaderTable, number, header)
+	}
+	WriteHeader(db, header)
+	return nil
+}
+
+// RepairBody replaces the stored body of a canonical block, in the freezer if
+// the block is already frozen.
+func RepairBody(db ethdb.Database, hash common.Hash, number uint64, body *types.Body) error {
+	if isFrozen(db, number) {
+		return overwriteAncient(db, chainFreezerBodiesTable, number, body)
+	}
+	WriteBody(db, hash, number, body)
+	return nil
+}
+
+// RepairReceipts replaces the stored receipts of a canonical block, in the
+// freezer if the block is already frozen.
+func RepairReceipts(db ethdb.Database, hash common.Hash, number uint64, receipts types.Receipts) error {
+	if isFrozen(db, number) {
+		stored := make([]*types.ReceiptForStorage, len(receipts))
+		for i, receipt := range receipts {
+			stored[i] = (*types.ReceiptForStorage)(receipt)
+		}
+		return overwriteAncient(db, chainFreezerReceiptTable, number, stored)
+	}
+	WriteReceipts(db, hash, number, receipts)
+	return nil
+}
+
+// RepairTd replaces the stored total difficulty of a canonical block, in the
+// freezer if the block is already frozen.
+func RepairTd(db ethdb.Database, hash common.Hash, number uint64, td *big.Int) error {
+	if isFrozen(db, number) {
+		return overwriteAncient(db, chainFreezerDifficultyTable, number, td)
+	}
+	WriteTd(db, hash, number, td)
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that frozen and recent block data can be repaired.
+func TestRepairBlockData(t *testing.T) {
+	db, err := NewDatabaseWithFreezer(NewMemoryDatabase(), t.TempDir(), "", false)
+	if err != nil {
+		t.Fatalf("failed to create database with ancient backend: %v", err)
+	}
+	defer db.Close()
+
+	newHeader := func(number int64, extra string) *types.Header {
+		return &types.Header{
+			Number:      big.NewInt(number),
+			Extra:       []byte(extra),
+			UncleHash:   types.EmptyUncleHash,
+			TxHash:      types.EmptyRootHash,
+			ReceiptHash: types.EmptyRootHash,
+		}
+	}
+	frozen := types.NewBlockWithHeader(newHeader(0, "test block"))
+	if _, err := WriteAncientBlocks(db, []*types.Block{frozen}, []types.Receipts{nil}, big.NewInt(100)); err != nil {
+		t.Fatalf("failed to write ancient block: %v", err)
+	}
+	// Corrupt the frozen header with a replacement of the same size
+	if err := RepairHeader(db, newHeader(0, "best block")); err != nil {
+		t.Fatalf("failed to overwrite header: %v", err)
+	}
+	if header := ReadHeader(db, frozen.Hash(), 0); header != nil {
+		t.Fatalf("corrupted header returned")
+	}
+	if err := RepairHeader(db, newHeader(0, "longer test block")); err == nil {
+		t.Fatalf("header of a different size overwritten")
+	}
+	if err := RepairHeader(db, frozen.Header()); err != nil {
+		t.Fatalf("failed to repair header: %v", err)
+	}
+	if header := ReadHeader(db, frozen.Hash(), 0); header == nil || header.Hash() != frozen.Hash() {
+		t.Fatalf("header not repaired")
+	}
+	if err := RepairCanonicalHash(db, common.Hash{0x01}, 0); err != nil {
+		t.Fatalf("failed to overwrite canonical hash: %v", err)
+	}
+	if err := RepairCanonicalHash(db, frozen.Hash(), 0); err != nil {
+		t.Fatalf("failed to repair canonical hash: %v", err)
+	}
+	if hash := ReadCanonicalHash(db, 0); hash != frozen.Hash() {
+		t.Fatalf("canonical hash mismatch: have %x, want %x", hash, frozen.Hash())
+	}
+	if err := RepairTd(db, frozen.Hash(), 0, big.NewInt(100)); err != nil {
+		t.Fatalf("failed to repair td: %v", err)
+	}
+	if td := ReadTd(db, frozen.Hash(), 0); td == nil || td.Int64() != 100 {
+		t.Fatalf("td mismatch: have %v, want 100", td)
+	}
+	// Recent blocks are repaired in the key-value store
+	recent := newHeader(1, "recent block")
+	if err := RepairHeader(db, recent); err != nil {
+		t.Fatalf("failed to repair recent header: %v", err)
+	}
+	if err := RepairBody(db, recent.Hash(), 1, new(types.Body)); err != nil {
+		t.Fatalf("failed to repair recent body: %v", err)
+	}
+	if block := ReadBlock(db, recent.Hash(), 1); block == nil {
+		t.Fatalf("recent block not repaired")
+	}
+}
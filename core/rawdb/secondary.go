@@ -0,0 +1,453 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+)
+
+var (
+	// errNotSecondary is returned when refreshing a database which wasn't opened
+	// as a secondary.
+	errNotSecondary = errors.New("not a secondary database")
+
+	// errSecondaryClosed is returned when accessing a secondary database after
+	// it has been closed.
+	errSecondaryClosed = errors.New("secondary database closed")
+)
+
+// Refresher is implemented by databases which can catch up with the writes of
+// another process, which is supported by the ones opened as secondaries.
+type Refresher interface {
+	Refresh() error
+}
+
+// Refresh catches a database opened as a secondary up with the writes of the
+// primary process. The ancients are refreshed first: items are only deleted
+// from the key-value store after being frozen, so they remain available from
+// either store meanwhile.
+func Refresh(db ethdb.Database) error {
+	r, ok := db.(Refresher)
+	if !ok {
+		return errNotSecondary
+	}
+	return r.Refresh()
+}
+
+// Refresh implements Refresher, catching up the freezer and the key-value store.
+func (frdb *freezerdb) Refresh() error {
+	if freezer, ok := frdb.AncientStore.(*chainFreezer); ok {
+		if err := freezer.refresh(); err != nil {
+			return err
+		}
+	}
+	return refreshStore(frdb.KeyValueStore)
+}
+
+// Refresh implements Refresher, catching up the key-value store.
+func (db *nofreezedb) Refresh() error {
+	return refreshStore(db.KeyValueStore)
+}
+
+// refreshStore refreshes the key-value store if it's a secondary.
+func refreshStore(db ethdb.KeyValueStore) error {
+	if r, ok := db.(Refresher); ok {
+		return r.Refresh()
+	}
+	return errNotSecondary
+}
+
+// secondaryOpenRetries is the number of times opening a secondary instance is
+// attempted if the primary changed its manifest meanwhile, e.g. by compacting.
+const secondaryOpenRetries = 5
+
+// secondaryStore is a key-value store opened read-only alongside the primary
+// process writing to it. As the underlying databases only observe the writes
+// made before they were opened, the store is reopened on refresh whenever the
+// logs of the primary changed. Instances are reference counted: iterators and
+// snapshots keep the instance they were created on open until released.
+type secondaryStore struct {
+	dir  string
+	open func() (ethdb.KeyValueStore, error)
+
+	db     *secondaryInstance // Current instance of the database
+	marker string             // Fingerprint of the primary's logs when db was opened
+	lock   sync.RWMutex
+}
+
+// secondaryInstance is a single opened instance of a secondary database, closed
+// once the store and all iterators and snapshots created on it released it.
+type secondaryInstance struct {
+	ethdb.KeyValueStore
+	refs int32
+}
+
+// retain adds a reference to the instance.
+func (db *secondaryInstance) retain() {
+	atomic.AddInt32(&db.refs, 1)
+}
+
+// release drops a reference to the instance, closing it if it was the last.
+func (db *secondaryInstance) release() error {
+	if atomic.AddInt32(&db.refs, -1) == 0 {
+		return db.KeyValueStore.Close()
+	}
+	return nil
+}
+
+// newSecondaryStore opens the key-value store in the given directory as a
+// secondary, detecting its engine.
+func newSecondaryStore(o OpenOptions) (*secondaryStore, error) {
+	store := &secondaryStore{dir: o.Directory}
+	switch PreexistingDatabase(o.Directory) {
+	case DBLeveldb:
+		store.open = func() (ethdb.KeyValueStore, error) {
+			db, err := leveldb.NewSecondary(o.Directory, o.Cache, o.Handles)
+			if err != nil {
+				return nil, err
+			}
+			return db, nil
+		}
+	case DBPebble:
+		store.open = func() (ethdb.KeyValueStore, error) {
+			return newPebbleSecondary(o.Directory, o.Cache, o.Handles)
+		}
+	default:
+		return nil, fmt.Errorf("no database to follow in %s", o.Directory)
+	}
+	if err := store.Refresh(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// logMarker returns a fingerprint of the manifest and write-ahead log files of
+// the database, which changes with every write of the primary.
+func logMarker(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	var marker strings.Builder
+	for _, entry := range entries {
+		name := entry.Name()
+		if name != "CURRENT" && !strings.HasPrefix(name, "MANIFEST-") && !strings.HasSuffix(name, ".log") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // Removed meanwhile
+			}
+			return "", err
+		}
+		fmt.Fprintf(&marker, "%s:%d:%d;", name, info.Size(), info.ModTime().UnixNano())
+	}
+	return marker.String(), nil
+}
+
+// manifestMarker returns the part of a log fingerprint covering the manifest,
+// which changes whenever the primary adds or removes table files.
+func manifestMarker(marker string) string {
+	var manifest strings.Builder
+	for _, entry := range strings.Split(marker, ";") {
+		if strings.HasPrefix(entry, "CURRENT:") || strings.HasPrefix(entry, "MANIFEST-") {
+			manifest.WriteString(entry + ";")
+		}
+	}
+	return manifest.String()
+}
+
+// Refresh reopens the database if the primary wrote to it since it was opened.
+// The previous instance is closed once all its iterators and snapshots have
+// been released.
+func (s *secondaryStore) Refresh() error {
+	// Take the fingerprint before opening, so that concurrent writes are
+	// detected on the next refresh.
+	marker, err := logMarker(s.dir)
+	if err != nil {
+		return err
+	}
+	s.lock.RLock()
+	unchanged := s.db != nil && marker == s.marker
+	s.lock.RUnlock()
+	if unchanged {
+		return nil
+	}
+	db, marker, err := s.openInstance(marker)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	stale := s.db
+	s.db, s.marker = db, marker
+	s.lock.Unlock()
+
+	if stale != nil {
+		return stale.release()
+	}
+	return nil
+}
+
+// openInstance opens a new instance of the database. The primary doesn't know
+// about the secondary and may delete table files obsoleted by a compaction while
+// they are being opened, so the open is retried if the manifest changed.
+func (s *secondaryStore) openInstance(marker string) (*secondaryInstance, string, error) {
+	for i := 0; ; i++ {
+		db, err := s.open()
+		current, merr := logMarker(s.dir)
+		if merr != nil {
+			if err == nil {
+				db.Close()
+			}
+			return nil, "", merr
+		}
+		if manifestMarker(current) == manifestMarker(marker) {
+			if err != nil {
+				return nil, "", err
+			}
+			return &secondaryInstance{KeyValueStore: db, refs: 1}, marker, nil
+		}
+		if err == nil {
+			db.Close()
+		}
+		if i+1 >= secondaryOpenRetries {
+			if err == nil {
+				err = errors.New("primary database changed while opening")
+			}
+			return nil, "", err
+		}
+		marker = current
+	}
+}
+
+// reopen replaces a broken instance of the database with a fresh one, unless it
+// was replaced or the store closed meanwhile.
+func (s *secondaryStore) reopen(broken *secondaryInstance) error {
+	marker, err := logMarker(s.dir)
+	if err != nil {
+		return err
+	}
+	s.lock.RLock()
+	replaced := s.db != broken
+	s.lock.RUnlock()
+	if replaced {
+		return nil
+	}
+	db, marker, err := s.openInstance(marker)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	if s.db != broken {
+		s.lock.Unlock()
+		return db.release()
+	}
+	s.db, s.marker = db, marker
+	s.lock.Unlock()
+
+	return broken.release()
+}
+
+// instance returns the current instance of the database, retained until the
+// caller releases it.
+func (s *secondaryStore) instance() (*secondaryInstance, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.db == nil {
+		return nil, errSecondaryClosed
+	}
+	s.db.retain()
+	return s.db, nil
+}
+
+// unreadable reports whether a key failed to be read from an instance of the
+// database, rather than being missing, which Has reports without an error.
+func unreadable(db ethdb.KeyValueReader, key []byte) bool {
+	ok, err := db.Has(key)
+	return err != nil || ok
+}
+
+// Has retrieves if a key is present in the key-value store. Table files are
+// only opened on first access, so the primary may have deleted the ones it
+// compacted away since the instance was opened: failed reads are retried once
+// on a fresh instance.
+func (s *secondaryStore) Has(key []byte) (bool, error) {
+	for retried := false; ; retried = true {
+		db, err := s.instance()
+		if err != nil {
+			return false, err
+		}
+		ok, err := db.Has(key)
+		db.release()
+		if err == nil || retried || s.reopen(db) != nil {
+			return ok, err
+		}
+	}
+}
+
+// Get retrieves the given key if it's present in the key-value store. Failed
+// reads are retried once on a fresh instance, like in Has.
+func (s *secondaryStore) Get(key []byte) ([]byte, error) {
+	for retried := false; ; retried = true {
+		db, err := s.instance()
+		if err != nil {
+			return nil, err
+		}
+		value, err := db.Get(key)
+		broken := err != nil && !retried && unreadable(db, key)
+		db.release()
+		if !broken || s.reopen(db) != nil {
+			return value, err
+		}
+	}
+}
+
+// Put is not supported by a secondary store.
+func (s *secondaryStore) Put(key []byte, value []byte) error {
+	return errReadOnly
+}
+
+// Delete is not supported by a secondary store.
+func (s *secondaryStore) Delete(key []byte) error {
+	return errReadOnly
+}
+
+// NewBatch creates a write-only key-value store that buffers changes until a
+// final write is called, which fails as the store is read-only.
+func (s *secondaryStore) NewBatch() ethdb.Batch {
+	return secondaryBatch{memorydb.New().NewBatch()}
+}
+
+// NewBatchWithSize creates a write-only batch with pre-allocated buffer, which
+// fails to be written as the store is read-only.
+func (s *secondaryStore) NewBatchWithSize(size int) ethdb.Batch {
+	return secondaryBatch{memorydb.New().NewBatchWithSize(size)}
+}
+
+// NewIterator creates a binary-alphabetical iterator over a subset of database
+// content with a particular key prefix, starting at a particular initial key.
+func (s *secondaryStore) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	db, err := s.instance()
+	if err != nil {
+		return &closedIterator{err: err}
+	}
+	return &secondaryIterator{Iterator: db.NewIterator(prefix, start), db: db}
+}
+
+// NewSnapshot creates a database snapshot based on the current state.
+func (s *secondaryStore) NewSnapshot() (ethdb.Snapshot, error) {
+	db, err := s.instance()
+	if err != nil {
+		return nil, err
+	}
+	snap, err := db.NewSnapshot()
+	if err != nil {
+		db.release()
+		return nil, err
+	}
+	return &secondarySnapshot{Snapshot: snap, db: db}, nil
+}
+
+// Stat returns a particular internal stat of the database.
+func (s *secondaryStore) Stat(property string) (string, error) {
+	db, err := s.instance()
+	if err != nil {
+		return "", err
+	}
+	defer db.release()
+	return db.Stat(property)
+}
+
+// Compact is not supported by a secondary store.
+func (s *secondaryStore) Compact(start []byte, limit []byte) error {
+	return errReadOnly
+}
+
+// Close releases the current instance of the database, which is closed once
+// all its iterators and snapshots are released too.
+func (s *secondaryStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.db == nil {
+		return nil
+	}
+	db := s.db
+	s.db = nil
+	return db.release()
+}
+
+// secondaryBatch is a batch buffering writes in memory, which fails to be
+// written as the store is read-only.
+type secondaryBatch struct {
+	ethdb.Batch
+}
+
+// Write implements ethdb.Batch, rejecting the write.
+func (b secondaryBatch) Write() error {
+	return errReadOnly
+}
+
+// secondaryIterator is an iterator holding a reference to the instance of the
+// database it iterates over.
+type secondaryIterator struct {
+	ethdb.Iterator
+	db   *secondaryInstance
+	once sync.Once
+}
+
+// Release implements ethdb.Iterator, releasing the instance after the iterator.
+func (it *secondaryIterator) Release() {
+	it.Iterator.Release()
+	it.once.Do(func() { it.db.release() })
+}
+
+// closedIterator is an empty iterator over a closed secondary store.
+type closedIterator struct {
+	err error
+}
+
+func (it *closedIterator) Next() bool    { return false }
+func (it *closedIterator) Error() error  { return it.err }
+func (it *closedIterator) Key() []byte   { return nil }
+func (it *closedIterator) Value() []byte { return nil }
+func (it *closedIterator) Release()      {}
+
+// secondarySnapshot is a snapshot holding a reference to the instance of the
+// database it was taken from.
+type secondarySnapshot struct {
+	ethdb.Snapshot
+	db   *secondaryInstance
+	once sync.Once
+}
+
+// Release implements ethdb.Snapshot, releasing the instance after the snapshot.
+func (snap *secondarySnapshot) Release() {
+	snap.Snapshot.Release()
+	snap.once.Do(func() { snap.db.release() })
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestSecondaryDatabase(t *testing.T) {
+	var (
+		dir     = t.TempDir()
+		ancient = filepath.Join(dir, "ancient")
+	)
+	primary, err := NewLevelDBDatabaseWithFreezer(dir, 16, 16, ancient, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer primary.Close()
+
+	var blocks []*types.Block
+	for i := uint64(0); i < 10; i++ {
+		header := &types.Header{Number: new(big.Int).SetUint64(i)}
+		if i > 0 {
+			header.ParentHash = blocks[i-1].Hash()
+		}
+		blocks = append(blocks, types.NewBlockWithHeader(header))
+	}
+	if _, err := WriteAncientBlocks(primary, blocks[:4], make([]types.Receipts, 4), big.NewInt(0)); err != nil {
+		t.Fatal(err)
+	}
+	WriteHeadBlockHash(primary, blocks[3].Hash())
+
+	// Open the secondary while the primary holds the locks
+	secondary, err := Open(OpenOptions{Directory: dir, AncientsDirectory: ancient, Secondary: true})
+	if err != nil {
+		t.Fatalf("failed to open secondary: %v", err)
+	}
+	defer secondary.Close()
+
+	if frozen, _ := secondary.Ancients(); frozen != 4 {
+		t.Fatalf("wrong ancient count: have %d, want 4", frozen)
+	}
+	if head := ReadHeadBlockHash(secondary); head != blocks[3].Hash() {
+		t.Fatalf("wrong head: have %x, want %x", head, blocks[3].Hash())
+	}
+	if err := secondary.Put([]byte("key"), []byte("value")); err == nil {
+		t.Fatal("secondary accepted write")
+	}
+	// Follow the primary through appends and tail truncation
+	if _, err := WriteAncientBlocks(primary, blocks[4:8], make([]types.Receipts, 4), big.NewInt(0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.TruncateTail(2); err != nil {
+		t.Fatal(err)
+	}
+	WriteHeadBlockHash(primary, blocks[7].Hash())
+
+	if frozen, _ := secondary.Ancients(); frozen != 4 {
+		t.Fatalf("ancients changed before refresh: have %d, want 4", frozen)
+	}
+	if err := Refresh(secondary); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	if frozen, _ := secondary.Ancients(); frozen != 8 {
+		t.Fatalf("wrong ancient count: have %d, want 8", frozen)
+	}
+	if tail, _ := secondary.Tail(); tail != 2 {
+		t.Fatalf("wrong tail: have %d, want 2", tail)
+	}
+	if head := ReadHeadBlockHash(secondary); head != blocks[7].Hash() {
+		t.Fatalf("wrong head: have %x, want %x", head, blocks[7].Hash())
+	}
+	for _, block := range blocks[:8] {
+		if header := ReadHeader(secondary, block.Hash(), block.NumberU64()); header == nil {
+			t.Errorf("header %d missing", block.NumberU64())
+		}
+	}
+	batch := secondary.NewBatch()
+	batch.Put([]byte("key"), []byte("value"))
+	if err := batch.Write(); err == nil {
+		t.Fatal("secondary accepted batch write")
+	}
+	// Refreshing a database that isn't a secondary fails
+	if err := Refresh(primary); err == nil {
+		t.Fatal("refreshed primary database")
+	}
+}
+
+// Tests that iterators and snapshots of a secondary remain usable after the
+// instance they were created on has been replaced by refreshes. Pebble panics
+// on accesses to a closed database, so the instance must stay open until they
+// are released.
+func TestSecondaryRefreshRelease(t *testing.T) {
+	if !PebbleEnabled {
+		t.Skip("pebble not supported on this platform")
+	}
+	dir := t.TempDir()
+	primary, err := NewPebbleDBDatabase(dir, 16, 16, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer primary.Close()
+
+	// Unsynced writes are buffered by the primary, compactions flush them
+	for i := byte(0); i < 10; i++ {
+		primary.Put([]byte{i}, []byte{i})
+	}
+	if err := primary.Compact(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	secondary, err := newSecondaryStore(OpenOptions{Directory: dir})
+	if err != nil {
+		t.Fatalf("failed to open secondary: %v", err)
+	}
+	it := secondary.NewIterator(nil, nil)
+	snap, err := secondary.NewSnapshot()
+	if err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+	for i := byte(10); i < 13; i++ {
+		primary.Put([]byte{i}, []byte{i})
+		if err := primary.Compact(nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := secondary.Refresh(); err != nil {
+			t.Fatalf("failed to refresh: %v", err)
+		}
+	}
+	var entries int
+	for it.Next() {
+		entries++
+	}
+	it.Release()
+	if entries != 10 {
+		t.Fatalf("iterated entry count mismatch: have %d, want 10", entries)
+	}
+	if has, _ := snap.Has([]byte{12}); has {
+		t.Fatal("snapshot observed later write")
+	}
+	snap.Release()
+
+	if has, _ := secondary.Has([]byte{12}); !has {
+		t.Fatal("refreshed secondary missing write")
+	}
+	if err := secondary.Close(); err != nil {
+		t.Fatalf("failed to close secondary: %v", err)
+	}
+}
+
+// Tests that reads of a secondary survive the primary deleting table files the
+// instance hasn't opened yet, and that the store can't be read once closed.
+func TestSecondaryDeletedTables(t *testing.T) {
+	dir := t.TempDir()
+	primary, err := NewLevelDBDatabase(dir, 16, 16, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := byte(0); i < 10; i++ {
+		primary.Put([]byte{i}, []byte{i})
+	}
+	if err := primary.Compact(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	secondary, err := newSecondaryStore(OpenOptions{Directory: dir})
+	if err != nil {
+		t.Fatalf("failed to open secondary: %v", err)
+	}
+	// Rewrite the tables of the primary before the secondary accessed them, the
+	// obsolete ones being deleted when the primary is closed
+	for i := byte(0); i < 20; i++ {
+		primary.Put([]byte{i}, []byte{i})
+	}
+	if err := primary.Compact(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	primary.Close()
+
+	if value, err := secondary.Get([]byte{1}); err != nil || !bytes.Equal(value, []byte{1}) {
+		t.Fatalf("value mismatch: have %x (%v), want %x", value, err, []byte{1})
+	}
+	if has, err := secondary.Has([]byte{2}); err != nil || !has {
+		t.Fatalf("key missing: %v", err)
+	}
+	if _, err := secondary.Get([]byte{20}); err == nil {
+		t.Fatal("missing key retrieved")
+	}
+	if err := secondary.Close(); err != nil {
+		t.Fatalf("failed to close secondary: %v", err)
+	}
+	if _, err := secondary.Get([]byte{1}); err != errSecondaryClosed {
+		t.Errorf("closed get error mismatch: have %v, want %v", err, errSecondaryClosed)
+	}
+	if _, err := secondary.Has([]byte{1}); err != errSecondaryClosed {
+		t.Errorf("closed has error mismatch: have %v, want %v", err, errSecondaryClosed)
+	}
+	it := secondary.NewIterator(nil, nil)
+	if it.Next() || it.Error() != errSecondaryClosed {
+		t.Errorf("closed iterator error mismatch: have %v, want %v", it.Error(), errSecondaryClosed)
+	}
+	it.Release()
+}
@@ -0,0 +1,318 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/crypto"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb"
+	"github.com/Altcoinchain/go-altcoinchain/log"
+	"github.com/Altcoinchain/go-altcoinchain/rlp"
+	"github.com/Altcoinchain/go-altcoinchain/trie"
+)
+
+// The items of a block verified by the scrubber.
+const (
+	ScrubHash     = "hash"     // Canonical hash of the block
+	ScrubHeader   = "header"   // Block header
+	ScrubBody     = "body"     // Transactions and uncles
+	ScrubReceipts = "receipts" // Transaction receipts
+	ScrubTd       = "td"       // Total difficulty
+)
+
+const (
+	// scrubProgressInterval is the number of blocks after which the scrub
+	// progress is persisted.
+	scrubProgressInterval = 1024
+
+	// scrubMaxRepairs is the number of times a block is rechecked after its
+	// issues were repaired, as fixing one item may uncover problems of another.
+	scrubMaxRepairs = 4
+)
+
+// ScrubIssue describes an inconsistency found in the stored data of a block.
+type ScrubIssue struct {
+	Number uint64      // Number of the affected block
+	Hash   common.Hash // Canonical hash the data should belong to, zero if unknown
+	Kind   string      // Affected item, one of the Scrub* constants
+	Frozen bool        // Whether the block is stored in the freezer
+	Err    error       // Description of the inconsistency
+}
+
+// String implements fmt.Stringer.
+func (i *ScrubIssue) String() string {
+	store := "key-value store"
+	if i.Frozen {
+		store = "freezer"
+	}
+	return fmt.Sprintf("block %d %s (%s): %v", i.Number, i.Kind, store, i.Err)
+}
+
+// Scrubber verifies that the canonical chain data in the key-value store and
+// the freezer is consistent: canonical hashes match the headers and link them
+// up, bodies and receipts match the roots of the headers and the total
+// difficulties add up.
+type Scrubber struct {
+	db   ethdb.Database
+	rate int // Maximum number of blocks checked per second, zero for unlimited
+}
+
+// NewScrubber creates a scrubber checking at most rate blocks per second, or
+// as fast as possible if zero.
+func NewScrubber(db ethdb.Database, rate int) *Scrubber {
+	return &Scrubber{db: db, rate: rate}
+}
+
+// Run checks the canonical blocks from start up to and including end, passing
+// every issue found to handle. If handle reports to have repaired any issue,
+// the block is checked again. The progress is persisted regularly and when the
+// run is interrupted by closing quit, so that it can be resumed from
+// rawdb.ReadScrubProgress. It returns the number of the next block to check,
+// which is end+1 if the whole range was checked.
+func (s *Scrubber) Run(start, end uint64, handle func(*ScrubIssue) bool, quit <-chan struct{}) uint64 {
+	var (
+		started = time.Now()
+		logged  = time.Now()
+		issues  int
+	)
+	for number := start; number <= end; number++ {
+		select {
+		case <-quit:
+			rawdb.WriteScrubProgress(s.db, number)
+			log.Info("Scrubbing chain data interrupted", "next", number, "end", end, "issues", issues)
+			return number
+		default:
+		}
+		for i := 0; i < scrubMaxRepairs; i++ {
+			var repaired bool
+			for _, issue := range s.CheckBlock(number) {
+				issues++
+				if handle(issue) {
+					repaired = true
+				}
+			}
+			if !repaired {
+				break
+			}
+		}
+		if (number-start+1)%scrubProgressInterval == 0 {
+			rawdb.WriteScrubProgress(s.db, number+1)
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Scrubbing chain data", "number", number, "end", end, "issues", issues, "elapsed", common.PrettyDuration(time.Since(started)))
+			logged = time.Now()
+		}
+		// Throttle the checks if a rate limit was requested
+		if s.rate > 0 {
+			wait := time.Duration(number-start+1)*time.Second/time.Duration(s.rate) - time.Since(started)
+			if wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-quit:
+				}
+			}
+		}
+	}
+	rawdb.DeleteScrubProgress(s.db)
+	log.Info("Scrubbed chain data", "start", start, "end", end, "issues", issues, "elapsed", common.PrettyDuration(time.Since(started)))
+	return end + 1
+}
+
+// CheckBlock verifies the data stored for the canonical block with the given
+// number, returning the issues found. Issues with the canonical hash or the
+// header prevent checking the rest of the block.
+func (s *Scrubber) CheckBlock(number uint64) []*ScrubIssue {
+	var (
+		issues []*ScrubIssue
+		frozen bool
+	)
+	if ancients, err := s.db.Ancients(); err == nil {
+		frozen = number < ancients
+	}
+	report := func(kind string, hash common.Hash, err error) {
+		issues = append(issues, &ScrubIssue{Number: number, Hash: hash, Kind: kind, Frozen: frozen, Err: err})
+	}
+	// The canonical hash must be the parent hash of the next block, which tells
+	// a corrupted hash apart from a corrupted header.
+	hash := rawdb.ReadCanonicalHash(s.db, number)
+	if next := s.nextParentHash(number); next != (common.Hash{}) && next != hash {
+		report(ScrubHash, next, fmt.Errorf("canonical hash %x, next block's parent hash %x", hash, next))
+		return issues
+	}
+	if hash == (common.Hash{}) {
+		report(ScrubHash, hash, errors.New("missing canonical hash"))
+		return issues
+	}
+	header, err := s.readHeader(hash, number)
+	if err != nil {
+		report(ScrubHeader, hash, err)
+		return issues
+	}
+	// Bodies and receipts are only checked above the expired history, except
+	// for the genesis body which is always kept.
+	var body *types.Body
+	if number == 0 || number >= rawdb.ReadHistoryTail(s.db) {
+		if blob := rawdb.ReadBodyRLP(s.db, hash, number); len(blob) == 0 {
+			report(ScrubBody, hash, errors.New("missing body"))
+		} else {
+			body = new(types.Body)
+			if err := rlp.DecodeBytes(blob, body); err != nil {
+				report(ScrubBody, hash, fmt.Errorf("invalid body: %v", err))
+				body = nil
+			} else if err := VerifyBody(header, body); err != nil {
+				report(ScrubBody, hash, err)
+				body = nil
+			}
+		}
+		if err := s.checkReceipts(header, body); err != nil {
+			report(ScrubReceipts, hash, err)
+		}
+	}
+	if err := s.checkTd(header); err != nil {
+		report(ScrubTd, hash, err)
+	}
+	return issues
+}
+
+// nextParentHash returns the parent hash of the canonical block following the
+// given one, or the zero hash if that block is missing or corrupted itself.
+func (s *Scrubber) nextParentHash(number uint64) common.Hash {
+	hash := rawdb.ReadCanonicalHash(s.db, number+1)
+	if hash == (common.Hash{}) {
+		return common.Hash{}
+	}
+	header, err := s.readHeader(hash, number+1)
+	if err != nil {
+		return common.Hash{}
+	}
+	return header.ParentHash
+}
+
+// readHeader retrieves a header, verifying that it matches its hash and number.
+// Frozen headers which don't match their hash can't be distinguished from
+// missing ones, as they are never returned by the accessor.
+func (s *Scrubber) readHeader(hash common.Hash, number uint64) (*types.Header, error) {
+	blob := rawdb.ReadHeaderRLP(s.db, hash, number)
+	if len(blob) == 0 {
+		return nil, errors.New("missing header")
+	}
+	if have := crypto.Keccak256Hash(blob); have != hash {
+		return nil, fmt.Errorf("header hash mismatch: have %x, want %x", have, hash)
+	}
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(blob, header); err != nil {
+		return nil, fmt.Errorf("invalid header: %v", err)
+	}
+	if header.Number == nil || header.Number.Uint64() != number {
+		return nil, fmt.Errorf("header number mismatch: have %v, want %d", header.Number, number)
+	}
+	return header, nil
+}
+
+// checkReceipts verifies the stored receipts of a block. The transaction types
+// aren't stored with the receipts, so the receipt root can only be checked if
+// the body is available.
+func (s *Scrubber) checkReceipts(header *types.Header, body *types.Body) error {
+	number := header.Number.Uint64()
+	blob := rawdb.ReadReceiptsRLP(s.db, header.Hash(), number)
+	if len(blob) == 0 {
+		return errors.New("missing receipts")
+	}
+	var stored []*types.ReceiptForStorage
+	if err := rlp.DecodeBytes(blob, &stored); err != nil {
+		return fmt.Errorf("invalid receipts: %v", err)
+	}
+	if body == nil {
+		return nil
+	}
+	if len(stored) != len(body.Transactions) {
+		return fmt.Errorf("receipt count mismatch: have %d, want %d", len(stored), len(body.Transactions))
+	}
+	receipts := make(types.Receipts, len(stored))
+	for i, receipt := range stored {
+		receipts[i] = (*types.Receipt)(receipt)
+		receipts[i].Type = body.Transactions[i].Type()
+	}
+	return VerifyReceipts(header, receipts)
+}
+
+// checkTd verifies that the total difficulty of a block is the one of its
+// parent plus its own difficulty.
+func (s *Scrubber) checkTd(header *types.Header) error {
+	number := header.Number.Uint64()
+	td, err := s.readTd(header.Hash(), number)
+	if err != nil {
+		return err
+	}
+	want := new(big.Int).Set(header.Difficulty)
+	if number > 0 {
+		parent, err := s.readTd(header.ParentHash, number-1)
+		if err != nil {
+			// Reported for the parent block
+			return nil
+		}
+		want.Add(want, parent)
+	}
+	if td.Cmp(want) != 0 {
+		return fmt.Errorf("total difficulty mismatch: have %v, want %v", td, want)
+	}
+	return nil
+}
+
+// readTd retrieves the total difficulty of a block.
+func (s *Scrubber) readTd(hash common.Hash, number uint64) (*big.Int, error) {
+	blob := rawdb.ReadTdRLP(s.db, hash, number)
+	if len(blob) == 0 {
+		return nil, errors.New("missing total difficulty")
+	}
+	td := new(big.Int)
+	if err := rlp.DecodeBytes(blob, td); err != nil {
+		return nil, fmt.Errorf("invalid total difficulty: %v", err)
+	}
+	return td, nil
+}
+
+// VerifyBody checks that a block body matches the transaction and uncle roots
+// of its header.
+func VerifyBody(header *types.Header, body *types.Body) error {
+	if hash := types.CalcUncleHash(body.Uncles); hash != header.UncleHash {
+		return fmt.Errorf("uncle root hash mismatch: have %x, want %x", hash, header.UncleHash)
+	}
+	if hash := types.DeriveSha(types.Transactions(body.Transactions), trie.NewStackTrie(nil)); hash != header.TxHash {
+		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, header.TxHash)
+	}
+	return nil
+}
+
+// VerifyReceipts checks that the receipts of a block match the receipt root and
+// the bloom filter of its header. The receipt types must be set.
+func VerifyReceipts(header *types.Header, receipts types.Receipts) error {
+	if hash := types.DeriveSha(receipts, trie.NewStackTrie(nil)); hash != header.ReceiptHash {
+		return fmt.Errorf("receipt root hash mismatch: have %x, want %x", hash, header.ReceiptHash)
+	}
+	if bloom := types.CreateBloom(receipts); bloom != header.Bloom {
+		return fmt.Errorf("bloom mismatch: have %x, want %x", bloom, header.Bloom)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb"
+	"github.com/Altcoinchain/go-altcoinchain/trie"
+)
+
+// newScrubTestChain writes a chain of blocks with one transaction each, the
+// first frozen ones to the freezer and the rest to the key-value store.
+func newScrubTestChain(t *testing.T, db ethdb.Database, frozen, n int) ([]*types.Block, []types.Receipts) {
+	var (
+		blocks   []*types.Block
+		receipts []types.Receipts
+		parent   common.Hash
+	)
+	for i := 0; i < n; i++ {
+		tx := types.NewTransaction(uint64(i), common.Address{0x01}, big.NewInt(1), 21000, big.NewInt(1), nil)
+		receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{}}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(int64(i)), Difficulty: big.NewInt(2)}
+		block := types.NewBlock(header, []*types.Transaction{tx}, nil, []*types.Receipt{receipt}, trie.NewStackTrie(nil))
+		blocks, receipts = append(blocks, block), append(receipts, types.Receipts{receipt})
+		parent = block.Hash()
+	}
+	if frozen > 0 {
+		if _, err := rawdb.WriteAncientBlocks(db, blocks[:frozen], receipts[:frozen], big.NewInt(2)); err != nil {
+			t.Fatalf("failed to write ancient blocks: %v", err)
+		}
+	}
+	for i := frozen; i < n; i++ {
+		rawdb.WriteBlock(db, blocks[i])
+		rawdb.WriteReceipts(db, blocks[i].Hash(), uint64(i), receipts[i])
+		rawdb.WriteTd(db, blocks[i].Hash(), uint64(i), big.NewInt(int64(2*(i+1))))
+		rawdb.WriteCanonicalHash(db, blocks[i].Hash(), uint64(i))
+	}
+	return blocks, receipts
+}
+
+// Tests that the scrubber detects corrupted block data and rechecks the blocks
+// whose issues were repaired.
+func TestScrubber(t *testing.T) {
+	db, err := rawdb.NewDatabaseWithFreezer(rawdb.NewMemoryDatabase(), t.TempDir(), "", false)
+	if err != nil {
+		t.Fatalf("failed to create database with ancient backend: %v", err)
+	}
+	defer db.Close()
+	blocks, receipts := newScrubTestChain(t, db, 4, 8)
+
+	scrubber := NewScrubber(db, 0)
+	for i := range blocks {
+		if issues := scrubber.CheckBlock(uint64(i)); len(issues) != 0 {
+			t.Fatalf("block %d: unexpected issues %v", i, issues)
+		}
+	}
+	// Corrupt frozen and recent data
+	rawdb.RepairTd(db, blocks[2].Hash(), 2, big.NewInt(1))
+	rawdb.WriteCanonicalHash(db, common.Hash{0x01}, 5)
+	rawdb.DeleteBody(db, blocks[6].Hash(), 6)
+
+	want := map[uint64]string{2: ScrubTd, 5: ScrubHash, 6: ScrubBody}
+	handle := func(issue *ScrubIssue) bool {
+		if want[issue.Number] != issue.Kind {
+			t.Errorf("block %d: unexpected %s issue: %v", issue.Number, issue.Kind, issue.Err)
+		}
+		if issue.Frozen != (issue.Number < 4) {
+			t.Errorf("block %d: frozen flag mismatch", issue.Number)
+		}
+		block := blocks[issue.Number]
+		switch issue.Kind {
+		case ScrubHash:
+			if issue.Hash != block.Hash() {
+				t.Errorf("block %d: expected hash mismatch: have %x, want %x", issue.Number, issue.Hash, block.Hash())
+			}
+			err = rawdb.RepairCanonicalHash(db, issue.Hash, issue.Number)
+		case ScrubBody:
+			err = rawdb.RepairBody(db, block.Hash(), issue.Number, block.Body())
+		case ScrubTd:
+			err = rawdb.RepairTd(db, block.Hash(), issue.Number, big.NewInt(6))
+		}
+		if err != nil {
+			t.Fatalf("block %d: failed to repair %s: %v", issue.Number, issue.Kind, err)
+		}
+		delete(want, issue.Number)
+		return true
+	}
+	if next := scrubber.Run(0, uint64(len(blocks)-1), handle, nil); next != uint64(len(blocks)) {
+		t.Fatalf("scrub ended early: next %d", next)
+	}
+	if len(want) != 0 {
+		t.Errorf("issues not found: %v", want)
+	}
+	for i := range blocks {
+		if issues := scrubber.CheckBlock(uint64(i)); len(issues) != 0 {
+			t.Errorf("block %d: issues left after repair: %v", i, issues)
+		}
+	}
+	// Receipts are verified against the header
+	rawdb.WriteReceipts(db, blocks[7].Hash(), 7, append(receipts[7], receipts[6]...))
+	if issues := scrubber.CheckBlock(7); len(issues) != 1 || issues[0].Kind != ScrubReceipts {
+		t.Errorf("corrupted receipts not detected: %v", issues)
+	}
+}
+
+// Tests that an interrupted scrub persists its progress.
+func TestScrubberInterrupt(t *testing.T) {
+	db := rawdb.NewMemoryDatabase()
+	blocks, _ := newScrubTestChain(t, db, 0, 4)
+
+	quit := make(chan struct{})
+	close(quit)
+	if next := NewScrubber(db, 0).Run(1, uint64(len(blocks)-1), func(*ScrubIssue) bool { return false }, quit); next != 1 {
+		t.Fatalf("next block mismatch: have %d, want 1", next)
+	}
+	if progress := rawdb.ReadScrubProgress(db); progress == nil || *progress != 1 {
+		t.Fatalf("progress not persisted: %v", progress)
+	}
+}
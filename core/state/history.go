@@ -0,0 +1,163 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// historicAccount is the content of an account in a rebuilt state, gathered
+// from the state diffs reverted to reach it.
+type historicAccount struct {
+	address common.Address
+	account *types.StateAccount // Account in the rebuilt state, nil if it didn't exist
+
+	// cleared is set if the storage was dropped by one of the reverted blocks,
+	// so its previous content can't be rebuilt from the newer storage.
+	cleared bool
+	storage map[common.Hash]common.Hash // Slots changed since the rebuilt state, with their previous values
+}
+
+// historicDB is a state database serving a state rebuilt by reverting state
+// diffs. The tries unchanged since the rebuilt state are read from the wrapped
+// database, the changed ones are rebuilt on demand.
+type historicDB struct {
+	Database
+
+	root     common.Hash
+	accounts Trie                             // Rebuilt account trie
+	base     Trie                             // Account trie of the newer state the rebuilt one derives from
+	changed  map[common.Hash]*historicAccount // Accounts changed since the rebuilt state, by address hash
+	storages map[common.Hash]Trie             // Rebuilt storage tries, by address hash
+}
+
+// NewHistoricState rebuilds the state with the given root from a newer state
+// available in the database, by reverting the state diffs of the blocks in
+// between. The diffs are ordered from the newest one, the diff of the block
+// producing the base state, to the oldest one, the diff of the block following
+// the rebuilt state.
+//
+// The returned state is only meant to be read. The storage dropped by any of
+// the reverted blocks, e.g. by a self destruct, is only accessible if it's
+// still present in the database.
+func NewHistoricState(root common.Hash, db Database, base common.Hash, diffs []*types.StateDiff) (*StateDB, error) {
+	changed := make(map[common.Hash]*historicAccount)
+	for _, diff := range diffs {
+		for _, d := range diff.Accounts {
+			addrHash := crypto.Keccak256Hash(d.Address.Bytes())
+			acc := changed[addrHash]
+			if acc == nil {
+				acc = &historicAccount{address: d.Address, storage: make(map[common.Hash]common.Hash)}
+				changed[addrHash] = acc
+			}
+			acc.account = d.Pre
+			if acc.cleared {
+				continue
+			}
+			if d.StorageCleared {
+				acc.cleared = true
+				continue
+			}
+			for _, slot := range d.Storage {
+				acc.storage[slot.Key] = slot.Pre
+			}
+		}
+	}
+	baseTrie, err := db.OpenTrie(base)
+	if err != nil {
+		return nil, err
+	}
+	accounts := db.CopyTrie(baseTrie)
+	for _, acc := range changed {
+		if acc.account == nil {
+			err = accounts.TryDeleteAccount(acc.address.Bytes())
+		} else {
+			err = accounts.TryUpdateAccount(acc.address.Bytes(), acc.account)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if have := accounts.Hash(); have != root {
+		return nil, fmt.Errorf("reverted state root mismatch: have %x, want %x", have, root)
+	}
+	return New(root, &historicDB{
+		Database: db,
+		root:     root,
+		accounts: accounts,
+		base:     baseTrie,
+		changed:  changed,
+		storages: make(map[common.Hash]Trie),
+	}, nil)
+}
+
+// OpenTrie opens the rebuilt account trie.
+func (db *historicDB) OpenTrie(root common.Hash) (Trie, error) {
+	if root != db.root {
+		return db.Database.OpenTrie(root)
+	}
+	return db.CopyTrie(db.accounts), nil
+}
+
+// OpenStorageTrie opens the storage trie of an account, rebuilding it if it was
+// changed since the rebuilt state and is not available anymore.
+func (db *historicDB) OpenStorageTrie(addrHash, root common.Hash) (Trie, error) {
+	tr, err := db.Database.OpenStorageTrie(addrHash, root)
+	if err == nil {
+		return tr, nil
+	}
+	if tr := db.storages[addrHash]; tr != nil && tr.Hash() == root {
+		return db.CopyTrie(tr), nil
+	}
+	acc := db.changed[addrHash]
+	if acc == nil || acc.cleared {
+		return nil, err
+	}
+	// Revert the changed slots of the newer storage trie
+	base, err := db.base.TryGetAccount(acc.address.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	baseRoot := emptyRoot
+	if base != nil {
+		baseRoot = base.Root
+	}
+	if tr, err = db.Database.OpenStorageTrie(addrHash, baseRoot); err != nil {
+		return nil, err
+	}
+	for key, value := range acc.storage {
+		if value == (common.Hash{}) {
+			err = tr.TryDelete(key[:])
+		} else {
+			v, _ := rlp.EncodeToBytes(common.TrimLeftZeroes(value[:]))
+			err = tr.TryUpdate(key[:], v)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if have := tr.Hash(); have != root {
+		return nil, fmt.Errorf("reverted storage root of %x mismatch: have %x, want %x", acc.address, have, root)
+	}
+	db.storages[addrHash] = tr
+	return db.CopyTrie(tr), nil
+}
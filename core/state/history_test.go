@@ -0,0 +1,170 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// Tests that older states are rebuilt by reverting the state diffs on top of a
+// newer state, with proofs verifying against the old roots.
+func TestHistoricState(t *testing.T) {
+	var (
+		db        = NewDatabase(rawdb.NewMemoryDatabase())
+		recreated = common.Address{0x05}
+		roots     []common.Hash
+		diffs     []*types.StateDiff
+
+		// The storage dropped in a block is lost in the states before it
+		dropped = map[common.Address]int{recreated: 2, {0x14}: 4}
+	)
+	commit := func(state *StateDB) {
+		root, err := state.Commit(true)
+		if err != nil {
+			t.Fatalf("failed to commit state: %v", err)
+		}
+		if err := db.TrieDB().Commit(root, false, nil); err != nil {
+			t.Fatalf("failed to commit trie: %v", err)
+		}
+		roots = append(roots, root)
+		diffs = append(diffs, state.StateDiff())
+	}
+	state, _ := New(common.Hash{}, db, nil)
+	state.TrackStateDiff()
+	for i := byte(1); i <= 50; i++ {
+		addr := common.Address{i}
+		state.SetBalance(addr, big.NewInt(int64(i)))
+		if i%5 == 0 {
+			state.SetCode(addr, []byte{i})
+			for j := byte(1); j <= 20; j++ {
+				state.SetState(addr, common.Hash{j}, common.Hash{i, j})
+			}
+		}
+	}
+	commit(state)
+
+	// Apply a few blocks of changes, covering created, deleted and recreated
+	// accounts and changed and deleted storage slots
+	for n := byte(1); n <= 4; n++ {
+		state, _ = New(roots[len(roots)-1], db, nil)
+		state.TrackStateDiff()
+		for i := n; i <= 50; i += 7 {
+			addr := common.Address{i}
+			state.AddBalance(addr, big.NewInt(1))
+			if i%5 == 0 && addr != recreated {
+				state.SetState(addr, common.Hash{n}, common.Hash{})
+				state.SetState(addr, common.Hash{n + 20}, common.Hash{n})
+			}
+		}
+		state.SetNonce(common.Address{0x80, n}, 1)
+		state.Suicide(common.Address{0x10 + n})
+		if n == 2 {
+			state.Suicide(recreated)
+			state.Finalise(true)
+			state.SetBalance(recreated, big.NewInt(1))
+			state.SetState(recreated, common.Hash{0xff}, common.Hash{0xff})
+		}
+		commit(state)
+	}
+	// Keep only the newest state in a fresh database
+	diskdb := memorydb.New()
+	for it := NewNodeIterator(state); it.Next(); {
+		if it.Hash == (common.Hash{}) {
+			continue
+		}
+		if blob, err := db.TrieDB().Node(it.Hash); err == nil {
+			rawdb.WriteTrieNode(diskdb, it.Hash, blob)
+		} else {
+			rawdb.WriteCode(diskdb, it.Hash, it.code)
+		}
+	}
+	head := roots[len(roots)-1]
+	for i := 0; i < len(roots)-1; i++ {
+		var reverted []*types.StateDiff
+		for j := len(diffs) - 1; j > i; j-- {
+			reverted = append(reverted, diffs[j])
+		}
+		historic, err := NewHistoricState(roots[i], NewDatabase(rawdb.NewDatabase(diskdb)), head, reverted)
+		if err != nil {
+			t.Fatalf("state %d: failed to rebuild: %v", i, err)
+		}
+		want, _ := New(roots[i], db, nil)
+		for k := 1; k <= 0x90; k++ {
+			addr := common.Address{byte(k)}
+			if k > 0x80 {
+				addr = common.Address{0x80, byte(k - 0x80)}
+			}
+			if have, want := historic.GetBalance(addr), want.GetBalance(addr); have.Cmp(want) != 0 {
+				t.Fatalf("state %d account %x: balance mismatch: have %v, want %v", i, addr, have, want)
+			}
+			if have, want := historic.GetNonce(addr), want.GetNonce(addr); have != want {
+				t.Fatalf("state %d account %x: nonce mismatch: have %v, want %v", i, addr, have, want)
+			}
+			proof, err := historic.GetProof(addr)
+			if err != nil {
+				t.Fatalf("state %d account %x: failed to prove: %v", i, addr, err)
+			}
+			if _, err := trie.VerifyProof(roots[i], crypto.Keccak256(addr.Bytes()), proofDB(proof)); err != nil {
+				t.Fatalf("state %d account %x: invalid proof: %v", i, addr, err)
+			}
+			if block, ok := dropped[addr]; ok && i < block || k%5 != 0 || k > 50 {
+				continue
+			}
+			root := want.StorageTrie(addr).Hash()
+			for j := byte(1); j <= 30; j++ {
+				slot := common.Hash{j}
+				if have, want := historic.GetState(addr, slot), want.GetState(addr, slot); have != want {
+					t.Fatalf("state %d account %x slot %x: value mismatch: have %x, want %x", i, addr, slot, have, want)
+				}
+				proof, err := historic.GetStorageProof(addr, slot)
+				if err != nil {
+					t.Fatalf("state %d account %x slot %x: failed to prove: %v", i, addr, slot, err)
+				}
+				if _, err := trie.VerifyProof(root, crypto.Keccak256(slot.Bytes()), proofDB(proof)); err != nil {
+					t.Fatalf("state %d account %x slot %x: invalid proof: %v", i, addr, slot, err)
+				}
+			}
+		}
+		if err := historic.Error(); err != nil {
+			t.Fatalf("state %d: unexpected error: %v", i, err)
+		}
+		for addr, block := range dropped {
+			if i < block {
+				if historic.StorageTrie(addr); historic.Error() == nil {
+					t.Fatalf("state %d account %x: dropped storage accessible", i, addr)
+				}
+			}
+		}
+	}
+}
+
+// proofDB collects a proof into a database to verify it.
+func proofDB(proof [][]byte) *memorydb.Database {
+	db := memorydb.New()
+	for _, node := range proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	return db
+}
@@ -0,0 +1,363 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package layout
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+// DefaultLimit is the default maximum number of elements decoded for a single
+// dynamic array or mapping.
+const DefaultLimit = 256
+
+// Storage provides access to the storage of a contract.
+type Storage interface {
+	// Slot retrieves the value of the storage slot with the given key.
+	Slot(key common.Hash) (common.Hash, error)
+
+	// Preimage retrieves the preimage of the given hash, or nil if unknown.
+	Preimage(hash common.Hash) []byte
+}
+
+// Value is a decoded storage value. Primitives, strings and bytes hold their
+// value directly, structs, arrays and mappings are made up of members.
+type Value struct {
+	Label     string      `json:"label,omitempty"`  // Name of the variable or struct member
+	Key       string      `json:"key,omitempty"`    // Key of the mapping entry
+	Type      string      `json:"type"`             // Solidity type
+	Slot      common.Hash `json:"slot"`             // Slot the value starts at
+	Offset    uint64      `json:"offset,omitempty"` // Offset in bytes within the slot, from the right
+	Value     string      `json:"value,omitempty"`
+	Length    *uint64     `json:"length,omitempty"`    // Length of dynamic arrays, bytes and strings
+	Members   []*Value    `json:"members,omitempty"`   // Struct members, array elements or mapping entries
+	Truncated bool        `json:"truncated,omitempty"` // Whether members or data were left out due to the limit
+}
+
+// mappingKey is the key of a mapping entry, recovered from the preimage of the
+// entry's location.
+type mappingKey []byte
+
+// Decoder decodes the storage of a contract according to its layout.
+//
+// Mapping entries can't be enumerated from the layout alone. They are recovered
+// from the preimages of the locations of the slots present in storage, so the
+// SHA3 preimages of the transactions writing them need to have been recorded.
+type Decoder struct {
+	layout  *Layout
+	storage Storage
+	limit   uint64
+
+	mappings map[common.Hash][]mappingKey // Known entry keys by mapping slot
+}
+
+// NewDecoder creates a decoder for the storage of a contract, indexing the
+// mapping entries found among the given keys of its non-empty slots. A limit
+// of zero decodes all the elements of arrays and mappings.
+func NewDecoder(layout *Layout, storage Storage, slots []common.Hash, limit uint64) *Decoder {
+	d := &Decoder{
+		layout:   layout,
+		storage:  storage,
+		limit:    limit,
+		mappings: make(map[common.Hash][]mappingKey),
+	}
+	d.indexMappings(slots)
+	return d
+}
+
+// indexMappings recovers the mapping entries containing the given slots. The
+// location of an entry is keccak256(key . slot), so an entry is found if the
+// preimage of a location at most a mapping value's size before a slot is known.
+// The mapping slot itself is in turn looked up for nested mappings.
+func (d *Decoder) indexMappings(slots []common.Hash) {
+	var (
+		span    = d.layout.mappingSpan()
+		visited = make(map[common.Hash]struct{})
+		keys    = make(map[common.Hash]map[string]struct{})
+		queue   = append([]common.Hash{}, slots...)
+	)
+	for len(queue) > 0 {
+		slot := queue[0]
+		queue = queue[1:]
+
+		for i := uint64(0); i < span; i++ {
+			location := offsetSlot(slot, -int64(i))
+			if _, ok := visited[location]; ok {
+				continue
+			}
+			visited[location] = struct{}{}
+
+			// Preimages of 32 bytes are array or bytes locations, not entries
+			preimage := d.storage.Preimage(location)
+			if len(preimage) <= common.HashLength {
+				continue
+			}
+			var (
+				key    = preimage[:len(preimage)-common.HashLength]
+				parent = common.BytesToHash(preimage[len(preimage)-common.HashLength:])
+			)
+			if keys[parent] == nil {
+				keys[parent] = make(map[string]struct{})
+			}
+			keys[parent][string(key)] = struct{}{}
+			queue = append(queue, parent)
+		}
+	}
+	for slot, set := range keys {
+		list := make([]mappingKey, 0, len(set))
+		for key := range set {
+			list = append(list, mappingKey(key))
+		}
+		sort.Slice(list, func(i, j int) bool { return bytes.Compare(list[i], list[j]) < 0 })
+		d.mappings[slot] = list
+	}
+}
+
+// Decode decodes all the state variables of the contract.
+func (d *Decoder) Decode() ([]*Value, error) {
+	values := make([]*Value, 0, len(d.layout.Storage))
+	for _, v := range d.layout.Storage {
+		value, err := d.decode(v.Type, v.slot, v.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("variable %s: %v", v.Label, err)
+		}
+		value.Label = v.Label
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// decode decodes the value of the given type stored at slot and offset.
+func (d *Decoder) decode(name string, slot common.Hash, offset uint64) (*Value, error) {
+	typ := d.layout.Types[name]
+	value := &Value{Type: typ.Label, Slot: slot, Offset: offset}
+
+	switch typ.Encoding {
+	case encodingInplace:
+		switch {
+		case len(typ.Members) > 0:
+			for _, member := range typ.Members {
+				child, err := d.decode(member.Type, addSlots(slot, member.slot), member.Offset)
+				if err != nil {
+					return nil, err
+				}
+				child.Label = member.Label
+				value.Members = append(value.Members, child)
+			}
+		case typ.Base != "":
+			if err := d.decodeArray(value, typ.Base, slot, typ.length); err != nil {
+				return nil, err
+			}
+		default:
+			word, err := d.storage.Slot(slot)
+			if err != nil {
+				return nil, err
+			}
+			value.Value = formatValue(typ.Label, word[32-offset-typ.size:32-offset])
+		}
+
+	case encodingMapping:
+		keys := d.mappings[slot]
+		for _, key := range keys {
+			if d.limit > 0 && uint64(len(value.Members)) == d.limit {
+				value.Truncated = true
+				break
+			}
+			formatted, ok := d.formatKey(typ.Key, key)
+			if !ok {
+				continue // Entry of a mapping with a different key type at the same slot
+			}
+			location := crypto.Keccak256Hash(key, slot[:])
+			child, err := d.decode(typ.Value, location, 0)
+			if err != nil {
+				return nil, err
+			}
+			child.Key = formatted
+			value.Members = append(value.Members, child)
+		}
+
+	case encodingDynamicArray:
+		word, err := d.storage.Slot(slot)
+		if err != nil {
+			return nil, err
+		}
+		length := new(big.Int).SetBytes(word[:])
+		if !length.IsUint64() {
+			return nil, fmt.Errorf("invalid array length %v at slot %x", length, slot)
+		}
+		value.Length = new(uint64)
+		*value.Length = length.Uint64()
+		if err := d.decodeArray(value, typ.Base, crypto.Keccak256Hash(slot[:]), *value.Length); err != nil {
+			return nil, err
+		}
+
+	case encodingBytes:
+		data, length, partial, err := d.decodeBytes(slot)
+		if err != nil {
+			return nil, err
+		}
+		value.Length, value.Truncated = &length, partial
+		if typ.Label == "string" {
+			value.Value = string(data)
+		} else {
+			value.Value = hexutil.Encode(data)
+		}
+	}
+	return value, nil
+}
+
+// decodeArray decodes the elements of an array starting at slot. Elements of
+// up to 16 bytes are packed into slots, larger ones start a new slot each.
+func (d *Decoder) decodeArray(value *Value, base string, slot common.Hash, length uint64) error {
+	if d.limit > 0 && length > d.limit {
+		length, value.Truncated = d.limit, true
+	}
+	typ := d.layout.Types[base]
+	for i := uint64(0); i < length; i++ {
+		var (
+			location common.Hash
+			offset   uint64
+		)
+		if typ.size <= 16 && typ.Encoding == encodingInplace && len(typ.Members) == 0 && typ.Base == "" {
+			perSlot := 32 / typ.size
+			location, offset = offsetSlot(slot, int64(i/perSlot)), (i%perSlot)*typ.size
+		} else {
+			location = offsetSlot(slot, int64(i*typ.slots()))
+		}
+		child, err := d.decode(base, location, offset)
+		if err != nil {
+			return err
+		}
+		value.Members = append(value.Members, child)
+	}
+	return nil
+}
+
+// decodeBytes decodes a bytes or string value. Short values are stored along
+// with their length in the slot itself, long ones in consecutive slots at the
+// hash of the slot.
+func (d *Decoder) decodeBytes(slot common.Hash) ([]byte, uint64, bool, error) {
+	word, err := d.storage.Slot(slot)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if word[31]&1 == 0 {
+		length := uint64(word[31] / 2)
+		if length > 31 {
+			return nil, 0, false, fmt.Errorf("invalid short bytes length %d at slot %x", length, slot)
+		}
+		return common.CopyBytes(word[:length]), length, false, nil
+	}
+	size := new(big.Int).SetBytes(word[:])
+	size.Rsh(size, 1)
+	if !size.IsUint64() {
+		return nil, 0, false, fmt.Errorf("invalid bytes length %v at slot %x", size, slot)
+	}
+	var (
+		length  = size.Uint64()
+		slots   = (length + 31) / 32
+		partial bool
+	)
+	if d.limit > 0 && slots > d.limit {
+		slots, partial = d.limit, true
+	}
+	var (
+		data     = make([]byte, 0, slots*32)
+		location = crypto.Keccak256Hash(slot[:])
+	)
+	for i := uint64(0); i < slots; i++ {
+		word, err := d.storage.Slot(offsetSlot(location, int64(i)))
+		if err != nil {
+			return nil, 0, false, err
+		}
+		data = append(data, word[:]...)
+	}
+	if uint64(len(data)) > length {
+		data = data[:length]
+	}
+	return data, length, partial, nil
+}
+
+// formatKey formats a mapping key recovered from a preimage, reporting false if
+// it doesn't fit the key type. Value type keys are padded to 32 bytes.
+func (d *Decoder) formatKey(name string, key []byte) (string, bool) {
+	typ := d.layout.Types[name]
+	if typ.Encoding == encodingBytes {
+		if typ.Label == "string" || strings.HasPrefix(typ.Label, "string ") {
+			return string(key), true
+		}
+		return hexutil.Encode(key), true
+	}
+	if len(key) != common.HashLength {
+		return "", false
+	}
+	if isFixedBytes(typ.Label) {
+		return formatValue(typ.Label, key[:typ.size]), true
+	}
+	return formatValue(typ.Label, key[common.HashLength-typ.size:]), true
+}
+
+// isFixedBytes reports whether the type label is a fixed size byte array.
+func isFixedBytes(label string) bool {
+	return strings.HasPrefix(label, "bytes") && label != "bytes" && !strings.HasPrefix(label, "bytes ")
+}
+
+// formatValue formats the bytes of a value type according to its label.
+func formatValue(label string, data []byte) string {
+	switch {
+	case label == "bool":
+		return fmt.Sprint(new(big.Int).SetBytes(data).Sign() != 0)
+	case strings.HasPrefix(label, "uint"), strings.HasPrefix(label, "enum "):
+		return new(big.Int).SetBytes(data).String()
+	case strings.HasPrefix(label, "int"):
+		n := new(big.Int).SetBytes(data)
+		if len(data) > 0 && data[0]&0x80 != 0 {
+			n.Sub(n, new(big.Int).Lsh(common.Big1, uint(len(data)*8)))
+		}
+		return n.String()
+	case strings.HasPrefix(label, "address"), strings.HasPrefix(label, "contract "):
+		return common.BytesToAddress(data).Hex()
+	default:
+		return hexutil.Encode(data)
+	}
+}
+
+// addSlots returns the sum of two slots, wrapping around like the EVM.
+func addSlots(a, b common.Hash) common.Hash {
+	x := new(uint256.Int).SetBytes(a[:])
+	x.Add(x, new(uint256.Int).SetBytes(b[:]))
+	return x.Bytes32()
+}
+
+// offsetSlot returns the slot n slots away from the given one.
+func offsetSlot(slot common.Hash, n int64) common.Hash {
+	x := new(uint256.Int).SetBytes(slot[:])
+	if n >= 0 {
+		x.Add(x, uint256.NewInt(uint64(n)))
+	} else {
+		x.Sub(x, uint256.NewInt(uint64(-n)))
+	}
+	return x.Bytes32()
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package layout decodes contract storage using the Solidity storage layout
+// emitted by solc.
+package layout
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Type encodings used by solc in storage layouts.
+const (
+	encodingInplace      = "inplace"
+	encodingMapping      = "mapping"
+	encodingDynamicArray = "dynamic_array"
+	encodingBytes        = "bytes"
+)
+
+// staticArrayLength extracts the length of a static array from its label.
+var staticArrayLength = regexp.MustCompile(`\[(\d+)\]$`)
+
+// Layout is the storage layout of a contract, as emitted by solc for the
+// storageLayout output selection.
+type Layout struct {
+	Storage []*Variable      `json:"storage"`
+	Types   map[string]*Type `json:"types"`
+}
+
+// Variable is a state variable of a contract or a member of a struct.
+type Variable struct {
+	Label  string `json:"label"`
+	Offset uint64 `json:"offset"` // Offset in bytes within the slot, from the right
+	Slot   string `json:"slot"`   // Decimal slot, relative to the struct for members
+	Type   string `json:"type"`
+
+	slot common.Hash
+}
+
+// Type describes how the values of a type are stored.
+type Type struct {
+	Encoding      string      `json:"encoding"`
+	Label         string      `json:"label"`
+	NumberOfBytes string      `json:"numberOfBytes"`
+	Base          string      `json:"base,omitempty"`    // Element type of arrays
+	Key           string      `json:"key,omitempty"`     // Key type of mappings
+	Value         string      `json:"value,omitempty"`   // Value type of mappings
+	Members       []*Variable `json:"members,omitempty"` // Members of structs
+
+	size   uint64 // Number of bytes occupied in storage
+	length uint64 // Number of elements of static arrays
+}
+
+// slots returns the number of slots occupied by a value of the type.
+func (t *Type) slots() uint64 {
+	return (t.size + 31) / 32
+}
+
+// Parse decodes and validates a storage layout. Both the layout itself and the
+// solc output of a contract containing it under storageLayout are accepted.
+func Parse(blob []byte) (*Layout, error) {
+	var layout Layout
+	if err := json.Unmarshal(blob, &layout); err != nil {
+		return nil, err
+	}
+	if layout.Storage == nil && layout.Types == nil {
+		var output struct {
+			StorageLayout *Layout `json:"storageLayout"`
+		}
+		if err := json.Unmarshal(blob, &output); err == nil && output.StorageLayout != nil {
+			layout = *output.StorageLayout
+		}
+	}
+	if layout.Storage == nil {
+		return nil, fmt.Errorf("no storage variables in layout")
+	}
+	for name, typ := range layout.Types {
+		size, err := strconv.ParseUint(typ.NumberOfBytes, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("type %s: invalid size %q", name, typ.NumberOfBytes)
+		}
+		typ.size = size
+	}
+	for name, typ := range layout.Types {
+		if err := layout.validateType(name, typ); err != nil {
+			return nil, err
+		}
+	}
+	if err := layout.validateVariables(layout.Storage); err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
+
+// validateVariables checks the variables or struct members reference known
+// types and resolves their slots.
+func (l *Layout) validateVariables(vars []*Variable) error {
+	for _, v := range vars {
+		slot, ok := new(big.Int).SetString(v.Slot, 10)
+		if !ok || slot.Sign() < 0 || slot.BitLen() > 256 {
+			return fmt.Errorf("variable %s: invalid slot %q", v.Label, v.Slot)
+		}
+		v.slot = common.BigToHash(slot)
+
+		typ, ok := l.Types[v.Type]
+		if !ok {
+			return fmt.Errorf("variable %s: unknown type %s", v.Label, v.Type)
+		}
+		if typ.Encoding == encodingInplace && typ.size <= 32 && v.Offset+typ.size > 32 {
+			return fmt.Errorf("variable %s: offset %d out of slot", v.Label, v.Offset)
+		}
+	}
+	return nil
+}
+
+// validateType checks a type is well formed and references known types.
+func (l *Layout) validateType(name string, typ *Type) error {
+	known := func(ref string) error {
+		if _, ok := l.Types[ref]; !ok {
+			return fmt.Errorf("type %s: unknown type %s", name, ref)
+		}
+		return nil
+	}
+	switch typ.Encoding {
+	case encodingInplace:
+		switch {
+		case len(typ.Members) > 0:
+			return l.validateVariables(typ.Members)
+		case typ.Base != "":
+			match := staticArrayLength.FindStringSubmatch(typ.Label)
+			if match == nil {
+				return fmt.Errorf("type %s: missing static array length", name)
+			}
+			length, err := strconv.ParseUint(match[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("type %s: invalid static array length: %v", name, err)
+			}
+			typ.length = length
+			return known(typ.Base)
+		case typ.size == 0 || typ.size > 32:
+			return fmt.Errorf("type %s: invalid size %d", name, typ.size)
+		}
+	case encodingMapping:
+		if err := known(typ.Key); err != nil {
+			return err
+		}
+		return known(typ.Value)
+	case encodingDynamicArray:
+		return known(typ.Base)
+	case encodingBytes:
+	default:
+		return fmt.Errorf("type %s: unknown encoding %q", name, typ.Encoding)
+	}
+	return nil
+}
+
+// mappingSpan returns the maximum number of slots occupied by a mapping value,
+// which bounds the distance between a slot found in storage and the location
+// of the mapping entry containing it.
+func (l *Layout) mappingSpan() uint64 {
+	span := uint64(1)
+	for _, typ := range l.Types {
+		if typ.Encoding != encodingMapping {
+			continue
+		}
+		if slots := l.Types[typ.Value].slots(); slots > span {
+			span = slots
+		}
+	}
+	return span
+}
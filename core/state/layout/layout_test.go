@@ -0,0 +1,266 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package layout
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testLayout is the storage layout emitted by solc for:
+//
+//	contract C {
+//	    struct S { uint128 x; uint128 y; bytes32 z; }
+//
+//	    uint8 small;
+//	    bool flag;
+//	    address owner;
+//	    int16 negative;
+//	    string name;
+//	    string text;
+//	    uint256[] list;
+//	    uint64[3] packed;
+//	    mapping(address => uint256) balances;
+//	    mapping(address => mapping(uint256 => S)) nested;
+//	    S single;
+//	    mapping(string => uint256) named;
+//	}
+const testLayout = `{
+  "storage": [
+    {"astId": 10, "contract": "C.sol:C", "label": "small", "offset": 0, "slot": "0", "type": "t_uint8"},
+    {"astId": 12, "contract": "C.sol:C", "label": "flag", "offset": 1, "slot": "0", "type": "t_bool"},
+    {"astId": 14, "contract": "C.sol:C", "label": "owner", "offset": 2, "slot": "0", "type": "t_address"},
+    {"astId": 16, "contract": "C.sol:C", "label": "negative", "offset": 22, "slot": "0", "type": "t_int16"},
+    {"astId": 18, "contract": "C.sol:C", "label": "name", "offset": 0, "slot": "1", "type": "t_string_storage"},
+    {"astId": 20, "contract": "C.sol:C", "label": "text", "offset": 0, "slot": "2", "type": "t_string_storage"},
+    {"astId": 23, "contract": "C.sol:C", "label": "list", "offset": 0, "slot": "3", "type": "t_array(t_uint256)dyn_storage"},
+    {"astId": 27, "contract": "C.sol:C", "label": "packed", "offset": 0, "slot": "4", "type": "t_array(t_uint64)3_storage"},
+    {"astId": 31, "contract": "C.sol:C", "label": "balances", "offset": 0, "slot": "5", "type": "t_mapping(t_address,t_uint256)"},
+    {"astId": 37, "contract": "C.sol:C", "label": "nested", "offset": 0, "slot": "6", "type": "t_mapping(t_address,t_mapping(t_uint256,t_struct(S)8_storage))"},
+    {"astId": 40, "contract": "C.sol:C", "label": "single", "offset": 0, "slot": "7", "type": "t_struct(S)8_storage"},
+    {"astId": 44, "contract": "C.sol:C", "label": "named", "offset": 0, "slot": "9", "type": "t_mapping(t_string_memory_ptr,t_uint256)"}
+  ],
+  "types": {
+    "t_address": {"encoding": "inplace", "label": "address", "numberOfBytes": "20"},
+    "t_array(t_uint256)dyn_storage": {"base": "t_uint256", "encoding": "dynamic_array", "label": "uint256[]", "numberOfBytes": "32"},
+    "t_array(t_uint64)3_storage": {"base": "t_uint64", "encoding": "inplace", "label": "uint64[3]", "numberOfBytes": "32"},
+    "t_bool": {"encoding": "inplace", "label": "bool", "numberOfBytes": "1"},
+    "t_bytes32": {"encoding": "inplace", "label": "bytes32", "numberOfBytes": "32"},
+    "t_int16": {"encoding": "inplace", "label": "int16", "numberOfBytes": "2"},
+    "t_mapping(t_address,t_mapping(t_uint256,t_struct(S)8_storage))": {"encoding": "mapping", "key": "t_address", "label": "mapping(address => mapping(uint256 => struct C.S))", "numberOfBytes": "32", "value": "t_mapping(t_uint256,t_struct(S)8_storage)"},
+    "t_mapping(t_address,t_uint256)": {"encoding": "mapping", "key": "t_address", "label": "mapping(address => uint256)", "numberOfBytes": "32", "value": "t_uint256"},
+    "t_mapping(t_string_memory_ptr,t_uint256)": {"encoding": "mapping", "key": "t_string_memory_ptr", "label": "mapping(string => uint256)", "numberOfBytes": "32", "value": "t_uint256"},
+    "t_mapping(t_uint256,t_struct(S)8_storage)": {"encoding": "mapping", "key": "t_uint256", "label": "mapping(uint256 => struct C.S)", "numberOfBytes": "32", "value": "t_struct(S)8_storage"},
+    "t_string_memory_ptr": {"encoding": "bytes", "label": "string", "numberOfBytes": "32"},
+    "t_string_storage": {"encoding": "bytes", "label": "string", "numberOfBytes": "32"},
+    "t_struct(S)8_storage": {"encoding": "inplace", "label": "struct C.S", "numberOfBytes": "64", "members": [
+      {"astId": 3, "contract": "C.sol:C", "label": "x", "offset": 0, "slot": "0", "type": "t_uint128"},
+      {"astId": 5, "contract": "C.sol:C", "label": "y", "offset": 16, "slot": "0", "type": "t_uint128"},
+      {"astId": 7, "contract": "C.sol:C", "label": "z", "offset": 0, "slot": "1", "type": "t_bytes32"}
+    ]},
+    "t_uint128": {"encoding": "inplace", "label": "uint128", "numberOfBytes": "16"},
+    "t_uint256": {"encoding": "inplace", "label": "uint256", "numberOfBytes": "32"},
+    "t_uint64": {"encoding": "inplace", "label": "uint64", "numberOfBytes": "8"},
+    "t_uint8": {"encoding": "inplace", "label": "uint8", "numberOfBytes": "1"}
+  }
+}`
+
+// testStorage is an in-memory contract storage, recording the preimages of the
+// locations hashed while writing it like the EVM does.
+type testStorage struct {
+	slots     map[common.Hash]common.Hash
+	preimages map[common.Hash][]byte
+}
+
+func newTestStorage() *testStorage {
+	return &testStorage{
+		slots:     make(map[common.Hash]common.Hash),
+		preimages: make(map[common.Hash][]byte),
+	}
+}
+
+func (s *testStorage) Slot(key common.Hash) (common.Hash, error) { return s.slots[key], nil }
+func (s *testStorage) Preimage(hash common.Hash) []byte          { return s.preimages[hash] }
+
+// hash computes a storage location, recording its preimage.
+func (s *testStorage) hash(data ...[]byte) common.Hash {
+	hash := crypto.Keccak256Hash(data...)
+	s.preimages[hash] = bytes.Join(data, nil)
+	return hash
+}
+
+// keys returns the keys of the non-empty slots.
+func (s *testStorage) keys() []common.Hash {
+	var keys []common.Hash
+	for key := range s.slots {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// find returns the member reached by following the given labels or keys.
+func find(t *testing.T, values []*Value, path ...string) *Value {
+	t.Helper()
+
+	var value *Value
+	for _, step := range path {
+		value = nil
+		for _, v := range values {
+			if v.Label == step || v.Key == step {
+				value = v
+				break
+			}
+		}
+		if value == nil {
+			t.Fatalf("value %s not found", strings.Join(path, "."))
+		}
+		values = value.Members
+	}
+	return value
+}
+
+func TestDecode(t *testing.T) {
+	layout, err := Parse([]byte(testLayout))
+	if err != nil {
+		t.Fatalf("failed to parse layout: %v", err)
+	}
+	var (
+		storage = newTestStorage()
+		owner   = common.HexToAddress("0x71562b71999873DB5b286dF957af199Ec94617F7")
+		other   = common.HexToAddress("0x00000000000000000000000000000000000000aa")
+		text    = strings.Repeat("a long string spanning slots ", 3)
+	)
+	// Slot 0: small = 7, flag = true, owner, negative = -2
+	var packed common.Hash
+	packed[31], packed[30] = 7, 1
+	copy(packed[10:30], owner[:])
+	packed[8], packed[9] = 0xff, 0xfe
+	storage.slots[common.Hash{}] = packed
+
+	// Slot 1: short string, slot 2: long string
+	var short common.Hash
+	copy(short[:], "hello")
+	short[31] = 10
+	storage.slots[common.BigToHash(big.NewInt(1))] = short
+	storage.slots[common.BigToHash(big.NewInt(2))] = common.BigToHash(big.NewInt(int64(2*len(text) + 1)))
+	data := storage.hash(common.BigToHash(big.NewInt(2)).Bytes())
+	for i := 0; i*32 < len(text); i++ {
+		var word common.Hash
+		copy(word[:], text[i*32:])
+		storage.slots[offsetSlot(data, int64(i))] = word
+	}
+	// Slot 3: dynamic array of 3 elements
+	storage.slots[common.BigToHash(big.NewInt(3))] = common.BigToHash(big.NewInt(3))
+	data = storage.hash(common.BigToHash(big.NewInt(3)).Bytes())
+	for i := int64(0); i < 3; i++ {
+		storage.slots[offsetSlot(data, i)] = common.BigToHash(big.NewInt(100 + i))
+	}
+	// Slot 4: packed static array {1, 2, 3}
+	var elems common.Hash
+	elems[31], elems[23], elems[15] = 1, 2, 3
+	storage.slots[common.BigToHash(big.NewInt(4))] = elems
+
+	// Slot 5: balances[owner] = 1000, balances[other] = 2000
+	storage.slots[storage.hash(common.LeftPadBytes(owner[:], 32), common.BigToHash(big.NewInt(5)).Bytes())] = common.BigToHash(big.NewInt(1000))
+	storage.slots[storage.hash(common.LeftPadBytes(other[:], 32), common.BigToHash(big.NewInt(5)).Bytes())] = common.BigToHash(big.NewInt(2000))
+
+	// Slot 6: nested[owner][42] = {x: 0, y: 5, z: 0x01..}, the location of the
+	// inner mapping is only known from the preimage of the entry's location
+	inner := storage.hash(common.LeftPadBytes(owner[:], 32), common.BigToHash(big.NewInt(6)).Bytes())
+	entry := storage.hash(common.BigToHash(big.NewInt(42)).Bytes(), inner[:])
+	var xy common.Hash
+	xy[15] = 5
+	storage.slots[entry] = xy
+	storage.slots[offsetSlot(entry, 1)] = common.Hash{0x01}
+
+	// Slot 7-8: single.z only, slot 9: named["alice"] = 9
+	storage.slots[common.BigToHash(big.NewInt(8))] = common.Hash{0x02}
+	storage.slots[storage.hash([]byte("alice"), common.BigToHash(big.NewInt(9)).Bytes())] = common.BigToHash(big.NewInt(9))
+
+	values, err := NewDecoder(layout, storage, storage.keys(), 0).Decode()
+	if err != nil {
+		t.Fatalf("failed to decode storage: %v", err)
+	}
+	tests := []struct {
+		path  []string
+		value string
+	}{
+		{[]string{"small"}, "7"},
+		{[]string{"flag"}, "true"},
+		{[]string{"owner"}, owner.Hex()},
+		{[]string{"negative"}, "-2"},
+		{[]string{"name"}, "hello"},
+		{[]string{"text"}, text},
+		{[]string{"balances", owner.Hex()}, "1000"},
+		{[]string{"balances", other.Hex()}, "2000"},
+		{[]string{"nested", owner.Hex(), "42", "x"}, "0"},
+		{[]string{"nested", owner.Hex(), "42", "y"}, "5"},
+		{[]string{"nested", owner.Hex(), "42", "z"}, common.Hash{0x01}.Hex()},
+		{[]string{"single", "z"}, common.Hash{0x02}.Hex()},
+		{[]string{"named", "alice"}, "9"},
+	}
+	for _, tt := range tests {
+		if have := find(t, values, tt.path...).Value; have != tt.value {
+			t.Errorf("%s: value mismatch: have %q, want %q", strings.Join(tt.path, "."), have, tt.value)
+		}
+	}
+	list := find(t, values, "list")
+	if list.Length == nil || *list.Length != 3 || len(list.Members) != 3 || list.Members[2].Value != "102" {
+		t.Errorf("dynamic array mismatch: %+v", list)
+	}
+	packedArray := find(t, values, "packed")
+	for i, want := range []string{"1", "2", "3"} {
+		if have := packedArray.Members[i].Value; have != want {
+			t.Errorf("packed array element %d mismatch: have %s, want %s", i, have, want)
+		}
+	}
+	// Limiting the decoded elements truncates arrays, mappings and bytes
+	values, err = NewDecoder(layout, storage, storage.keys(), 1).Decode()
+	if err != nil {
+		t.Fatalf("failed to decode storage: %v", err)
+	}
+	for _, label := range []string{"list", "balances", "text"} {
+		if value := find(t, values, label); !value.Truncated || len(value.Members) > 1 || len(value.Value) > 32 {
+			t.Errorf("%s not truncated: %+v", label, value)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		`{"storage": [{"label": "a", "offset": 0, "slot": "0", "type": "t_missing"}], "types": {}}`,
+		`{"storage": [{"label": "a", "offset": 0, "slot": "x", "type": "t_uint8"}], "types": {"t_uint8": {"encoding": "inplace", "label": "uint8", "numberOfBytes": "1"}}}`,
+		`{"storage": [{"label": "a", "offset": 31, "slot": "0", "type": "t_uint16"}], "types": {"t_uint16": {"encoding": "inplace", "label": "uint16", "numberOfBytes": "2"}}}`,
+		`{"storage": [], "types": {"t_m": {"encoding": "mapping", "key": "t_missing", "value": "t_missing", "label": "m", "numberOfBytes": "32"}}}`,
+		`{"storage": [], "types": {"t_x": {"encoding": "unknown", "label": "x", "numberOfBytes": "32"}}}`,
+		`{"types": {}}`,
+	}
+	for i, blob := range tests {
+		if _, err := Parse([]byte(blob)); err == nil {
+			t.Errorf("test %d: invalid layout accepted", i)
+		}
+	}
+	// The solc output of a contract is accepted too
+	if _, err := Parse([]byte(`{"abi": [], "storageLayout": ` + testLayout + `}`)); err != nil {
+		t.Errorf("failed to parse contract output: %v", err)
+	}
+}
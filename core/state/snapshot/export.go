@@ -0,0 +1,586 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/era/e2store"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// exportVersion is the version of the export layout and manifest format.
+	exportVersion = 1
+
+	// exportManifestName is the name of the manifest file in an export directory.
+	exportManifestName = "manifest.json"
+
+	// DefaultExportChunkSize is the default size at which export chunks are cut.
+	DefaultExportChunkSize = 128 * 1024 * 1024
+
+	// exportStorageBatch is the maximum number of slots in a storage entry.
+	exportStorageBatch = 1024
+)
+
+// Entry types of the export chunks, stored in the e2store format.
+const (
+	exportTypeVersion uint16 = 0x3265
+	exportTypeAccount uint16 = 0x20
+	exportTypeStorage uint16 = 0x21
+	exportTypeCode    uint16 = 0x22
+)
+
+// ExportManifest describes the content of a snapshot export.
+type ExportManifest struct {
+	Version  uint64        `json:"version"`
+	Number   uint64        `json:"number"`   // Number of the exported block
+	Hash     common.Hash   `json:"hash"`     // Hash of the exported block
+	Root     common.Hash   `json:"root"`     // State root of the exported block
+	Accounts uint64        `json:"accounts"` // Number of exported accounts
+	Slots    uint64        `json:"slots"`    // Number of exported storage slots
+	Codes    uint64        `json:"codes"`    // Number of exported contract codes
+	Chunks   []ExportChunk `json:"chunks"`
+}
+
+// ExportChunk describes a single file of a snapshot export.
+type ExportChunk struct {
+	File     string `json:"file"`
+	Size     uint64 `json:"size"`
+	Checksum string `json:"sha256"`
+}
+
+// exportAccount is the value of an account entry: the account hash and its
+// slim RLP encoding. The code and storage entries of the account follow it.
+type exportAccount struct {
+	Hash    common.Hash
+	Account []byte
+}
+
+// exportSlots is the value of a storage entry: a batch of consecutive slots
+// of an account.
+type exportSlots struct {
+	Account common.Hash
+	Keys    []common.Hash
+	Values  [][]byte
+}
+
+// chunkWriter writes the entries of an export, cutting a new chunk file every
+// time the current one exceeds the chunk size.
+type chunkWriter struct {
+	dir      string
+	limit    uint64
+	manifest *ExportManifest
+
+	file   *os.File
+	buf    *bufio.Writer
+	hasher hash.Hash
+	writer *e2store.Writer
+	size   uint64
+}
+
+// write appends an entry to the current chunk, opening a new one if needed.
+func (w *chunkWriter) write(typ uint16, value []byte) error {
+	if w.file != nil && w.size >= w.limit {
+		if err := w.close(); err != nil {
+			return err
+		}
+	}
+	if w.file == nil {
+		name := fmt.Sprintf("chunk-%05d.e2s", len(w.manifest.Chunks))
+		file, err := os.Create(filepath.Join(w.dir, name))
+		if err != nil {
+			return err
+		}
+		w.file, w.buf, w.hasher, w.size = file, bufio.NewWriter(file), sha256.New(), 0
+		w.writer = e2store.NewWriter(io.MultiWriter(w.buf, w.hasher))
+		w.manifest.Chunks = append(w.manifest.Chunks, ExportChunk{File: name})
+
+		if _, err := w.writer.Write(exportTypeVersion, nil); err != nil {
+			return err
+		}
+	}
+	n, err := w.writer.Write(typ, value)
+	w.size += uint64(n)
+	return err
+}
+
+// close flushes and closes the current chunk, recording its checksum.
+func (w *chunkWriter) close() error {
+	if w.file == nil {
+		return nil
+	}
+	if err := w.buf.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+	if err := w.file.Close(); err != nil {
+		return err
+	}
+	chunk := &w.manifest.Chunks[len(w.manifest.Chunks)-1]
+	chunk.Size = w.size + 8 // Version entry header
+	chunk.Checksum = hex.EncodeToString(w.hasher.Sum(nil))
+	w.file = nil
+	return nil
+}
+
+// Export writes the accounts, storage slots and contract codes of the state of
+// the given block into dir, which must not exist yet. The state is split into
+// chunks of roughly chunkSize bytes, which are listed along with their sha256
+// checksums in the manifest. The snapshot of the block's state root needs to
+// be available and fully generated.
+func Export(t *Tree, header *types.Header, dir string, chunkSize uint64) (*ExportManifest, error) {
+	if _, err := os.Stat(dir); err == nil {
+		return nil, fmt.Errorf("export directory %s already exists", dir)
+	}
+	if chunkSize == 0 {
+		chunkSize = DefaultExportChunkSize
+	}
+	accIt, err := t.AccountIterator(header.Root, common.Hash{})
+	if err != nil {
+		return nil, err
+	}
+	defer accIt.Release()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	var (
+		start    = time.Now()
+		logged   = time.Now()
+		codes    = make(map[common.Hash]struct{})
+		manifest = &ExportManifest{
+			Version: exportVersion,
+			Number:  header.Number.Uint64(),
+			Hash:    header.Hash(),
+			Root:    header.Root,
+		}
+		w = &chunkWriter{dir: dir, limit: chunkSize, manifest: manifest}
+	)
+	defer w.close()
+
+	for accIt.Next() {
+		hash := accIt.Hash()
+		account, err := FullAccount(accIt.Account())
+		if err != nil {
+			return nil, err
+		}
+		blob, _ := rlp.EncodeToBytes(&exportAccount{Hash: hash, Account: accIt.Account()})
+		if err := w.write(exportTypeAccount, blob); err != nil {
+			return nil, err
+		}
+		manifest.Accounts++
+
+		// Export the contract code the first time it's referenced
+		if codeHash := common.BytesToHash(account.CodeHash); codeHash != emptyCode {
+			if _, ok := codes[codeHash]; !ok {
+				code := rawdb.ReadCode(t.diskdb, codeHash)
+				if len(code) == 0 {
+					return nil, fmt.Errorf("missing code %x of account %x", codeHash, hash)
+				}
+				if err := w.write(exportTypeCode, code); err != nil {
+					return nil, err
+				}
+				codes[codeHash] = struct{}{}
+				manifest.Codes++
+			}
+		}
+		// Export the storage slots in batches
+		if common.BytesToHash(account.Root) != emptyRoot {
+			if err := exportStorage(t, header.Root, hash, w); err != nil {
+				return nil, err
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Exporting state snapshot", "at", hash, "accounts", manifest.Accounts, "chunks", len(manifest.Chunks), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := accIt.Error(); err != nil {
+		return nil, err
+	}
+	if err := w.close(); err != nil {
+		return nil, err
+	}
+	blob, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, exportManifestName), blob, 0644); err != nil {
+		return nil, err
+	}
+	log.Info("Exported state snapshot", "number", manifest.Number, "root", manifest.Root, "accounts", manifest.Accounts,
+		"slots", manifest.Slots, "codes", manifest.Codes, "chunks", len(manifest.Chunks), "elapsed", common.PrettyDuration(time.Since(start)))
+	return manifest, nil
+}
+
+// exportStorage writes the storage slots of an account in batches.
+func exportStorage(t *Tree, root common.Hash, account common.Hash, w *chunkWriter) error {
+	it, err := t.StorageIterator(root, account, common.Hash{})
+	if err != nil {
+		return err
+	}
+	defer it.Release()
+
+	batch := &exportSlots{Account: account}
+	flush := func() error {
+		if len(batch.Keys) == 0 {
+			return nil
+		}
+		blob, _ := rlp.EncodeToBytes(batch)
+		w.manifest.Slots += uint64(len(batch.Keys))
+		batch.Keys, batch.Values = batch.Keys[:0], batch.Values[:0]
+		return w.write(exportTypeStorage, blob)
+	}
+	for it.Next() {
+		batch.Keys = append(batch.Keys, it.Hash())
+		batch.Values = append(batch.Values, common.CopyBytes(it.Slot()))
+		if len(batch.Keys) == exportStorageBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// ReadExportManifest reads the manifest of the snapshot export in dir.
+func ReadExportManifest(dir string) (*ExportManifest, error) {
+	blob, err := os.ReadFile(filepath.Join(dir, exportManifestName))
+	if err != nil {
+		return nil, err
+	}
+	var manifest ExportManifest
+	if err := json.Unmarshal(blob, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid export manifest: %v", err)
+	}
+	if manifest.Version != exportVersion {
+		return nil, fmt.Errorf("unsupported export version %d, want %d", manifest.Version, exportVersion)
+	}
+	return &manifest, nil
+}
+
+// verifyChunk checks the size and checksum of an export chunk.
+func verifyChunk(dir string, chunk ExportChunk) error {
+	file, err := os.Open(filepath.Join(dir, chunk.File))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return err
+	}
+	if uint64(size) != chunk.Size {
+		return fmt.Errorf("chunk %s size mismatch: have %d, want %d", chunk.File, size, chunk.Size)
+	}
+	if have := hex.EncodeToString(hasher.Sum(nil)); have != chunk.Checksum {
+		return fmt.Errorf("chunk %s checksum mismatch: have %s, want %s", chunk.File, have, chunk.Checksum)
+	}
+	return nil
+}
+
+// importer rebuilds the state tries and the snapshot from the entries of an
+// export. The storage and code entries of an account follow it directly, the
+// account is completed once the next one is reached.
+type importer struct {
+	batch ethdb.Batch
+
+	accTrie  *trie.StackTrie
+	accHash  *common.Hash // Hash of the account being imported, nil before the first
+	account  []byte       // Slim RLP of the account being imported
+	root     common.Hash  // Storage root of the account being imported
+	stTrie   *trie.StackTrie
+	lastSlot *common.Hash // Hash of the last imported slot of the account
+
+	codes    map[common.Hash]bool // Referenced contract codes, and whether they've been imported
+	accounts uint64
+	slots    uint64
+}
+
+// flush writes out the batch once it grows large enough.
+func (imp *importer) flush(force bool) error {
+	if !force && imp.batch.ValueSize() < ethdb.IdealBatchSize {
+		return nil
+	}
+	if err := imp.batch.Write(); err != nil {
+		return err
+	}
+	imp.batch.Reset()
+	return nil
+}
+
+// importAccount finishes the current account and starts a new one.
+func (imp *importer) importAccount(value []byte) error {
+	var entry exportAccount
+	if err := rlp.DecodeBytes(value, &entry); err != nil {
+		return fmt.Errorf("invalid account entry: %v", err)
+	}
+	if imp.accHash != nil && bytes.Compare(entry.Hash[:], imp.accHash[:]) <= 0 {
+		return fmt.Errorf("account %x out of order after %x", entry.Hash, *imp.accHash)
+	}
+	if err := imp.finishAccount(); err != nil {
+		return err
+	}
+	account, err := FullAccount(entry.Account)
+	if err != nil {
+		return fmt.Errorf("invalid account %x: %v", entry.Hash, err)
+	}
+	if codeHash := common.BytesToHash(account.CodeHash); codeHash != emptyCode {
+		if _, ok := imp.codes[codeHash]; !ok {
+			imp.codes[codeHash] = false
+		}
+	}
+	hash := entry.Hash
+	imp.accHash, imp.account, imp.root = &hash, entry.Account, common.BytesToHash(account.Root)
+	imp.stTrie, imp.lastSlot = nil, nil
+
+	rawdb.WriteAccountSnapshot(imp.batch, hash, entry.Account)
+	imp.accounts++
+	return imp.flush(false)
+}
+
+// importStorage adds a batch of slots to the current account.
+func (imp *importer) importStorage(value []byte) error {
+	var entry exportSlots
+	if err := rlp.DecodeBytes(value, &entry); err != nil {
+		return fmt.Errorf("invalid storage entry: %v", err)
+	}
+	if imp.accHash == nil || entry.Account != *imp.accHash {
+		return fmt.Errorf("storage of account %x out of place", entry.Account)
+	}
+	if len(entry.Keys) != len(entry.Values) {
+		return fmt.Errorf("storage of account %x has %d keys but %d values", entry.Account, len(entry.Keys), len(entry.Values))
+	}
+	if imp.stTrie == nil {
+		imp.stTrie = trie.NewStackTrieWithOwner(imp.batch, entry.Account)
+	}
+	for i, key := range entry.Keys {
+		if imp.lastSlot != nil && bytes.Compare(key[:], imp.lastSlot[:]) <= 0 {
+			return fmt.Errorf("slot %x of account %x out of order", key, entry.Account)
+		}
+		key := key
+		imp.lastSlot = &key
+
+		if err := imp.stTrie.TryUpdate(key[:], entry.Values[i]); err != nil {
+			return err
+		}
+		rawdb.WriteStorageSnapshot(imp.batch, entry.Account, key, entry.Values[i])
+		imp.slots++
+	}
+	return imp.flush(false)
+}
+
+// importCode stores a contract code referenced by an already imported account.
+func (imp *importer) importCode(code []byte) error {
+	hash := crypto.Keccak256Hash(code)
+	if _, ok := imp.codes[hash]; !ok {
+		return fmt.Errorf("unreferenced code %x", hash)
+	}
+	imp.codes[hash] = true
+	rawdb.WriteCode(imp.batch, hash, code)
+	return imp.flush(false)
+}
+
+// finishAccount verifies the storage root of the current account and inserts
+// it into the account trie.
+func (imp *importer) finishAccount() error {
+	if imp.accHash == nil {
+		return nil
+	}
+	root := emptyRoot
+	if imp.stTrie != nil {
+		var err error
+		if root, err = imp.stTrie.Commit(); err != nil {
+			return err
+		}
+	}
+	if root != imp.root {
+		return fmt.Errorf("storage root mismatch of account %x: have %x, want %x", *imp.accHash, root, imp.root)
+	}
+	full, err := FullAccountRLP(imp.account)
+	if err != nil {
+		return err
+	}
+	return imp.accTrie.TryUpdate(imp.accHash[:], full)
+}
+
+// importChunk feeds all the entries of an export chunk into the importer.
+func (imp *importer) importChunk(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := e2store.NewReader(file)
+	for off := int64(0); ; {
+		entry, size, err := reader.ReadAt(off)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch entry.Type {
+		case exportTypeVersion:
+			if off != 0 {
+				return fmt.Errorf("version entry at %d", off)
+			}
+		case exportTypeAccount:
+			err = imp.importAccount(entry.Value)
+		case exportTypeStorage:
+			err = imp.importStorage(entry.Value)
+		case exportTypeCode:
+			err = imp.importCode(entry.Value)
+		default:
+			err = fmt.Errorf("unknown entry type %#x", entry.Type)
+		}
+		if err != nil {
+			return err
+		}
+		off += int64(size)
+	}
+}
+
+// Import rebuilds the state trie and the snapshot of the given header from the
+// snapshot export in dir. The header is trusted, all the chunks are verified
+// against the manifest and the state rebuilt from them against its root before
+// the snapshot is marked as complete. Existing snapshot entries are wiped.
+func Import(db ethdb.Database, dir string, header *types.Header) error {
+	if rawdb.ReadStateScheme(db) == rawdb.PathScheme {
+		return errors.New("importing snapshot exports is not supported by the path-based state scheme")
+	}
+	manifest, err := ReadExportManifest(dir)
+	if err != nil {
+		return err
+	}
+	if manifest.Hash != header.Hash() || manifest.Root != header.Root {
+		return fmt.Errorf("export of block %d [%x] does not match trusted header %d [%x]", manifest.Number, manifest.Hash, header.Number, header.Hash())
+	}
+	for _, chunk := range manifest.Chunks {
+		if err := verifyChunk(dir, chunk); err != nil {
+			return err
+		}
+	}
+	// Remove any existing snapshot, it's replaced by the imported one
+	start := time.Now()
+	rawdb.DeleteSnapshotRoot(db)
+	if err := wipeSnapshotEntries(db); err != nil {
+		return err
+	}
+	batch := db.NewBatch()
+	imp := &importer{
+		batch:   batch,
+		accTrie: trie.NewStackTrie(batch),
+		codes:   make(map[common.Hash]bool),
+	}
+	logged := time.Now()
+	for i, chunk := range manifest.Chunks {
+		if err := imp.importChunk(filepath.Join(dir, chunk.File)); err != nil {
+			return fmt.Errorf("chunk %s: %v", chunk.File, err)
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Importing state snapshot", "chunks", i+1, "total", len(manifest.Chunks), "accounts", imp.accounts, "slots", imp.slots, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := imp.finishAccount(); err != nil {
+		return err
+	}
+	for hash, imported := range imp.codes {
+		if !imported {
+			return fmt.Errorf("missing code %x", hash)
+		}
+	}
+	root, err := imp.accTrie.Commit()
+	if err != nil {
+		return err
+	}
+	if root != header.Root {
+		return fmt.Errorf("state root mismatch: have %x, want %x", root, header.Root)
+	}
+	// The state is complete, mark the snapshot as fully generated
+	rawdb.WriteSnapshotRoot(batch, root)
+	journalProgress(batch, nil, nil)
+	rawdb.DeleteSnapshotJournal(batch)
+	rawdb.DeleteSnapshotDisabled(batch)
+	rawdb.DeleteSnapshotRecoveryNumber(batch)
+	if err := imp.flush(true); err != nil {
+		return err
+	}
+	log.Info("Imported state snapshot", "number", header.Number, "root", root, "accounts", imp.accounts, "slots", imp.slots, "codes", len(imp.codes), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// wipeSnapshotEntries deletes all the account and storage snapshot entries.
+func wipeSnapshotEntries(db ethdb.Database) error {
+	for _, wipe := range []struct {
+		prefix []byte
+		keylen int
+	}{
+		{rawdb.SnapshotAccountPrefix, len(rawdb.SnapshotAccountPrefix) + common.HashLength},
+		{rawdb.SnapshotStoragePrefix, len(rawdb.SnapshotStoragePrefix) + 2*common.HashLength},
+	} {
+		batch := db.NewBatch()
+		it := db.NewIterator(wipe.prefix, nil)
+		for it.Next() {
+			if len(it.Key()) != wipe.keylen {
+				continue
+			}
+			batch.Delete(it.Key())
+			if batch.ValueSize() >= ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					it.Release()
+					return err
+				}
+				batch.Reset()
+			}
+		}
+		it.Release()
+		if err := it.Error(); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
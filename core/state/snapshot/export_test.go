@@ -0,0 +1,174 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// makeExportTree creates a generated snapshot tree of a state with contracts
+// sharing code and storage large enough to span multiple export chunks.
+func makeExportTree(t *testing.T) (*Tree, *types.Header) {
+	var (
+		helper = newHelper()
+		code   = []byte{0x60, 0x00, 0x60, 0x00, 0xf3}
+		keys   []string
+		vals   []string
+	)
+	codeHash := crypto.Keccak256(code)
+	rawdb.WriteCode(helper.diskdb, common.BytesToHash(codeHash), code)
+
+	for i := 0; i < 2500; i++ {
+		keys = append(keys, fmt.Sprintf("key-%d", i))
+		vals = append(vals, fmt.Sprintf("val-%d", i))
+	}
+	stRoot := helper.makeStorageTrie(common.Hash{}, common.Hash{}, keys, vals, false)
+	for i := 0; i < 100; i++ {
+		acc := &Account{Balance: big.NewInt(int64(i)), Nonce: uint64(i), Root: emptyRoot.Bytes(), CodeHash: emptyCode.Bytes()}
+		if i%10 == 0 {
+			acc.Root, acc.CodeHash = stRoot, codeHash
+			helper.makeStorageTrie(common.Hash{}, hashData([]byte(fmt.Sprintf("acc-%d", i))), keys, vals, true)
+		}
+		helper.addTrieAccount(fmt.Sprintf("acc-%d", i), acc)
+	}
+	root, snap := helper.CommitAndGenerate()
+	select {
+	case <-snap.genPending:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("snapshot generation failed")
+	}
+	t.Cleanup(func() {
+		stop := make(chan *generatorStats)
+		snap.genAbort <- stop
+		<-stop
+	})
+	tree := &Tree{
+		diskdb: helper.diskdb,
+		triedb: helper.triedb,
+		layers: map[common.Hash]snapshot{root: snap},
+	}
+	return tree, &types.Header{Number: big.NewInt(1), Root: root}
+}
+
+// Tests that a state exported into chunks can be imported into an empty
+// database, rebuilding both the state trie and the snapshot.
+func TestExportImport(t *testing.T) {
+	tree, header := makeExportTree(t)
+
+	dir := filepath.Join(t.TempDir(), "export")
+	manifest, err := Export(tree, header, dir, 64*1024)
+	if err != nil {
+		t.Fatalf("failed to export snapshot: %v", err)
+	}
+	if manifest.Accounts != 100 || manifest.Slots != 10*2500 || manifest.Codes != 1 {
+		t.Fatalf("export content mismatch: accounts %d, slots %d, codes %d", manifest.Accounts, manifest.Slots, manifest.Codes)
+	}
+	if len(manifest.Chunks) < 2 {
+		t.Fatalf("export not split into chunks: %d", len(manifest.Chunks))
+	}
+	db := rawdb.NewMemoryDatabase()
+	if err := Import(db, dir, header); err != nil {
+		t.Fatalf("failed to import snapshot: %v", err)
+	}
+	// The imported state needs to match the exported one, both in the tries
+	// and in the snapshot
+	if root := rawdb.ReadSnapshotRoot(db); root != header.Root {
+		t.Fatalf("snapshot root mismatch: have %x, want %x", root, header.Root)
+	}
+	triedb := trie.NewDatabase(db)
+	snap := &diskLayer{diskdb: db, triedb: triedb, root: header.Root}
+	checkSnapRoot(t, snap, header.Root)
+
+	accTrie, err := trie.NewStateTrie(common.Hash{}, header.Root, triedb)
+	if err != nil {
+		t.Fatalf("failed to open imported account trie: %v", err)
+	}
+	var accounts, slots int
+	for it := trie.NewIterator(accTrie.NodeIterator(nil)); it.Next(); accounts++ {
+		acc, _ := FullAccount(it.Value)
+		stTrie, err := trie.NewStateTrie(common.BytesToHash(it.Key), common.BytesToHash(acc.Root), triedb)
+		if err != nil {
+			t.Fatalf("failed to open imported storage trie: %v", err)
+		}
+		for stIt := trie.NewIterator(stTrie.NodeIterator(nil)); stIt.Next(); slots++ {
+		}
+	}
+	if accounts != 100 || slots != 10*2500 {
+		t.Fatalf("imported trie content mismatch: accounts %d, slots %d", accounts, slots)
+	}
+
+	accIt := tree.layers[header.Root].(*diskLayer).AccountIterator(common.Hash{})
+	defer accIt.Release()
+	for accIt.Next() {
+		if blob := rawdb.ReadAccountSnapshot(db, accIt.Hash()); !bytes.Equal(blob, accIt.Account()) {
+			t.Fatalf("account %x mismatch", accIt.Hash())
+		}
+	}
+	// Importing into a database with an existing snapshot replaces it
+	rawdb.WriteAccountSnapshot(db, common.Hash{0x01}, []byte{0x01})
+	if err := Import(db, dir, header); err != nil {
+		t.Fatalf("failed to re-import snapshot: %v", err)
+	}
+	if blob := rawdb.ReadAccountSnapshot(db, common.Hash{0x01}); len(blob) != 0 {
+		t.Fatalf("stale snapshot entry not wiped")
+	}
+}
+
+// Tests that corrupted or mismatching exports are rejected.
+func TestImportCorrupted(t *testing.T) {
+	tree, header := makeExportTree(t)
+
+	dir := filepath.Join(t.TempDir(), "export")
+	manifest, err := Export(tree, header, dir, 64*1024)
+	if err != nil {
+		t.Fatalf("failed to export snapshot: %v", err)
+	}
+	// An untrusted header is rejected
+	other := &types.Header{Number: big.NewInt(1), Root: common.Hash{0x01}}
+	if err := Import(rawdb.NewMemoryDatabase(), dir, other); err == nil {
+		t.Fatalf("import of mismatching header succeeded")
+	}
+	// A chunk modified on disk is rejected
+	path := filepath.Join(dir, manifest.Chunks[1].File)
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read chunk: %v", err)
+	}
+	blob[len(blob)-1] ^= 0xff
+	if err := os.WriteFile(path, blob, 0644); err != nil {
+		t.Fatalf("failed to write chunk: %v", err)
+	}
+	db := rawdb.NewMemoryDatabase()
+	if err := Import(db, dir, header); err == nil {
+		t.Fatalf("import of corrupted chunk succeeded")
+	}
+	if root := rawdb.ReadSnapshotRoot(db); root != (common.Hash{}) {
+		t.Fatalf("snapshot marked complete after failed import")
+	}
+}
@@ -86,12 +86,17 @@ type stateObject struct {
 	dirtyStorage   Storage // Storage entries that have been modified in the current transaction execution
 	fakeStorage    Storage // Fake storage which constructed by caller for debugging purpose.
 
+	// Diff tracking, relative to the last commit.
+	origin      *types.StateAccount // Account data at the last commit, nil if it didn't exist
+	prevStorage Storage             // Values at the last commit of the storage entries written since
+
 	// Cache flags.
 	// When an object is marked suicided it will be delete from the trie
 	// during the "update" phase of the state transition.
 	dirtyCode bool // true if the code was updated
 	suicided  bool
 	deleted   bool
+	recreated bool // true if the account replaced an existing one, whose storage is dropped
 }
 
 // empty returns whether the account is considered empty.
@@ -327,6 +332,14 @@ func (s *stateObject) updateTrie(db Database) Trie {
 		if value == s.originStorage[key] {
 			continue
 		}
+		if s.db.diffs {
+			if s.prevStorage == nil {
+				s.prevStorage = make(Storage)
+			}
+			if _, ok := s.prevStorage[key]; !ok {
+				s.prevStorage[key] = s.originStorage[key]
+			}
+		}
 		s.originStorage[key] = value
 
 		var v []byte
@@ -439,9 +452,12 @@ func (s *stateObject) deepCopy(db *StateDB) *stateObject {
 	stateObject.dirtyStorage = s.dirtyStorage.Copy()
 	stateObject.originStorage = s.originStorage.Copy()
 	stateObject.pendingStorage = s.pendingStorage.Copy()
+	stateObject.prevStorage = s.prevStorage.Copy()
+	stateObject.origin = copyAccount(s.origin)
 	stateObject.suicided = s.suicided
 	stateObject.dirtyCode = s.dirtyCode
 	stateObject.deleted = s.deleted
+	stateObject.recreated = s.recreated
 	return stateObject
 }
 
@@ -43,6 +43,12 @@ type revision struct {
 var (
 	// emptyRoot is the known root hash of an empty trie.
 	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+	// maxStorageDeletion is the maximum number of storage trie nodes deleted
+	// along with a destructed account in the path scheme. Destructing an account
+	// is cheap regardless of its storage, so the deletion has to be bounded to
+	// not stall the block processing.
+	maxStorageDeletion = 100000
 )
 
 type proofList [][]byte
@@ -99,9 +105,16 @@ type StateDB struct {
 
 	preimages map[common.Hash][]byte
 
+	// State changes made by the last commit, only recorded if diffs is set
+	diffs bool
+	diff  *types.StateDiff
+
 	// Per-transaction access list
 	accessList *accessList
 
+	// Accesses made by the executed transactions, nil unless tracked
+	tracker *accessTracker
+
 	// Journal of state modifications. This is the backbone of
 	// Snapshot and RevertToSnapshot.
 	journal        *journal
@@ -309,6 +322,9 @@ func (s *StateDB) GetCodeHash(addr common.Address) common.Hash {
 
 // GetState retrieves a value from the given account's storage trie.
 func (s *StateDB) GetState(addr common.Address, hash common.Hash) common.Hash {
+	if s.tracker != nil {
+		s.tracker.reads.addSlot(addr, hash)
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.GetState(s.db, hash)
@@ -341,6 +357,9 @@ func (s *StateDB) GetStorageProof(a common.Address, key common.Hash) ([][]byte,
 
 // GetCommittedState retrieves a value from the given account's committed storage trie.
 func (s *StateDB) GetCommittedState(addr common.Address, hash common.Hash) common.Hash {
+	if s.tracker != nil {
+		s.tracker.reads.addSlot(addr, hash)
+	}
 	stateObject := s.getStateObject(addr)
 	if stateObject != nil {
 		return stateObject.GetCommittedState(s.db, hash)
@@ -362,7 +381,13 @@ func (s *StateDB) StorageTrie(addr common.Address) Trie {
 	}
 	cpy := stateObject.deepCopy(s)
 	cpy.updateTrie(s.db)
-	return cpy.getTrie(s.db)
+	tr := cpy.getTrie(s.db)
+
+	// Surface a missing storage trie instead of silently handing out an empty one
+	if cpy.dbErr != nil {
+		s.setError(cpy.dbErr)
+	}
+	return tr
 }
 
 func (s *StateDB) HasSuicided(addr common.Address) bool {
@@ -379,6 +404,17 @@ func (s *StateDB) HasSuicided(addr common.Address) bool {
 
 // AddBalance adds amount to the account associated with addr.
 func (s *StateDB) AddBalance(addr common.Address, amount *big.Int) {
+	if s.tracker != nil {
+		// Crediting an account doesn't depend on its previous state
+		s.tracker.muted = true
+		defer func() { s.tracker.muted = false }()
+
+		balance := new(big.Int)
+		if obj := s.getStateObject(addr); obj != nil {
+			balance = obj.data.Balance
+		}
+		s.tracker.credit(addr, balance)
+	}
 	stateObject := s.GetOrNewStateObject(addr)
 	if stateObject != nil {
 		stateObject.AddBalance(amount)
@@ -504,6 +540,9 @@ func (s *StateDB) getStateObject(addr common.Address) *stateObject {
 // flag set. This is needed by the state journal to revert to the correct s-
 // destructed object instead of wiping all knowledge about the state object.
 func (s *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
+	if s.tracker != nil {
+		s.tracker.readAccount(addr)
+	}
 	// Prefer live objects if any is available
 	if obj := s.stateObjects[addr]; obj != nil {
 		return obj
@@ -552,6 +591,9 @@ func (s *StateDB) getDeletedStateObject(addr common.Address) *stateObject {
 	}
 	// Insert into the live set
 	obj := newObject(s, addr, *data)
+	if s.diffs {
+		obj.origin = copyAccount(&obj.data)
+	}
 	s.setStateObject(obj)
 	return obj
 }
@@ -582,6 +624,10 @@ func (s *StateDB) createObject(addr common.Address) (newobj, prev *stateObject)
 		}
 	}
 	newobj = newObject(s, addr, types.StateAccount{})
+	newobj.recreated = prev != nil
+	if prev != nil {
+		newobj.origin = prev.origin
+	}
 	if prev == nil {
 		s.journal.append(createObjectChange{account: &addr})
 	} else {
@@ -657,6 +703,7 @@ func (s *StateDB) Copy() *StateDB {
 		preimages:           make(map[common.Hash][]byte, len(s.preimages)),
 		journal:             newJournal(),
 		hasher:              crypto.NewKeccakState(),
+		diffs:               s.diffs,
 	}
 	// Copy the dirty states, logs, and preimages
 	for addr := range s.journal.dirties {
@@ -812,6 +859,10 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 	if s.prefetcher != nil && len(addressesToPrefetch) > 0 {
 		s.prefetcher.prefetch(common.Hash{}, s.originalRoot, addressesToPrefetch)
 	}
+	// Hand the surviving changes to the access tracker before dropping them
+	if s.tracker != nil {
+		s.tracker.collect(s.journal)
+	}
 	// Invalidate journal because reverting across transactions is not allowed.
 	s.clearJournalAndRefund()
 }
@@ -901,6 +952,9 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 	}
 	// Finalize any pending changes and merge everything into the tries
 	s.IntermediateRoot(deleteEmptyObjects)
+	if s.diffs {
+		s.diff = s.makeStateDiff()
+	}
 
 	// Commit objects to the trie, measuring the elapsed time
 	var (
@@ -910,24 +964,44 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 	)
 	codeWriter := s.db.TrieDB().DiskDB().NewBatch()
 	for addr := range s.stateObjectsDirty {
-		if obj := s.stateObjects[addr]; !obj.deleted {
+		var (
+			obj = s.stateObjects[addr]
+			set *trie.NodeSet
+			err error
+		)
+		if !obj.deleted {
 			// Write any contract code associated with the state object
 			if obj.code != nil && obj.dirtyCode {
 				rawdb.WriteCode(codeWriter, common.BytesToHash(obj.CodeHash()), obj.code)
 				obj.dirtyCode = false
 			}
 			// Write any storage changes in the state object to its storage trie
-			set, err := obj.CommitTrie(s.db)
+			set, err = obj.CommitTrie(s.db)
 			if err != nil {
 				return common.Hash{}, err
 			}
-			// Merge the dirty nodes of storage trie into global set
-			if set != nil {
-				if err := nodes.Merge(set); err != nil {
-					return common.Hash{}, err
-				}
-				storageTrieNodes += set.Len()
+		}
+		// The path scheme only overwrites the nodes of the new storage, the
+		// storage of destructed accounts has to be deleted explicitly.
+		if (obj.deleted || obj.recreated) && s.db.TrieDB().Scheme() == rawdb.PathScheme {
+			set, err = s.deleteStorage(obj, set)
+			if err != nil {
+				return common.Hash{}, err
+			}
+		}
+		// Reset the diff tracking to the committed state
+		obj.recreated = false
+		obj.origin, obj.prevStorage = nil, nil
+		if s.diffs && !obj.deleted {
+			obj.origin = copyAccount(&obj.data)
+		}
+
+		// Merge the dirty nodes of storage trie into global set
+		if set != nil {
+			if err := nodes.Merge(set); err != nil {
+				return common.Hash{}, err
 			}
+			storageTrieNodes += set.Len()
 		}
 	}
 	if len(s.stateObjectsDirty) > 0 {
@@ -986,13 +1060,61 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (common.Hash, error) {
 		}
 		s.snap, s.snapDestructs, s.snapAccounts, s.snapStorage = nil, nil, nil, nil
 	}
-	if err := s.db.TrieDB().Update(nodes); err != nil {
+	if err := s.db.TrieDB().UpdateState(root, s.originalRoot, nodes); err != nil {
 		return common.Hash{}, err
 	}
 	s.originalRoot = root
 	return root, err
 }
 
+// deleteStorage marks the nodes of the storage trie an account had before the
+// changes as deleted, except for the ones overwritten by the given set of the
+// new storage trie.
+//
+// At most maxStorageDeletion nodes are deleted, the remaining ones are left on
+// disk. They can't be reached from any state and nodes are verified against
+// their hash when resolved, so they only waste space.
+func (s *StateDB) deleteStorage(obj *stateObject, set *trie.NodeSet) (*trie.NodeSet, error) {
+	tr, err := s.db.OpenTrie(s.originalRoot)
+	if err != nil {
+		return nil, err
+	}
+	account, err := tr.TryGetAccount(obj.address.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if account == nil || account.Root == emptyRoot {
+		return set, nil
+	}
+	storage, err := s.db.OpenStorageTrie(obj.addrHash, account.Root)
+	if err != nil {
+		return nil, err
+	}
+	if set == nil {
+		set = trie.NewNodeSet(obj.addrHash)
+	}
+	var (
+		deleted int
+		it      = storage.NodeIterator(nil)
+	)
+	for it.Next(true) {
+		// Embedded nodes aren't stored on their own
+		if it.Hash() == (common.Hash{}) {
+			continue
+		}
+		if deleted >= maxStorageDeletion {
+			log.Warn("Storage too large to delete", "address", obj.address, "root", account.Root, "deleted", deleted)
+			return set, nil
+		}
+		set.MarkDeleted(it.Path())
+		deleted++
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
 // PrepareAccessList handles the preparatory steps for executing a state transition with
 // regards to both EIP-2929 and EIP-2930:
 //
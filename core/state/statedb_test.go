@@ -32,14 +32,28 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
 )
 
+// newTestDatabase creates a state database storing the trie nodes in the given
+// scheme.
+func newTestDatabase(diskdb ethdb.Database, scheme string) Database {
+	return NewDatabaseWithConfig(diskdb, &trie.Config{Scheme: scheme})
+}
+
 // Tests that updating a state trie does not leak any database writes prior to
 // actually committing the state.
 func TestUpdateLeaks(t *testing.T) {
+	testUpdateLeaks(t, rawdb.HashScheme)
+	testUpdateLeaks(t, rawdb.PathScheme)
+}
+
+func testUpdateLeaks(t *testing.T, scheme string) {
 	// Create an empty state database
 	db := rawdb.NewMemoryDatabase()
-	state, _ := New(common.Hash{}, NewDatabase(db), nil)
+	state, _ := New(common.Hash{}, newTestDatabase(db, scheme), nil)
 
 	// Update it with some accounts
 	for i := byte(0); i < 255; i++ {
@@ -54,8 +68,9 @@ func TestUpdateLeaks(t *testing.T) {
 		}
 	}
 
+	// The path-based database rejects the uncommitted state as unknown
 	root := state.IntermediateRoot(false)
-	if err := state.Database().TrieDB().Commit(root, false, nil); err != nil {
+	if err := state.Database().TrieDB().Commit(root, false, nil); err != nil && scheme == rawdb.HashScheme {
 		t.Errorf("can not commit trie %v to persistent database", root.Hex())
 	}
 
@@ -70,11 +85,16 @@ func TestUpdateLeaks(t *testing.T) {
 // Tests that no intermediate state of an object is stored into the database,
 // only the one right before the commit.
 func TestIntermediateLeaks(t *testing.T) {
+	testIntermediateLeaks(t, rawdb.HashScheme)
+	testIntermediateLeaks(t, rawdb.PathScheme)
+}
+
+func testIntermediateLeaks(t *testing.T, scheme string) {
 	// Create two state databases, one transitioning to the final state, the other final from the beginning
 	transDb := rawdb.NewMemoryDatabase()
 	finalDb := rawdb.NewMemoryDatabase()
-	transState, _ := New(common.Hash{}, NewDatabase(transDb), nil)
-	finalState, _ := New(common.Hash{}, NewDatabase(finalDb), nil)
+	transState, _ := New(common.Hash{}, newTestDatabase(transDb, scheme), nil)
+	finalState, _ := New(common.Hash{}, newTestDatabase(finalDb, scheme), nil)
 
 	modify := func(state *StateDB, addr common.Address, i, tweak byte) {
 		state.SetBalance(addr, big.NewInt(int64(11*i)+int64(tweak)))
@@ -508,7 +528,12 @@ func TestCopyOfCopy(t *testing.T) {
 //
 // See https://github.com/ethereum/go-ethereum/issues/20106.
 func TestCopyCommitCopy(t *testing.T) {
-	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	testCopyCommitCopy(t, rawdb.HashScheme)
+	testCopyCommitCopy(t, rawdb.PathScheme)
+}
+
+func testCopyCommitCopy(t *testing.T, scheme string) {
+	state, _ := New(common.Hash{}, newTestDatabase(rawdb.NewMemoryDatabase(), scheme), nil)
 
 	// Create an account and check if the retrieved balance is correct
 	addr := common.HexToAddress("0xaffeaffeaffeaffeaffeaffeaffeaffeaffeaffe")
@@ -580,7 +605,12 @@ func TestCopyCommitCopy(t *testing.T) {
 //
 // See https://github.com/ethereum/go-ethereum/issues/20106.
 func TestCopyCopyCommitCopy(t *testing.T) {
-	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	testCopyCopyCommitCopy(t, rawdb.HashScheme)
+	testCopyCopyCommitCopy(t, rawdb.PathScheme)
+}
+
+func testCopyCopyCommitCopy(t *testing.T, scheme string) {
+	state, _ := New(common.Hash{}, newTestDatabase(rawdb.NewMemoryDatabase(), scheme), nil)
 
 	// Create an account and check if the retrieved balance is correct
 	addr := common.HexToAddress("0xaffeaffeaffeaffeaffeaffeaffeaffeaffeaffe")
@@ -669,8 +699,13 @@ func TestCopyCopyCommitCopy(t *testing.T) {
 // each transaction, so this works ok. The rework accumulated writes in memory
 // first, but the journal wiped the entire state object on create-revert.
 func TestDeleteCreateRevert(t *testing.T) {
+	testDeleteCreateRevert(t, rawdb.HashScheme)
+	testDeleteCreateRevert(t, rawdb.PathScheme)
+}
+
+func testDeleteCreateRevert(t *testing.T, scheme string) {
 	// Create an initial state with a single contract
-	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	state, _ := New(common.Hash{}, newTestDatabase(rawdb.NewMemoryDatabase(), scheme), nil)
 
 	addr := common.BytesToAddress([]byte("so"))
 	state.SetBalance(addr, big.NewInt(1))
@@ -695,13 +730,136 @@ func TestDeleteCreateRevert(t *testing.T) {
 	}
 }
 
+// Tests that the storage of destructed and recreated accounts is removed from
+// disk in the path scheme, as its nodes aren't overwritten by the new storage.
+func TestDeleteStoragePathScheme(t *testing.T) {
+	memDb := rawdb.NewMemoryDatabase()
+	db := NewDatabaseWithConfig(memDb, &trie.Config{Scheme: rawdb.PathScheme})
+
+	var (
+		deleted   = common.BytesToAddress([]byte("deleted"))
+		recreated = common.BytesToAddress([]byte("recreated"))
+	)
+	state, _ := New(common.Hash{}, db, nil)
+	for i := 0; i < 64; i++ {
+		state.SetState(deleted, common.BigToHash(big.NewInt(int64(i))), common.Hash{0x01})
+		state.SetState(recreated, common.BigToHash(big.NewInt(int64(i))), common.Hash{0x01})
+	}
+	root, _ := state.Commit(false)
+
+	// Destruct both accounts and recreate one with a different storage
+	state, _ = New(root, db, nil)
+	state.Suicide(deleted)
+	state.Suicide(recreated)
+	state.Finalise(true)
+	state.SetBalance(recreated, big.NewInt(1))
+	state.SetState(recreated, common.Hash{0x02}, common.Hash{0x02})
+	root, err := state.Commit(true)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := db.TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to persist state: %v", err)
+	}
+	// Ensure only the nodes of the latest state are left on disk
+	state, _ = New(root, db, nil)
+	if have := state.GetState(recreated, common.Hash{}); have != (common.Hash{}) {
+		t.Fatalf("storage of recreated account left: %x", have)
+	}
+	if have := state.GetState(recreated, common.Hash{0x02}); have != (common.Hash{0x02}) {
+		t.Fatalf("storage of recreated account mismatch: have %x, want %x", have, common.Hash{0x02})
+	}
+	var want int
+	it := NewNodeIterator(state)
+	for it.Next() {
+		if it.Hash != (common.Hash{}) && it.code == nil {
+			want++
+		}
+	}
+	if it.Error != nil {
+		t.Fatalf("failed to iterate state: %v", it.Error)
+	}
+	var have int
+	dbIt := memDb.NewIterator(nil, nil)
+	defer dbIt.Release()
+	for dbIt.Next() {
+		if rawdb.IsPathTrieNodeKey(dbIt.Key()) {
+			have++
+		}
+	}
+	if have != want {
+		t.Fatalf("persisted node count mismatch: have %d, want %d", have, want)
+	}
+}
+
+// Tests that the deletion of the storage of destructed accounts is bounded in
+// the path scheme, the excess nodes being left on disk without affecting the
+// state.
+func TestDeleteStorageLimit(t *testing.T) {
+	defer func(limit int) { maxStorageDeletion = limit }(maxStorageDeletion)
+	maxStorageDeletion = 4
+
+	memDb := rawdb.NewMemoryDatabase()
+	db := newTestDatabase(memDb, rawdb.PathScheme)
+
+	persisted := func() int {
+		it := memDb.NewIterator(nil, nil)
+		defer it.Release()
+
+		var nodes int
+		for it.Next() {
+			if rawdb.IsPathTrieNodeKey(it.Key()) {
+				nodes++
+			}
+		}
+		return nodes
+	}
+	addr := common.BytesToAddress([]byte("deleted"))
+	state, _ := New(common.Hash{}, db, nil)
+	for i := 0; i < 64; i++ {
+		state.SetState(addr, common.BigToHash(big.NewInt(int64(i))), common.Hash{0x01})
+	}
+	root, _ := state.Commit(false)
+	if err := db.TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to persist state: %v", err)
+	}
+	before := persisted()
+
+	state, _ = New(root, db, nil)
+	state.Suicide(addr)
+	root, err := state.Commit(true)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := db.TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to persist state: %v", err)
+	}
+	// The root node of the emptied account trie is deleted along with the
+	// limited number of storage nodes
+	if have, want := before-persisted(), maxStorageDeletion+1; have != want {
+		t.Fatalf("deleted node count mismatch: have %d, want %d", have, want)
+	}
+	state, _ = New(root, db, nil)
+	if state.Exist(addr) {
+		t.Fatal("destructed account left in state")
+	}
+	if have := state.GetState(addr, common.Hash{}); have != (common.Hash{}) {
+		t.Fatalf("storage of destructed account left: %x", have)
+	}
+}
+
 // TestMissingTrieNodes tests that if the StateDB fails to load parts of the trie,
 // the Commit operation fails with an error
 // If we are missing trie nodes, we should not continue writing to the trie
 func TestMissingTrieNodes(t *testing.T) {
+	testMissingTrieNodes(t, rawdb.HashScheme)
+	testMissingTrieNodes(t, rawdb.PathScheme)
+}
+
+func testMissingTrieNodes(t *testing.T, scheme string) {
 	// Create an initial state with a few accounts
 	memDb := rawdb.NewMemoryDatabase()
-	db := NewDatabase(memDb)
+	db := newTestDatabase(memDb, scheme)
 	var root common.Hash
 	state, _ := New(common.Hash{}, db, nil)
 	addr := common.BytesToAddress([]byte("so"))
@@ -722,8 +880,8 @@ func TestMissingTrieNodes(t *testing.T) {
 	it := memDb.NewIterator(nil, nil)
 	for it.Next() {
 		k := it.Key()
-		// Leave the root intact
-		if !bytes.Equal(k, root[:]) {
+		// Leave the root intact, it's keyed by its path in the path scheme
+		if crypto.Keccak256Hash(it.Value()) != root {
 			t.Logf("key: %x", k)
 			memDb.Delete(k)
 		}
@@ -918,10 +1076,15 @@ func TestStateDBAccessList(t *testing.T) {
 // Tests that account and storage tries are flushed in the correct order and that
 // no data loss occurs.
 func TestFlushOrderDataLoss(t *testing.T) {
+	testFlushOrderDataLoss(t, rawdb.HashScheme)
+	testFlushOrderDataLoss(t, rawdb.PathScheme)
+}
+
+func testFlushOrderDataLoss(t *testing.T, scheme string) {
 	// Create a state trie with many accounts and slots
 	var (
 		memdb    = rawdb.NewMemoryDatabase()
-		statedb  = NewDatabase(memdb)
+		statedb  = newTestDatabase(memdb, scheme)
 		state, _ = New(common.Hash{}, statedb, nil)
 	)
 	for a := byte(0); a < 10; a++ {
@@ -942,7 +1105,7 @@ func TestFlushOrderDataLoss(t *testing.T) {
 		t.Fatalf("failed to commit state trie: %v", err)
 	}
 	// Reopen the state trie from flushed disk and verify it
-	state, err = New(root, NewDatabase(memdb), nil)
+	state, err = New(root, newTestDatabase(memdb, scheme), nil)
 	if err != nil {
 		t.Fatalf("failed to reopen state trie: %v", err)
 	}
@@ -0,0 +1,99 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TrackStateDiff enables recording the state changes made by each Commit. It
+// has to be called before the state is accessed, as the values of the loaded
+// accounts are captured on first access.
+func (s *StateDB) TrackStateDiff() {
+	s.diffs = true
+}
+
+// StateDiff returns the state changes made by the last Commit, or nil if the
+// state was not committed yet or the changes are not tracked.
+func (s *StateDB) StateDiff() *types.StateDiff {
+	return s.diff
+}
+
+// makeStateDiff collects the changes of the dirty objects since the last commit.
+// It needs to be called once all changes are merged into the tries.
+func (s *StateDB) makeStateDiff() *types.StateDiff {
+	diff := new(types.StateDiff)
+	for addr := range s.stateObjectsDirty {
+		obj := s.stateObjects[addr]
+
+		account := &types.AccountDiff{
+			Address:        addr,
+			Pre:            copyAccount(obj.origin),
+			StorageCleared: (obj.deleted || obj.recreated) && obj.origin != nil && obj.origin.Root != emptyRoot,
+		}
+		if !obj.deleted {
+			account.Post = copyAccount(&obj.data)
+		}
+		for key, prev := range obj.prevStorage {
+			var post common.Hash
+			if !obj.deleted {
+				post = obj.originStorage[key]
+			}
+			if prev != post {
+				account.Storage = append(account.Storage, &types.StorageDiff{Key: key, Pre: prev, Post: post})
+			}
+		}
+		// Skip the objects touched without changes
+		if len(account.Storage) == 0 && !account.StorageCleared && equalAccounts(account.Pre, account.Post) {
+			continue
+		}
+		sort.Slice(account.Storage, func(i, j int) bool {
+			return bytes.Compare(account.Storage[i].Key[:], account.Storage[j].Key[:]) < 0
+		})
+		diff.Accounts = append(diff.Accounts, account)
+	}
+	sort.Slice(diff.Accounts, func(i, j int) bool {
+		return bytes.Compare(diff.Accounts[i].Address[:], diff.Accounts[j].Address[:]) < 0
+	})
+	return diff
+}
+
+// copyAccount returns a deep copy of an account, nil if it's nil.
+func copyAccount(account *types.StateAccount) *types.StateAccount {
+	if account == nil {
+		return nil
+	}
+	return &types.StateAccount{
+		Nonce:    account.Nonce,
+		Balance:  new(big.Int).Set(account.Balance),
+		Root:     account.Root,
+		CodeHash: common.CopyBytes(account.CodeHash),
+	}
+}
+
+// equalAccounts reports whether two accounts, possibly nil, are the same.
+func equalAccounts(a, b *types.StateAccount) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Nonce == b.Nonce && a.Balance.Cmp(b.Balance) == 0 && a.Root == b.Root && bytes.Equal(a.CodeHash, b.CodeHash)
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that committing a state produces the diff of the changes made since the
+// previous commit.
+func TestStateDiff(t *testing.T) {
+	var (
+		updated   = common.Address{0x01}
+		touched   = common.Address{0x02}
+		recreated = common.Address{0x03}
+		created   = common.Address{0x04}
+		deleted   = common.Address{0x05}
+
+		one, two, three = common.Hash{0x01}, common.Hash{0x02}, common.Hash{0x03}
+	)
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	state.TrackStateDiff()
+	state.SetBalance(updated, big.NewInt(1))
+	state.SetState(updated, one, one)
+	state.SetState(updated, two, two)
+	state.SetBalance(touched, big.NewInt(2))
+	state.SetBalance(recreated, big.NewInt(3))
+	state.SetState(recreated, one, one)
+	state.SetBalance(deleted, big.NewInt(4))
+	state.SetState(deleted, one, one)
+	root, _ := state.Commit(false)
+
+	diff := state.StateDiff()
+	if len(diff.Accounts) != 4 {
+		t.Fatalf("account count mismatch: have %d, want 4", len(diff.Accounts))
+	}
+	if account := diff.Accounts[0]; account.Pre != nil || account.Post.Balance.Int64() != 1 || len(account.Storage) != 2 {
+		t.Fatalf("created account mismatch: %s", spew.Sdump(account))
+	}
+	// Apply all kinds of changes on top of the committed state
+	state, _ = New(root, state.db, nil)
+	state.TrackStateDiff()
+	pre := make(map[common.Address]*types.StateAccount)
+	for _, addr := range []common.Address{updated, recreated, deleted} {
+		pre[addr] = copyAccount(&state.getStateObject(addr).data)
+	}
+	state.SetState(updated, one, three)
+	state.SetState(updated, two, common.Hash{})
+	state.SetState(updated, three, three)
+	state.SetState(updated, three, common.Hash{}) // Reverted within the block
+	state.AddBalance(touched, new(big.Int))
+	state.Suicide(recreated)
+	state.Suicide(deleted)
+	state.Finalise(true)
+	state.SetBalance(recreated, big.NewInt(5))
+	state.SetState(recreated, two, two)
+	state.SetNonce(created, 1)
+	state.Commit(true)
+
+	post := make(map[common.Address]*types.StateAccount)
+	for _, addr := range []common.Address{updated, recreated, created} {
+		post[addr] = copyAccount(&state.getStateObject(addr).data)
+	}
+	want := &types.StateDiff{Accounts: []*types.AccountDiff{
+		{
+			Address: updated,
+			Pre:     pre[updated],
+			Post:    post[updated],
+			Storage: []*types.StorageDiff{
+				{Key: one, Pre: one, Post: three},
+				{Key: two, Pre: two},
+			},
+		},
+		{
+			Address:        recreated,
+			Pre:            pre[recreated],
+			Post:           post[recreated],
+			StorageCleared: true,
+			Storage:        []*types.StorageDiff{{Key: two, Post: two}},
+		},
+		{
+			Address: created,
+			Post:    post[created],
+		},
+		{
+			Address:        deleted,
+			Pre:            pre[deleted],
+			StorageCleared: true,
+		},
+	}}
+	if have := state.StateDiff(); !reflect.DeepEqual(have, want) {
+		t.Fatalf("state diff mismatch:\nhave %s\nwant %s", spew.Sdump(have), spew.Sdump(want))
+	}
+	// Ensure a further commit only reports the new changes
+	state.SetState(updated, one, one)
+	state.Commit(true)
+
+	want = &types.StateDiff{Accounts: []*types.AccountDiff{{
+		Address: updated,
+		Pre:     post[updated],
+		Post:    copyAccount(&state.getStateObject(updated).data),
+		Storage: []*types.StorageDiff{{Key: one, Pre: three, Post: one}},
+	}}}
+	if have := state.StateDiff(); !reflect.DeepEqual(have, want) {
+		t.Fatalf("second state diff mismatch:\nhave %s\nwant %s", spew.Sdump(have), spew.Sdump(want))
+	}
+}
+
+// Tests that no state diff is recorded unless tracking is enabled.
+func TestStateDiffUntracked(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	state.SetBalance(common.Address{0x01}, big.NewInt(1))
+	state.SetState(common.Address{0x01}, common.Hash{0x01}, common.Hash{0x01})
+	state.Commit(false)
+
+	if diff := state.StateDiff(); diff != nil {
+		t.Fatalf("untracked state diff recorded: %s", spew.Sdump(diff))
+	}
+	if obj := state.getStateObject(common.Address{0x01}); obj.origin != nil || obj.prevStorage != nil {
+		t.Fatalf("untracked state object captured diff data: %v %v", obj.origin, obj.prevStorage)
+	}
+}
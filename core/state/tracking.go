@@ -0,0 +1,257 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// AccessSet is a set of accounts and storage slots accessed by a transaction.
+type AccessSet struct {
+	Accounts map[common.Address]struct{}
+	Slots    map[common.Address]map[common.Hash]struct{}
+}
+
+// NewAccessSet creates an empty access set.
+func NewAccessSet() *AccessSet {
+	return &AccessSet{
+		Accounts: make(map[common.Address]struct{}),
+		Slots:    make(map[common.Address]map[common.Hash]struct{}),
+	}
+}
+
+// addAccount inserts an account into the set.
+func (set *AccessSet) addAccount(addr common.Address) {
+	set.Accounts[addr] = struct{}{}
+}
+
+// addSlot inserts a storage slot into the set.
+func (set *AccessSet) addSlot(addr common.Address, key common.Hash) {
+	slots, ok := set.Slots[addr]
+	if !ok {
+		slots = make(map[common.Hash]struct{})
+		set.Slots[addr] = slots
+	}
+	slots[key] = struct{}{}
+}
+
+// Merge adds all the accounts and slots of another set into this one.
+func (set *AccessSet) Merge(other *AccessSet) {
+	for addr := range other.Accounts {
+		set.addAccount(addr)
+	}
+	for addr, slots := range other.Slots {
+		for key := range slots {
+			set.addSlot(addr, key)
+		}
+	}
+}
+
+// Overlaps reports whether the two sets share any account or storage slot.
+func (set *AccessSet) Overlaps(other *AccessSet) bool {
+	for addr := range set.Accounts {
+		if _, ok := other.Accounts[addr]; ok {
+			return true
+		}
+	}
+	for addr, slots := range set.Slots {
+		theirs, ok := other.Slots[addr]
+		if !ok {
+			continue
+		}
+		for key := range slots {
+			if _, ok := theirs[key]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// accessTracker records the state accessed by a transaction, so that its
+// speculative execution against a stale state can be validated and replayed.
+//
+// Balance credits are tracked separately from other writes: an account that is
+// only ever credited (e.g. the coinbase receiving fees) is not read by the
+// transaction, so the credit commutes with the changes of other transactions
+// and can be replayed as a delta instead of an absolute value.
+type accessTracker struct {
+	reads   *AccessSet                  // Accounts and slots the transaction depends on
+	writes  *AccessSet                  // Accounts and slots changed by the transaction
+	created map[common.Address]struct{} // Accounts (re)created by the transaction
+	credits map[common.Address]*big.Int // Balances of the credited accounts before the first credit
+	muted   bool                        // Whether account reads are currently not tracked
+}
+
+// newAccessTracker creates an empty access tracker.
+func newAccessTracker() *accessTracker {
+	return &accessTracker{
+		reads:   NewAccessSet(),
+		writes:  NewAccessSet(),
+		created: make(map[common.Address]struct{}),
+		credits: make(map[common.Address]*big.Int),
+	}
+}
+
+// readAccount records an access to the given account, unless it happens as
+// part of a balance credit.
+func (t *accessTracker) readAccount(addr common.Address) {
+	if !t.muted {
+		t.reads.addAccount(addr)
+	}
+}
+
+// credit records that the given account is about to be credited. Only the
+// balance before the first credit is kept, the replayed delta is derived from
+// the final balance so that credits made in reverted calls are dropped.
+func (t *accessTracker) credit(addr common.Address, balance *big.Int) {
+	if _, ok := t.credits[addr]; !ok {
+		t.credits[addr] = new(big.Int).Set(balance)
+	}
+}
+
+// creditOnly reports whether the only access made to an account was crediting
+// its balance.
+func (t *accessTracker) creditOnly(addr common.Address) bool {
+	if _, ok := t.credits[addr]; !ok {
+		return false
+	}
+	_, read := t.reads.Accounts[addr]
+	return !read
+}
+
+// collect gathers the changes of a journal about to be cleared. Changes that
+// have been reverted are already dropped from the journal, so the writes are
+// exactly the ones surviving the transaction.
+func (t *accessTracker) collect(j *journal) {
+	for _, entry := range j.entries {
+		switch entry := entry.(type) {
+		case storageChange:
+			t.writes.addSlot(*entry.account, entry.key)
+		case createObjectChange:
+			t.writes.addAccount(*entry.account)
+			t.created[*entry.account] = struct{}{}
+		case resetObjectChange:
+			t.writes.addAccount(entry.prev.address)
+			t.created[entry.prev.address] = struct{}{}
+		default:
+			if addr := entry.dirtied(); addr != nil {
+				t.writes.addAccount(*addr)
+			}
+		}
+	}
+	// Pick up the accounts made dirty outside of the journal entries (RIPEMD)
+	for addr := range j.dirties {
+		if _, ok := t.writes.Slots[addr]; !ok {
+			t.writes.addAccount(addr)
+		}
+	}
+}
+
+// TrackAccesses starts (or restarts) recording the accounts and storage slots
+// accessed through the state, along with the changes surviving each Finalise.
+func (s *StateDB) TrackAccesses() {
+	s.tracker = newAccessTracker()
+}
+
+// StopTrackingAccesses stops recording the accesses made through the state.
+func (s *StateDB) StopTrackingAccesses() {
+	s.tracker = nil
+}
+
+// TrackedReads returns the accounts and storage slots read since tracking was
+// started. Accounts only credited with a balance are not considered read.
+func (s *StateDB) TrackedReads() *AccessSet {
+	return s.tracker.reads
+}
+
+// TrackedWrites returns the accounts and storage slots changed by the finalised
+// transactions since tracking was started.
+func (s *StateDB) TrackedWrites() *AccessSet {
+	return s.tracker.writes
+}
+
+// ApplyTracked replays the finalised changes recorded on src onto s. The src
+// state is expected to be a copy of an earlier version of s, which executed a
+// single transaction while tracking its accesses. The replay is only correct
+// if none of the reads of src were changed in s since the copy was made; the
+// caller is responsible for validating that via the tracked access sets.
+//
+// The transaction hash and index of the logs are taken from s, which needs to
+// be prepared for the transaction beforehand.
+func (s *StateDB) ApplyTracked(src *StateDB) {
+	t := src.tracker
+
+	for addr := range t.writes.Accounts {
+		// Credits commute with the changes of other transactions, replay them
+		// as they were made to get the same account creations and touches.
+		if t.creditOnly(addr) {
+			balance := new(big.Int)
+			if obj := src.stateObjects[addr]; obj != nil {
+				balance = obj.data.Balance
+			}
+			s.AddBalance(addr, new(big.Int).Sub(balance, t.credits[addr]))
+			continue
+		}
+		s.applyTracked(src, addr, true)
+	}
+	for addr := range t.writes.Slots {
+		if _, ok := t.writes.Accounts[addr]; ok {
+			continue // already replayed with the account
+		}
+		s.applyTracked(src, addr, false)
+	}
+	for _, log := range src.logs[src.thash] {
+		cpy := new(types.Log)
+		*cpy = *log
+		s.AddLog(cpy)
+	}
+	for hash, preimage := range src.preimages {
+		s.AddPreimage(hash, preimage)
+	}
+}
+
+// applyTracked copies the finalised changes of a single account from src into
+// s. The account fields are only replayed if they were changed, otherwise just
+// the written storage slots are.
+func (s *StateDB) applyTracked(src *StateDB, addr common.Address, fields bool) {
+	obj := src.stateObjects[addr]
+	if obj == nil {
+		return // touched RIPEMD which was never loaded, nothing to do
+	}
+	if fields {
+		if _, ok := src.tracker.created[addr]; ok {
+			s.CreateAccount(addr)
+		}
+		if obj.suicided {
+			s.Suicide(addr)
+			return
+		}
+		s.SetNonce(addr, obj.data.Nonce)
+		s.SetBalance(addr, obj.data.Balance)
+		if !bytes.Equal(obj.CodeHash(), s.GetCodeHash(addr).Bytes()) {
+			s.SetCode(addr, obj.Code(src.db))
+		}
+	}
+	for key := range src.tracker.writes.Slots[addr] {
+		s.SetState(addr, key, obj.pendingStorage[key])
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that replaying the tracked changes of transactions executed against a
+// stale state, re-executing the ones whose reads were invalidated, results in
+// the same state and logs as executing them in order.
+func TestApplyTracked(t *testing.T) {
+	var (
+		coinbase = common.Address{0xc0}
+		alice    = common.Address{0x01}
+		bob      = common.Address{0x02}
+		carol    = common.Address{0x03}
+		dave     = common.Address{0x04}
+		token    = common.Address{0x05}
+		doomed   = common.Address{0x06}
+		heir     = common.Address{0x07}
+		lucky    = common.Address{0x08}
+		empty    = common.Address{0x09}
+		fresh    = common.Address{0x0a}
+
+		one, two, three = common.Hash{0x01}, common.Hash{0x02}, common.Hash{0x03}
+	)
+	state, _ := New(common.Hash{}, NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	for _, addr := range []common.Address{alice, bob, carol, dave, doomed} {
+		state.SetBalance(addr, big.NewInt(100))
+	}
+	state.SetCode(token, []byte{0x60})
+	state.SetState(token, one, common.Hash{0x10})
+	state.SetState(token, two, common.Hash{0x20})
+	state.SetState(doomed, one, one)
+	state.SetBalance(empty, big.NewInt(1))
+	root, _ := state.Commit(false)
+	state.Database().TrieDB().Commit(root, false, nil)
+
+	// Transactions access the state the same way the EVM does: accounts are
+	// checked for existence and slots are read before being written
+	transfer := func(from, to common.Address, amount int64) func(*StateDB) {
+		return func(s *StateDB) {
+			s.GetBalance(from)
+			s.SubBalance(from, big.NewInt(amount))
+			s.Exist(to)
+			s.AddBalance(to, big.NewInt(amount))
+		}
+	}
+	increment := func(slot common.Hash) func(*StateDB) {
+		return func(s *StateDB) {
+			value := s.GetState(token, slot).Big()
+			s.GetCommittedState(token, slot)
+			s.SetState(token, slot, common.BigToHash(value.Add(value, big.NewInt(1))))
+		}
+	}
+	txs := []func(*StateDB){
+		transfer(alice, bob, 10),
+		transfer(carol, dave, 20),
+		transfer(bob, carol, 30), // Conflicts with both transfers above
+		increment(one),
+		increment(two),
+		increment(one), // Conflicts with the first increment
+		func(s *StateDB) {
+			s.Suicide(doomed)
+			s.AddBalance(heir, big.NewInt(100))
+		},
+		func(s *StateDB) {
+			snap := s.Snapshot()
+			s.AddBalance(lucky, big.NewInt(5))
+			s.RevertToSnapshot(snap)
+			s.AddBalance(lucky, big.NewInt(1))
+		},
+		func(s *StateDB) {
+			s.GetBalance(empty)
+			s.SubBalance(empty, big.NewInt(1))
+			s.AddBalance(empty, new(big.Int)) // Touch it for deletion
+		},
+		func(s *StateDB) {
+			s.Exist(fresh)
+			s.CreateAccount(fresh)
+			s.SetNonce(fresh, 1)
+			s.SetCode(fresh, []byte{0x61})
+			s.GetState(fresh, three)
+			s.SetState(fresh, three, three)
+			s.AddLog(&types.Log{Address: fresh})
+		},
+		func(s *StateDB) {
+			s.GetBalance(heir) // Conflicts with the credit of the suicide
+			s.AddLog(&types.Log{Address: heir})
+		},
+	}
+	execute := func(s *StateDB, i int) {
+		s.Prepare(common.Hash{byte(i)}, i)
+		txs[i](s)
+		s.AddBalance(coinbase, big.NewInt(int64(i)))
+		s.Finalise(true)
+	}
+	// Execute the transactions in order
+	sequential, _ := New(root, state.Database(), nil)
+	for i := range txs {
+		execute(sequential, i)
+	}
+	// Execute the transactions against the initial state and replay them
+	parallel, _ := New(root, state.Database(), nil)
+
+	specs := make([]*StateDB, len(txs))
+	for i := range txs {
+		specs[i] = parallel.Copy()
+		specs[i].TrackAccesses()
+		execute(specs[i], i)
+	}
+	var (
+		written     = NewAccessSet()
+		reexecuted  []int
+		reexecution = []int{2, 5, 10}
+	)
+	for i, spec := range specs {
+		if !spec.TrackedReads().Overlaps(written) {
+			parallel.Prepare(common.Hash{byte(i)}, i)
+			parallel.ApplyTracked(spec)
+			parallel.Finalise(true)
+			written.Merge(spec.TrackedWrites())
+			continue
+		}
+		reexecuted = append(reexecuted, i)
+
+		parallel.TrackAccesses()
+		execute(parallel, i)
+		written.Merge(parallel.TrackedWrites())
+		parallel.StopTrackingAccesses()
+	}
+	if !reflect.DeepEqual(reexecuted, reexecution) {
+		t.Errorf("re-executed transactions mismatch: have %v, want %v", reexecuted, reexecution)
+	}
+	if have, want := parallel.IntermediateRoot(true), sequential.IntermediateRoot(true); have != want {
+		t.Errorf("state root mismatch: have %x, want %x", have, want)
+	}
+	for i := range txs {
+		hash := common.Hash{byte(i)}
+		if have, want := parallel.GetLogs(hash, common.Hash{}), sequential.GetLogs(hash, common.Hash{}); !reflect.DeepEqual(have, want) {
+			t.Errorf("tx %d: logs mismatch: have %v, want %v", i, have, want)
+		}
+	}
+	if balance := parallel.GetBalance(lucky); balance.Int64() != 1 {
+		t.Errorf("reverted credit replayed: have balance %v, want 1", balance)
+	}
+	if parallel.Exist(empty) {
+		t.Errorf("touched empty account not deleted")
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// witnessDB wraps a state database, recording all the trie nodes and contract
+// code read through it into a witness.
+type witnessDB struct {
+	Database
+	witness *stateless.Witness
+}
+
+// NewWitnessDatabase wraps a state database to record the trie nodes and code
+// accessed through it into the given witness.
+//
+// A StateDB on top of it must be created without snapshots, so that all the
+// state is read through the tries. Without a snapshot no prefetcher is run
+// either, whose tries would escape the recording.
+func NewWitnessDatabase(db Database, witness *stateless.Witness) Database {
+	return &witnessDB{Database: db, witness: witness}
+}
+
+// OpenTrie opens the main account trie, recording the nodes it loads.
+func (db *witnessDB) OpenTrie(root common.Hash) (Trie, error) {
+	tr, err := trie.NewStateTrieWithWitness(common.Hash{}, root, db.TrieDB(), db.witness)
+	if err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// OpenStorageTrie opens the storage trie of an account, recording the nodes it
+// loads.
+func (db *witnessDB) OpenStorageTrie(addrHash, root common.Hash) (Trie, error) {
+	tr, err := trie.NewStateTrieWithWitness(addrHash, root, db.TrieDB(), db.witness)
+	if err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// ContractCode retrieves a particular contract's code and records it.
+func (db *witnessDB) ContractCode(addrHash, codeHash common.Hash) ([]byte, error) {
+	code, err := db.Database.ContractCode(addrHash, codeHash)
+	if err == nil {
+		db.witness.AddCode(code)
+	}
+	return code, err
+}
+
+// ContractCodeSize retrieves a particular contract's code size. The whole code
+// is recorded, since it is needed to learn the size without the database.
+func (db *witnessDB) ContractCodeSize(addrHash, codeHash common.Hash) (int, error) {
+	code, err := db.ContractCode(addrHash, codeHash)
+	return len(code), err
+}
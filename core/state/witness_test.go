@@ -0,0 +1,105 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that the witness recorded while mutating a state is sufficient to apply
+// the same mutations to the state opened from the witness alone.
+func TestWitness(t *testing.T) {
+	db := NewDatabase(rawdb.NewMemoryDatabase())
+	state, _ := New(common.Hash{}, db, nil)
+	for i := byte(0); i < 200; i++ {
+		addr := common.Address{i}
+		state.SetBalance(addr, big.NewInt(int64(i)+1))
+		if i%10 == 0 {
+			state.SetCode(addr, []byte{i, 0x60, 0x00})
+			for j := byte(0); j < 50; j++ {
+				state.SetState(addr, common.Hash{j}, common.Hash{i, j})
+			}
+		}
+	}
+	root, _ := state.Commit(false)
+	if err := db.TrieDB().Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	// Read, modify and delete accounts and slots, including enough deletions to
+	// collapse trie nodes
+	mutate := func(state *StateDB) common.Hash {
+		for i := byte(0); i < 200; i += 3 {
+			addr := common.Address{i}
+			switch i % 9 {
+			case 0:
+				if have, want := state.GetBalance(addr), big.NewInt(int64(i)+1); have.Cmp(want) != 0 {
+					t.Fatalf("account %d: balance mismatch: have %v, want %v", i, have, want)
+				}
+			case 3:
+				state.AddBalance(addr, big.NewInt(1))
+			case 6:
+				state.Suicide(addr)
+			}
+			if i%10 == 0 {
+				state.GetCodeSize(common.Address{i + 10})
+				for j := byte(0); j < 50; j += 2 {
+					if have, want := state.GetState(addr, common.Hash{j}), (common.Hash{i, j}); have != want && i%9 != 6 {
+						t.Fatalf("account %d slot %d: value mismatch: have %x, want %x", i, j, have, want)
+					}
+					state.SetState(addr, common.Hash{j + 1}, common.Hash{})
+				}
+			}
+		}
+		return state.IntermediateRoot(true)
+	}
+	witness := stateless.NewWitness(&types.Header{Root: root, Number: big.NewInt(1), Difficulty: big.NewInt(1)})
+	state, _ = New(root, NewWitnessDatabase(db, witness), nil)
+	want := mutate(state)
+	if err := state.Error(); err != nil {
+		t.Fatalf("failed to mutate state: %v", err)
+	}
+	// The code sizes of every third contract are accessed
+	if len(witness.Codes) != 7 {
+		t.Fatalf("witness code count mismatch: have %d, want %d", len(witness.Codes), 7)
+	}
+	// Ensure the witness survives its encoding
+	blob, err := json.Marshal(witness)
+	if err != nil {
+		t.Fatalf("failed to encode witness: %v", err)
+	}
+	witness = new(stateless.Witness)
+	if err := json.Unmarshal(blob, witness); err != nil {
+		t.Fatalf("failed to decode witness: %v", err)
+	}
+	state, err = New(witness.Root(), NewDatabase(witness.MakeDatabase()), nil)
+	if err != nil {
+		t.Fatalf("failed to open state from witness: %v", err)
+	}
+	if have := mutate(state); have != want {
+		t.Fatalf("root mismatch: have %x, want %x", have, want)
+	}
+	if err := state.Error(); err != nil {
+		t.Fatalf("failed to mutate state from witness: %v", err)
+	}
+}
@@ -35,17 +35,26 @@ import (
 //
 // StateProcessor implements Processor.
 type StateProcessor struct {
-	config *params.ChainConfig // Chain configuration options
-	bc     *BlockChain         // Canonical block chain
-	engine consensus.Engine    // Consensus engine used for block rewards
+	config  *params.ChainConfig // Chain configuration options
+	bc      processorChain      // Canonical block chain
+	engine  consensus.Engine    // Consensus engine used for block rewards
+	workers int                 // Number of goroutines executing transactions speculatively (0 = sequential)
+}
+
+// processorChain is the chain access needed to process blocks, provided by the
+// canonical chain or, when executing statelessly, by the witness headers.
+type processorChain interface {
+	ChainContext
+	consensus.ChainHeaderReader
 }
 
 // NewStateProcessor initialises a new StateProcessor.
 func NewStateProcessor(config *params.ChainConfig, bc *BlockChain, engine consensus.Engine) *StateProcessor {
 	return &StateProcessor{
-		config: config,
-		bc:     bc,
-		engine: engine,
+		config:  config,
+		bc:      bc,
+		engine:  engine,
+		workers: bc.cacheConfig.TxWorkers,
 	}
 }
 
@@ -72,19 +81,33 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 	}
 	blockContext := NewEVMBlockContext(header, p.bc, nil)
 	vmenv := vm.NewEVM(blockContext, vm.TxContext{}, statedb, p.config, cfg)
-	// Iterate over and process the individual transactions
-	for i, tx := range block.Transactions() {
-		msg, err := tx.AsMessage(types.MakeSigner(p.config, header.Number), header.BaseFee)
-		if err != nil {
-			return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+
+	// If enabled, execute the transactions in parallel. Intermediate roots are
+	// not speculated on and tracers need to see the transactions in order, so
+	// pre-Byzantium and traced blocks always go through the sequential path.
+	if p.workers > 0 && !cfg.Debug && p.config.IsByzantium(blockNumber) {
+		var err error
+		if receipts, err = p.applyParallel(block, statedb, cfg, vmenv, gp, usedGas); err != nil {
+			return nil, nil, 0, err
 		}
-		statedb.Prepare(tx.Hash(), i)
-		receipt, err := applyTransaction(msg, p.config, nil, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv)
-		if err != nil {
-			return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		for _, receipt := range receipts {
+			allLogs = append(allLogs, receipt.Logs...)
+		}
+	} else {
+		// Iterate over and process the individual transactions
+		for i, tx := range block.Transactions() {
+			msg, err := tx.AsMessage(types.MakeSigner(p.config, header.Number), header.BaseFee)
+			if err != nil {
+				return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+			}
+			statedb.Prepare(tx.Hash(), i)
+			receipt, err := applyTransaction(msg, p.config, nil, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv)
+			if err != nil {
+				return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+			}
+			receipts = append(receipts, receipt)
+			allLogs = append(allLogs, receipt.Logs...)
 		}
-		receipts = append(receipts, receipt)
-		allLogs = append(allLogs, receipt.Logs...)
 	}
 	// Finalize the block, applying any consensus engine specific extras (e.g. block rewards)
 	p.engine.Finalize(p.bc, header, statedb, block.Transactions(), block.Uncles())
@@ -116,9 +139,13 @@ func applyTransaction(msg types.Message, config *params.ChainConfig, author *com
 	}
 	*usedGas += result.UsedGas
 
-	// Create a new receipt for the transaction, storing the intermediate root and gas used
-	// by the tx.
-	receipt := &types.Receipt{Type: tx.Type(), PostState: root, CumulativeGasUsed: *usedGas}
+	return newReceipt(tx, msg, result, statedb, root, *usedGas, blockNumber, blockHash), nil
+}
+
+// newReceipt creates the receipt of a transaction executed on the given state,
+// storing the intermediate root and gas used by the tx.
+func newReceipt(tx *types.Transaction, msg types.Message, result *ExecutionResult, statedb *state.StateDB, root []byte, usedGas uint64, blockNumber *big.Int, blockHash common.Hash) *types.Receipt {
+	receipt := &types.Receipt{Type: tx.Type(), PostState: root, CumulativeGasUsed: usedGas}
 	if result.Failed() {
 		receipt.Status = types.ReceiptStatusFailed
 	} else {
@@ -129,7 +156,7 @@ func applyTransaction(msg types.Message, config *params.ChainConfig, author *com
 
 	// If the transaction created a contract, store the creation address in the receipt.
 	if msg.To() == nil {
-		receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, tx.Nonce())
+		receipt.ContractAddress = crypto.CreateAddress(msg.From(), tx.Nonce())
 	}
 
 	// Set the receipt logs and create the bloom filter.
@@ -138,7 +165,7 @@ func applyTransaction(msg types.Message, config *params.ChainConfig, author *com
 	receipt.BlockHash = blockHash
 	receipt.BlockNumber = blockNumber
 	receipt.TransactionIndex = uint(statedb.TxIndex())
-	return receipt, err
+	return receipt
 }
 
 // ApplyTransaction attempts to apply a transaction to the given state database
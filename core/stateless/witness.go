@@ -0,0 +1,178 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package stateless contains the witness needed to execute a block without
+// access to the full state.
+package stateless
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/crypto"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb"
+)
+
+// Witness contains everything needed to execute a block on top of its parent
+// state: the trie nodes and contract code touched by the execution and the
+// ancestor headers whose hashes are accessed, starting with the parent.
+//
+// The nodes and code may be recorded concurrently.
+type Witness struct {
+	Headers []*types.Header     // Parent header and the ancestors needed for BLOCKHASH, newest first
+	Codes   map[string]struct{} // Contract code accessed by the execution
+	State   map[string]struct{} // Trie nodes resolved by the execution, including the hashing of the post-state
+
+	lock sync.Mutex
+}
+
+// NewWitness creates an empty witness for executing a block on top of parent.
+func NewWitness(parent *types.Header) *Witness {
+	return &Witness{
+		Headers: []*types.Header{parent},
+		Codes:   make(map[string]struct{}),
+		State:   make(map[string]struct{}),
+	}
+}
+
+// Root returns the pre-state root the witness proves, which is the state root
+// of the parent block.
+func (w *Witness) Root() common.Hash {
+	return w.Headers[0].Root
+}
+
+// AddNode records an rlp-encoded trie node. It implements trie.WitnessRecorder.
+func (w *Witness) AddNode(blob []byte) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.State[string(blob)] = struct{}{}
+}
+
+// AddCode records a contract code.
+func (w *Witness) AddCode(code []byte) {
+	if len(code) == 0 {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.Codes[string(code)] = struct{}{}
+}
+
+// AddHeader records an ancestor header, keeping the headers ordered from the
+// newest to the oldest.
+func (w *Witness) AddHeader(header *types.Header) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	hash := header.Hash()
+	for _, have := range w.Headers {
+		if have.Hash() == hash {
+			return
+		}
+	}
+	w.Headers = append(w.Headers, header)
+	sort.Slice(w.Headers, func(i, j int) bool {
+		return w.Headers[i].Number.Cmp(w.Headers[j].Number) > 0
+	})
+}
+
+// Verify checks that the witness headers form a chain ending at the given
+// parent hash.
+func (w *Witness) Verify(parent common.Hash) error {
+	if len(w.Headers) == 0 {
+		return errors.New("witness has no headers")
+	}
+	for i, header := range w.Headers {
+		if hash := header.Hash(); hash != parent {
+			return fmt.Errorf("witness header %d (#%d) mismatch: have %x, want %x", i, header.Number, hash, parent)
+		}
+		parent = header.ParentHash
+	}
+	return nil
+}
+
+// MakeDatabase creates a database holding the trie nodes and the contract code
+// of the witness, which the pre-state can be opened from with the hash scheme.
+func (w *Witness) MakeDatabase() ethdb.Database {
+	db := rawdb.NewMemoryDatabase()
+	for blob := range w.State {
+		rawdb.WriteTrieNode(db, crypto.Keccak256Hash([]byte(blob)), []byte(blob))
+	}
+	for code := range w.Codes {
+		rawdb.WriteCode(db, crypto.Keccak256Hash([]byte(code)), []byte(code))
+	}
+	return db
+}
+
+// extWitness is the JSON encoding of a witness, with the nodes and codes sorted
+// to make it deterministic.
+type extWitness struct {
+	Headers []*types.Header `json:"headers"`
+	Codes   []hexutil.Bytes `json:"codes"`
+	State   []hexutil.Bytes `json:"state"`
+}
+
+// sortedBlobs returns the members of a blob set in ascending order.
+func sortedBlobs(set map[string]struct{}) []hexutil.Bytes {
+	blobs := make([]hexutil.Bytes, 0, len(set))
+	for blob := range set {
+		blobs = append(blobs, hexutil.Bytes(blob))
+	}
+	sort.Slice(blobs, func(i, j int) bool {
+		return bytes.Compare(blobs[i], blobs[j]) < 0
+	})
+	return blobs
+}
+
+// MarshalJSON implements json.Marshaler.
+func (w *Witness) MarshalJSON() ([]byte, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return json.Marshal(&extWitness{
+		Headers: w.Headers,
+		Codes:   sortedBlobs(w.Codes),
+		State:   sortedBlobs(w.State),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (w *Witness) UnmarshalJSON(input []byte) error {
+	var ext extWitness
+	if err := json.Unmarshal(input, &ext); err != nil {
+		return err
+	}
+	w.Headers = ext.Headers
+	w.Codes = make(map[string]struct{}, len(ext.Codes))
+	for _, code := range ext.Codes {
+		w.Codes[string(code)] = struct{}{}
+	}
+	w.State = make(map[string]struct{}, len(ext.State))
+	for _, blob := range ext.State {
+		w.State[string(blob)] = struct{}{}
+	}
+	return nil
+}
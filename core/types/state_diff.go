@@ -0,0 +1,47 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// StateDiff is the set of state changes made by a block, sorted by address.
+type StateDiff struct {
+	Accounts []*AccountDiff
+}
+
+// AccountDiff is the change of a single account made by a block.
+type AccountDiff struct {
+	Address common.Address
+	Pre     *StateAccount `rlp:"nil"` // Account before the block, nil if it didn't exist
+	Post    *StateAccount `rlp:"nil"` // Account after the block, nil if it was deleted
+
+	// StorageCleared is set if the storage the account had before the block
+	// was dropped, by a self destruct or by recreating the account. The storage
+	// changes are then relative to the empty storage.
+	StorageCleared bool
+
+	Storage []*StorageDiff // Changed storage slots, sorted by key
+}
+
+// StorageDiff is the change of a single storage slot made by a block.
+type StorageDiff struct {
+	Key  common.Hash // Slot key, not hashed
+	Pre  common.Hash
+	Post common.Hash
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/consensus"
+	"github.com/Altcoinchain/go-altcoinchain/core/state"
+	"github.com/Altcoinchain/go-altcoinchain/core/stateless"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/core/vm"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+	"github.com/Altcoinchain/go-altcoinchain/trie"
+)
+
+// witnessChain is the canonical chain recording the headers looked up during
+// block processing, which are the ancestors accessed by BLOCKHASH.
+type witnessChain struct {
+	*BlockChain
+	witness *stateless.Witness
+}
+
+// GetHeader retrieves a block header by hash and number, recording it.
+func (c *witnessChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header := c.BlockChain.GetHeader(hash, number)
+	if header != nil {
+		c.witness.AddHeader(header)
+	}
+	return header
+}
+
+// ExecutionWitness re-executes a block on top of its parent state and returns
+// the witness of all the trie nodes, contract code and ancestor headers needed
+// to execute it again without the state. The parent state must be available.
+func (bc *BlockChain) ExecutionWitness(block *types.Block) (*stateless.Witness, error) {
+	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	witness := stateless.NewWitness(parent)
+
+	// Open the state without snapshots, so all the accessed state is read from
+	// the tries and recorded
+	statedb, err := state.New(parent.Root, state.NewWitnessDatabase(bc.stateCache, witness), nil)
+	if err != nil {
+		return nil, err
+	}
+	processor := &StateProcessor{
+		config: bc.chainConfig,
+		bc:     &witnessChain{BlockChain: bc, witness: witness},
+		engine: bc.engine,
+	}
+	if _, _, _, err := processor.Process(block, statedb, vm.Config{}); err != nil {
+		return nil, err
+	}
+	// Hash the post-state to record the nodes needed to recompute the root
+	root := statedb.IntermediateRoot(bc.chainConfig.IsEIP158(block.Number()))
+	if err := statedb.Error(); err != nil {
+		return nil, err
+	}
+	if root != block.Root() {
+		return nil, fmt.Errorf("state root mismatch: have %x, want %x", root, block.Root())
+	}
+	return witness, nil
+}
+
+// statelessChain serves the headers of a witness to the block processing.
+type statelessChain struct {
+	config  *params.ChainConfig
+	engine  consensus.Engine
+	headers []*types.Header // Verified ancestor headers, newest first
+}
+
+func (c *statelessChain) Config() *params.ChainConfig  { return c.config }
+func (c *statelessChain) Engine() consensus.Engine     { return c.engine }
+func (c *statelessChain) CurrentHeader() *types.Header { return c.headers[0] }
+
+func (c *statelessChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.GetHeaderByHash(hash); header != nil && header.Number.Uint64() == number {
+		return header
+	}
+	return nil
+}
+
+func (c *statelessChain) GetHeaderByNumber(number uint64) *types.Header {
+	for _, header := range c.headers {
+		if header.Number.Uint64() == number {
+			return header
+		}
+	}
+	return nil
+}
+
+func (c *statelessChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	for _, header := range c.headers {
+		if header.Hash() == hash {
+			return header
+		}
+	}
+	return nil
+}
+
+// GetTd is not available without the chain, the processing doesn't need it.
+func (c *statelessChain) GetTd(hash common.Hash, number uint64) *big.Int { return nil }
+
+// ExecuteStateless executes a block using only the state provided by the given
+// witness and returns the resulting state root and receipt root, which the
+// caller is expected to compare against the block header.
+func ExecuteStateless(config *params.ChainConfig, engine consensus.Engine, block *types.Block, witness *stateless.Witness) (common.Hash, common.Hash, error) {
+	if err := witness.Verify(block.ParentHash()); err != nil {
+		return common.Hash{}, common.Hash{}, err
+	}
+	statedb, err := state.New(witness.Root(), state.NewDatabase(witness.MakeDatabase()), nil)
+	if err != nil {
+		return common.Hash{}, common.Hash{}, err
+	}
+	processor := &StateProcessor{
+		config: config,
+		bc:     &statelessChain{config: config, engine: engine, headers: witness.Headers},
+		engine: engine,
+	}
+	receipts, _, _, err := processor.Process(block, statedb, vm.Config{})
+	if err != nil {
+		return common.Hash{}, common.Hash{}, err
+	}
+	root := statedb.IntermediateRoot(config.IsEIP158(block.Number()))
+	if err := statedb.Error(); err != nil {
+		return common.Hash{}, common.Hash{}, fmt.Errorf("incomplete witness: %w", err)
+	}
+	return root, types.DeriveSha(receipts, trie.NewStackTrie(nil)), nil
+}
@@ -17,8 +17,10 @@
 package eth
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -30,9 +32,12 @@ import (
 
 	"github.com/Altcoinchain/go-altcoinchain/common"
 	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
 	"github.com/Altcoinchain/go-altcoinchain/core"
 	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
 	"github.com/Altcoinchain/go-altcoinchain/core/state"
+	"github.com/Altcoinchain/go-altcoinchain/core/state/layout"
+	"github.com/Altcoinchain/go-altcoinchain/core/stateless"
 	"github.com/Altcoinchain/go-altcoinchain/core/types"
 	"github.com/Altcoinchain/go-altcoinchain/internal/ethapi"
 	"github.com/Altcoinchain/go-altcoinchain/log"
@@ -134,6 +139,48 @@ func (api *MinerAPI) SetRecommitInterval(interval int) {
 	api.e.Miner().SetRecommitInterval(time.Duration(interval) * time.Millisecond)
 }
 
+// RewardsResult is the expected reward breakdown of a sealing work package.
+type RewardsResult struct {
+	Subsidy        *hexutil.Big `json:"subsidy"`
+	UncleInclusion *hexutil.Big `json:"uncleInclusion"`
+	Fees           *hexutil.Big `json:"fees"`
+	Split          *hexutil.Big `json:"split"`
+	Treasury       *hexutil.Big `json:"treasury"`
+	Total          *hexutil.Big `json:"total"`
+}
+
+// newRewardsResult converts an expected reward breakdown into its RPC form.
+func newRewardsResult(rewards *ethash.BlockRewards) *RewardsResult {
+	return &RewardsResult{
+		Subsidy:        (*hexutil.Big)(rewards.Subsidy),
+		UncleInclusion: (*hexutil.Big)(rewards.UncleInclusion),
+		Fees:           (*hexutil.Big)(rewards.Fees),
+		Split:          (*hexutil.Big)(rewards.Split),
+		Treasury:       (*hexutil.Big)(rewards.Treasury),
+		Total:          (*hexutil.Big)(rewards.Total()),
+	}
+}
+
+// GetRewards returns the expected reward breakdown of the work currently being
+// sealed.
+func (api *MinerAPI) GetRewards() (*RewardsResult, error) {
+	rewards := api.e.Miner().PendingRewards()
+	if rewards == nil {
+		return nil, errors.New("no mining work available yet")
+	}
+	return newRewardsResult(rewards), nil
+}
+
+// GetWorkRewards returns the expected reward breakdown of the work package with
+// the given seal hash, as handed out by ethash_getWork.
+func (api *MinerAPI) GetWorkRewards(sealhash common.Hash) (*RewardsResult, error) {
+	rewards := api.e.Miner().WorkRewards(sealhash)
+	if rewards == nil {
+		return nil, fmt.Errorf("unknown or stale work %x", sealhash)
+	}
+	return newRewardsResult(rewards), nil
+}
+
 // AdminAPI is the collection of Ethereum full node related APIs for node
 // administration.
 type AdminAPI struct {
@@ -243,6 +290,35 @@ func (api *AdminAPI) ImportChain(file string) (bool, error) {
 	return true, nil
 }
 
+// Backup writes a consistent copy of the chain database into the given
+// directory, which must not exist yet. The node keeps running while the
+// key-value store and the ancients are copied.
+func (api *AdminAPI) Backup(dir string) (*rawdb.BackupManifest, error) {
+	if api.eth.dbEngine == "" {
+		return nil, errors.New("ephemeral database can't be backed up")
+	}
+	return rawdb.Backup(api.eth.ChainDb(), dir, api.eth.dbEngine)
+}
+
+// StartScrub launches a background check of the canonical chain data in the
+// key-value store and the freezer. Without a start block, the last interrupted
+// scrub is resumed. Corrupted items are re-fetched from peers if requested.
+func (api *AdminAPI) StartScrub(args ScrubArgs) error {
+	return api.eth.startScrub(args)
+}
+
+// StopScrub interrupts the running background scrub, which can be resumed
+// later. It returns whether a scrub was running.
+func (api *AdminAPI) StopScrub() bool {
+	return api.eth.stopScrub()
+}
+
+// ScrubStatus returns the progress and the issues found by the last background
+// scrub, or nil if none was started.
+func (api *AdminAPI) ScrubStatus() *ScrubStatus {
+	return api.eth.scrubStatus()
+}
+
 // DebugAPI is the collection of Ethereum full node APIs for debugging the
 // protocol.
 type DebugAPI struct {
@@ -445,6 +521,86 @@ func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeRes
 	return result, nil
 }
 
+// RegisterStorageLayout registers the storage layout of a contract, as emitted
+// by solc, to decode its storage with. Either the layout itself or the solc
+// output of the contract is accepted.
+func (api *DebugAPI) RegisterStorageLayout(address common.Address, blob json.RawMessage) error {
+	if _, err := layout.Parse(blob); err != nil {
+		return fmt.Errorf("invalid storage layout: %v", err)
+	}
+	rawdb.WriteStorageLayout(api.eth.ChainDb(), address, blob)
+	return nil
+}
+
+// UnregisterStorageLayout removes the storage layout registered for a contract.
+func (api *DebugAPI) UnregisterStorageLayout(address common.Address) {
+	rawdb.DeleteStorageLayout(api.eth.ChainDb(), address)
+}
+
+// StorageLayout returns the storage layout registered for a contract.
+func (api *DebugAPI) StorageLayout(address common.Address) (json.RawMessage, error) {
+	blob := rawdb.ReadStorageLayout(api.eth.ChainDb(), address)
+	if len(blob) == 0 {
+		return nil, fmt.Errorf("no storage layout registered for %#x", address)
+	}
+	return blob, nil
+}
+
+// DecodeStorageAt decodes the storage of a contract at the given block using
+// its registered storage layout. At most limit elements of every dynamic array
+// and mapping are decoded, 0 meaning all of them. Mapping entries are only
+// found if the preimages of their locations were recorded (--vmdebug).
+func (api *DebugAPI) DecodeStorageAt(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, address common.Address, limit *uint64) ([]*layout.Value, error) {
+	blob := rawdb.ReadStorageLayout(api.eth.ChainDb(), address)
+	if len(blob) == 0 {
+		return nil, fmt.Errorf("no storage layout registered for %#x", address)
+	}
+	storageLayout, err := layout.Parse(blob)
+	if err != nil {
+		return nil, err
+	}
+	statedb, _, err := api.eth.APIBackend.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if statedb == nil || err != nil {
+		return nil, err
+	}
+	st := statedb.StorageTrie(address)
+	if st == nil {
+		return nil, fmt.Errorf("account %x doesn't exist", address)
+	}
+	if err := statedb.Error(); err != nil {
+		return nil, err
+	}
+	// Collect the keys of the slots in storage, as far as their preimages are known
+	var slots []common.Hash
+	for it := trie.NewIterator(st.NodeIterator(nil)); it.Next(); {
+		if preimage := st.GetKey(it.Key); preimage != nil {
+			slots = append(slots, common.BytesToHash(preimage))
+		}
+	}
+	max := uint64(layout.DefaultLimit)
+	if limit != nil {
+		max = *limit
+	}
+	storage := &stateStorage{state: statedb, address: address}
+	return layout.NewDecoder(storageLayout, storage, slots, max).Decode()
+}
+
+// stateStorage gives the storage layout decoder access to the storage of a
+// contract in a state.
+type stateStorage struct {
+	state   *state.StateDB
+	address common.Address
+}
+
+func (s *stateStorage) Slot(key common.Hash) (common.Hash, error) {
+	value := s.state.GetState(s.address, key)
+	return value, s.state.Error()
+}
+
+func (s *stateStorage) Preimage(hash common.Hash) []byte {
+	return s.state.Database().TrieDB().Preimage(hash)
+}
+
 // GetModifiedAccountsByNumber returns all accounts that have changed between the
 // two blocks specified. A change is defined as a difference in nonce, balance,
 // code hash, or storage hash.
@@ -528,6 +684,146 @@ func (api *DebugAPI) getModifiedAccounts(startBlock, endBlock *types.Block) ([]c
 	return dirty, nil
 }
 
+// StateDiffResult is the state diff of a block as served over RPC.
+type StateDiffResult struct {
+	BlockHash   common.Hash          `json:"blockHash"`
+	BlockNumber hexutil.Uint64       `json:"blockNumber"`
+	Accounts    []*AccountDiffResult `json:"accounts"`
+}
+
+// AccountDiffResult is the change of an account made by a block.
+type AccountDiffResult struct {
+	Address        common.Address       `json:"address"`
+	Pre            *AccountStateResult  `json:"pre"`  // Nil if the account didn't exist
+	Post           *AccountStateResult  `json:"post"` // Nil if the account was deleted
+	StorageCleared bool                 `json:"storageCleared"`
+	Storage        []*StorageDiffResult `json:"storage"`
+}
+
+// AccountStateResult is the state of an account before or after a block. The
+// code is only included if it was changed by the block.
+type AccountStateResult struct {
+	Nonce       hexutil.Uint64 `json:"nonce"`
+	Balance     *hexutil.Big   `json:"balance"`
+	CodeHash    common.Hash    `json:"codeHash"`
+	Code        hexutil.Bytes  `json:"code,omitempty"`
+	StorageRoot common.Hash    `json:"storageRoot"`
+}
+
+// StorageDiffResult is the change of a storage slot made by a block.
+type StorageDiffResult struct {
+	Key  common.Hash `json:"key"`
+	Pre  common.Hash `json:"pre"`
+	Post common.Hash `json:"post"`
+}
+
+// StateDiff returns the state changes made by a block. They are only available
+// for the blocks processed with state diffs enabled, within the retained window.
+func (api *DebugAPI) StateDiff(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*StateDiffResult, error) {
+	header, err := api.eth.APIBackend.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("block not found")
+	}
+	hash := header.Hash()
+	diff := api.eth.blockchain.GetStateDiff(hash, header.Number.Uint64())
+	if diff == nil {
+		return nil, fmt.Errorf("state diff of block %#x not available", hash)
+	}
+	return api.stateDiffResult(hash, header.Number.Uint64(), diff), nil
+}
+
+// StateDiffs creates a subscription that is notified with the state changes of
+// every block inserted as the new chain head.
+func (api *DebugAPI) StateDiffs(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	if !api.eth.blockchain.StateDiffsEnabled() {
+		return &rpc.Subscription{}, errors.New("state diffs are not enabled")
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		diffs := make(chan core.StateDiffEvent, 16)
+		sub := api.eth.blockchain.SubscribeStateDiffEvent(diffs)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-diffs:
+				notifier.Notify(rpcSub.ID, api.stateDiffResult(ev.Block.Hash(), ev.Block.NumberU64(), ev.Diff))
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// ExecutionWitness re-executes a block and returns the witness of the trie nodes,
+// contract code and ancestor headers it touches, which is sufficient to execute
+// the block again without the state. The state of the parent must be available.
+func (api *DebugAPI) ExecutionWitness(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*stateless.Witness, error) {
+	block, err := api.eth.APIBackend.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, errors.New("block not found")
+	}
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not executable")
+	}
+	return api.eth.blockchain.ExecutionWitness(block)
+}
+
+// stateDiffResult converts a block state diff into its RPC representation.
+func (api *DebugAPI) stateDiffResult(hash common.Hash, number uint64, diff *types.StateDiff) *StateDiffResult {
+	result := &StateDiffResult{
+		BlockHash:   hash,
+		BlockNumber: hexutil.Uint64(number),
+		Accounts:    make([]*AccountDiffResult, 0, len(diff.Accounts)),
+	}
+	for _, account := range diff.Accounts {
+		res := &AccountDiffResult{
+			Address:        account.Address,
+			Pre:            api.accountStateResult(account.Pre, account.Post),
+			Post:           api.accountStateResult(account.Post, account.Pre),
+			StorageCleared: account.StorageCleared,
+			Storage:        make([]*StorageDiffResult, 0, len(account.Storage)),
+		}
+		for _, slot := range account.Storage {
+			res.Storage = append(res.Storage, &StorageDiffResult{Key: slot.Key, Pre: slot.Pre, Post: slot.Post})
+		}
+		result.Accounts = append(result.Accounts, res)
+	}
+	return result
+}
+
+// accountStateResult converts an account into its RPC representation, adding
+// the code if it differs from the other side of the diff.
+func (api *DebugAPI) accountStateResult(account, other *types.StateAccount) *AccountStateResult {
+	if account == nil {
+		return nil
+	}
+	res := &AccountStateResult{
+		Nonce:       hexutil.Uint64(account.Nonce),
+		Balance:     (*hexutil.Big)(account.Balance),
+		CodeHash:    common.BytesToHash(account.CodeHash),
+		StorageRoot: account.Root,
+	}
+	if other == nil || !bytes.Equal(account.CodeHash, other.CodeHash) {
+		res.Code = rawdb.ReadCode(api.eth.chainDb, res.CodeHash)
+	}
+	return res
+}
+
 // GetAccessibleState returns the first number where the node has accessible
 // state on disk. Note this being the post-state of that block and the pre-state
 // of the next block.
@@ -35,6 +35,7 @@ import (
 	"github.com/Altcoinchain/go-altcoinchain/eth/gasprice"
 	"github.com/Altcoinchain/go-altcoinchain/ethdb"
 	"github.com/Altcoinchain/go-altcoinchain/event"
+	"github.com/Altcoinchain/go-altcoinchain/internal/ethapi"
 	"github.com/Altcoinchain/go-altcoinchain/miner"
 	"github.com/Altcoinchain/go-altcoinchain/params"
 	"github.com/Altcoinchain/go-altcoinchain/rpc"
@@ -126,11 +127,27 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 	if number == rpc.SafeBlockNumber {
 		return b.eth.blockchain.CurrentSafeBlock(), nil
 	}
-	return b.eth.blockchain.GetBlockByNumber(uint64(number)), nil
+	block := b.eth.blockchain.GetBlockByNumber(uint64(number))
+	if block == nil && b.historyPruned(uint64(number)) {
+		return nil, &ethapi.PrunedHistoryError{}
+	}
+	return block, nil
 }
 
 func (b *EthAPIBackend) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
-	return b.eth.blockchain.GetBlockByHash(hash), nil
+	block := b.eth.blockchain.GetBlockByHash(hash)
+	if block == nil {
+		if header := b.eth.blockchain.GetHeaderByHash(hash); header != nil && b.historyPruned(header.Number.Uint64()) {
+			return nil, &ethapi.PrunedHistoryError{}
+		}
+	}
+	return block, nil
+}
+
+// historyPruned reports whether the body and receipts of the given block have
+// been expired from the database.
+func (b *EthAPIBackend) historyPruned(number uint64) bool {
+	return number < rawdb.ReadHistoryTail(b.eth.chainDb)
 }
 
 func (b *EthAPIBackend) BlockByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*types.Block, error) {
@@ -147,6 +164,9 @@ func (b *EthAPIBackend) BlockByNumberOrHash(ctx context.Context, blockNrOrHash r
 		}
 		block := b.eth.blockchain.GetBlock(hash, header.Number.Uint64())
 		if block == nil {
+			if b.historyPruned(header.Number.Uint64()) {
+				return nil, &ethapi.PrunedHistoryError{}
+			}
 			return nil, errors.New("header found, but block body is missing")
 		}
 		return block, nil
@@ -197,12 +217,36 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 	return nil, nil, errors.New("invalid arguments; neither block nor hash specified")
 }
 
+// HistoricStateAt returns the state of a block, rebuilding it from the state
+// history if it was pruned.
+func (b *EthAPIBackend) HistoricStateAt(ctx context.Context, header *types.Header) (*state.StateDB, error) {
+	return b.eth.blockchain.HistoricStateAt(header)
+}
+
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
-	return b.eth.blockchain.GetReceiptsByHash(hash), nil
+	receipts := b.eth.blockchain.GetReceiptsByHash(hash)
+	if receipts == nil {
+		if number := rawdb.ReadHeaderNumber(b.eth.chainDb, hash); number != nil && b.historyPruned(*number) {
+			return nil, &ethapi.PrunedHistoryError{}
+		}
+	}
+	return receipts, nil
+}
+
+func (b *EthAPIBackend) GetStateDiff(ctx context.Context, hash common.Hash) (*types.StateDiff, error) {
+	number := rawdb.ReadHeaderNumber(b.eth.chainDb, hash)
+	if number == nil {
+		return nil, nil
+	}
+	return b.eth.blockchain.GetStateDiff(hash, *number), nil
 }
 
 func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash, number uint64) ([][]*types.Log, error) {
-	return rawdb.ReadLogs(b.eth.chainDb, hash, number, b.ChainConfig()), nil
+	logs := rawdb.ReadLogs(b.eth.chainDb, hash, number, b.ChainConfig())
+	if logs == nil && b.historyPruned(number) {
+		return nil, &ethapi.PrunedHistoryError{}
+	}
+	return logs, nil
 }
 
 func (b *EthAPIBackend) GetTd(ctx context.Context, hash common.Hash) *big.Int {
@@ -247,6 +291,10 @@ func (b *EthAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscri
 }
 
 func (b *EthAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	// A secondary node has no peers to propagate the transaction to
+	if b.eth.secondary {
+		return core.ErrSecondaryChain
+	}
 	return b.eth.txPool.AddLocal(signedTx)
 }
 
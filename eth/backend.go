@@ -75,7 +75,9 @@ type Ethereum struct {
 	merger             *consensus.Merger
 
 	// DB interfaces
-	chainDb ethdb.Database // Block chain database
+	chainDb   ethdb.Database // Block chain database
+	dbEngine  string         // Engine of the key-value store, empty if ephemeral
+	secondary bool           // Whether the database is followed read-only from a primary node
 
 	eventMux       *event.TypeMux
 	engine         consensus.Engine
@@ -99,6 +101,9 @@ type Ethereum struct {
 	lock sync.RWMutex // Protects the variadic fields (e.g. gas price and etherbase)
 
 	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully
+
+	scrub     *scrubJob  // Last background database scrub, nil if none was started
+	scrubLock sync.Mutex // Protects the scrub job
 }
 
 // New creates a new Ethereum object (including the
@@ -135,9 +140,44 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
-	chainConfig, genesisHash, genesisErr := core.SetupGenesisBlockWithOverride(chainDb, config.Genesis, config.OverrideTerminalTotalDifficulty, config.OverrideTerminalTotalDifficultyPassed)
-	if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
-		return nil, genesisErr
+	secondary := stack.Config().DBSecondary
+
+	// The state scheme is fixed when the database is created
+	scheme, err := rawdb.ParseStateScheme(config.StateScheme, chainDb)
+	if err != nil {
+		return nil, err
+	}
+	if scheme == rawdb.PathScheme {
+		if config.NoPruning {
+			return nil, errors.New("archive mode is not supported by the path state scheme")
+		}
+		if config.SyncMode == downloader.SnapSync {
+			log.Warn("Snap sync is not supported by the path state scheme, switching to full sync")
+			config.SyncMode = downloader.FullSync
+		}
+	}
+	if !secondary && rawdb.ReadStateScheme(chainDb) == "" {
+		rawdb.WriteStateScheme(chainDb, scheme)
+	}
+	log.Info("Initialised state storage", "scheme", scheme)
+
+	var (
+		chainConfig *params.ChainConfig
+		genesisHash common.Hash
+		genesisErr  error
+	)
+	if secondary {
+		// The database belongs to the primary node, use the chain configuration
+		// it stored instead of setting up the genesis.
+		genesisHash = rawdb.ReadCanonicalHash(chainDb, 0)
+		if chainConfig = rawdb.ReadChainConfig(chainDb, genesisHash); chainConfig == nil {
+			return nil, errors.New("chain configuration not found in the primary database")
+		}
+	} else {
+		chainConfig, genesisHash, genesisErr = core.SetupGenesisBlockWithOverride(chainDb, config.Genesis, config.OverrideTerminalTotalDifficulty, config.OverrideTerminalTotalDifficultyPassed)
+		if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
+			return nil, genesisErr
+		}
 	}
 
 	if !config.IsNetworkIdSet && chainConfig != nil {
@@ -154,8 +194,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	log.Info(strings.Repeat("-", 153))
 	log.Info("")
 
-	if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb, stack.ResolvePath(config.TrieCleanCacheJournal)); err != nil {
-		log.Error("Failed to recover state", "error", err)
+	if !secondary {
+		if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb, stack.ResolvePath(config.TrieCleanCacheJournal)); err != nil {
+			log.Error("Failed to recover state", "error", err)
+		}
 	}
 	merger := consensus.NewMerger(chainDb)
 	eth := &Ethereum{
@@ -173,6 +215,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		bloomIndexer:      core.NewBloomIndexer(chainDb, params.BloomBitsBlocks, params.BloomConfirms),
 		p2pServer:         stack.Server(),
 		shutdownTracker:   shutdowncheck.NewShutdownTracker(chainDb),
+		secondary:         secondary,
+	}
+	if path := stack.ResolvePath("chaindata"); path != "" {
+		eth.dbEngine = rawdb.PreexistingDatabase(path)
 	}
 
 	bcVersion := rawdb.ReadDatabaseVersion(chainDb)
@@ -182,7 +228,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	}
 	log.Info("Initialising Ethereum protocol", "network", config.NetworkId, "dbversion", dbVer)
 
-	if !config.SkipBcVersionCheck {
+	if !config.SkipBcVersionCheck && !secondary {
 		if bcVersion != nil && *bcVersion > core.BlockChainVersion {
 			return nil, fmt.Errorf("database version is v%d, Geth %s only supports v%d", *bcVersion, params.VersionWithMeta, core.BlockChainVersion)
 		} else if bcVersion == nil || *bcVersion < core.BlockChainVersion {
@@ -206,8 +252,31 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			TrieTimeLimit:       config.TrieTimeout,
 			SnapshotLimit:       config.SnapshotCache,
 			Preimages:           config.Preimages,
+			StateScheme:         scheme,
+			StateDiffs:          config.StateDiffs,
+			StateDiffRetain:     config.StateDiffRetain,
+			StateHistory:        config.StateHistory,
+			TxWorkers:           config.TxWorkers,
+			HistoryRetain:       config.HistoryRetain,
 		}
 	)
+	// Historic states are rebuilt from the state diffs, keep them for the window
+	if config.StateHistory > 0 {
+		cacheConfig.StateDiffs = true
+		if retain := cacheConfig.StateDiffRetain; retain != 0 && retain < config.StateHistory {
+			log.Warn("Extending state diff retention to the state history", "retain", retain, "history", config.StateHistory)
+			cacheConfig.StateDiffRetain = config.StateHistory
+		}
+	}
+	if secondary {
+		// Nothing is written by a secondary node, state is only served from what
+		// the primary has persisted.
+		cacheConfig.Secondary = true
+		cacheConfig.TrieCleanJournal, cacheConfig.TrieCleanRejournal = "", 0
+		cacheConfig.TrieDirtyDisabled = true
+		cacheConfig.SnapshotLimit = 0
+		config.TxPool.Journal = ""
+	}
 	eth.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, chainConfig, eth.engine, vmConfig, eth.shouldPreserve, &config.TxLookupLimit)
 	if err != nil {
 		return nil, err
@@ -218,7 +287,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		eth.blockchain.SetHead(compat.RewindTo)
 		rawdb.WriteChainConfig(chainDb, genesisHash, chainConfig)
 	}
-	eth.bloomIndexer.Start(eth.blockchain)
+	if !secondary {
+		eth.bloomIndexer.Start(eth.blockchain)
+	}
 
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
@@ -279,7 +350,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	stack.RegisterLifecycle(eth)
 
 	// Successful startup; push a marker and check previous unclean shutdowns.
-	eth.shutdownTracker.MarkStartup()
+	if !secondary {
+		eth.shutdownTracker.MarkStartup()
+	}
 
 	return eth, nil
 }
@@ -531,7 +604,9 @@ func (s *Ethereum) Start() error {
 	s.startBloomHandlers(params.BloomBitsBlocks)
 
 	// Regularly update shutdown marker
-	s.shutdownTracker.Start()
+	if !s.secondary {
+		s.shutdownTracker.Start()
+	}
 
 	// Figure out a max peers count based on the server limits
 	maxPeers := s.p2pServer.MaxPeers
@@ -555,6 +630,7 @@ func (s *Ethereum) Stop() error {
 	s.handler.Stop()
 
 	// Then stop everything else.
+	s.stopScrub()
 	s.bloomIndexer.Close()
 	close(s.closeBloomHandler)
 	s.txPool.Stop()
@@ -563,7 +639,9 @@ func (s *Ethereum) Stop() error {
 	s.engine.Close()
 
 	// Clean shutdown marker as the last thing before closing db
-	s.shutdownTracker.Stop()
+	if !s.secondary {
+		s.shutdownTracker.Stop()
+	}
 
 	s.chainDb.Close()
 	s.eventMux.Stop()
@@ -138,6 +138,7 @@ type Config struct {
 	NoPrefetch bool // Whether to disable prefetching and only load state on demand
 
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
+	HistoryRetain uint64 `toml:",omitempty"` // The maximum number of blocks from head whose bodies and receipts are reserved.
 
 	// RequiredBlocks is a set of block number -> hash mappings which must be in the
 	// canonical chain of all remote peers. Setting the option makes geth verify the
@@ -171,6 +172,11 @@ type Config struct {
 	TrieTimeout             time.Duration
 	SnapshotCache           int
 	Preimages               bool
+	StateScheme             string `toml:",omitempty"` // Scheme used to store the state trie nodes, set when the database is created
+	StateDiffs              bool   `toml:",omitempty"` // Whether to store the state changes made by every block
+	StateDiffRetain         uint64 `toml:",omitempty"` // Number of recent blocks to keep state diffs for (0 = all)
+	StateHistory            uint64 `toml:",omitempty"` // Number of recent blocks whose state can be rebuilt for proofs (0 = disabled)
+	TxWorkers               int    `toml:",omitempty"` // Number of goroutines executing block transactions speculatively (0 = sequential)
 
 	// This is the number of blocks for which logs will be cached in the filter system.
 	FilterLogCacheSize int
@@ -0,0 +1,357 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
+	"github.com/Altcoinchain/go-altcoinchain/core"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/eth/protocols/eth"
+	"github.com/Altcoinchain/go-altcoinchain/log"
+)
+
+const (
+	// scrubFetchTimeout is the time a peer is given to deliver the data needed
+	// to repair a corrupted item.
+	scrubFetchTimeout = 10 * time.Second
+
+	// maxScrubIssues is the number of issues retained for the scrub status.
+	maxScrubIssues = 1024
+)
+
+var (
+	errScrubRunning = errors.New("scrub already running")
+	errScrubStopped = errors.New("scrub stopped")
+	errNoScrubPeer  = errors.New("no peer to fetch repairs from")
+)
+
+// ScrubArgs are the settings of a background database scrub.
+type ScrubArgs struct {
+	Start  *hexutil.Uint64 `json:"start"`  // First block to check, defaults to resuming the last scrub
+	End    *hexutil.Uint64 `json:"end"`    // Last block to check, defaults to the current head
+	Rate   int             `json:"rate"`   // Maximum number of blocks checked per second, zero for unlimited
+	Repair bool            `json:"repair"` // Whether to re-fetch corrupted items from peers
+}
+
+// ScrubStatus reports the progress of a background database scrub.
+type ScrubStatus struct {
+	Running  bool           `json:"running"`
+	Start    hexutil.Uint64 `json:"start"`
+	End      hexutil.Uint64 `json:"end"`
+	Next     hexutil.Uint64 `json:"next"`     // Next block to check, as last persisted while running
+	Found    int            `json:"found"`    // Number of issues found
+	Repaired int            `json:"repaired"` // Number of issues repaired
+	Issues   []string       `json:"issues"`   // The first issues found
+}
+
+// scrubJob is a database scrub running in the background.
+type scrubJob struct {
+	quit chan struct{}
+	done chan struct{}
+
+	lock   sync.Mutex
+	status ScrubStatus
+}
+
+// startScrub launches a background scrub of the canonical chain data, which
+// optionally repairs the corrupted items with data fetched from peers.
+func (s *Ethereum) startScrub(args ScrubArgs) error {
+	if s.secondary {
+		return core.ErrSecondaryChain
+	}
+	s.scrubLock.Lock()
+	defer s.scrubLock.Unlock()
+
+	if s.scrub != nil {
+		select {
+		case <-s.scrub.done:
+		default:
+			return errScrubRunning
+		}
+	}
+	var start uint64
+	if args.Start != nil {
+		start = uint64(*args.Start)
+	} else if progress := rawdb.ReadScrubProgress(s.chainDb); progress != nil {
+		start = *progress
+	}
+	end := s.blockchain.CurrentBlock().NumberU64()
+	if args.End != nil && uint64(*args.End) < end {
+		end = uint64(*args.End)
+	}
+	if start > end {
+		return fmt.Errorf("invalid scrub range [%d, %d]", start, end)
+	}
+	job := &scrubJob{
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+		status: ScrubStatus{
+			Running: true,
+			Start:   hexutil.Uint64(start),
+			End:     hexutil.Uint64(end),
+			Next:    hexutil.Uint64(start),
+		},
+	}
+	s.scrub = job
+
+	go func() {
+		defer close(job.done)
+
+		handle := func(issue *core.ScrubIssue) bool {
+			log.Warn("Found corrupted chain data", "number", issue.Number, "item", issue.Kind, "frozen", issue.Frozen, "err", issue.Err)
+
+			var repaired bool
+			if args.Repair {
+				if err := s.repairScrubIssue(issue, job.quit); err != nil {
+					log.Warn("Failed to repair chain data", "number", issue.Number, "item", issue.Kind, "err", err)
+				} else {
+					log.Info("Repaired chain data", "number", issue.Number, "item", issue.Kind)
+					repaired = true
+				}
+			}
+			job.lock.Lock()
+			defer job.lock.Unlock()
+
+			job.status.Found++
+			if repaired {
+				job.status.Repaired++
+			}
+			if len(job.status.Issues) < maxScrubIssues {
+				job.status.Issues = append(job.status.Issues, issue.String())
+			}
+			return repaired
+		}
+		next := core.NewScrubber(s.chainDb, args.Rate).Run(start, end, handle, job.quit)
+
+		job.lock.Lock()
+		job.status.Running = false
+		job.status.Next = hexutil.Uint64(next)
+		job.lock.Unlock()
+	}()
+	return nil
+}
+
+// stopScrub interrupts the running background scrub, persisting its progress.
+// It returns whether a scrub was running.
+func (s *Ethereum) stopScrub() bool {
+	s.scrubLock.Lock()
+	defer s.scrubLock.Unlock()
+
+	if s.scrub == nil {
+		return false
+	}
+	select {
+	case <-s.scrub.done:
+		return false
+	default:
+	}
+	close(s.scrub.quit)
+	<-s.scrub.done
+	return true
+}
+
+// scrubStatus returns the status of the last background scrub, or nil if none
+// was started.
+func (s *Ethereum) scrubStatus() *ScrubStatus {
+	s.scrubLock.Lock()
+	defer s.scrubLock.Unlock()
+
+	if s.scrub == nil {
+		return nil
+	}
+	s.scrub.lock.Lock()
+	defer s.scrub.lock.Unlock()
+
+	status := s.scrub.status
+	status.Issues = append([]string{}, status.Issues...)
+	if status.Running {
+		if progress := rawdb.ReadScrubProgress(s.chainDb); progress != nil && *progress >= uint64(status.Start) {
+			status.Next = hexutil.Uint64(*progress)
+		}
+	}
+	return &status
+}
+
+// repairScrubIssue replaces a corrupted item with a copy fetched from a peer and
+// verified against the local chain, or recomputes it if possible.
+func (s *Ethereum) repairScrubIssue(issue *core.ScrubIssue, quit chan struct{}) error {
+	var (
+		db     = s.chainDb
+		number = issue.Number
+	)
+	switch issue.Kind {
+	case core.ScrubHash:
+		hash := issue.Hash
+		if hash == (common.Hash{}) {
+			if number == 0 {
+				hash = s.blockchain.Genesis().Hash()
+			} else {
+				recovered, err := s.recoverCanonicalHash(number, quit)
+				if err != nil {
+					return err
+				}
+				hash = recovered
+			}
+		}
+		return rawdb.RepairCanonicalHash(db, hash, number)
+
+	case core.ScrubHeader:
+		header, err := s.fetchScrubHeader(number, issue.Hash, quit)
+		if err != nil {
+			return err
+		}
+		return rawdb.RepairHeader(db, header)
+	}
+	// The remaining items are verified against the local header
+	header := rawdb.ReadHeader(db, issue.Hash, number)
+	if header == nil {
+		return errors.New("header unavailable")
+	}
+	switch issue.Kind {
+	case core.ScrubBody:
+		res, err := s.fetchScrubData(func(peer *eth.Peer, sink chan *eth.Response) (*eth.Request, error) {
+			return peer.RequestBodies([]common.Hash{issue.Hash}, sink)
+		}, quit)
+		if err != nil {
+			return err
+		}
+		txs, uncles := res.(*eth.BlockBodiesPacket).Unpack()
+		if len(txs) == 0 {
+			return errors.New("body not delivered")
+		}
+		body := &types.Body{Transactions: txs[0], Uncles: uncles[0]}
+		if err := core.VerifyBody(header, body); err != nil {
+			return fmt.Errorf("fetched invalid body: %v", err)
+		}
+		return rawdb.RepairBody(db, issue.Hash, number, body)
+
+	case core.ScrubReceipts:
+		res, err := s.fetchScrubData(func(peer *eth.Peer, sink chan *eth.Response) (*eth.Request, error) {
+			return peer.RequestReceipts([]common.Hash{issue.Hash}, sink)
+		}, quit)
+		if err != nil {
+			return err
+		}
+		packet := *res.(*eth.ReceiptsPacket)
+		if len(packet) == 0 {
+			return errors.New("receipts not delivered")
+		}
+		if err := core.VerifyReceipts(header, packet[0]); err != nil {
+			return fmt.Errorf("fetched invalid receipts: %v", err)
+		}
+		return rawdb.RepairReceipts(db, issue.Hash, number, packet[0])
+
+	case core.ScrubTd:
+		td := new(big.Int).Set(header.Difficulty)
+		if number > 0 {
+			parent := rawdb.ReadTd(db, header.ParentHash, number-1)
+			if parent == nil {
+				return errors.New("parent total difficulty unavailable")
+			}
+			td.Add(td, parent)
+		}
+		return rawdb.RepairTd(db, issue.Hash, number, td)
+	}
+	return fmt.Errorf("unknown item %q", issue.Kind)
+}
+
+// recoverCanonicalHash determines a lost canonical hash. The local canonical
+// child commits to it through its parent hash; without a child, e.g. at the
+// head, a peer's header is only accepted if it links to the local chain and
+// passes the consensus checks, seal included.
+func (s *Ethereum) recoverCanonicalHash(number uint64, quit chan struct{}) (common.Hash, error) {
+	db := s.chainDb
+	if hash := rawdb.ReadCanonicalHash(db, number+1); hash != (common.Hash{}) {
+		if child := rawdb.ReadHeader(db, hash, number+1); child != nil {
+			return child.ParentHash, nil
+		}
+	}
+	header, err := s.fetchScrubHeader(number, common.Hash{}, quit)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if header.ParentHash != rawdb.ReadCanonicalHash(db, number-1) {
+		return common.Hash{}, fmt.Errorf("fetched header %x doesn't link to the local chain", header.Hash())
+	}
+	if err := s.engine.VerifyHeader(s.blockchain, header, true); err != nil {
+		return common.Hash{}, fmt.Errorf("fetched invalid header %x: %v", header.Hash(), err)
+	}
+	return header.Hash(), nil
+}
+
+// fetchScrubHeader retrieves a header from a peer, by hash if it's known or by
+// number otherwise.
+func (s *Ethereum) fetchScrubHeader(number uint64, hash common.Hash, quit chan struct{}) (*types.Header, error) {
+	res, err := s.fetchScrubData(func(peer *eth.Peer, sink chan *eth.Response) (*eth.Request, error) {
+		if hash == (common.Hash{}) {
+			return peer.RequestHeadersByNumber(number, 1, 0, false, sink)
+		}
+		return peer.RequestHeadersByHash(hash, 1, 0, false, sink)
+	}, quit)
+	if err != nil {
+		return nil, err
+	}
+	headers := *res.(*eth.BlockHeadersPacket)
+	if len(headers) == 0 {
+		return nil, errors.New("header not delivered")
+	}
+	header := headers[0]
+	if header.Number == nil || header.Number.Uint64() != number {
+		return nil, fmt.Errorf("fetched header number mismatch: have %v, want %d", header.Number, number)
+	}
+	if hash != (common.Hash{}) && header.Hash() != hash {
+		return nil, fmt.Errorf("fetched header hash mismatch: have %x, want %x", header.Hash(), hash)
+	}
+	return header, nil
+}
+
+// fetchScrubData sends a request to the best peer, waiting for the response
+// until it times out or the scrub is stopped.
+func (s *Ethereum) fetchScrubData(request func(*eth.Peer, chan *eth.Response) (*eth.Request, error), quit chan struct{}) (interface{}, error) {
+	peer := s.handler.peers.peerWithHighestTD()
+	if peer == nil {
+		return nil, errNoScrubPeer
+	}
+	sink := make(chan *eth.Response)
+	req, err := request(peer, sink)
+	if err != nil {
+		return nil, err
+	}
+	defer req.Close()
+
+	timeout := time.NewTimer(scrubFetchTimeout)
+	defer timeout.Stop()
+
+	select {
+	case <-quit:
+		return nil, errScrubStopped
+	case <-timeout.C:
+		return nil, fmt.Errorf("request to peer %s timed out", peer.ID())
+	case res := <-sink:
+		// The response is verified by the caller, which doesn't punish the peer
+		res.Done <- nil
+		return res.Res, nil
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/Altcoinchain/go-altcoinchain/common"
 	"github.com/Altcoinchain/go-altcoinchain/core"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
 	"github.com/Altcoinchain/go-altcoinchain/core/state"
 	"github.com/Altcoinchain/go-altcoinchain/core/types"
 	"github.com/Altcoinchain/go-altcoinchain/core/vm"
@@ -59,6 +60,14 @@ func (eth *Ethereum) StateAtBlock(block *types.Block, reexec uint64, base *state
 			return statedb, nil
 		}
 	}
+	// The path scheme only keeps the recent states, which can't be regenerated
+	// in an ephemeral database as their ancestors are gone from disk.
+	if eth.blockchain.StateCache().TrieDB().Scheme() == rawdb.PathScheme {
+		if statedb, err = eth.blockchain.StateAt(block.Root()); err != nil {
+			return nil, fmt.Errorf("historical state %x is not available in the path scheme", block.Root())
+		}
+		return statedb, nil
+	}
 	if base != nil {
 		if preferDisk {
 			// Create an ephemeral trie.Database for isolating the live one. Otherwise
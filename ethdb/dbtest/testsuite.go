@@ -374,6 +374,19 @@ func TestDatabaseSuite(t *testing.T, New func() ethdb.KeyValueStore) {
 				t.Fatal("Unexpected deletion")
 			}
 		}
+		// Iteration must see the original content too
+		if got, want := iterateKeys(snapshot.NewIterator(nil, nil)), []string{"k1", "k2", "k3", "k4"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("Unexpected snapshot keys want: %v, got %v", want, got)
+		}
+		if got, want := iterateKeys(snapshot.NewIterator([]byte("k"), []byte("2"))), []string{"k2", "k3", "k4"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("Unexpected snapshot keys want: %v, got %v", want, got)
+		}
+		it := snapshot.NewIterator(nil, []byte("k1"))
+		if !it.Next() || !bytes.Equal(it.Value(), []byte("v1")) {
+			t.Fatalf("Unexpected snapshot value for k1: %q", it.Value())
+		}
+		it.Release()
+		snapshot.Release()
 	})
 }
 
@@ -17,6 +17,7 @@
 package leveldb
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/Altcoinchain/go-altcoinchain/ethdb"
@@ -38,3 +39,47 @@ func TestLevelDB(t *testing.T) {
 		})
 	})
 }
+
+func TestSecondary(t *testing.T) {
+	dir := t.TempDir()
+	primary, err := New(dir, 16, 16, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer primary.Close()
+
+	if err := primary.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	// The secondary can be opened while the primary holds the lock
+	secondary, err := NewSecondary(dir, 16, 16)
+	if err != nil {
+		t.Fatalf("failed to open secondary: %v", err)
+	}
+	if val, err := secondary.Get([]byte("a")); err != nil || !bytes.Equal(val, []byte("1")) {
+		t.Fatalf("wrong value: have %x, err %v", val, err)
+	}
+	if err := secondary.Put([]byte("b"), []byte("2")); err == nil {
+		t.Fatal("secondary accepted write")
+	}
+	// Writes of the primary are visible after reopening
+	if err := primary.Put([]byte("b"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.Compact(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.Put([]byte("c"), []byte("3")); err != nil {
+		t.Fatal(err)
+	}
+	secondary.Close()
+	if secondary, err = NewSecondary(dir, 16, 16); err != nil {
+		t.Fatalf("failed to reopen secondary: %v", err)
+	}
+	defer secondary.Close()
+	for _, key := range []string{"a", "b", "c"} {
+		if ok, err := secondary.Has([]byte(key)); err != nil || !ok {
+			t.Errorf("key %s missing from secondary: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !js
+// +build !js
+
+package leveldb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Altcoinchain/go-altcoinchain/log"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+)
+
+// NewSecondary opens the database read-only while another process, the primary,
+// is writing to it. The primary's lock is not taken, and the database reflects
+// the content of the primary at the time of opening: it needs to be reopened to
+// observe later writes. No metrics are collected.
+func NewSecondary(file string, cache int, handles int) (*Database, error) {
+	if cache < minCache {
+		cache = minCache
+	}
+	if handles < minHandles {
+		handles = minHandles
+	}
+	options := configureOptions(func(options *opt.Options) {
+		options.OpenFilesCacheCapacity = handles
+		options.BlockCacheCapacity = cache / 2 * opt.MiB
+		options.WriteBuffer = cache / 4 * opt.MiB
+		options.ReadOnly = true
+	})
+	db, err := leveldb.Open(&secondaryStorage{path: file}, options)
+	if err != nil {
+		return nil, err
+	}
+	return &Database{
+		fn:  file,
+		db:  db,
+		log: log.New("database", file),
+	}, nil
+}
+
+// secondaryStorage is a read-only leveldb storage which doesn't lock the
+// database directory, so that it can be opened alongside the primary.
+type secondaryStorage struct {
+	path string
+}
+
+type secondaryLock struct{}
+
+func (secondaryLock) Unlock() {}
+
+func (s *secondaryStorage) Lock() (storage.Locker, error) { return secondaryLock{}, nil }
+
+func (s *secondaryStorage) Log(str string) {}
+
+func (s *secondaryStorage) SetMeta(fd storage.FileDesc) error { return leveldb.ErrReadOnly }
+
+// GetMeta returns the manifest currently in use by the primary.
+func (s *secondaryStorage) GetMeta() (storage.FileDesc, error) {
+	blob, err := os.ReadFile(filepath.Join(s.path, "CURRENT"))
+	if err != nil {
+		return storage.FileDesc{}, err
+	}
+	fd := storage.FileDesc{Type: storage.TypeManifest}
+	if _, err := fmt.Sscanf(strings.TrimSpace(string(blob)), "MANIFEST-%d", &fd.Num); err != nil {
+		return storage.FileDesc{}, &storage.ErrCorrupted{Err: fmt.Errorf("invalid CURRENT file: %v", err)}
+	}
+	return fd, nil
+}
+
+// List returns the files of the given types in the database directory.
+func (s *secondaryStorage) List(ft storage.FileType) ([]storage.FileDesc, error) {
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		return nil, err
+	}
+	var fds []storage.FileDesc
+	for _, entry := range entries {
+		var (
+			fd   storage.FileDesc
+			name = entry.Name()
+			ext  string
+		)
+		if n, _ := fmt.Sscanf(name, "MANIFEST-%d", &fd.Num); n == 1 {
+			fd.Type = storage.TypeManifest
+		} else if n, _ := fmt.Sscanf(name, "%d.%s", &fd.Num, &ext); n == 2 {
+			switch ext {
+			case "log":
+				fd.Type = storage.TypeJournal
+			case "ldb", "sst":
+				fd.Type = storage.TypeTable
+			case "tmp":
+				fd.Type = storage.TypeTemp
+			default:
+				continue
+			}
+		} else {
+			continue
+		}
+		if fd.Type&ft != 0 {
+			fds = append(fds, fd)
+		}
+	}
+	return fds, nil
+}
+
+// Open opens the file for reading, trying the legacy table extension too.
+func (s *secondaryStorage) Open(fd storage.FileDesc) (storage.Reader, error) {
+	f, err := os.Open(filepath.Join(s.path, fd.String()))
+	if os.IsNotExist(err) && fd.Type == storage.TypeTable {
+		f, err = os.Open(filepath.Join(s.path, fmt.Sprintf("%06d.sst", fd.Num)))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *secondaryStorage) Create(fd storage.FileDesc) (storage.Writer, error) {
+	return nil, leveldb.ErrReadOnly
+}
+
+func (s *secondaryStorage) Remove(fd storage.FileDesc) error { return leveldb.ErrReadOnly }
+
+func (s *secondaryStorage) Rename(oldfd, newfd storage.FileDesc) error { return leveldb.ErrReadOnly }
+
+func (s *secondaryStorage) Close() error { return nil }
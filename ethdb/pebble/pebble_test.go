@@ -0,0 +1,91 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+//go:build arm64 || amd64
+// +build arm64 amd64
+
+package pebble
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/ethdb"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb/dbtest"
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/vfs"
+)
+
+func TestPebbleDB(t *testing.T) {
+	t.Run("DatabaseSuite", func(t *testing.T) {
+		dbtest.TestDatabaseSuite(t, func() ethdb.KeyValueStore {
+			db, err := pebble.Open("", &pebble.Options{
+				FS: vfs.NewMem(),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return &Database{
+				db: db,
+			}
+		})
+	})
+}
+
+func TestSecondary(t *testing.T) {
+	dir := t.TempDir()
+	primary, err := New(dir, 16, 16, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer primary.Close()
+
+	// Unsynced writes are buffered by the primary, flush them to disk
+	if err := primary.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.db.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	// The secondary can be opened while the primary holds the lock
+	secondary, err := NewSecondary(dir, 16, 16)
+	if err != nil {
+		t.Fatalf("failed to open secondary: %v", err)
+	}
+	if val, err := secondary.Get([]byte("a")); err != nil || !bytes.Equal(val, []byte("1")) {
+		t.Fatalf("wrong value: have %x, err %v", val, err)
+	}
+	if err := secondary.Put([]byte("b"), []byte("2")); err == nil {
+		t.Fatal("secondary accepted write")
+	}
+	// Writes of the primary are visible after reopening
+	if err := primary.Put([]byte("b"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.db.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	secondary.Close()
+	if secondary, err = NewSecondary(dir, 16, 16); err != nil {
+		t.Fatalf("failed to reopen secondary: %v", err)
+	}
+	defer secondary.Close()
+	for _, key := range []string{"a", "b"} {
+		if ok, err := secondary.Has([]byte(key)); err != nil || !ok {
+			t.Errorf("key %s missing from secondary: %v", key, err)
+		}
+	}
+}
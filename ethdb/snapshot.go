@@ -25,6 +25,11 @@ type Snapshot interface {
 	// key-value data store.
 	Get(key []byte) ([]byte, error)
 
+	// NewIterator creates a binary-alphabetical iterator over a subset of the
+	// snapshot content with a particular key prefix, starting at a particular
+	// initial key (or after, if it does not exist).
+	NewIterator(prefix []byte, start []byte) Iterator
+
 	// Release releases associated resources. Release should always succeed and can
 	// be called multiple times without causing error.
 	Release()
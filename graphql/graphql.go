@@ -166,6 +166,79 @@ func (l *Log) Data(ctx context.Context) hexutil.Bytes {
 	return l.log.Data
 }
 
+// AccountDiff represents the change of an account made by a block.
+type AccountDiff struct {
+	diff *types.AccountDiff
+}
+
+func (d *AccountDiff) Address(ctx context.Context) common.Address {
+	return d.diff.Address
+}
+
+func (d *AccountDiff) Pre(ctx context.Context) *AccountState {
+	if d.diff.Pre == nil {
+		return nil
+	}
+	return &AccountState{account: d.diff.Pre}
+}
+
+func (d *AccountDiff) Post(ctx context.Context) *AccountState {
+	if d.diff.Post == nil {
+		return nil
+	}
+	return &AccountState{account: d.diff.Post}
+}
+
+func (d *AccountDiff) StorageCleared(ctx context.Context) bool {
+	return d.diff.StorageCleared
+}
+
+func (d *AccountDiff) Storage(ctx context.Context) []*StorageDiff {
+	ret := make([]*StorageDiff, 0, len(d.diff.Storage))
+	for _, slot := range d.diff.Storage {
+		ret = append(ret, &StorageDiff{diff: slot})
+	}
+	return ret
+}
+
+// AccountState represents the state of an account before or after a block.
+type AccountState struct {
+	account *types.StateAccount
+}
+
+func (s *AccountState) Nonce(ctx context.Context) Long {
+	return Long(s.account.Nonce)
+}
+
+func (s *AccountState) Balance(ctx context.Context) hexutil.Big {
+	return hexutil.Big(*s.account.Balance)
+}
+
+func (s *AccountState) CodeHash(ctx context.Context) common.Hash {
+	return common.BytesToHash(s.account.CodeHash)
+}
+
+func (s *AccountState) StorageRoot(ctx context.Context) common.Hash {
+	return s.account.Root
+}
+
+// StorageDiff represents the change of a storage slot made by a block.
+type StorageDiff struct {
+	diff *types.StorageDiff
+}
+
+func (d *StorageDiff) Key(ctx context.Context) common.Hash {
+	return d.diff.Key
+}
+
+func (d *StorageDiff) Pre(ctx context.Context) common.Hash {
+	return d.diff.Pre
+}
+
+func (d *StorageDiff) Post(ctx context.Context) common.Hash {
+	return d.diff.Post
+}
+
 // AccessTuple represents EIP-2930
 type AccessTuple struct {
 	address     common.Address
@@ -1024,6 +1097,26 @@ func (b *Block) Account(ctx context.Context, args struct {
 	}, nil
 }
 
+func (b *Block) StateDiff(ctx context.Context) (*[]*AccountDiff, error) {
+	hash := b.hash
+	if hash == (common.Hash{}) {
+		header, err := b.resolveHeader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		hash = header.Hash()
+	}
+	diff, err := b.r.backend.GetStateDiff(ctx, hash)
+	if err != nil || diff == nil {
+		return nil, err
+	}
+	ret := make([]*AccountDiff, 0, len(diff.Accounts))
+	for _, account := range diff.Accounts {
+		ret = append(ret, &AccountDiff{diff: account})
+	}
+	return &ret, nil
+}
+
 // CallData encapsulates arguments to `call` or `estimateGas`.
 // All arguments are optional.
 type CallData struct {
@@ -69,6 +69,43 @@ const schema string = `
         transaction: Transaction!
     }
 
+    # AccountDiff is the change of an account made by a block.
+    type AccountDiff {
+        # Address is the address of the changed account.
+        address: Address!
+        # Pre is the account before the block, null if it didn't exist.
+        pre: AccountState
+        # Post is the account after the block, null if it was deleted.
+        post: AccountState
+        # StorageCleared is set if the storage the account had before the block
+        # was dropped. The storage changes are then relative to the empty storage.
+        storageCleared: Boolean!
+        # Storage is the list of changed storage slots.
+        storage: [StorageDiff!]!
+    }
+
+    # AccountState is the state of an account before or after a block.
+    type AccountState {
+        # Nonce is the nonce of the account.
+        nonce: Long!
+        # Balance is the balance of the account, in wei.
+        balance: BigInt!
+        # CodeHash is the keccak256 hash of the account code.
+        codeHash: Bytes32!
+        # StorageRoot is the root hash of the account storage trie.
+        storageRoot: Bytes32!
+    }
+
+    # StorageDiff is the change of a storage slot made by a block.
+    type StorageDiff {
+        # Key is the storage slot key.
+        key: Bytes32!
+        # Pre is the value before the block.
+        pre: Bytes32!
+        # Post is the value after the block.
+        post: Bytes32!
+    }
+
     #EIP-2718
     type AccessTuple{
         address: Address!
@@ -237,6 +274,10 @@ const schema string = `
         logs(filter: BlockFilterCriteria!): [Log!]!
         # Account fetches an Ethereum account at the current block's state.
         account(address: Address!): Account!
+        # StateDiff is the list of account changes made by this block. If the
+        # node didn't record the state changes of this block, this field will
+        # be null.
+        stateDiff: [AccountDiff!]
         # Call executes a local call operation at the current block's state.
         call(data: CallData!): CallResult
         # EstimateGas estimates the amount of gas that will be required for
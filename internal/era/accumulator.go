@@ -0,0 +1,100 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package era
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+)
+
+// ComputeAccumulator calculates the accumulator root of an era: the SSZ hash
+// tree root of its list of header records, each pairing a block hash with the
+// total difficulty after that block. The list is limited to MaxEraSize entries.
+func ComputeAccumulator(hashes []common.Hash, tds []*big.Int) (common.Hash, error) {
+	if len(hashes) != len(tds) {
+		return common.Hash{}, fmt.Errorf("mismatched header records: %d hashes, %d difficulties", len(hashes), len(tds))
+	}
+	if len(hashes) > MaxEraSize {
+		return common.Hash{}, fmt.Errorf("too many header records: %d > %d", len(hashes), MaxEraSize)
+	}
+	leaves := make([][32]byte, len(hashes))
+	for i, hash := range hashes {
+		if tds[i].Sign() < 0 || tds[i].BitLen() > 256 {
+			return common.Hash{}, fmt.Errorf("invalid total difficulty %v", tds[i])
+		}
+		var record [64]byte
+		copy(record[:32], hash[:])
+		putUint256(record[32:], tds[i])
+		leaves[i] = sha256.Sum256(record[:])
+	}
+	var length [64]byte
+	root := merkleize(leaves, MaxEraSize)
+	copy(length[:32], root[:])
+	binary.LittleEndian.PutUint64(length[32:], uint64(len(hashes)))
+	return sha256.Sum256(length[:]), nil
+}
+
+// merkleize computes the root of the binary merkle tree over the given leaves,
+// padded with zero chunks up to limit, which must be a power of two.
+func merkleize(leaves [][32]byte, limit int) [32]byte {
+	var (
+		layer = leaves
+		zero  [32]byte // Root of an all-zero subtree at the current depth
+		pair  [64]byte
+	)
+	for width := limit; width > 1; width /= 2 {
+		next := make([][32]byte, (len(layer)+1)/2)
+		for i := range next {
+			copy(pair[:32], layer[2*i][:])
+			if 2*i+1 < len(layer) {
+				copy(pair[32:], layer[2*i+1][:])
+			} else {
+				copy(pair[32:], zero[:])
+			}
+			next[i] = sha256.Sum256(pair[:])
+		}
+		copy(pair[:32], zero[:])
+		copy(pair[32:], zero[:])
+		zero = sha256.Sum256(pair[:])
+		layer = next
+	}
+	if len(layer) == 0 {
+		return zero
+	}
+	return layer[0]
+}
+
+// putUint256 writes v into the 32 byte buffer as a little-endian integer.
+func putUint256(buf []byte, v *big.Int) {
+	b := v.Bytes()
+	for i := range b {
+		buf[i] = b[len(b)-1-i]
+	}
+}
+
+// readUint256 decodes a little-endian integer.
+func readUint256(buf []byte) *big.Int {
+	b := make([]byte, len(buf))
+	for i := range buf {
+		b[i] = buf[len(buf)-1-i]
+	}
+	return new(big.Int).SetBytes(b)
+}
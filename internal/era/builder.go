@@ -0,0 +1,163 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package era
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/internal/era/e2store"
+	"github.com/Altcoinchain/go-altcoinchain/rlp"
+	"github.com/golang/snappy"
+)
+
+// Builder writes the blocks of a single era into an era file. Blocks must be
+// added in order; the accumulator and block index are appended by Finalize.
+type Builder struct {
+	w       *e2store.Writer
+	start   *uint64
+	offsets []uint64
+	hashes  []common.Hash
+	tds     []*big.Int
+	written uint64
+
+	buf    *bytes.Buffer
+	snappy *snappy.Writer
+}
+
+// NewBuilder returns a new Builder writing to w.
+func NewBuilder(w io.Writer) *Builder {
+	buf := new(bytes.Buffer)
+	return &Builder{
+		w:      e2store.NewWriter(w),
+		buf:    buf,
+		snappy: snappy.NewBufferedWriter(buf),
+	}
+}
+
+// Add appends a block with its receipts and the total difficulty after it.
+func (b *Builder) Add(block *types.Block, receipts types.Receipts, td *big.Int) error {
+	header, err := rlp.EncodeToBytes(block.Header())
+	if err != nil {
+		return err
+	}
+	body, err := rlp.EncodeToBytes(block.Body())
+	if err != nil {
+		return err
+	}
+	encReceipts, err := rlp.EncodeToBytes(receipts)
+	if err != nil {
+		return err
+	}
+	return b.AddRLP(header, body, encReceipts, block.NumberU64(), block.Hash(), td)
+}
+
+// AddRLP appends an already encoded block with its receipts and the total
+// difficulty after it.
+func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash common.Hash, td *big.Int) error {
+	if b.start == nil {
+		if b.written == 0 {
+			// Write the version entry before the first block
+			n, err := b.w.Write(TypeVersion, nil)
+			if err != nil {
+				return err
+			}
+			b.written += uint64(n)
+		}
+		b.start = &number
+	} else if number != *b.start+uint64(len(b.offsets)) {
+		return fmt.Errorf("non-contiguous block %d, expected %d", number, *b.start+uint64(len(b.offsets)))
+	}
+	if len(b.offsets) >= MaxEraSize {
+		return fmt.Errorf("exceeds maximum era size of %d blocks", MaxEraSize)
+	}
+	b.offsets = append(b.offsets, b.written)
+	b.hashes = append(b.hashes, hash)
+	b.tds = append(b.tds, new(big.Int).Set(td))
+
+	for _, entry := range []struct {
+		typ  uint16
+		data []byte
+	}{
+		{TypeCompressedHeader, header},
+		{TypeCompressedBody, body},
+		{TypeCompressedReceipts, receipts},
+	} {
+		if err := b.writeCompressed(entry.typ, entry.data); err != nil {
+			return err
+		}
+	}
+	var difficulty [32]byte
+	putUint256(difficulty[:], td)
+	n, err := b.w.Write(TypeTotalDifficulty, difficulty[:])
+	b.written += uint64(n)
+	return err
+}
+
+// Finalize writes the accumulator and the block index, returning the
+// accumulator root of the era.
+func (b *Builder) Finalize() (common.Hash, error) {
+	if b.start == nil {
+		return common.Hash{}, errors.New("finalize called on empty era")
+	}
+	root, err := ComputeAccumulator(b.hashes, b.tds)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	n, err := b.w.Write(TypeAccumulator, root[:])
+	if err != nil {
+		return common.Hash{}, err
+	}
+	b.written += uint64(n)
+
+	// The block offsets are relative to the start of the index entry
+	var (
+		count = len(b.offsets)
+		index = make([]byte, 16+count*8)
+		base  = int64(b.written)
+	)
+	binary.LittleEndian.PutUint64(index, *b.start)
+	for i, offset := range b.offsets {
+		binary.LittleEndian.PutUint64(index[8+i*8:], uint64(int64(offset)-base))
+	}
+	binary.LittleEndian.PutUint64(index[8+count*8:], uint64(count))
+	if _, err := b.w.Write(TypeBlockIndex, index); err != nil {
+		return common.Hash{}, err
+	}
+	return root, nil
+}
+
+// writeCompressed writes a snappy framed entry.
+func (b *Builder) writeCompressed(typ uint16, data []byte) error {
+	b.buf.Reset()
+	b.snappy.Reset(b.buf)
+	if _, err := b.snappy.Write(data); err != nil {
+		return err
+	}
+	if err := b.snappy.Flush(); err != nil {
+		return err
+	}
+	n, err := b.w.Write(typ, b.buf.Bytes())
+	b.written += uint64(n)
+	return err
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package e2store implements the simple type-length-value container format
+// used by the era history archives.
+package e2store
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// headerSize is the size of an entry header: a 2 byte type, a 4 byte length
+// and 2 reserved bytes, all little-endian.
+const headerSize = 8
+
+var errReservedNonZero = errors.New("reserved header bytes are not zero")
+
+// Entry is a variable-length encoded entry within an e2store.
+type Entry struct {
+	Type  uint16
+	Value []byte
+}
+
+// Writer appends entries to an e2store stream.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter returns a new Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes a single entry with the given type and value, returning the
+// number of bytes written including the header.
+func (w *Writer) Write(typ uint16, value []byte) (int, error) {
+	var header [headerSize]byte
+	binary.LittleEndian.PutUint16(header[:2], typ)
+	binary.LittleEndian.PutUint32(header[2:6], uint32(len(value)))
+	if n, err := w.w.Write(header[:]); err != nil {
+		return n, err
+	}
+	n, err := w.w.Write(value)
+	return headerSize + n, err
+}
+
+// Reader reads entries from an e2store at arbitrary offsets.
+type Reader struct {
+	r io.ReaderAt
+}
+
+// NewReader returns a new Reader that reads from r.
+func NewReader(r io.ReaderAt) *Reader {
+	return &Reader{r: r}
+}
+
+// ReadMetadataAt reads the type and value length of the entry at offset off.
+func (r *Reader) ReadMetadataAt(off int64) (typ uint16, length uint32, err error) {
+	var header [headerSize]byte
+	if _, err := r.r.ReadAt(header[:], off); err != nil {
+		return 0, 0, err
+	}
+	if header[6] != 0 || header[7] != 0 {
+		return 0, 0, errReservedNonZero
+	}
+	return binary.LittleEndian.Uint16(header[:2]), binary.LittleEndian.Uint32(header[2:6]), nil
+}
+
+// ReadAt reads the entry at offset off, returning it along with its total
+// size including the header.
+func (r *Reader) ReadAt(off int64) (*Entry, int, error) {
+	typ, length, err := r.ReadMetadataAt(off)
+	if err != nil {
+		return nil, 0, err
+	}
+	entry := &Entry{Type: typ, Value: make([]byte, length)}
+	if length > 0 {
+		if _, err := r.r.ReadAt(entry.Value, off+headerSize); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, 0, fmt.Errorf("entry at %d: %w", off, err)
+		}
+	}
+	return entry, headerSize + int(length), nil
+}
+
+// Find returns the first entry of the given type, scanning from the start of
+// the store.
+func (r *Reader) Find(want uint16) (*Entry, error) {
+	for off := int64(0); ; {
+		typ, length, err := r.ReadMetadataAt(off)
+		if err != nil {
+			return nil, err
+		}
+		if typ == want {
+			entry, _, err := r.ReadAt(off)
+			return entry, err
+		}
+		off += headerSize + int64(length)
+	}
+}
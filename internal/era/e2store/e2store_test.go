@@ -0,0 +1,78 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package e2store
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	entries := []Entry{
+		{Type: 0x3265, Value: nil},
+		{Type: 0x01, Value: []byte{0xde, 0xad, 0xbe, 0xef}},
+		{Type: 0x02, Value: bytes.Repeat([]byte{0x42}, 300)},
+	}
+	var (
+		buf bytes.Buffer
+		w   = NewWriter(&buf)
+	)
+	for _, entry := range entries {
+		n, err := w.Write(entry.Type, entry.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != headerSize+len(entry.Value) {
+			t.Fatalf("wrong write size: have %d, want %d", n, headerSize+len(entry.Value))
+		}
+	}
+	r := NewReader(bytes.NewReader(buf.Bytes()))
+	var off int64
+	for i, want := range entries {
+		have, n, err := r.ReadAt(off)
+		if err != nil {
+			t.Fatalf("entry %d: %v", i, err)
+		}
+		if have.Type != want.Type || !bytes.Equal(have.Value, want.Value) {
+			t.Fatalf("entry %d mismatch: have %x %x, want %x %x", i, have.Type, have.Value, want.Type, want.Value)
+		}
+		off += int64(n)
+	}
+	if _, _, err := r.ReadAt(off); err != io.EOF {
+		t.Fatalf("expected EOF, have %v", err)
+	}
+	if entry, err := r.Find(0x02); err != nil || len(entry.Value) != 300 {
+		t.Fatalf("failed to find entry: %v", err)
+	}
+	if _, err := r.Find(0x03); err != io.EOF {
+		t.Fatalf("expected EOF for missing entry, have %v", err)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	// Reserved bytes must be zero
+	r := NewReader(bytes.NewReader([]byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00}))
+	if _, _, err := r.ReadAt(0); err != errReservedNonZero {
+		t.Fatalf("expected reserved bytes error, have %v", err)
+	}
+	// Value shorter than its length
+	r = NewReader(bytes.NewReader([]byte{0x01, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff}))
+	if _, _, err := r.ReadAt(0); err == nil {
+		t.Fatal("expected error for truncated value")
+	}
+}
@@ -0,0 +1,364 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package era implements the era archive format for distributing verifiable
+// block history.
+//
+// An era file holds the blocks of a single epoch of MaxEraSize blocks as an
+// e2store stream:
+//
+//	era        := Version | block-tuple* | Accumulator | BlockIndex
+//	block-tuple := CompressedHeader | CompressedBody | CompressedReceipts | TotalDifficulty
+//
+// Headers, bodies and receipts are snappy framed RLP. The accumulator is the
+// root over the (hash, total difficulty) records of the contained blocks, and
+// the block index maps block numbers to the offsets of their tuples.
+package era
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/internal/era/e2store"
+	"github.com/Altcoinchain/go-altcoinchain/rlp"
+	"github.com/Altcoinchain/go-altcoinchain/trie"
+	"github.com/golang/snappy"
+)
+
+// Entry types of the era format.
+const (
+	TypeVersion            uint16 = 0x3265
+	TypeCompressedHeader   uint16 = 0x03
+	TypeCompressedBody     uint16 = 0x04
+	TypeCompressedReceipts uint16 = 0x05
+	TypeTotalDifficulty    uint16 = 0x06
+	TypeAccumulator        uint16 = 0x07
+	TypeBlockIndex         uint16 = 0x3266
+)
+
+// MaxEraSize is the number of blocks in an epoch, and thus the maximum
+// number of blocks in an era file.
+const MaxEraSize = 8192
+
+var errOutOfRange = errors.New("block number out of range")
+
+// Filename returns the name of the era file of the given network and epoch,
+// including the start of its accumulator root.
+func Filename(network string, epoch int, root common.Hash) string {
+	return fmt.Sprintf("%s-%05d-%s.era1", network, epoch, hex.EncodeToString(root[:4]))
+}
+
+// ReadDir returns the era files of the given network in dir, ordered by epoch.
+// It fails if the epochs are not contiguous.
+func ReadDir(dir, network string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		files  []string
+		epochs = make(map[string]int)
+	)
+	for _, entry := range entries {
+		name := entry.Name()
+		if filepath.Ext(name) != ".era1" {
+			continue
+		}
+		parts := strings.Split(strings.TrimSuffix(name, ".era1"), "-")
+		if len(parts) != 3 || parts[0] != network {
+			continue
+		}
+		epoch, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("malformed era file name %q", name)
+		}
+		files, epochs[name] = append(files, name), epoch
+	}
+	sort.Slice(files, func(i, j int) bool { return epochs[files[i]] < epochs[files[j]] })
+	for i := 1; i < len(files); i++ {
+		if epochs[files[i]] != epochs[files[i-1]]+1 {
+			return nil, fmt.Errorf("missing era file for epoch %d", epochs[files[i-1]]+1)
+		}
+	}
+	return files, nil
+}
+
+// ReadAtSeekCloser is the file access needed by Era.
+type ReadAtSeekCloser interface {
+	io.ReaderAt
+	io.Seeker
+	io.Closer
+}
+
+// Era provides access to the blocks of an era file.
+type Era struct {
+	f     ReadAtSeekCloser
+	s     *e2store.Reader
+	start uint64 // Number of the first block
+	count uint64 // Number of blocks
+	index int64  // Offset of the block index entry
+}
+
+// Open opens the era file with the given name.
+func Open(filename string) (*Era, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	e, err := From(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// From returns an Era backed by f, which is closed along with it.
+func From(f ReadAtSeekCloser) (*Era, error) {
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	s := e2store.NewReader(f)
+	if typ, _, err := s.ReadMetadataAt(0); err != nil {
+		return nil, err
+	} else if typ != TypeVersion {
+		return nil, fmt.Errorf("not an era file: unexpected version type %#x", typ)
+	}
+	// The block count is the last field of the index at the end of the file
+	var buf [8]byte
+	if size < 8 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if _, err := f.ReadAt(buf[:], size-8); err != nil {
+		return nil, err
+	}
+	count := binary.LittleEndian.Uint64(buf[:])
+	if count == 0 || count > MaxEraSize {
+		return nil, fmt.Errorf("invalid block count %d", count)
+	}
+	e := &Era{f: f, s: s, count: count, index: size - int64(8+16+count*8)}
+	typ, length, err := s.ReadMetadataAt(e.index)
+	if err != nil {
+		return nil, err
+	}
+	if typ != TypeBlockIndex || uint64(length) != 16+count*8 {
+		return nil, errors.New("malformed block index")
+	}
+	if _, err := f.ReadAt(buf[:], e.index+8); err != nil {
+		return nil, err
+	}
+	e.start = binary.LittleEndian.Uint64(buf[:])
+	return e, nil
+}
+
+// Close closes the underlying file.
+func (e *Era) Close() error {
+	return e.f.Close()
+}
+
+// Start returns the number of the first block in the era.
+func (e *Era) Start() uint64 {
+	return e.start
+}
+
+// Count returns the number of blocks in the era.
+func (e *Era) Count() uint64 {
+	return e.count
+}
+
+// Accumulator returns the accumulator root stored in the era.
+func (e *Era) Accumulator() (common.Hash, error) {
+	entry, _, err := e.s.ReadAt(e.index - 8 - common.HashLength)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if entry.Type != TypeAccumulator || len(entry.Value) != common.HashLength {
+		return common.Hash{}, errors.New("malformed accumulator")
+	}
+	return common.BytesToHash(entry.Value), nil
+}
+
+// GetBlockByNumber returns the block with the given number.
+func (e *Era) GetBlockByNumber(num uint64) (*types.Block, error) {
+	block, _, _, err := e.readTuple(num, false)
+	return block, err
+}
+
+// GetReceiptsByNumber returns the receipts of the block with the given number.
+func (e *Era) GetReceiptsByNumber(num uint64) (types.Receipts, error) {
+	_, receipts, _, err := e.readTuple(num, true)
+	return receipts, err
+}
+
+// offset returns the file offset of the block tuple of the given number.
+func (e *Era) offset(num uint64) (int64, error) {
+	if num < e.start || num >= e.start+e.count {
+		return 0, errOutOfRange
+	}
+	var buf [8]byte
+	if _, err := e.f.ReadAt(buf[:], e.index+8+8+int64(num-e.start)*8); err != nil {
+		return 0, err
+	}
+	return e.index + int64(binary.LittleEndian.Uint64(buf[:])), nil
+}
+
+// readTuple decodes the block tuple of the given number. The receipts are only
+// decoded if requested.
+func (e *Era) readTuple(num uint64, withReceipts bool) (*types.Block, types.Receipts, *big.Int, error) {
+	off, err := e.offset(num)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var entries [4]*e2store.Entry
+	for i, typ := range []uint16{TypeCompressedHeader, TypeCompressedBody, TypeCompressedReceipts, TypeTotalDifficulty} {
+		entry, n, err := e.s.ReadAt(off)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if entry.Type != typ {
+			return nil, nil, nil, fmt.Errorf("block %d: unexpected entry type %#x, want %#x", num, entry.Type, typ)
+		}
+		entries[i], off = entry, off+int64(n)
+	}
+	var (
+		header   types.Header
+		body     types.Body
+		receipts types.Receipts
+	)
+	if err := decodeCompressed(entries[0].Value, &header); err != nil {
+		return nil, nil, nil, fmt.Errorf("block %d header: %w", num, err)
+	}
+	if err := decodeCompressed(entries[1].Value, &body); err != nil {
+		return nil, nil, nil, fmt.Errorf("block %d body: %w", num, err)
+	}
+	if withReceipts {
+		if err := decodeCompressed(entries[2].Value, &receipts); err != nil {
+			return nil, nil, nil, fmt.Errorf("block %d receipts: %w", num, err)
+		}
+	}
+	if len(entries[3].Value) != 32 {
+		return nil, nil, nil, fmt.Errorf("block %d: malformed total difficulty", num)
+	}
+	block := types.NewBlockWithHeader(&header).WithBody(body.Transactions, body.Uncles)
+	return block, receipts, readUint256(entries[3].Value), nil
+}
+
+// decodeCompressed decodes a snappy framed RLP value.
+func decodeCompressed(data []byte, val interface{}) error {
+	return rlp.Decode(snappy.NewReader(bytes.NewReader(data)), val)
+}
+
+// Iterator walks over the blocks of an era in order.
+type Iterator struct {
+	e    *Era
+	next uint64
+	err  error
+
+	block    *types.Block
+	receipts types.Receipts
+	td       *big.Int
+}
+
+// NewIterator returns an iterator positioned before the first block of e.
+func NewIterator(e *Era) *Iterator {
+	return &Iterator{e: e, next: e.start}
+}
+
+// Next advances to the next block, returning false at the end of the era or on
+// error.
+func (it *Iterator) Next() bool {
+	if it.err != nil || it.next >= it.e.start+it.e.count {
+		return false
+	}
+	it.block, it.receipts, it.td, it.err = it.e.readTuple(it.next, true)
+	if it.err != nil {
+		return false
+	}
+	it.next++
+	return true
+}
+
+// Block returns the current block.
+func (it *Iterator) Block() *types.Block {
+	return it.block
+}
+
+// Receipts returns the receipts of the current block.
+func (it *Iterator) Receipts() types.Receipts {
+	return it.receipts
+}
+
+// TotalDifficulty returns the total difficulty after the current block.
+func (it *Iterator) TotalDifficulty() *big.Int {
+	return it.td
+}
+
+// Error returns the error that stopped the iteration, if any.
+func (it *Iterator) Error() error {
+	return it.err
+}
+
+// Verify checks that the blocks of the era match its accumulator, and that
+// their bodies and receipts match their headers. The accumulator root is
+// returned on success.
+func (e *Era) Verify() (common.Hash, error) {
+	want, err := e.Accumulator()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var (
+		it     = NewIterator(e)
+		hashes = make([]common.Hash, 0, e.count)
+		tds    = make([]*big.Int, 0, e.count)
+	)
+	for it.Next() {
+		block, receipts := it.Block(), it.Receipts()
+		if hash := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); hash != block.TxHash() {
+			return common.Hash{}, fmt.Errorf("block %d: transaction root mismatch", block.NumberU64())
+		}
+		if hash := types.CalcUncleHash(block.Uncles()); hash != block.UncleHash() {
+			return common.Hash{}, fmt.Errorf("block %d: uncle hash mismatch", block.NumberU64())
+		}
+		if hash := types.DeriveSha(receipts, trie.NewStackTrie(nil)); hash != block.ReceiptHash() {
+			return common.Hash{}, fmt.Errorf("block %d: receipt root mismatch", block.NumberU64())
+		}
+		hashes, tds = append(hashes, block.Hash()), append(tds, it.TotalDifficulty())
+	}
+	if it.Error() != nil {
+		return common.Hash{}, it.Error()
+	}
+	have, err := ComputeAccumulator(hashes, tds)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if have != want {
+		return common.Hash{}, fmt.Errorf("accumulator mismatch: have %x, want %x", have, want)
+	}
+	return have, nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package era
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/trie"
+)
+
+// makeTestChain creates a chain of n blocks starting at the given number, each
+// with a single transaction and its receipt.
+func makeTestChain(start uint64, n int) ([]*types.Block, []types.Receipts, []*big.Int) {
+	var (
+		blocks   []*types.Block
+		receipts []types.Receipts
+		tds      []*big.Int
+		parent   common.Hash
+		td       = new(big.Int)
+		to       = common.HexToAddress("0x1111")
+	)
+	for i := 0; i < n; i++ {
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     new(big.Int).SetUint64(start + uint64(i)),
+			Difficulty: big.NewInt(int64(i + 1)),
+			GasLimit:   1000000,
+			GasUsed:    21000,
+		}
+		tx := types.NewTx(&types.LegacyTx{Nonce: uint64(i), GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)})
+		receipt := &types.Receipt{
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: 21000,
+			Logs:              []*types.Log{{Address: to, Topics: []common.Hash{{byte(i)}}, Data: []byte{byte(i)}}},
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+		block := types.NewBlock(header, []*types.Transaction{tx}, nil, []*types.Receipt{receipt}, trie.NewStackTrie(nil))
+
+		td = new(big.Int).Add(td, header.Difficulty)
+		blocks, receipts, tds = append(blocks, block), append(receipts, types.Receipts{receipt}), append(tds, td)
+		parent = block.Hash()
+	}
+	return blocks, receipts, tds
+}
+
+func writeTestEra(t *testing.T, path string, blocks []*types.Block, receipts []types.Receipts, tds []*big.Int) common.Hash {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	builder := NewBuilder(f)
+	for i, block := range blocks {
+		if err := builder.Add(block, receipts[i], tds[i]); err != nil {
+			t.Fatalf("failed to add block %d: %v", i, err)
+		}
+	}
+	root, err := builder.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestEraRoundTrip(t *testing.T) {
+	var (
+		path                 = filepath.Join(t.TempDir(), "test.era1")
+		blocks, receipts, td = makeTestChain(100, 128)
+		root                 = writeTestEra(t, path, blocks, receipts, td)
+	)
+	e, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	if e.Start() != 100 || e.Count() != 128 {
+		t.Fatalf("wrong era range: start %d, count %d", e.Start(), e.Count())
+	}
+	if have, err := e.Accumulator(); err != nil || have != root {
+		t.Fatalf("wrong accumulator: have %x, want %x (err %v)", have, root, err)
+	}
+	if have, err := e.Verify(); err != nil || have != root {
+		t.Fatalf("verification failed: %v", err)
+	}
+	for i, want := range blocks {
+		block, err := e.GetBlockByNumber(want.NumberU64())
+		if err != nil {
+			t.Fatalf("failed to read block %d: %v", i, err)
+		}
+		if block.Hash() != want.Hash() || block.Transactions()[0].Hash() != want.Transactions()[0].Hash() {
+			t.Fatalf("block %d mismatch", i)
+		}
+		have, err := e.GetReceiptsByNumber(want.NumberU64())
+		if err != nil {
+			t.Fatalf("failed to read receipts %d: %v", i, err)
+		}
+		if types.DeriveSha(have, trie.NewStackTrie(nil)) != want.ReceiptHash() {
+			t.Fatalf("receipts %d mismatch", i)
+		}
+	}
+	if _, err := e.GetBlockByNumber(99); err != errOutOfRange {
+		t.Fatalf("expected out of range error, have %v", err)
+	}
+	it := NewIterator(e)
+	for i := 0; it.Next(); i++ {
+		if it.Block().Hash() != blocks[i].Hash() || it.TotalDifficulty().Cmp(td[i]) != 0 {
+			t.Fatalf("iterator mismatch at %d", i)
+		}
+	}
+	if it.Error() != nil {
+		t.Fatal(it.Error())
+	}
+}
+
+func TestEraVerifyTampered(t *testing.T) {
+	var (
+		dir                  = t.TempDir()
+		blocks, receipts, td = makeTestChain(0, 16)
+		path                 = filepath.Join(dir, "accumulator.era1")
+		root                 = writeTestEra(t, path, blocks, receipts, td)
+	)
+	// A modified accumulator no longer matches the blocks
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := root
+	forged[0] ^= 0xff
+	if err := os.WriteFile(path, bytes.Replace(data, root[:], forged[:], 1), 0644); err != nil {
+		t.Fatal(err)
+	}
+	verifyFails(t, path)
+
+	// Receipts not matching their header are rejected too
+	receipts[5] = receipts[6]
+	path = filepath.Join(dir, "receipts.era1")
+	writeTestEra(t, path, blocks, receipts, td)
+	verifyFails(t, path)
+}
+
+func verifyFails(t *testing.T, path string) {
+	t.Helper()
+
+	e, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	if _, err := e.Verify(); err == nil {
+		t.Fatalf("tampered era %s verified", filepath.Base(path))
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		Filename("mainnet", 1, common.Hash{1}),
+		Filename("mainnet", 0, common.Hash{2}),
+		Filename("goerli", 0, common.Hash{3}),
+		"checksums.txt",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := ReadDir(dir, "mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0] != "mainnet-00000-02000000.era1" || files[1] != "mainnet-00001-01000000.era1" {
+		t.Fatalf("wrong era files: %v", files)
+	}
+	if err := os.WriteFile(filepath.Join(dir, Filename("mainnet", 3, common.Hash{})), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadDir(dir, "mainnet"); err == nil {
+		t.Fatal("missing epoch not detected")
+	}
+}
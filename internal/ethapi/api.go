@@ -663,7 +663,11 @@ type StorageResult struct {
 
 // GetProof returns the Merkle-proof for a given account and optionally some storage keys.
 func (s *BlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []string, blockNrOrHash rpc.BlockNumberOrHash) (*AccountResult, error) {
-	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	state, header, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil && header != nil {
+		// The state of the block is gone, try rebuilding it from the state history
+		state, err = s.b.HistoricStateAt(ctx, header)
+	}
 	if state == nil || err != nil {
 		return nil, err
 	}
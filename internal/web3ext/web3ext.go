@@ -152,6 +152,21 @@ web3._extend({
 			call: 'admin_importChain',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'backup',
+			call: 'admin_backup',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'startScrub',
+			call: 'admin_startScrub',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'stopScrub',
+			call: 'admin_stopScrub'
+		}),
 		new web3._extend.Method({
 			name: 'sleepBlocks',
 			call: 'admin_sleepBlocks',
@@ -203,6 +218,10 @@ web3._extend({
 			name: 'datadir',
 			getter: 'admin_datadir'
 		}),
+		new web3._extend.Property({
+			name: 'scrubStatus',
+			getter: 'admin_scrubStatus'
+		}),
 	]
 });
 `
@@ -447,12 +466,45 @@ web3._extend({
 			call: 'debug_storageRangeAt',
 			params: 5,
 		}),
+		new web3._extend.Method({
+			name: 'registerStorageLayout',
+			call: 'debug_registerStorageLayout',
+			params: 2,
+		}),
+		new web3._extend.Method({
+			name: 'unregisterStorageLayout',
+			call: 'debug_unregisterStorageLayout',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'storageLayout',
+			call: 'debug_storageLayout',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'decodeStorageAt',
+			call: 'debug_decodeStorageAt',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null, null],
+		}),
 		new web3._extend.Method({
 			name: 'getModifiedAccountsByNumber',
 			call: 'debug_getModifiedAccountsByNumber',
 			params: 2,
 			inputFormatter: [null, null],
 		}),
+		new web3._extend.Method({
+			name: 'stateDiff',
+			call: 'debug_stateDiff',
+			params: 1,
+			inputFormatter: [null],
+		}),
+		new web3._extend.Method({
+			name: 'executionWitness',
+			call: 'debug_executionWitness',
+			params: 1,
+			inputFormatter: [null],
+		}),
 		new web3._extend.Method({
 			name: 'getModifiedAccountsByHash',
 			call: 'debug_getModifiedAccountsByHash',
@@ -664,6 +716,15 @@ web3._extend({
 			name: 'getHashrate',
 			call: 'miner_getHashrate'
 		}),
+		new web3._extend.Method({
+			name: 'getRewards',
+			call: 'miner_getRewards'
+		}),
+		new web3._extend.Method({
+			name: 'getWorkRewards',
+			call: 'miner_getWorkRewards',
+			params: 1
+		}),
 	],
 	properties: []
 });
@@ -161,6 +161,10 @@ func (b *LesApiBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 	return nil, nil, errors.New("invalid arguments; neither block nor hash specified")
 }
 
+func (b *LesApiBackend) HistoricStateAt(ctx context.Context, header *types.Header) (*state.StateDB, error) {
+	return light.NewState(ctx, header, b.eth.odr), nil
+}
+
 func (b *LesApiBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
 	if number := rawdb.ReadHeaderNumber(b.eth.chainDb, hash); number != nil {
 		return light.GetBlockReceipts(ctx, b.eth.odr, hash, *number)
@@ -168,6 +172,10 @@ func (b *LesApiBackend) GetReceipts(ctx context.Context, hash common.Hash) (type
 	return nil, nil
 }
 
+func (b *LesApiBackend) GetStateDiff(ctx context.Context, hash common.Hash) (*types.StateDiff, error) {
+	return nil, errors.New("state diffs are not available in light mode")
+}
+
 func (b *LesApiBackend) GetLogs(ctx context.Context, hash common.Hash, number uint64) ([][]*types.Log, error) {
 	return light.GetBlockLogs(ctx, b.eth.odr, hash, number)
 }
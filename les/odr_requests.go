@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
 	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
 	"github.com/Altcoinchain/go-altcoinchain/core/types"
 	"github.com/Altcoinchain/go-altcoinchain/crypto"
@@ -42,6 +43,7 @@ var (
 	errDataHashMismatch    = errors.New("data hash mismatch")
 	errCHTHashMismatch     = errors.New("cht hash mismatch")
 	errCHTNumberMismatch   = errors.New("cht number mismatch")
+	errRewardsUnavailable  = errors.New("reward proof unavailable")
 	errUselessNodes        = errors.New("useless nodes in merkle proof nodeset")
 )
 
@@ -68,6 +70,8 @@ func LesRequest(req light.OdrRequest) LesOdrRequest {
 		return (*BloomRequest)(r)
 	case *light.TxStatusRequest:
 		return (*TxStatusRequest)(r)
+	case *light.RewardsRequest:
+		return (*RewardsRequest)(r)
 	default:
 		return nil
 	}
@@ -294,6 +298,7 @@ const (
 	// helper trie type constants
 	htCanonical = iota // Canonical hash trie
 	htBloomBits        // BloomBits trie
+	htRewards          // Block reward trie
 
 	// helper trie auxiliary types
 	// htAuxNone = 1 ; deprecated number, used in les2/3 previously.
@@ -534,3 +539,57 @@ func (db *readTraceDB) Has(key []byte) (bool, error) {
 	_, err := db.Get(key)
 	return err == nil, nil
 }
+
+// ODR request type for requesting the rewards of an address credited by a block,
+// see LesOdrRequest interface
+type RewardsRequest light.RewardsRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *RewardsRequest) GetCost(peer *serverPeer) uint64 {
+	return peer.getRequestCost(GetHelperTrieProofsMsg, 1)
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *RewardsRequest) CanSend(peer *serverPeer) bool {
+	return peer.serveRewards && peer.HasBlock(r.Hash, r.Number, false)
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *RewardsRequest) Request(reqID uint64, peer *serverPeer) error {
+	peer.Log().Debug("Requesting reward proof", "number", r.Number, "address", r.Address)
+	req := HelperTrieReq{
+		Type:    htRewards,
+		TrieIdx: r.Number,
+		Key:     r.Address.Bytes(),
+	}
+	return peer.requestHelperTrieProofs(reqID, []HelperTrieReq{req})
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *RewardsRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating reward proof", "number", r.Number, "address", r.Address)
+
+	if msg.MsgType != MsgHelperTrieProofs {
+		return errInvalidMessageType
+	}
+	resp := msg.Obj.(HelperTrieResps)
+	nodeSet := resp.Proofs.NodeSet()
+	if nodeSet.KeyCount() == 0 {
+		return errRewardsUnavailable
+	}
+	// Verify the proof against the commitment of the header and store if checks out
+	reads := &readTraceDB{db: nodeSet}
+	rewards, err := ethash.VerifyRewardsProof(r.RewardsRoot, r.Address, reads)
+	if err != nil {
+		return fmt.Errorf("merkle proof verification failed: %v", err)
+	}
+	if len(reads.reads) != nodeSet.KeyCount() {
+		return errUselessNodes
+	}
+	r.Rewards = rewards
+	r.Proof = nodeSet
+	return nil
+}
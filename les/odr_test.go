@@ -190,6 +190,37 @@ func odrTxStatus(ctx context.Context, db ethdb.Database, config *params.ChainCon
 	return rlp
 }
 
+// Tests that reward proofs are only requested from servers advertising them,
+// which they don't unless their chain commits to the block rewards.
+func TestOdrRewardsCapabilityLes4(t *testing.T) {
+	server, client, tearDown := newClientServerEnv(t, testnetConfig{
+		blocks:    4,
+		protocol:  lpv4,
+		connect:   true,
+		nopruning: true,
+	})
+	defer tearDown()
+
+	peer := client.peer.speer
+	if peer.serveRewards {
+		t.Fatal("server without reward commitments advertised reward proofs")
+	}
+	head := server.handler.blockchain.CurrentHeader()
+	req := &RewardsRequest{Hash: head.Hash(), Number: head.Number.Uint64()}
+
+	client.handler.backend.peers.lock.Lock()
+	peer.hasBlockHook = func(common.Hash, uint64, bool) bool { return true }
+	client.handler.backend.peers.lock.Unlock()
+
+	if req.CanSend(peer) {
+		t.Fatal("reward proofs requestable from server not serving them")
+	}
+	peer.serveRewards = true
+	if !req.CanSend(peer) {
+		t.Fatal("reward proofs not requestable from server serving them")
+	}
+}
+
 // testOdr tests odr requests whose validation guaranteed by block headers.
 func testOdr(t *testing.T, protocol int, expFail uint64, checkCached bool, fn odrTestFn) {
 	// Assemble the test environment
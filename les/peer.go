@@ -344,6 +344,7 @@ type serverPeer struct {
 	chainSince, chainRecent uint64 // The range of chain server peer can serve.
 	stateSince, stateRecent uint64 // The range of state server peer can serve.
 	txHistory               uint64 // The length of available tx history, 0 means all, 1 means disabled
+	serveRewards            bool   // The flag whether the server can serve reward proofs.
 
 	// Advertised checkpoint fields
 	checkpointNumber uint64                   // The block height which the checkpoint is registered.
@@ -630,6 +631,7 @@ func (p *serverPeer) Handshake(genesis common.Hash, forkid forkid.ID, forkFilter
 		if recv.get("txRelay", nil) != nil {
 			p.onlyAnnounce = true
 		}
+		p.serveRewards = recv.get("serveRewards", nil) == nil
 		if p.version >= lpv4 {
 			var recentTx uint
 			if err := recv.get("recentTxLookup", &recentTx); err != nil {
@@ -1030,6 +1032,11 @@ func (p *clientPeer) Handshake(td *big.Int, head common.Hash, headNum uint64, ge
 			}
 			*lists = (*lists).add("serveRecentState", stateRecent)
 			*lists = (*lists).add("txRelay", nil)
+
+			// Reward proofs can only be served if the blocks commit to them
+			if server.handler.blockchain.Config().RewardCommitBlock != nil {
+				*lists = (*lists).add("serveRewards", nil)
+			}
 		}
 		if p.version >= lpv4 {
 			*lists = (*lists).add("recentTxLookup", recentTx)
@@ -24,6 +24,7 @@ import (
 
 	"github.com/Altcoinchain/go-altcoinchain/common"
 	"github.com/Altcoinchain/go-altcoinchain/common/mclock"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
 	"github.com/Altcoinchain/go-altcoinchain/core"
 	"github.com/Altcoinchain/go-altcoinchain/core/forkid"
 	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
@@ -387,6 +388,8 @@ func (h *serverHandler) GetHelperTrie(typ uint, index uint64) *trie.Trie {
 	case htBloomBits:
 		sectionHead := rawdb.ReadCanonicalHash(h.chainDb, (index+1)*h.server.iConfig.BloomTrieSize-1)
 		root, prefix = light.GetBloomTrieRoot(h.chainDb, index, sectionHead), light.BloomTrieTablePrefix
+	case htRewards:
+		return h.getRewardTrie(index)
 	}
 	if root == (common.Hash{}) {
 		return nil
@@ -395,6 +398,21 @@ func (h *serverHandler) GetHelperTrie(typ uint, index uint64) *trie.Trie {
 	return trie
 }
 
+// getRewardTrie rebuilds the reward trie committed to by the canonical block with
+// the given number, or returns nil if the block carries no reward commitment.
+func (h *serverHandler) getRewardTrie(number uint64) *trie.Trie {
+	block := h.blockchain.GetBlockByNumber(number)
+	if block == nil || !h.blockchain.Config().IsRewardCommit(block.Number()) {
+		return nil
+	}
+	trie, err := ethash.RewardTrie(ethash.BlockRewardList(h.blockchain.Config(), block.Header(), block.Uncles()))
+	if err != nil {
+		log.Error("Failed to build reward trie", "number", number, "err", err)
+		return nil
+	}
+	return trie
+}
+
 // broadcastLoop broadcasts new block information to all connected light
 // clients. According to the agreement between client and server, server should
 // only broadcast new announcement if the total difficulty is higher than the
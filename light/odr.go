@@ -22,6 +22,7 @@ import (
 	"math/big"
 
 	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
 	"github.com/Altcoinchain/go-altcoinchain/core"
 	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
 	"github.com/Altcoinchain/go-altcoinchain/core/types"
@@ -191,3 +192,17 @@ type TxStatusRequest struct {
 
 // StoreResult stores the retrieved data in local database
 func (req *TxStatusRequest) StoreResult(db ethdb.Database) {}
+
+// RewardsRequest is the ODR request type for retrieving the rewards credited to
+// an address by a block, proven against the reward commitment of its header.
+type RewardsRequest struct {
+	Hash        common.Hash
+	Number      uint64
+	RewardsRoot common.Hash
+	Address     common.Address
+	Rewards     []ethash.RewardEntry
+	Proof       *NodeSet
+}
+
+// StoreResult stores the retrieved data in local database
+func (req *RewardsRequest) StoreResult(db ethdb.Database) {}
@@ -88,6 +88,19 @@ func (odr *testOdr) Retrieve(ctx context.Context, req OdrRequest) error {
 		req.Proof = nodes
 	case *CodeRequest:
 		req.Data = rawdb.ReadCode(odr.sdb, req.Hash)
+	case *RewardsRequest:
+		config := rawdb.ReadChainConfig(odr.sdb, rawdb.ReadCanonicalHash(odr.sdb, 0))
+		block := rawdb.ReadBlock(odr.sdb, req.Hash, req.Number)
+		t, err := ethash.RewardTrie(ethash.BlockRewardList(config, block.Header(), block.Uncles()))
+		if err != nil {
+			return err
+		}
+		nodes := NewNodeSet()
+		t.Prove(req.Address.Bytes(), 0, nodes)
+		if req.Rewards, err = ethash.VerifyRewardsProof(req.RewardsRoot, req.Address, nodes); err != nil {
+			return err
+		}
+		req.Proof = nodes
 	}
 	req.StoreResult(odr.ldb)
 	return nil
@@ -208,6 +221,76 @@ func odrContractCall(ctx context.Context, db ethdb.Database, bc *core.BlockChain
 	return res, nil
 }
 
+// Tests that the rewards credited by a block are retrieved and proven against
+// the reward commitment of its header.
+func TestOdrGetBlockRewards(t *testing.T) {
+	var (
+		sdb    = rawdb.NewMemoryDatabase()
+		ldb    = rawdb.NewMemoryDatabase()
+		config = *params.TestChainConfig
+	)
+	config.RewardCommitBlock = big.NewInt(2)
+	gspec := core.Genesis{
+		Config:  &config,
+		Alloc:   core.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
+		BaseFee: big.NewInt(params.InitialBaseFee),
+	}
+	genesis := gspec.MustCommit(sdb)
+	gspec.MustCommit(ldb)
+
+	blockchain, _ := core.NewBlockChain(sdb, nil, &config, ethash.NewFullFaker(), vm.Config{}, nil, nil)
+	gchain, _ := core.GenerateChain(&config, genesis, ethash.NewFaker(), sdb, 4, testChainGen)
+	if _, err := blockchain.InsertChain(gchain); err != nil {
+		t.Fatal(err)
+	}
+	odr := &testOdr{sdb: sdb, ldb: ldb, indexerConfig: TestClientIndexerConfig}
+	lightchain, err := NewLightChain(odr, &config, ethash.NewFullFaker(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := make([]*types.Header, len(gchain))
+	for i, block := range gchain {
+		headers[i] = block.Header()
+	}
+	if _, err := lightchain.InsertHeaderChain(headers, 1); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Blocks before the fork don't commit to their rewards
+	if _, err := GetBlockRewards(ctx, odr, gchain[0].Hash(), 1, gchain[0].Coinbase()); err != errNoRewardCommit {
+		t.Fatalf("pre-fork rewards error mismatch: have %v, want %v", err, errNoRewardCommit)
+	}
+	for _, block := range gchain[1:] {
+		want := make(map[common.Address][]ethash.RewardEntry)
+		for _, reward := range ethash.BlockRewardList(&config, block.Header(), block.Uncles()) {
+			want[reward.Address] = append(want[reward.Address], ethash.RewardEntry{Kind: reward.Kind, Amount: reward.Amount})
+		}
+		for addr, entries := range want {
+			have, err := GetBlockRewards(ctx, odr, block.Hash(), block.NumberU64(), addr)
+			if err != nil {
+				t.Fatalf("block %d: failed to retrieve rewards of %x: %v", block.NumberU64(), addr, err)
+			}
+			if len(have) != len(entries) {
+				t.Fatalf("block %d: reward count mismatch of %x: have %d, want %d", block.NumberU64(), addr, len(have), len(entries))
+			}
+			for i := range have {
+				if have[i].Kind != entries[i].Kind || have[i].Amount.Cmp(entries[i].Amount) != 0 {
+					t.Errorf("block %d: reward %d mismatch of %x: have %+v, want %+v", block.NumberU64(), i, addr, have[i], entries[i])
+				}
+			}
+		}
+		// Addresses not rewarded by the block are proven absent
+		if have, err := GetBlockRewards(ctx, odr, block.Hash(), block.NumberU64(), testBankAddress); err != nil || len(have) != 0 {
+			t.Fatalf("block %d: unrewarded address mismatch: have %v, %v", block.NumberU64(), have, err)
+		}
+	}
+	// Non-canonical hashes are rejected before requesting anything
+	if _, err := GetBlockRewards(ctx, odr, common.Hash{0x01}, 2, testBankAddress); err != errNonCanonicalHash {
+		t.Fatalf("non-canonical rewards error mismatch: have %v, want %v", err, errNonCanonicalHash)
+	}
+}
+
 func testChainGen(i int, block *core.BlockGen) {
 	signer := types.HomesteadSigner{}
 	switch i {
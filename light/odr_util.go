@@ -23,6 +23,7 @@ import (
 	"math/big"
 
 	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
 	"github.com/Altcoinchain/go-altcoinchain/core"
 	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
 	"github.com/Altcoinchain/go-altcoinchain/core/types"
@@ -34,6 +35,10 @@ import (
 // by the CHT or Bloom trie for verification.
 var errNonCanonicalHash = errors.New("hash is not currently canonical")
 
+// errNoRewardCommit is returned if rewards are requested for a block whose header
+// does not commit to them.
+var errNoRewardCommit = errors.New("block carries no reward commitment")
+
 // GetHeaderByNumber retrieves the canonical block header corresponding to the
 // given number. The returned header is proven by local CHT.
 func GetHeaderByNumber(ctx context.Context, odr OdrBackend, number uint64) (*types.Header, error) {
@@ -269,6 +274,31 @@ func GetBloomBits(ctx context.Context, odr OdrBackend, bit uint, sections []uint
 	return result, nil
 }
 
+// GetBlockRewards retrieves the rewards credited to an address by the canonical
+// block given by its hash, proven against the reward commitment of the header.
+// An address not rewarded by the block yields no rewards.
+func GetBlockRewards(ctx context.Context, odr OdrBackend, hash common.Hash, number uint64, addr common.Address) ([]ethash.RewardEntry, error) {
+	header, err := GetHeaderByNumber(ctx, odr, number)
+	if err != nil {
+		return nil, errNoHeader
+	}
+	if header.Hash() != hash {
+		return nil, errNonCanonicalHash
+	}
+	genesis := rawdb.ReadCanonicalHash(odr.Database(), 0)
+	config := rawdb.ReadChainConfig(odr.Database(), genesis)
+
+	root, ok := ethash.HeaderRewardsRoot(config, header)
+	if !ok {
+		return nil, errNoRewardCommit
+	}
+	r := &RewardsRequest{Hash: hash, Number: number, RewardsRoot: root, Address: addr}
+	if err := odr.Retrieve(ctx, r); err != nil {
+		return nil, err
+	}
+	return r.Rewards, nil
+}
+
 // GetTransaction retrieves a canonical transaction by hash and also returns
 // its position in the chain. There is no guarantee in the LES protocol that
 // the mined transaction will be retrieved back for sure because of different
@@ -0,0 +1,268 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package dagshare implements background ethash DAG pre-generation and sharing
+// of the generated files between the nodes of a mining farm.
+package dagshare
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/consensus"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/beacon"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
+	"github.com/Altcoinchain/go-altcoinchain/core"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/event"
+	"github.com/Altcoinchain/go-altcoinchain/log"
+	"github.com/Altcoinchain/go-altcoinchain/node"
+)
+
+const (
+	// chainEventChanSize is the size of channel listening to ChainEvent.
+	chainEventChanSize = 10
+
+	// fetchInterval is the minimum time between two attempts to fetch missing
+	// DAG files from the farm peers.
+	fetchInterval = time.Minute
+)
+
+var (
+	// errNoEthash is returned if the service is started on top of a non-ethash engine.
+	errNoEthash = errors.New("DAG sharing requires the ethash engine")
+
+	// errNoSecret is returned if the service is configured without a shared secret.
+	errNoSecret = errors.New("DAG sharing requires a shared secret")
+
+	// errNoDatasetDir is returned if the engine does not keep its DAGs on disk.
+	errNoDatasetDir = errors.New("DAG sharing requires an ethash DAG directory")
+)
+
+// Config contains the configuration options of the DAG sharing service.
+type Config struct {
+	Enabled          bool     // Whether to pre-generate and share DAGs in the background
+	ListenAddr       string   // HTTP address to serve the generated DAG files on, empty to not serve
+	SecretFile       string   // File holding the shared secret authenticating farm nodes
+	Peers            []string // URLs of farm nodes to fetch DAG files from before generating them
+	GenerateDistance uint64   // Blocks before the epoch boundary to fall back to local generation
+}
+
+// DefaultConfig contains the default settings for the DAG sharing service.
+var DefaultConfig = Config{
+	GenerateDistance: 1000,
+}
+
+// Backend encompasses the chain functionality needed by the service.
+type Backend interface {
+	Engine() consensus.Engine
+	CurrentHeader() *types.Header
+	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
+}
+
+// Service pre-generates the ethash verification caches and mining datasets of
+// upcoming epochs and shares them with the other nodes of the farm.
+type Service struct {
+	config  Config
+	backend Backend
+	engine  *ethash.Ethash
+	secret  string
+	hashes  *hashCache
+	client  *http.Client
+
+	server   *http.Server
+	chainSub event.Subscription
+	ctx      context.Context    // Context of the running downloads, cancelled on stop
+	cancel   context.CancelFunc // Cancels all running downloads
+	quit     chan struct{}
+	wg       sync.WaitGroup
+
+	pregenerated uint64    // Last epoch (plus one) whose successor was generated locally
+	fetched      time.Time // Last time missing files were fetched from the peers
+}
+
+// New creates the DAG sharing service and registers it with the node.
+func New(stack *node.Node, backend Backend, config Config) (*Service, error) {
+	engine := backend.Engine()
+	if b, ok := engine.(*beacon.Beacon); ok {
+		engine = b.InnerEngine()
+	}
+	pow, ok := engine.(*ethash.Ethash)
+	if !ok {
+		return nil, errNoEthash
+	}
+	if pow.DAGPath(ethash.DAGDataset, 0) == "" {
+		return nil, errNoDatasetDir
+	}
+	var secret string
+	if config.SecretFile != "" {
+		blob, err := os.ReadFile(config.SecretFile)
+		if err != nil {
+			return nil, err
+		}
+		secret = strings.TrimSpace(string(blob))
+	}
+	if secret == "" && (config.ListenAddr != "" || len(config.Peers) > 0) {
+		return nil, errNoSecret
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	service := &Service{
+		config:  config,
+		backend: backend,
+		engine:  pow,
+		secret:  secret,
+		hashes:  newHashCache(),
+		client:  &http.Client{},
+		ctx:     ctx,
+		cancel:  cancel,
+		quit:    make(chan struct{}),
+	}
+	stack.RegisterLifecycle(service)
+	return service, nil
+}
+
+// Start implements node.Lifecycle, starting the DAG file server if configured
+// and tracking the chain head to prepare upcoming epochs.
+func (s *Service) Start() error {
+	if s.config.ListenAddr != "" {
+		listener, err := net.Listen("tcp", s.config.ListenAddr)
+		if err != nil {
+			return err
+		}
+		s.server = &http.Server{Handler: newHandler(s.engine, s.backend, s.hashes, s.secret)}
+		go s.server.Serve(listener)
+		log.Info("DAG sharing endpoint opened", "url", "http://"+listener.Addr().String())
+	}
+	events := make(chan core.ChainEvent, chainEventChanSize)
+	s.chainSub = s.backend.SubscribeChainEvent(events)
+
+	s.wg.Add(1)
+	go s.loop(events)
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating the service.
+func (s *Service) Stop() error {
+	s.chainSub.Unsubscribe()
+	close(s.quit)
+	s.cancel()
+	s.wg.Wait()
+
+	if s.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.server.Shutdown(ctx)
+	}
+	log.Info("DAG sharing stopped")
+	return nil
+}
+
+// loop prepares the DAG files of the current and next epochs on every new head.
+// Preparation may take a long time when downloading from the farm peers, so it
+// runs on a separate goroutine and heads arriving meanwhile are coalesced into
+// the latest one, keeping chain event delivery unblocked.
+func (s *Service) loop(events chan core.ChainEvent) {
+	defer s.wg.Done()
+
+	var (
+		head    uint64
+		pending bool          // Whether a head is waiting to be prepared
+		done    chan struct{} // Non-nil while a preparation is running
+	)
+	if header := s.backend.CurrentHeader(); header != nil {
+		head, pending = header.Number.Uint64(), true
+	}
+	for {
+		if pending && done == nil {
+			done = make(chan struct{})
+			go func(head uint64, done chan struct{}) {
+				defer close(done)
+				s.prepare(head)
+			}(head, done)
+			pending = false
+		}
+		select {
+		case ev := <-events:
+			head, pending = ev.Block.NumberU64(), true
+
+		case <-done:
+			done = nil
+
+		case <-s.chainSub.Err():
+			if done != nil {
+				<-done
+			}
+			return
+		case <-s.quit:
+			if done != nil {
+				<-done
+			}
+			return
+		}
+	}
+}
+
+// prepare fetches the missing DAG files of the current and next epochs from the
+// farm peers and falls back to generating them locally once the head gets close
+// to the epoch boundary. Without peers, the next epoch is generated right away.
+func (s *Service) prepare(head uint64) {
+	epoch := head / ethash.EpochLength
+	if len(s.config.Peers) > 0 && time.Since(s.fetched) > fetchInterval {
+		s.fetched = time.Now()
+		for _, e := range []uint64{epoch, epoch + 1} {
+			s.fetchEpoch(e)
+		}
+	}
+	if s.pregenerated == epoch+1 {
+		return
+	}
+	boundary := (epoch + 1) * ethash.EpochLength
+	if len(s.config.Peers) == 0 || boundary-head <= s.config.GenerateDistance {
+		log.Info("Pre-generating ethash DAG", "epoch", epoch+1)
+		s.engine.Pregenerate(head)
+		s.pregenerated = epoch + 1
+	}
+}
+
+// fetchEpoch downloads the DAG files of an epoch which are missing locally from
+// the first farm peer serving them.
+func (s *Service) fetchEpoch(epoch uint64) {
+	for _, kind := range []string{ethash.DAGCache, ethash.DAGDataset} {
+		path := s.engine.DAGPath(kind, epoch)
+		if path == "" || fileExists(path) {
+			continue
+		}
+		for _, peer := range s.config.Peers {
+			if s.ctx.Err() != nil {
+				return
+			}
+			ctx, cancel := context.WithTimeout(s.ctx, 30*time.Minute)
+			err := fetchFile(ctx, s.client, peer, s.secret, kind, epoch, path)
+			cancel()
+			if err == nil {
+				log.Info("Fetched ethash DAG file", "kind", kind, "epoch", epoch, "peer", peer)
+				break
+			}
+			log.Debug("Failed to fetch ethash DAG file", "kind", kind, "epoch", epoch, "peer", peer, "err", err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dagshare
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/consensus"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
+	"github.com/Altcoinchain/go-altcoinchain/core"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/event"
+)
+
+type testBackend struct {
+	engine *ethash.Ethash
+}
+
+func (b *testBackend) Engine() consensus.Engine { return b.engine }
+func (b *testBackend) CurrentHeader() *types.Header {
+	return &types.Header{Number: big.NewInt(0)}
+}
+func (b *testBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error { <-quit; return nil })
+}
+
+// newTestEngine creates a test sized ethash engine storing its DAGs on disk.
+func newTestEngine(t *testing.T) *ethash.Ethash {
+	engine := ethash.New(ethash.Config{
+		CacheDir:       t.TempDir(),
+		CachesOnDisk:   2,
+		DatasetDir:     t.TempDir(),
+		DatasetsOnDisk: 2,
+		PowMode:        ethash.ModeTest,
+	}, nil, false)
+	t.Cleanup(func() { engine.Close() })
+	return engine
+}
+
+// Tests that pre-generated DAG files are served to authenticated peers only and
+// can be fetched and verified by them.
+func TestDAGSharing(t *testing.T) {
+	source := newTestEngine(t)
+	source.Pregenerate(0)
+
+	for deadline := time.Now().Add(time.Minute); len(source.DAGFiles(1)) < 2; {
+		if time.Now().After(deadline) {
+			t.Fatalf("next epoch not pre-generated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	server := httptest.NewServer(newHandler(source, &testBackend{engine: source}, newHashCache(), "secret"))
+	defer server.Close()
+
+	// Unauthenticated requests must be rejected
+	res, err := http.Get(server.URL + manifestPath)
+	if err != nil {
+		t.Fatalf("failed to request manifest: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("unauthenticated status mismatch: have %d, want %d", res.StatusCode, http.StatusUnauthorized)
+	}
+	entries, err := fetchManifest(context.Background(), http.DefaultClient, server.URL, "secret")
+	if err != nil {
+		t.Fatalf("failed to fetch manifest: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("manifest length mismatch: have %d, want 4", len(entries))
+	}
+	// Fetch the next epoch into a fresh engine and ensure the files are identical
+	sink := newTestEngine(t)
+	for _, kind := range []string{ethash.DAGCache, ethash.DAGDataset} {
+		path := sink.DAGPath(kind, 1)
+		if err := fetchFile(context.Background(), http.DefaultClient, server.URL, "wrong", kind, 1, path); err == nil {
+			t.Errorf("%s: fetched with wrong secret", kind)
+		}
+		if err := fetchFile(context.Background(), http.DefaultClient, server.URL, "secret", kind, 1, path); err != nil {
+			t.Fatalf("%s: failed to fetch file: %v", kind, err)
+		}
+		have, _ := os.ReadFile(path)
+		want, _ := os.ReadFile(source.DAGPath(kind, 1))
+		if !bytes.Equal(have, want) {
+			t.Errorf("%s: fetched file mismatch", kind)
+		}
+	}
+	if err := fetchFile(context.Background(), http.DefaultClient, server.URL, "secret", ethash.DAGDataset, 5, sink.DAGPath(ethash.DAGDataset, 5)); err != errNotServed {
+		t.Errorf("unknown epoch error mismatch: have %v, want %v", err, errNotServed)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dagshare
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+)
+
+var (
+	// errNotServed is returned if a peer does not offer the requested DAG file.
+	errNotServed = errors.New("DAG file not served by peer")
+
+	// errHashMismatch is returned if a downloaded DAG file fails the integrity check.
+	errHashMismatch = errors.New("DAG file hash mismatch")
+)
+
+// get issues an authenticated GET request against a farm peer.
+func get(ctx context.Context, client *http.Client, url, secret string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+secret)
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("unexpected response: %s", res.Status)
+	}
+	return res, nil
+}
+
+// fetchManifest retrieves the list of DAG files offered by a farm peer.
+func fetchManifest(ctx context.Context, client *http.Client, peer, secret string) ([]manifestEntry, error) {
+	res, err := get(ctx, client, strings.TrimSuffix(peer, "/")+manifestPath, secret)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var entries []manifestEntry
+	if err := json.NewDecoder(res.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// fetchFile downloads a DAG file from a farm peer into the given path, verifying
+// it against the integrity hash advertised in the peer's manifest. The file is
+// written to a temporary location first and only moved into place once fully
+// verified, so the engine never loads a partial download.
+func fetchFile(ctx context.Context, client *http.Client, peer, secret string, kind string, epoch uint64, path string) error {
+	entries, err := fetchManifest(ctx, client, peer, secret)
+	if err != nil {
+		return err
+	}
+	var entry *manifestEntry
+	for i := range entries {
+		// Matching the name also ensures the revision and endianness are compatible
+		if entries[i].Kind == kind && entries[i].Epoch == epoch && entries[i].Name == filepath.Base(path) {
+			entry = &entries[i]
+			break
+		}
+	}
+	if entry == nil {
+		return errNotServed
+	}
+	res, err := get(ctx, client, strings.TrimSuffix(peer, "/")+filesPath+entry.Name, secret)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	temp := path + "." + strconv.Itoa(rand.Int())
+	dump, err := os.Create(temp)
+	if err != nil {
+		return err
+	}
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(dump, hasher), res.Body)
+	if closeErr := dump.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size != entry.Size {
+		err = fmt.Errorf("DAG file size mismatch: have %d, want %d", size, entry.Size)
+	}
+	if err == nil && common.BytesToHash(hasher.Sum(nil)) != entry.Hash {
+		err = errHashMismatch
+	}
+	if err != nil {
+		os.Remove(temp)
+		return err
+	}
+	return os.Rename(temp, path)
+}
+
+// fileExists reports whether a regular file exists at the given path.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package dagshare
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
+	"github.com/Altcoinchain/go-altcoinchain/log"
+)
+
+const (
+	// manifestPath is the endpoint listing the DAG files served by a node.
+	manifestPath = "/manifest"
+
+	// filesPath is the endpoint prefix serving the content of the DAG files.
+	filesPath = "/files/"
+
+	// hashHeader is the response header carrying the SHA-256 of a served file.
+	hashHeader = "X-Dag-Sha256"
+)
+
+// manifestEntry describes a single DAG file served by a node.
+type manifestEntry struct {
+	Kind  string      `json:"kind"`
+	Epoch uint64      `json:"epoch"`
+	Name  string      `json:"name"`
+	Size  int64       `json:"size"`
+	Hash  common.Hash `json:"sha256"`
+}
+
+// hashCache caches the SHA-256 of DAG files, as hashing a full dataset takes a
+// while. Entries are invalidated when the size or modification time changes.
+type hashCache struct {
+	hashes map[string]cachedHash
+	lock   sync.Mutex
+}
+
+// cachedHash is the hash of a file along with the metadata it was computed for.
+type cachedHash struct {
+	size    int64
+	modTime time.Time
+	hash    common.Hash
+}
+
+func newHashCache() *hashCache {
+	return &hashCache{hashes: make(map[string]cachedHash)}
+}
+
+// hash returns the SHA-256 of the given DAG file.
+func (c *hashCache) hash(file ethash.DAGFile) (common.Hash, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if cached, ok := c.hashes[file.Path]; ok && cached.size == file.Size && cached.modTime.Equal(file.ModTime) {
+		return cached.hash, nil
+	}
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return common.Hash{}, err
+	}
+	hash := common.BytesToHash(hasher.Sum(nil))
+	c.hashes[file.Path] = cachedHash{size: file.Size, modTime: file.ModTime, hash: hash}
+	return hash, nil
+}
+
+// handler serves the DAG files of the current and next epochs to authenticated
+// farm nodes.
+type handler struct {
+	engine  *ethash.Ethash
+	backend Backend
+	hashes  *hashCache
+	secret  []byte
+}
+
+func newHandler(engine *ethash.Ethash, backend Backend, hashes *hashCache, secret string) *handler {
+	return &handler{
+		engine:  engine,
+		backend: backend,
+		hashes:  hashes,
+		secret:  []byte(secret),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), h.secret) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.URL.Path == manifestPath:
+		h.serveManifest(w)
+	case strings.HasPrefix(r.URL.Path, filesPath):
+		h.serveFile(w, r, strings.TrimPrefix(r.URL.Path, filesPath))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// files returns the DAG files currently on offer.
+func (h *handler) files() []ethash.DAGFile {
+	var epoch uint64
+	if head := h.backend.CurrentHeader(); head != nil {
+		epoch = head.Number.Uint64() / ethash.EpochLength
+	}
+	return append(h.engine.DAGFiles(epoch), h.engine.DAGFiles(epoch+1)...)
+}
+
+// serveManifest lists the DAG files on offer along with their integrity hashes.
+func (h *handler) serveManifest(w http.ResponseWriter) {
+	entries := []manifestEntry{}
+	for _, file := range h.files() {
+		hash, err := h.hashes.hash(file)
+		if err != nil {
+			log.Warn("Failed to hash ethash DAG file", "path", file.Path, "err", err)
+			continue
+		}
+		entries = append(entries, manifestEntry{
+			Kind:  file.Kind,
+			Epoch: file.Epoch,
+			Name:  file.Name,
+			Size:  file.Size,
+			Hash:  hash,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// serveFile streams the content of a DAG file on offer. Only files listed in the
+// manifest can be requested, so the name is never used as a path directly.
+func (h *handler) serveFile(w http.ResponseWriter, r *http.Request, name string) {
+	for _, file := range h.files() {
+		if file.Name != name {
+			continue
+		}
+		hash, err := h.hashes.hash(file)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		f, err := os.Open(file.Path)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+
+		w.Header().Set(hashHeader, hash.Hex())
+		http.ServeContent(w, r, file.Name, file.ModTime, f)
+		return
+	}
+	http.NotFound(w, r)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/Altcoinchain/go-altcoinchain/common"
 	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
 	"github.com/Altcoinchain/go-altcoinchain/consensus"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
 	"github.com/Altcoinchain/go-altcoinchain/core"
 	"github.com/Altcoinchain/go-altcoinchain/core/state"
 	"github.com/Altcoinchain/go-altcoinchain/core/types"
@@ -53,6 +54,8 @@ type Config struct {
 	GasPrice   *big.Int       // Minimum gas price for mining a transaction
 	Recommit   time.Duration  // The time interval for miner to re-create mining work.
 	Noverify   bool           // Disable remote mining solution verification(only useful in ethash).
+
+	Beneficiaries []ethash.RewardSplit `toml:",omitempty"` // Weighted recipients of the coinbase reward after the reward split fork
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -206,6 +209,18 @@ func (miner *Miner) PendingBlockAndReceipts() (*types.Block, types.Receipts) {
 	return miner.worker.pendingBlockAndReceipts()
 }
 
+// PendingRewards returns the expected reward breakdown of the work currently
+// being sealed, or nil if there is none.
+func (miner *Miner) PendingRewards() *ethash.BlockRewards {
+	return miner.worker.pendingRewards()
+}
+
+// WorkRewards returns the expected reward breakdown of the work package with
+// the given seal hash, or nil if it is unknown or stale.
+func (miner *Miner) WorkRewards(sealhash common.Hash) *ethash.BlockRewards {
+	return miner.worker.workRewards(sealhash)
+}
+
 func (miner *Miner) SetEtherbase(addr common.Address) {
 	miner.coinbase = addr
 	miner.worker.setEtherbase(addr)
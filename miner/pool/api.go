@@ -0,0 +1,135 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pool
+
+import (
+	"math"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
+)
+
+// API exposes the share ledger of the solo pool over the pool namespace.
+type API struct {
+	pool *Pool
+}
+
+// NewAPI creates a new pool API instance.
+func NewAPI(pool *Pool) *API {
+	return &API{pool: pool}
+}
+
+// WindowResult is the current state of the PPLNS window.
+type WindowResult struct {
+	Size            hexutil.Uint64                  `json:"size"`
+	ShareDifficulty hexutil.Uint64                  `json:"shareDifficulty"`
+	Total           *hexutil.Big                    `json:"total"`
+	Miners          map[common.Address]*hexutil.Big `json:"miners"`
+}
+
+// CreditResult is the amount credited to a single miner in a round.
+type CreditResult struct {
+	Miner  common.Address `json:"miner"`
+	Weight *hexutil.Big   `json:"weight"`
+	Amount *hexutil.Big   `json:"amount"`
+}
+
+// RoundResult is the reward accounting of a single block mined by the pool.
+type RoundResult struct {
+	Number  hexutil.Uint64  `json:"number"`
+	Hash    common.Hash     `json:"hash"`
+	Reward  *hexutil.Big    `json:"reward"`
+	Fee     *hexutil.Big    `json:"fee"`
+	Status  string          `json:"status"`
+	Credits []*CreditResult `json:"credits"`
+}
+
+// BalanceResult is the payout state of a single miner.
+type BalanceResult struct {
+	Owed *hexutil.Big `json:"owed"`
+	Paid *hexutil.Big `json:"paid"`
+}
+
+// Window returns the cumulative share difficulty of every miner in the current
+// PPLNS window.
+func (api *API) Window() *WindowResult {
+	weights, total := api.pool.ledger.Window(math.MaxUint64)
+
+	result := &WindowResult{
+		Size:            hexutil.Uint64(api.pool.config.Window),
+		ShareDifficulty: hexutil.Uint64(api.pool.config.ShareDifficulty),
+		Total:           (*hexutil.Big)(total),
+		Miners:          make(map[common.Address]*hexutil.Big),
+	}
+	for miner, weight := range weights {
+		result.Miners[miner] = (*hexutil.Big)(weight)
+	}
+	return result
+}
+
+// Rounds returns the reward split of every block the pool mined at the given
+// height.
+func (api *API) Rounds(number hexutil.Uint64) []*RoundResult {
+	rounds := api.pool.ledger.Rounds(uint64(number))
+
+	results := make([]*RoundResult, 0, len(rounds))
+	for _, round := range rounds {
+		results = append(results, newRoundResult(round))
+	}
+	return results
+}
+
+// newRoundResult converts a ledger round into its RPC representation.
+func newRoundResult(round *Round) *RoundResult {
+	result := &RoundResult{
+		Number:  hexutil.Uint64(round.Number),
+		Hash:    round.Hash,
+		Reward:  (*hexutil.Big)(round.Reward),
+		Fee:     (*hexutil.Big)(round.Fee),
+		Status:  round.Status.String(),
+		Credits: make([]*CreditResult, 0, len(round.Credits)),
+	}
+	for _, credit := range round.Credits {
+		result.Credits = append(result.Credits, &CreditResult{
+			Miner:  credit.Miner,
+			Weight: (*hexutil.Big)(credit.Weight),
+			Amount: (*hexutil.Big)(credit.Amount),
+		})
+	}
+	return result
+}
+
+// Balance returns the owed and paid amounts of the given miner.
+func (api *API) Balance(miner common.Address) *BalanceResult {
+	balance := api.pool.ledger.Balance(miner)
+	return &BalanceResult{
+		Owed: (*hexutil.Big)(balance.Owed),
+		Paid: (*hexutil.Big)(balance.Paid),
+	}
+}
+
+// Balances returns the owed and paid amounts of all miners ever credited.
+func (api *API) Balances() map[common.Address]*BalanceResult {
+	balances := make(map[common.Address]*BalanceResult)
+	for miner, balance := range api.pool.ledger.Balances() {
+		balances[miner] = &BalanceResult{
+			Owed: (*hexutil.Big)(balance.Owed),
+			Paid: (*hexutil.Big)(balance.Paid),
+		}
+	}
+	return balances
+}
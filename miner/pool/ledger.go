@@ -0,0 +1,417 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb"
+	"github.com/Altcoinchain/go-altcoinchain/rlp"
+)
+
+var (
+	// errUnknownRound is returned if a round is requested that was never opened.
+	errUnknownRound = errors.New("unknown round")
+
+	// errRoundSettled is returned when attempting to settle a round twice.
+	errRoundSettled = errors.New("round already settled")
+
+	// errInsufficientBalance is returned if a payout exceeds the owed amount.
+	errInsufficientBalance = errors.New("payout exceeds owed balance")
+
+	// errExcessiveRefund is returned if a refund exceeds the paid amount.
+	errExcessiveRefund = errors.New("refund exceeds paid balance")
+)
+
+// RoundStatus is the lifecycle state of a pool round.
+type RoundStatus uint8
+
+const (
+	RoundPending   RoundStatus = iota // Block mined, credits not yet final
+	RoundConfirmed                    // Block canonical at confirmation depth, credits booked
+	RoundOrphaned                     // Block reorged out, credits discarded
+)
+
+// String implements fmt.Stringer.
+func (s RoundStatus) String() string {
+	switch s {
+	case RoundPending:
+		return "pending"
+	case RoundConfirmed:
+		return "confirmed"
+	case RoundOrphaned:
+		return "orphaned"
+	default:
+		return "unknown"
+	}
+}
+
+// Share is a single low-difficulty proof-of-work accepted from a miner.
+type Share struct {
+	Miner      common.Address
+	Number     uint64   // Block number of the work package the share was found for
+	Difficulty *big.Int // Share difficulty, used as the PPLNS weight
+}
+
+// Credit is the amount owed to a single miner from a pool round.
+type Credit struct {
+	Miner  common.Address
+	Weight *big.Int // Cumulative difficulty of the miner's shares in the window
+	Amount *big.Int
+}
+
+// Round is the reward accounting of a single block mined by the pool.
+type Round struct {
+	Number  uint64
+	Hash    common.Hash
+	Reward  *big.Int // Total reward earned by the pool's coinbase
+	Fee     *big.Int // Portion of the reward retained by the pool operator
+	Status  RoundStatus
+	Credits []*Credit
+}
+
+// Balance is the payout state of a single miner.
+type Balance struct {
+	Owed *big.Int // Confirmed credits not yet paid out
+	Paid *big.Int // Cumulative amount paid out
+}
+
+// Ledger is a persistent PPLNS (pay per last N shares) share ledger. Every
+// accepted share is appended to the ledger and whenever the pool mines a block,
+// the reward is split proportionally to the difficulty of the last N shares.
+type Ledger struct {
+	db     ethdb.KeyValueStore
+	window uint64 // Number of shares to split a block reward across
+	fee    uint64 // Pool operator fee in basis points
+
+	head uint64 // Sequence number of the next share
+	lock sync.Mutex
+}
+
+// NewLedger creates a share ledger on top of the given database, splitting the
+// block rewards across the last window shares and retaining a fee, specified
+// in basis points, for the pool operator.
+func NewLedger(db ethdb.KeyValueStore, window uint64, fee uint64) *Ledger {
+	ledger := &Ledger{
+		db:     db,
+		window: window,
+		fee:    fee,
+	}
+	if blob, _ := db.Get(shareHeadKey); len(blob) == 8 {
+		ledger.head = binary.BigEndian.Uint64(blob)
+	}
+	return ledger
+}
+
+// RecordShare implements ethash.ShareRecorder, appending a share to the ledger.
+// Shares falling out of twice the PPLNS window are deleted, the slack ensures
+// rounds opened a bit late still see a full window.
+func (l *Ledger) RecordShare(miner common.Address, number uint64, difficulty *big.Int) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	blob, err := rlp.EncodeToBytes(&Share{Miner: miner, Number: number, Difficulty: difficulty})
+	if err != nil {
+		return err
+	}
+	batch := l.db.NewBatch()
+	batch.Put(shareKey(l.head), blob)
+	batch.Put(shareHeadKey, encodeUint64(l.head+1))
+	if l.head >= 2*l.window {
+		batch.Delete(shareKey(l.head - 2*l.window))
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	l.head++
+	return nil
+}
+
+// Window returns the cumulative share difficulty per miner within the PPLNS
+// window ending at the given block number, along with the total difficulty.
+func (l *Ledger) Window(number uint64) (map[common.Address]*big.Int, *big.Int) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.shareWindow(number)
+}
+
+// shareWindow walks the share log backwards, accumulating the last N shares that
+// were found for work packages up to and including the given block number.
+//
+// The caller must hold the ledger lock.
+func (l *Ledger) shareWindow(number uint64) (map[common.Address]*big.Int, *big.Int) {
+	var (
+		weights = make(map[common.Address]*big.Int)
+		total   = new(big.Int)
+		counted uint64
+	)
+	for seq := l.head; seq > 0 && counted < l.window; seq-- {
+		blob, err := l.db.Get(shareKey(seq - 1))
+		if err != nil {
+			break // Pruned, window exhausted
+		}
+		share := new(Share)
+		if err := rlp.DecodeBytes(blob, share); err != nil {
+			break
+		}
+		if share.Number > number {
+			continue
+		}
+		if weights[share.Miner] == nil {
+			weights[share.Miner] = new(big.Int)
+		}
+		weights[share.Miner].Add(weights[share.Miner], share.Difficulty)
+		total.Add(total, share.Difficulty)
+		counted++
+	}
+	return weights, total
+}
+
+// OpenRound splits the reward of a block mined by the pool across the miners
+// in the current PPLNS window. The credits are not payable until the round is
+// settled as canonical at the required confirmation depth.
+func (l *Ledger) OpenRound(number uint64, hash common.Hash, reward *big.Int) (*Round, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	round := &Round{
+		Number: number,
+		Hash:   hash,
+		Reward: new(big.Int).Set(reward),
+		Fee:    new(big.Int),
+		Status: RoundPending,
+	}
+	fee := new(big.Int).Mul(reward, new(big.Int).SetUint64(l.fee))
+	fee.Div(fee, big.NewInt(10000))
+	payable := new(big.Int).Sub(reward, fee)
+
+	weights, total := l.shareWindow(number)
+	if total.Sign() > 0 {
+		distributed := new(big.Int)
+		for miner, weight := range weights {
+			amount := new(big.Int).Mul(payable, weight)
+			amount.Div(amount, total)
+			distributed.Add(distributed, amount)
+
+			round.Credits = append(round.Credits, &Credit{Miner: miner, Weight: weight, Amount: amount})
+		}
+		// Rounding dust stays with the pool operator
+		payable.Sub(payable, distributed)
+		sort.Slice(round.Credits, func(i, j int) bool {
+			return bytes.Compare(round.Credits[i].Miner[:], round.Credits[j].Miner[:]) < 0
+		})
+	}
+	round.Fee.Add(fee, payable)
+
+	if err := l.writeRound(l.db, round); err != nil {
+		return nil, err
+	}
+	return round, nil
+}
+
+// SettleRound finalizes the round opened for the given block. If the round's
+// block is the canonical one, the credits are booked to the miners' balances,
+// otherwise the round is marked orphaned and the credits discarded.
+func (l *Ledger) SettleRound(number uint64, hash common.Hash, canonical common.Hash) (*Round, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	round, err := l.readRound(number, hash)
+	if err != nil {
+		return nil, err
+	}
+	if round.Status != RoundPending {
+		return round, errRoundSettled
+	}
+	batch := l.db.NewBatch()
+	if round.Hash != canonical {
+		round.Status = RoundOrphaned
+	} else {
+		round.Status = RoundConfirmed
+		for _, credit := range round.Credits {
+			balance := l.readBalance(credit.Miner)
+			balance.Owed.Add(balance.Owed, credit.Amount)
+			if err := l.writeBalance(batch, credit.Miner, balance); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := l.writeRound(batch, round); err != nil {
+		return nil, err
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	return round, nil
+}
+
+// Round retrieves the round opened for the given block.
+func (l *Ledger) Round(number uint64, hash common.Hash) (*Round, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.readRound(number, hash)
+}
+
+// Rounds retrieves all the rounds opened at the given block number, one for
+// every sibling block the pool mined at that height.
+func (l *Ledger) Rounds(number uint64) []*Round {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.iterateRounds(roundsKey(number), false)
+}
+
+// PendingRounds returns all the rounds not yet settled, in ascending order.
+func (l *Ledger) PendingRounds() []*Round {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.iterateRounds(roundPrefix, true)
+}
+
+// iterateRounds retrieves all the rounds under the given key prefix, optionally
+// filtering out those already settled.
+func (l *Ledger) iterateRounds(prefix []byte, pending bool) []*Round {
+	var rounds []*Round
+
+	it := l.db.NewIterator(prefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(roundPrefix)+8+common.HashLength {
+			continue
+		}
+		round := new(Round)
+		if err := rlp.DecodeBytes(it.Value(), round); err != nil {
+			continue
+		}
+		if !pending || round.Status == RoundPending {
+			rounds = append(rounds, round)
+		}
+	}
+	return rounds
+}
+
+// Balance returns the payout state of the given miner.
+func (l *Ledger) Balance(miner common.Address) *Balance {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.readBalance(miner)
+}
+
+// Balances returns the payout state of all miners ever credited.
+func (l *Ledger) Balances() map[common.Address]*Balance {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	balances := make(map[common.Address]*Balance)
+
+	it := l.db.NewIterator(balancePrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(balancePrefix)+common.AddressLength {
+			continue
+		}
+		balance := new(Balance)
+		if err := rlp.DecodeBytes(it.Value(), balance); err != nil {
+			continue
+		}
+		balances[common.BytesToAddress(it.Key()[len(balancePrefix):])] = balance
+	}
+	return balances
+}
+
+// Pay moves the given amount of a miner's owed balance to paid, to be called
+// before a payout transaction is submitted.
+func (l *Ledger) Pay(miner common.Address, amount *big.Int) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	balance := l.readBalance(miner)
+	if balance.Owed.Cmp(amount) < 0 {
+		return errInsufficientBalance
+	}
+	balance.Owed.Sub(balance.Owed, amount)
+	balance.Paid.Add(balance.Paid, amount)
+	return l.writeBalance(l.db, miner, balance)
+}
+
+// Refund reverts a previous Pay of the given amount, to be called if the payout
+// transaction could not be submitted.
+func (l *Ledger) Refund(miner common.Address, amount *big.Int) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	balance := l.readBalance(miner)
+	if balance.Paid.Cmp(amount) < 0 {
+		return errExcessiveRefund
+	}
+	balance.Paid.Sub(balance.Paid, amount)
+	balance.Owed.Add(balance.Owed, amount)
+	return l.writeBalance(l.db, miner, balance)
+}
+
+// readRound retrieves a round from the database.
+func (l *Ledger) readRound(number uint64, hash common.Hash) (*Round, error) {
+	blob, err := l.db.Get(roundKey(number, hash))
+	if err != nil {
+		return nil, errUnknownRound
+	}
+	round := new(Round)
+	if err := rlp.DecodeBytes(blob, round); err != nil {
+		return nil, err
+	}
+	return round, nil
+}
+
+// writeRound stores a round into the database.
+func (l *Ledger) writeRound(db ethdb.KeyValueWriter, round *Round) error {
+	blob, err := rlp.EncodeToBytes(round)
+	if err != nil {
+		return err
+	}
+	return db.Put(roundKey(round.Number, round.Hash), blob)
+}
+
+// readBalance retrieves the balance of a miner, or a zero balance if the miner
+// was never credited.
+func (l *Ledger) readBalance(miner common.Address) *Balance {
+	balance := &Balance{Owed: new(big.Int), Paid: new(big.Int)}
+	if blob, err := l.db.Get(balanceKey(miner)); err == nil {
+		rlp.DecodeBytes(blob, balance)
+	}
+	return balance
+}
+
+// writeBalance stores the balance of a miner into the database.
+func (l *Ledger) writeBalance(db ethdb.KeyValueWriter, miner common.Address, balance *Balance) error {
+	blob, err := rlp.EncodeToBytes(balance)
+	if err != nil {
+		return err
+	}
+	return db.Put(balanceKey(miner), blob)
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pool
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb/memorydb"
+)
+
+var (
+	minerA = common.HexToAddress("0xaaaa")
+	minerB = common.HexToAddress("0xbbbb")
+	minerC = common.HexToAddress("0xcccc")
+)
+
+// Tests that the PPLNS window only counts the last N shares found up to the
+// block being credited.
+func TestLedgerWindow(t *testing.T) {
+	ledger := NewLedger(memorydb.New(), 3, 0)
+
+	ledger.RecordShare(minerA, 1, big.NewInt(10)) // Outside of the window
+	ledger.RecordShare(minerB, 1, big.NewInt(10))
+	ledger.RecordShare(minerA, 2, big.NewInt(20))
+	ledger.RecordShare(minerB, 2, big.NewInt(30))
+	ledger.RecordShare(minerC, 3, big.NewInt(40)) // Newer than the credited block
+
+	weights, total := ledger.Window(2)
+	if total.Cmp(big.NewInt(60)) != 0 {
+		t.Fatalf("window total mismatch: have %v, want %v", total, 60)
+	}
+	if w := weights[minerA]; w == nil || w.Cmp(big.NewInt(20)) != 0 {
+		t.Errorf("miner A weight mismatch: have %v, want %v", w, 20)
+	}
+	if w := weights[minerB]; w == nil || w.Cmp(big.NewInt(40)) != 0 {
+		t.Errorf("miner B weight mismatch: have %v, want %v", w, 40)
+	}
+	if w := weights[minerC]; w != nil {
+		t.Errorf("miner C credited for future share: %v", w)
+	}
+}
+
+// Tests that rounds split the reward proportionally, book credits only once
+// confirmed and discard them if orphaned.
+func TestLedgerRounds(t *testing.T) {
+	db := memorydb.New()
+	ledger := NewLedger(db, 10, 100) // 1% fee
+
+	ledger.RecordShare(minerA, 1, big.NewInt(1))
+	ledger.RecordShare(minerB, 1, big.NewInt(3))
+
+	round, err := ledger.OpenRound(1, common.Hash{0x01}, big.NewInt(10000))
+	if err != nil {
+		t.Fatalf("failed to open round: %v", err)
+	}
+	if round.Fee.Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("round fee mismatch: have %v, want %v", round.Fee, 100)
+	}
+	if len(round.Credits) != 2 {
+		t.Fatalf("round credit count mismatch: have %d, want 2", len(round.Credits))
+	}
+	if round.Credits[0].Miner != minerA || round.Credits[0].Amount.Cmp(big.NewInt(2475)) != 0 {
+		t.Errorf("miner A credit mismatch: have %x/%v, want %x/%v", round.Credits[0].Miner, round.Credits[0].Amount, minerA, 2475)
+	}
+	if round.Credits[1].Miner != minerB || round.Credits[1].Amount.Cmp(big.NewInt(7425)) != 0 {
+		t.Errorf("miner B credit mismatch: have %x/%v, want %x/%v", round.Credits[1].Miner, round.Credits[1].Amount, minerB, 7425)
+	}
+	// Pending credits must not be payable
+	if owed := ledger.Balance(minerA).Owed; owed.Sign() != 0 {
+		t.Errorf("pending round credited: %v", owed)
+	}
+	if _, err := ledger.SettleRound(1, common.Hash{0x01}, common.Hash{0x01}); err != nil {
+		t.Fatalf("failed to settle round: %v", err)
+	}
+	if _, err := ledger.SettleRound(1, common.Hash{0x01}, common.Hash{0x01}); err != errRoundSettled {
+		t.Errorf("double settlement error mismatch: have %v, want %v", err, errRoundSettled)
+	}
+	// Open two sibling rounds, one of which gets reorged out
+	if _, err := ledger.OpenRound(2, common.Hash{0x02}, big.NewInt(10000)); err != nil {
+		t.Fatalf("failed to open round: %v", err)
+	}
+	if _, err := ledger.OpenRound(2, common.Hash{0x03}, big.NewInt(10000)); err != nil {
+		t.Fatalf("failed to open sibling round: %v", err)
+	}
+	if rounds := ledger.Rounds(2); len(rounds) != 2 {
+		t.Fatalf("sibling round count mismatch: have %d, want 2", len(rounds))
+	}
+	round, err = ledger.SettleRound(2, common.Hash{0x02}, common.Hash{0x04})
+	if err != nil {
+		t.Fatalf("failed to settle round: %v", err)
+	}
+	if round.Status != RoundOrphaned {
+		t.Errorf("round status mismatch: have %v, want %v", round.Status, RoundOrphaned)
+	}
+	round, err = ledger.SettleRound(2, common.Hash{0x03}, common.Hash{0x04})
+	if err != nil {
+		t.Fatalf("failed to settle sibling round: %v", err)
+	}
+	if round.Status != RoundOrphaned {
+		t.Errorf("sibling round status mismatch: have %v, want %v", round.Status, RoundOrphaned)
+	}
+	// Reopen the ledger and ensure everything was persisted
+	ledger = NewLedger(db, 10, 100)
+	if owed := ledger.Balance(minerB).Owed; owed.Cmp(big.NewInt(7425)) != 0 {
+		t.Errorf("miner B owed mismatch: have %v, want %v", owed, 7425)
+	}
+	if err := ledger.Pay(minerB, big.NewInt(7000)); err != nil {
+		t.Fatalf("failed to pay miner: %v", err)
+	}
+	if err := ledger.Pay(minerB, big.NewInt(7000)); err != errInsufficientBalance {
+		t.Errorf("overpayment error mismatch: have %v, want %v", err, errInsufficientBalance)
+	}
+	// Refund an unsent payout and pay it again
+	if err := ledger.Refund(minerB, big.NewInt(7000)); err != nil {
+		t.Fatalf("failed to refund miner: %v", err)
+	}
+	if err := ledger.Refund(minerB, big.NewInt(1)); err != errExcessiveRefund {
+		t.Errorf("excessive refund error mismatch: have %v, want %v", err, errExcessiveRefund)
+	}
+	if err := ledger.Pay(minerB, big.NewInt(7000)); err != nil {
+		t.Fatalf("failed to pay miner: %v", err)
+	}
+	balances := ledger.Balances()
+	if len(balances) != 2 {
+		t.Fatalf("balance count mismatch: have %d, want 2", len(balances))
+	}
+	if b := balances[minerB]; b.Owed.Cmp(big.NewInt(425)) != 0 || b.Paid.Cmp(big.NewInt(7000)) != 0 {
+		t.Errorf("miner B balance mismatch: have %v/%v, want %v/%v", b.Owed, b.Paid, 425, 7000)
+	}
+	if pending := ledger.PendingRounds(); len(pending) != 0 {
+		t.Errorf("pending round count mismatch: have %d, want 0", len(pending))
+	}
+}
+
+// Tests that shares falling out of the retention range are pruned.
+func TestLedgerSharePruning(t *testing.T) {
+	db := memorydb.New()
+	ledger := NewLedger(db, 2, 0)
+	for i := 0; i < 10; i++ {
+		ledger.RecordShare(minerA, uint64(i), big.NewInt(1))
+	}
+	for seq := uint64(0); seq < 10; seq++ {
+		has, _ := db.Has(shareKey(seq))
+		if want := seq >= 6; has != want {
+			t.Errorf("share %d presence mismatch: have %v, want %v", seq, has, want)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package pool implements solo pool share accounting with PPLNS payouts.
+package pool
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/accounts"
+	"github.com/Altcoinchain/go-altcoinchain/accounts/keystore"
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/consensus"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/beacon"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
+	"github.com/Altcoinchain/go-altcoinchain/core"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb"
+	"github.com/Altcoinchain/go-altcoinchain/event"
+	"github.com/Altcoinchain/go-altcoinchain/log"
+	"github.com/Altcoinchain/go-altcoinchain/node"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+	"github.com/Altcoinchain/go-altcoinchain/rpc"
+)
+
+const (
+	// chainEventChanSize is the size of channel listening to ChainEvent.
+	chainEventChanSize = 10
+
+	// payoutGas is the gas allowance of a plain value transfer payout.
+	payoutGas = params.TxGas
+)
+
+// errNoEthash is returned if the pool is started on top of a non-ethash engine.
+var errNoEthash = errors.New("pool accounting requires the ethash engine")
+
+// Config contains the configuration options of the solo pool.
+type Config struct {
+	Enabled         bool           // Whether to run the solo pool on top of the remote sealer
+	Window          uint64         // Number of last shares to split block rewards across (the N in PPLNS)
+	ShareDifficulty uint64         // Difficulty a submitted share must satisfy
+	Fee             uint64         // Pool operator fee in basis points
+	Confirmations   uint64         // Number of blocks after which a round's credits become payable
+	Payouts         bool           // Whether to automatically send payout transactions
+	PayoutThreshold *big.Int       // Minimum owed balance before a payout is sent
+	PayoutGasTip    *big.Int       // Priority fee of the payout transactions, paid on top of the base fee
+	Etherbase       common.Address `toml:"-"` // Coinbase of the pool, source of the payouts
+}
+
+// DefaultConfig contains the default settings for the solo pool.
+var DefaultConfig = Config{
+	Window:          10000,
+	ShareDifficulty: 4000000000,
+	Fee:             100,
+	Confirmations:   7,
+	PayoutThreshold: big.NewInt(params.Ether),
+	PayoutGasTip:    big.NewInt(params.GWei),
+}
+
+// Backend encompasses the chain functionality needed by the pool.
+type Backend interface {
+	ChainConfig() *params.ChainConfig
+	Engine() consensus.Engine
+	AccountManager() *accounts.Manager
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error)
+	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
+	GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error)
+	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
+}
+
+// Pool is the solo pool service, accounting shares submitted to the ethash
+// remote sealer and crediting miners whenever the pool mines a block.
+type Pool struct {
+	config  Config
+	backend Backend
+	engine  *ethash.Ethash
+	db      ethdb.Database
+	ledger  *Ledger
+
+	chainSub event.Subscription
+	quit     chan struct{}
+}
+
+// New creates the solo pool service and registers it with the node.
+func New(stack *node.Node, backend Backend, config Config) (*Pool, error) {
+	engine := backend.Engine()
+	if b, ok := engine.(*beacon.Beacon); ok {
+		engine = b.InnerEngine()
+	}
+	pow, ok := engine.(*ethash.Ethash)
+	if !ok {
+		return nil, errNoEthash
+	}
+	db, err := stack.OpenDatabase("pool", 16, 16, "pool/", false)
+	if err != nil {
+		return nil, err
+	}
+	pool := &Pool{
+		config:  config,
+		backend: backend,
+		engine:  pow,
+		db:      db,
+		ledger:  NewLedger(db, config.Window, config.Fee),
+		quit:    make(chan struct{}),
+	}
+	stack.RegisterAPIs([]rpc.API{{
+		Namespace: "pool",
+		Service:   NewAPI(pool),
+	}})
+	stack.RegisterLifecycle(pool)
+	return pool, nil
+}
+
+// Ledger returns the share ledger of the pool.
+func (p *Pool) Ledger() *Ledger {
+	return p.ledger
+}
+
+// Start implements node.Lifecycle, enabling share accounting on the remote
+// sealer and starting to track mined blocks.
+func (p *Pool) Start() error {
+	p.engine.SetShareRecorder(p.ledger, new(big.Int).SetUint64(p.config.ShareDifficulty))
+
+	events := make(chan core.ChainEvent, chainEventChanSize)
+	p.chainSub = p.backend.SubscribeChainEvent(events)
+	go p.loop(events)
+
+	log.Info("Solo pool started", "etherbase", p.config.Etherbase, "window", p.config.Window, "sharediff", p.config.ShareDifficulty)
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating the pool.
+func (p *Pool) Stop() error {
+	p.chainSub.Unsubscribe()
+	close(p.quit)
+	p.db.Close()
+	log.Info("Solo pool stopped")
+	return nil
+}
+
+// loop opens a round for every block imported with the pool's coinbase and
+// settles rounds once they are deep enough in the chain.
+func (p *Pool) loop(events chan core.ChainEvent) {
+	for {
+		select {
+		case ev := <-events:
+			block := ev.Block
+			if block.Coinbase() == p.config.Etherbase {
+				p.openRound(block)
+			}
+			p.settleRounds(block.NumberU64())
+
+		case <-p.chainSub.Err():
+			return
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// openRound splits the reward of a freshly mined block across the PPLNS window.
+func (p *Pool) openRound(block *types.Block) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Only the part of the miner reward credited to the pool's etherbase is
+	// distributed, the rest goes to the recipients of the block's reward split
+	reward := new(big.Int)
+	for _, credit := range ethash.BlockRewardList(p.backend.ChainConfig(), block.Header(), block.Uncles()) {
+		if credit.Kind == ethash.RewardMiner && credit.Address == p.config.Etherbase {
+			reward.Add(reward, credit.Amount)
+		}
+	}
+	receipts, err := p.backend.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		log.Warn("Failed to retrieve pool block receipts", "number", block.NumberU64(), "hash", block.Hash(), "err", err)
+	}
+	for i, tx := range block.Transactions() {
+		if i >= len(receipts) {
+			break
+		}
+		tip, _ := tx.EffectiveGasTip(block.BaseFee())
+		reward.Add(reward, new(big.Int).Mul(new(big.Int).SetUint64(receipts[i].GasUsed), tip))
+	}
+	round, err := p.ledger.OpenRound(block.NumberU64(), block.Hash(), reward)
+	if err != nil {
+		log.Error("Failed to open pool round", "number", block.NumberU64(), "err", err)
+		return
+	}
+	log.Info("Opened pool round", "number", round.Number, "hash", round.Hash, "reward", round.Reward, "miners", len(round.Credits))
+}
+
+// settleRounds settles all pending rounds that reached the confirmation depth,
+// sending out payouts if enabled.
+func (p *Pool) settleRounds(head uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var settled bool
+	for _, round := range p.ledger.PendingRounds() {
+		if round.Number+p.config.Confirmations > head {
+			break
+		}
+		header, err := p.backend.HeaderByNumber(ctx, rpc.BlockNumber(round.Number))
+		if err != nil || header == nil {
+			continue
+		}
+		settledRound, err := p.ledger.SettleRound(round.Number, round.Hash, header.Hash())
+		if err != nil {
+			log.Error("Failed to settle pool round", "number", round.Number, "err", err)
+			continue
+		}
+		log.Info("Settled pool round", "number", settledRound.Number, "hash", settledRound.Hash, "status", settledRound.Status)
+		settled = true
+	}
+	if settled && p.config.Payouts {
+		if err := p.payout(ctx); err != nil {
+			log.Warn("Failed to send pool payouts", "err", err)
+		}
+	}
+}
+
+// payout signs and submits a transfer from the pool's etherbase to every miner
+// whose owed balance exceeds the payout threshold. The etherbase account must
+// be unlocked in the keystore.
+func (p *Pool) payout(ctx context.Context) error {
+	backends := p.backend.AccountManager().Backends(keystore.KeyStoreType)
+	if len(backends) == 0 {
+		return errors.New("no keystore available")
+	}
+	ks := backends[0].(*keystore.KeyStore)
+	account := accounts.Account{Address: p.config.Etherbase}
+
+	head, err := p.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return err
+	}
+	if head == nil {
+		return errors.New("chain head unavailable")
+	}
+	// Sign for the next block, the chain ID changes at the EthPoW fork
+	chainID := types.MakeSigner(p.backend.ChainConfig(), new(big.Int).Add(head.Number, common.Big1)).ChainID()
+
+	nonce, err := p.backend.GetPoolNonce(ctx, p.config.Etherbase)
+	if err != nil {
+		return err
+	}
+	for miner, balance := range p.ledger.Balances() {
+		if balance.Owed.Sign() == 0 || balance.Owed.Cmp(p.config.PayoutThreshold) < 0 {
+			continue
+		}
+		tx := p.payoutTx(chainID, head.BaseFee, nonce, miner, balance.Owed)
+		signed, err := ks.SignTx(account, tx, chainID)
+		if err != nil {
+			return err
+		}
+		// Book the payout before sending it out, so a failed ledger write can
+		// never result in paying the same balance twice
+		if err := p.ledger.Pay(miner, balance.Owed); err != nil {
+			return err
+		}
+		if err := p.backend.SendTx(ctx, signed); err != nil {
+			if err := p.ledger.Refund(miner, balance.Owed); err != nil {
+				log.Error("Failed to refund unsent pool payout", "miner", miner, "amount", balance.Owed, "err", err)
+			}
+			return err
+		}
+		log.Info("Sent pool payout", "miner", miner, "amount", balance.Owed, "tx", signed.Hash())
+		nonce++
+	}
+	return nil
+}
+
+// payoutTx creates the transfer paying out the given amount to a miner. After
+// London it is a dynamic fee transaction whose fee cap leaves room for the base
+// fee to double before it gets included.
+func (p *Pool) payoutTx(chainID *big.Int, baseFee *big.Int, nonce uint64, miner common.Address, amount *big.Int) *types.Transaction {
+	if baseFee == nil {
+		return types.NewTransaction(nonce, miner, amount, payoutGas, p.config.PayoutGasTip, nil)
+	}
+	feeCap := new(big.Int).Mul(baseFee, common.Big2)
+	feeCap.Add(feeCap, p.config.PayoutGasTip)
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: p.config.PayoutGasTip,
+		GasFeeCap: feeCap,
+		Gas:       payoutGas,
+		To:        &miner,
+		Value:     amount,
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pool
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/accounts"
+	"github.com/Altcoinchain/go-altcoinchain/accounts/keystore"
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/consensus"
+	"github.com/Altcoinchain/go-altcoinchain/core"
+	"github.com/Altcoinchain/go-altcoinchain/core/types"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb/memorydb"
+	"github.com/Altcoinchain/go-altcoinchain/event"
+	"github.com/Altcoinchain/go-altcoinchain/params"
+	"github.com/Altcoinchain/go-altcoinchain/rpc"
+)
+
+// testBackend is a pool backend with a fixed head, collecting the sent transactions.
+type testBackend struct {
+	config  *params.ChainConfig
+	manager *accounts.Manager
+	head    *types.Header
+	sent    []*types.Transaction
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig  { return b.config }
+func (b *testBackend) Engine() consensus.Engine          { return nil }
+func (b *testBackend) AccountManager() *accounts.Manager { return b.manager }
+
+func (b *testBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	return b.head, nil
+}
+
+func (b *testBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return nil, nil
+}
+
+func (b *testBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
+	return uint64(len(b.sent)), nil
+}
+
+func (b *testBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	b.sent = append(b.sent, signedTx)
+	return nil
+}
+
+func (b *testBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+}
+
+// Tests that payouts sent after the EthPoW fork are signed for the post-fork
+// chain ID and pay at least the base fee.
+func TestPayoutAfterEthPoWFork(t *testing.T) {
+	ks := keystore.NewKeyStore(t.TempDir(), keystore.LightScryptN, keystore.LightScryptP)
+	etherbase, err := ks.NewAccount("")
+	if err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	if err := ks.Unlock(etherbase, ""); err != nil {
+		t.Fatalf("failed to unlock account: %v", err)
+	}
+	config := *params.TestChainConfig
+	config.ChainID_ALT = big.NewInt(2330)
+	config.EthPoWForkBlock = big.NewInt(10)
+
+	backend := &testBackend{
+		config:  &config,
+		manager: accounts.NewManager(&accounts.Config{InsecureUnlockAllowed: true}, ks),
+		head:    &types.Header{Number: big.NewInt(100), BaseFee: big.NewInt(10 * params.GWei)},
+	}
+	pool := &Pool{
+		config:  DefaultConfig,
+		backend: backend,
+		ledger:  NewLedger(memorydb.New(), 10, 0),
+	}
+	pool.config.Etherbase = etherbase.Address
+	pool.config.PayoutThreshold = big.NewInt(1)
+
+	pool.ledger.RecordShare(minerA, 1, big.NewInt(1))
+	if _, err := pool.ledger.OpenRound(1, common.Hash{0x01}, big.NewInt(params.Ether)); err != nil {
+		t.Fatalf("failed to open round: %v", err)
+	}
+	if _, err := pool.ledger.SettleRound(1, common.Hash{0x01}, common.Hash{0x01}); err != nil {
+		t.Fatalf("failed to settle round: %v", err)
+	}
+	if err := pool.payout(context.Background()); err != nil {
+		t.Fatalf("failed to send payouts: %v", err)
+	}
+	if len(backend.sent) != 1 {
+		t.Fatalf("payout count mismatch: have %d, want 1", len(backend.sent))
+	}
+	tx := backend.sent[0]
+	signer := types.MakeSigner(&config, big.NewInt(101))
+	if from, err := types.Sender(signer, tx); err != nil || from != etherbase.Address {
+		t.Fatalf("payout sender mismatch: have %x (%v), want %x", from, err, etherbase.Address)
+	}
+	if tx.ChainId().Cmp(config.ChainID_ALT) != 0 {
+		t.Errorf("payout chain ID mismatch: have %v, want %v", tx.ChainId(), config.ChainID_ALT)
+	}
+	if tx.GasFeeCapIntCmp(backend.head.BaseFee) < 0 {
+		t.Errorf("payout fee cap below base fee: have %v, want >= %v", tx.GasFeeCap(), backend.head.BaseFee)
+	}
+	if tx.To() == nil || *tx.To() != minerA || tx.Value().Cmp(big.NewInt(params.Ether)) != 0 {
+		t.Errorf("payout transfer mismatch: have %v to %v, want %v to %x", tx.Value(), tx.To(), params.Ether, minerA)
+	}
+	if owed := pool.ledger.Balance(minerA).Owed; owed.Sign() != 0 {
+		t.Errorf("paid balance still owed: %v", owed)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package pool
+
+import (
+	"encoding/binary"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+)
+
+// The fields below define the low level database schema of the share ledger.
+var (
+	// shareHeadKey tracks the sequence number of the next share to be recorded.
+	shareHeadKey = []byte("PoolShareHead")
+
+	sharePrefix   = []byte("ps") // sharePrefix + seq (uint64 big endian) -> share
+	roundPrefix   = []byte("pr") // roundPrefix + num (uint64 big endian) + hash -> round
+	balancePrefix = []byte("pb") // balancePrefix + address -> account
+)
+
+// encodeUint64 encodes a number as big endian uint64.
+func encodeUint64(number uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, number)
+	return enc
+}
+
+// shareKey = sharePrefix + seq (uint64 big endian)
+func shareKey(seq uint64) []byte {
+	return append(append([]byte{}, sharePrefix...), encodeUint64(seq)...)
+}
+
+// roundsKey = roundPrefix + num (uint64 big endian)
+func roundsKey(number uint64) []byte {
+	return append(append([]byte{}, roundPrefix...), encodeUint64(number)...)
+}
+
+// roundKey = roundPrefix + num (uint64 big endian) + hash
+func roundKey(number uint64, hash common.Hash) []byte {
+	return append(roundsKey(number), hash.Bytes()...)
+}
+
+// balanceKey = balancePrefix + address
+func balanceKey(addr common.Address) []byte {
+	return append(append([]byte{}, balancePrefix...), addr.Bytes()...)
+}
@@ -27,6 +27,8 @@ import (
 	mapset "github.com/deckarep/golang-set"
 	"github.com/Altcoinchain/go-altcoinchain/common"
 	"github.com/Altcoinchain/go-altcoinchain/consensus"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/beacon"
+	"github.com/Altcoinchain/go-altcoinchain/consensus/ethash"
 	"github.com/Altcoinchain/go-altcoinchain/consensus/misc"
 	"github.com/Altcoinchain/go-altcoinchain/core"
 	"github.com/Altcoinchain/go-altcoinchain/core/state"
@@ -151,6 +153,7 @@ type task struct {
 	receipts  []*types.Receipt
 	state     *state.StateDB
 	block     *types.Block
+	rewards   *ethash.BlockRewards
 	createdAt time.Time
 }
 
@@ -665,6 +668,9 @@ func (w *worker) taskLoop() {
 			w.pendingTasks[sealHash] = task
 			w.pendingMu.Unlock()
 
+			if reporter, ok := w.rewardReporter(); ok {
+				reporter.ReportWorkRewards(task.block.Header(), task.rewards)
+			}
 			if err := w.engine.Seal(w.chain, task.block, w.resultCh, stopCh); err != nil {
 				log.Warn("Block sealing failed", "err", err)
 				w.pendingMu.Lock()
@@ -759,6 +765,9 @@ func (w *worker) makeEnv(parent *types.Block, header *types.Header, coinbase com
 	if err != nil {
 		return nil, err
 	}
+	if w.chain.StateDiffsEnabled() {
+		state.TrackStateDiff()
+	}
 	state.StartPrefetcher("miner")
 
 	// Note the passed coinbase may be different with header.Coinbase.
@@ -1004,6 +1013,15 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 	if !genParams.noExtra && len(w.extra) != 0 {
 		header.Extra = w.extra
 	}
+	// Encode the reward split into the extra field after the fork if configured
+	if !genParams.noExtra && len(w.config.Beneficiaries) > 0 && w.chainConfig.IsRewardSplit(header.Number) {
+		extra, err := ethash.EncodeRewardSplit(w.config.Beneficiaries, w.extra)
+		if err != nil {
+			log.Error("Failed to encode reward split", "err", err)
+			return nil, err
+		}
+		header.Extra = extra
+	}
 	// Set the randomness field from the beacon chain if it's available.
 	if genParams.random != (common.Hash{}) {
 		header.MixDigest = genParams.random
@@ -1154,8 +1172,9 @@ func (w *worker) commit(env *environment, interval func(), update bool, start ti
 		}
 		// If we're post merge, just ignore
 		if !w.isTTDReached(block.Header()) {
+			rewards := ethash.ExpectedRewards(w.chainConfig, block.Header(), block.Uncles(), blockFees(block, env.receipts))
 			select {
-			case w.taskCh <- &task{receipts: env.receipts, state: env.state, block: block, createdAt: time.Now()}:
+			case w.taskCh <- &task{receipts: env.receipts, state: env.state, block: block, rewards: rewards, createdAt: time.Now()}:
 				w.unconfirmed.Shift(block.NumberU64() - 1)
 				log.Info("Commit new sealing work", "number", block.Number(), "sealhash", w.engine.SealHash(block.Header()),
 					"uncles", len(env.uncles), "txs", env.tcount,
@@ -1233,10 +1252,62 @@ func (w *worker) postSideBlock(event core.ChainSideEvent) {
 
 // totalFees computes total consumed miner fees in ETH. Block transactions and receipts have to have the same order.
 func totalFees(block *types.Block, receipts []*types.Receipt) *big.Float {
+	return new(big.Float).Quo(new(big.Float).SetInt(blockFees(block, receipts)), new(big.Float).SetInt(big.NewInt(params.Ether)))
+}
+
+// blockFees computes total consumed miner fees in wei. Block transactions and receipts have to have the same order.
+func blockFees(block *types.Block, receipts []*types.Receipt) *big.Int {
 	feesWei := new(big.Int)
 	for i, tx := range block.Transactions() {
 		minerFee, _ := tx.EffectiveGasTip(block.BaseFee())
 		feesWei.Add(feesWei, new(big.Int).Mul(new(big.Int).SetUint64(receipts[i].GasUsed), minerFee))
 	}
-	return new(big.Float).Quo(new(big.Float).SetInt(feesWei), new(big.Float).SetInt(big.NewInt(params.Ether)))
+	return feesWei
+}
+
+// workRewardReporter is implemented by consensus engines that hand out work
+// packages annotated with their expected rewards.
+type workRewardReporter interface {
+	ReportWorkRewards(header *types.Header, rewards *ethash.BlockRewards)
+}
+
+// rewardReporter returns the consensus engine if it accepts expected reward
+// reports for its work packages.
+func (w *worker) rewardReporter() (workRewardReporter, bool) {
+	engine := w.engine
+	if b, ok := engine.(*beacon.Beacon); ok {
+		engine = b.InnerEngine()
+	}
+	reporter, ok := engine.(workRewardReporter)
+	return reporter, ok
+}
+
+// pendingRewards returns the expected reward breakdown of the latest sealing
+// task, or nil if no task is being sealed.
+func (w *worker) pendingRewards() *ethash.BlockRewards {
+	w.pendingMu.RLock()
+	defer w.pendingMu.RUnlock()
+
+	var latest *task
+	for _, t := range w.pendingTasks {
+		if latest == nil || t.createdAt.After(latest.createdAt) {
+			latest = t
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	return latest.rewards
+}
+
+// workRewards returns the expected reward breakdown of the sealing task with
+// the given seal hash, or nil if the task is unknown or already stale.
+func (w *worker) workRewards(sealhash common.Hash) *ethash.BlockRewards {
+	w.pendingMu.RLock()
+	defer w.pendingMu.RUnlock()
+
+	if t, ok := w.pendingTasks[sealhash]; ok {
+		return t.rewards
+	}
+	return nil
 }
@@ -203,6 +203,47 @@ type Config struct {
 
 	// JWTSecret is the hex-encoded jwt secret.
 	JWTSecret string `toml:",omitempty"`
+
+	// BatchRequestLimit is the maximum number of requests in a batch. Larger
+	// batches are rejected as a whole. Zero means unlimited.
+	BatchRequestLimit int `toml:",omitempty"`
+
+	// BatchResponseMaxSize is the maximum number of response bytes a batch may
+	// produce. Batch execution stops once exceeded. Zero means unlimited.
+	BatchResponseMaxSize int `toml:",omitempty"`
+
+	// RPCAccessPolicy is the path of a JSON file restricting the methods available
+	// over HTTP and WebSocket and assigning per API key request quotas. Empty means
+	// unrestricted. IPC and the authenticated endpoints are never restricted.
+	RPCAccessPolicy string `toml:",omitempty"`
+
+	// RPCAuditLog is the location of the audit log recording every call served over
+	// IPC, HTTP and WebSocket. Relative paths are resolved in the instance directory.
+	// Empty means no auditing.
+	RPCAuditLog string `toml:",omitempty"`
+
+	// RPCAuditBackend selects how the audit log is stored: "file" writes rotating
+	// JSON files into the RPCAuditLog directory, "db" a key-value database.
+	RPCAuditBackend string `toml:",omitempty"`
+
+	// RPCAuditResponseHash enables recording the hash of every response.
+	RPCAuditResponseHash bool `toml:",omitempty"`
+
+	// RPCAuditMaxFileSize and RPCAuditMaxFiles limit the size of a single audit log
+	// file and the number of files kept. Zero means unlimited.
+	RPCAuditMaxFileSize int64 `toml:",omitempty"`
+	RPCAuditMaxFiles    int   `toml:",omitempty"`
+
+	// DBEngine is the key-value database engine, "leveldb" or "pebble". Empty means
+	// the engine of the existing databases, or leveldb for new ones.
+	DBEngine string `toml:",omitempty"`
+
+	// DBSecondary opens the databases read-only alongside another node, the
+	// primary, running on the same data directory. The data directory isn't
+	// locked, and the p2p node database is kept in memory. Only state the
+	// primary has flushed to disk is visible, so serving recent state requires
+	// the primary to run as an archive node.
+	DBSecondary bool `toml:",omitempty"`
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
@@ -232,8 +273,8 @@ func (c *Config) IPCEndpoint() string {
 
 // NodeDB returns the path to the discovery node database.
 func (c *Config) NodeDB() string {
-	if c.DataDir == "" {
-		return "" // ephemeral
+	if c.DataDir == "" || c.DBSecondary {
+		return "" // ephemeral, or owned by the primary
 	}
 	return c.ResolvePath(datadirNodeDatabase)
 }
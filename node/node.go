@@ -64,6 +64,8 @@ type Node struct {
 	ipc           *ipcServer  // Stores information about the ipc http server
 	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
 
+	rpcAudit rpc.AuditLog // Audit log of the calls served by the RPC endpoints, if enabled
+
 	databases map[*closeTrackingDB]struct{} // All open databases
 }
 
@@ -319,6 +321,9 @@ func (n *Node) openDataDir() error {
 		return nil // ephemeral
 	}
 
+	if n.config.DBSecondary {
+		return nil // locked by the primary
+	}
 	instdir := filepath.Join(n.config.DataDir, n.config.name())
 	if err := os.MkdirAll(instdir, 0700); err != nil {
 		return err
@@ -385,12 +390,38 @@ func (n *Node) startRPC() error {
 		return err
 	}
 
+	rpcConfig := rpcEndpointConfig{
+		batchItemLimit:         n.config.BatchRequestLimit,
+		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+	}
+	// The authenticated endpoints are never audited, the consensus client is trusted.
+	authConfig := rpcConfig
+
+	// Configure the audit log.
+	if n.config.RPCAuditLog != "" {
+		audit, err := n.openRPCAudit()
+		if err != nil {
+			return err
+		}
+		n.rpcAudit = audit
+		rpcConfig.auditLog = audit
+		rpcConfig.auditHashes = n.config.RPCAuditResponseHash
+	}
 	// Configure IPC.
 	if n.ipc.endpoint != "" {
-		if err := n.ipc.start(n.rpcAPIs); err != nil {
+		if err := n.ipc.start(n.rpcAPIs, rpcConfig); err != nil {
 			return err
 		}
 	}
+	// Restrict the public endpoints if an access policy is configured.
+	publicConfig := rpcConfig
+	if n.config.RPCAccessPolicy != "" {
+		policy, err := rpc.LoadAccessPolicy(n.config.RPCAccessPolicy)
+		if err != nil {
+			return err
+		}
+		publicConfig.accessPolicy = policy
+	}
 	var (
 		servers   []*httpServer
 		open, all = n.GetAPIs()
@@ -405,6 +436,7 @@ func (n *Node) startRPC() error {
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
 			prefix:             n.config.HTTPPathPrefix,
+			rpcEndpointConfig:  publicConfig,
 		}); err != nil {
 			return err
 		}
@@ -418,9 +450,10 @@ func (n *Node) startRPC() error {
 			return err
 		}
 		if err := server.enableWS(n.rpcAPIs, wsConfig{
-			Modules: n.config.WSModules,
-			Origins: n.config.WSOrigins,
-			prefix:  n.config.WSPathPrefix,
+			Modules:           n.config.WSModules,
+			Origins:           n.config.WSOrigins,
+			prefix:            n.config.WSPathPrefix,
+			rpcEndpointConfig: publicConfig,
 		}); err != nil {
 			return err
 		}
@@ -440,6 +473,7 @@ func (n *Node) startRPC() error {
 			Modules:            DefaultAuthModules,
 			prefix:             DefaultAuthPrefix,
 			jwtSecret:          secret,
+			rpcEndpointConfig:  authConfig,
 		}); err != nil {
 			return err
 		}
@@ -450,10 +484,11 @@ func (n *Node) startRPC() error {
 			return err
 		}
 		if err := server.enableWS(apis, wsConfig{
-			Modules:   DefaultAuthModules,
-			Origins:   DefaultAuthOrigins,
-			prefix:    DefaultAuthPrefix,
-			jwtSecret: secret,
+			Modules:           DefaultAuthModules,
+			Origins:           DefaultAuthOrigins,
+			prefix:            DefaultAuthPrefix,
+			jwtSecret:         secret,
+			rpcEndpointConfig: authConfig,
 		}); err != nil {
 			return err
 		}
@@ -512,6 +547,41 @@ func (n *Node) stopRPC() {
 	n.wsAuth.stop()
 	n.ipc.stop()
 	n.stopInProc()
+
+	if n.rpcAudit != nil {
+		if err := n.rpcAudit.Close(); err != nil {
+			n.log.Error("Failed to close RPC audit log", "err", err)
+		}
+		n.rpcAudit = nil
+	}
+}
+
+// openRPCAudit opens the configured audit log of the RPC endpoints.
+func (n *Node) openRPCAudit() (rpc.AuditLog, error) {
+	path := n.ResolvePath(n.config.RPCAuditLog)
+	if path == "" {
+		return nil, errors.New("relative RPC audit log path requires a data directory")
+	}
+	switch n.config.RPCAuditBackend {
+	case "", "file":
+		n.log.Info("Writing RPC audit log", "dir", path)
+		return rpc.OpenAuditFile(path, n.config.RPCAuditMaxFileSize, n.config.RPCAuditMaxFiles)
+	case "db":
+		db, err := rawdb.Open(rawdb.OpenOptions{
+			Type:      n.config.DBEngine,
+			Directory: path,
+			Namespace: "rpc/audit/",
+			Cache:     16,
+			Handles:   16,
+		})
+		if err != nil {
+			return nil, err
+		}
+		n.log.Info("Writing RPC audit log", "database", path)
+		return rpc.NewAuditDatabase(db), nil
+	default:
+		return nil, fmt.Errorf("unknown RPC audit log backend %q", n.config.RPCAuditBackend)
+	}
 }
 
 // startInProc registers all RPC APIs on the inproc server.
@@ -689,7 +759,15 @@ func (n *Node) OpenDatabase(name string, cache, handles int, namespace string, r
 	if n.config.DataDir == "" {
 		db = rawdb.NewMemoryDatabase()
 	} else {
-		db, err = rawdb.NewLevelDBDatabase(n.ResolvePath(name), cache, handles, namespace, readonly)
+		db, err = rawdb.Open(rawdb.OpenOptions{
+			Type:      n.config.DBEngine,
+			Directory: n.ResolvePath(name),
+			Namespace: namespace,
+			Cache:     cache,
+			Handles:   handles,
+			ReadOnly:  readonly,
+			Secondary: n.config.DBSecondary,
+		})
 	}
 
 	if err == nil {
@@ -715,7 +793,16 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient
 	if n.config.DataDir == "" {
 		db = rawdb.NewMemoryDatabase()
 	} else {
-		db, err = rawdb.NewLevelDBDatabaseWithFreezer(n.ResolvePath(name), cache, handles, n.ResolveAncient(name, ancient), namespace, readonly)
+		db, err = rawdb.Open(rawdb.OpenOptions{
+			Type:              n.config.DBEngine,
+			Directory:         n.ResolvePath(name),
+			AncientsDirectory: n.ResolveAncient(name, ancient),
+			Namespace:         namespace,
+			Cache:             cache,
+			Handles:           handles,
+			ReadOnly:          readonly,
+			Secondary:         n.config.DBSecondary,
+		})
 	}
 
 	if err == nil {
@@ -755,6 +842,11 @@ func (db *closeTrackingDB) Close() error {
 	return db.Database.Close()
 }
 
+// Refresh catches a secondary database up with the writes of the primary.
+func (db *closeTrackingDB) Refresh() error {
+	return rawdb.Refresh(db.Database)
+}
+
 // wrapDatabase ensures the database will be auto-closed when Node is closed.
 func (n *Node) wrapDatabase(db ethdb.Database) ethdb.Database {
 	wrapper := &closeTrackingDB{db, n}
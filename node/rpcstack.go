@@ -41,6 +41,7 @@ type httpConfig struct {
 	Vhosts             []string
 	prefix             string // path prefix on which to mount http handler
 	jwtSecret          []byte // optional JWT secret
+	rpcEndpointConfig
 }
 
 // wsConfig is the JSON-RPC/Websocket configuration
@@ -49,6 +50,17 @@ type wsConfig struct {
 	Modules   []string
 	prefix    string // path prefix on which to mount ws handler
 	jwtSecret []byte // optional JWT secret
+	rpcEndpointConfig
+}
+
+// rpcEndpointConfig contains the settings shared by all RPC endpoints.
+type rpcEndpointConfig struct {
+	batchItemLimit         int // maximum number of requests in a batch
+	batchResponseSizeLimit int // maximum number of response bytes of a batch
+
+	accessPolicy *rpc.AccessPolicy // method access and client quotas, nil = unrestricted
+	auditLog     rpc.AuditLog      // destination of the records of served calls, nil = none
+	auditHashes  bool              // whether to record the hash of every response
 }
 
 type rpcHandler struct {
@@ -219,9 +231,10 @@ func (h *httpServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 // checkPath checks whether a given request URL matches a given path prefix.
 func checkPath(r *http.Request, path string) bool {
-	// if no prefix has been specified, request URL must be on root
+	// if no prefix has been specified, request URL must be on root, optionally
+	// followed by the API key of the request
 	if path == "" {
-		return r.URL.Path == "/"
+		return r.URL.Path == "/" || strings.HasPrefix(r.URL.Path, rpc.APIKeyPathPrefix)
 	}
 	// otherwise, check to make sure prefix matches
 	return len(r.URL.Path) >= len(path) && r.URL.Path[:len(path)] == path
@@ -293,6 +306,11 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
+	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetAuditLog(config.auditLog, config.auditHashes)
+	if err := srv.SetAccessPolicy(config.accessPolicy); err != nil {
+		return err
+	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
@@ -324,6 +342,11 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	}
 	// Create RPC server and handler.
 	srv := rpc.NewServer()
+	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetAuditLog(config.auditLog, config.auditHashes)
+	if err := srv.SetAccessPolicy(config.accessPolicy); err != nil {
+		return err
+	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil {
 		return err
 	}
@@ -475,7 +498,8 @@ func (w *gzipResponseWriter) Write(b []byte) (int, error) {
 
 func newGzipHandler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		// Subscription streams must reach the client unbuffered.
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") || rpc.IsStreamRequest(r) {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -506,14 +530,17 @@ func newIPCServer(log log.Logger, endpoint string) *ipcServer {
 }
 
 // Start starts the httpServer's http.Server
-func (is *ipcServer) start(apis []rpc.API) error {
+func (is *ipcServer) start(apis []rpc.API, config rpcEndpointConfig) error {
 	is.mu.Lock()
 	defer is.mu.Unlock()
 
 	if is.listener != nil {
 		return nil // already running
 	}
-	listener, srv, err := rpc.StartIPCEndpoint(is.endpoint, apis)
+	srv := rpc.NewServer()
+	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetAuditLog(config.auditLog, config.auditHashes)
+	listener, err := srv.StartIPCEndpoint(is.endpoint, apis)
 	if err != nil {
 		is.log.Warn("IPC opening failed", "url", is.endpoint, "error", err)
 		return err
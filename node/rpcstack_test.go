@@ -212,6 +212,11 @@ func Test_checkPath(t *testing.T) {
 			prefix:   "",
 			expected: false,
 		},
+		{
+			req:      &http.Request{URL: &url.URL{Path: "/key/secret"}},
+			prefix:   "",
+			expected: true,
+		},
 		{
 			req:      &http.Request{URL: &url.URL{Path: "/"}},
 			prefix:   "/",
@@ -0,0 +1,331 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb"
+)
+
+const (
+	auditFilePrefix = "audit-"
+	auditFileSuffix = ".jsonl"
+)
+
+var (
+	// auditRedactedNamespaces are the namespaces whose call parameters are never
+	// recorded, as they carry passwords, keys or payloads to sign.
+	auditRedactedNamespaces = map[string]bool{
+		"personal": true,
+		"account":  true,
+		"clef":     true,
+	}
+	// auditRedactedMethods are the methods of other namespaces whose parameters
+	// are never recorded.
+	auditRedactedMethods = map[string]bool{
+		"eth_sign":             true,
+		"eth_signTransaction":  true,
+		"eth_signTypedData":    true,
+		"eth_signTypedData_v3": true,
+		"eth_signTypedData_v4": true,
+	}
+)
+
+// AuditRecord describes a single call served by a server.
+type AuditRecord struct {
+	Time         time.Time       `json:"time"`
+	Client       string          `json:"client"`    // IP address of the client
+	Transport    string          `json:"transport"` // Transport the call was served on
+	Method       string          `json:"method"`
+	Params       json.RawMessage `json:"params,omitempty"`
+	Redacted     bool            `json:"redacted,omitempty"` // Set if the params were withheld as sensitive
+	Duration     time.Duration   `json:"duration"`
+	ResponseSize int             `json:"responseSize"`           // Length of the JSON result
+	ErrorCode    int             `json:"errorCode,omitempty"`    // Code of the error response, if any
+	ResponseHash *common.Hash    `json:"responseHash,omitempty"` // SHA256 hash of the JSON result, if enabled
+}
+
+// AuditLog is the destination of the audit records of a server. Implementations
+// must be safe for concurrent use.
+type AuditLog interface {
+	Append(rec *AuditRecord) error
+	Close() error
+}
+
+// newAuditRecord creates the audit record of a served call. Notifications don't
+// have a response.
+func newAuditRecord(info PeerInfo, msg, resp *jsonrpcMessage, start time.Time, hash bool) *AuditRecord {
+	client, _, err := net.SplitHostPort(info.RemoteAddr)
+	if err != nil {
+		client = info.RemoteAddr
+	}
+	rec := &AuditRecord{
+		Time:      start,
+		Client:    client,
+		Transport: info.Transport,
+		Method:    msg.Method,
+		Params:    msg.Params,
+		Duration:  time.Since(start),
+	}
+	if auditRedacted(msg.Method) {
+		rec.Params, rec.Redacted = nil, true
+	}
+	if resp != nil {
+		rec.ResponseSize = len(resp.Result)
+		if resp.Error != nil {
+			rec.ErrorCode = resp.Error.Code
+		}
+		if hash {
+			h := AuditResponseHash(resp.Result)
+			rec.ResponseHash = &h
+		}
+	}
+	return rec
+}
+
+// auditRedacted reports whether the parameters of a method must not be recorded.
+func auditRedacted(method string) bool {
+	if auditRedactedMethods[method] {
+		return true
+	}
+	if i := strings.Index(method, serviceMethodSeparator); i > 0 {
+		return auditRedactedNamespaces[method[:i]]
+	}
+	return false
+}
+
+// AuditResponseHash returns the hash recorded in the audit log for a JSON result.
+func AuditResponseHash(result json.RawMessage) common.Hash {
+	return sha256.Sum256(result)
+}
+
+// AuditFile is an audit log writing the records as JSON lines into the files of
+// a directory. A new file is started once the current one exceeds the size limit,
+// deleting the oldest ones beyond the file limit.
+type AuditFile struct {
+	dir      string
+	maxSize  int64 // Maximum size of a single file, zero = unlimited
+	maxFiles int   // Maximum number of files kept, zero = unlimited
+
+	lock sync.Mutex
+	file *os.File
+	size int64
+}
+
+// OpenAuditFile creates an audit log in the given directory, starting a new file.
+func OpenAuditFile(dir string, maxSize int64, maxFiles int) (*AuditFile, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	af := &AuditFile{dir: dir, maxSize: maxSize, maxFiles: maxFiles}
+	if err := af.rotate(); err != nil {
+		return nil, err
+	}
+	return af, nil
+}
+
+// Append writes a record to the current file, rotating it if it grew too large.
+func (af *AuditFile) Append(rec *AuditRecord) error {
+	blob, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	af.lock.Lock()
+	defer af.lock.Unlock()
+
+	if af.file == nil {
+		return os.ErrClosed
+	}
+	if af.maxSize > 0 && af.size > 0 && af.size+int64(len(blob))+1 > af.maxSize {
+		if err := af.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := af.file.Write(append(blob, '\n'))
+	af.size += int64(n)
+	return err
+}
+
+// rotate closes the current file, if any, starts a new one and deletes the ones
+// exceeding the file limit.
+func (af *AuditFile) rotate() error {
+	if af.file != nil {
+		if err := af.file.Close(); err != nil {
+			return err
+		}
+		af.file = nil
+	}
+	name := fmt.Sprintf("%s%020d%s", auditFilePrefix, time.Now().UnixNano(), auditFileSuffix)
+	file, err := os.OpenFile(filepath.Join(af.dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	af.file, af.size = file, 0
+
+	if af.maxFiles > 0 {
+		files, err := auditFiles(af.dir)
+		if err != nil {
+			return err
+		}
+		for len(files) > af.maxFiles {
+			if err := os.Remove(files[0]); err != nil {
+				return err
+			}
+			files = files[1:]
+		}
+	}
+	return nil
+}
+
+// Close closes the current file.
+func (af *AuditFile) Close() error {
+	af.lock.Lock()
+	defer af.lock.Unlock()
+
+	if af.file == nil {
+		return nil
+	}
+	err := af.file.Close()
+	af.file = nil
+	return err
+}
+
+// auditFiles returns the audit log files of a directory, oldest first.
+func auditFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if name := entry.Name(); !entry.IsDir() && strings.HasPrefix(name, auditFilePrefix) && strings.HasSuffix(name, auditFileSuffix) {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ReadAuditFiles iterates over the records of an audit log in the order they were
+// written. The path is either a single file or an audit log directory.
+func ReadAuditFiles(path string, fn func(*AuditRecord) error) error {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	files := []string{path}
+	if stat.IsDir() {
+		if files, err = auditFiles(path); err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		if err := readAuditFile(file, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readAuditFile(path string, fn func(*AuditRecord) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		blob, err := r.ReadBytes('\n')
+		if err == io.EOF && len(blob) == 0 {
+			return nil
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+		rec := new(AuditRecord)
+		if err := json.Unmarshal(blob, rec); err != nil {
+			return fmt.Errorf("%s:%d: invalid audit record: %v", path, line, err)
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+}
+
+// AuditDatabase is an audit log storing the records in a key-value store, keyed
+// by the time they were served.
+type AuditDatabase struct {
+	db  ethdb.KeyValueStore
+	seq uint32
+}
+
+// NewAuditDatabase creates an audit log on top of a key-value store. The store is
+// owned by the log and closed together with it.
+func NewAuditDatabase(db ethdb.KeyValueStore) *AuditDatabase {
+	return &AuditDatabase{db: db}
+}
+
+// Append stores a record under its time, made unique by a sequence number.
+func (ad *AuditDatabase) Append(rec *AuditRecord) error {
+	blob, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	key := make([]byte, 12)
+	binary.BigEndian.PutUint64(key, uint64(rec.Time.UnixNano()))
+	binary.BigEndian.PutUint32(key[8:], atomic.AddUint32(&ad.seq, 1))
+	return ad.db.Put(key, blob)
+}
+
+// Close closes the underlying key-value store.
+func (ad *AuditDatabase) Close() error {
+	return ad.db.Close()
+}
+
+// ReadAuditDatabase iterates over the records of an audit database in the order
+// they were served.
+func ReadAuditDatabase(db ethdb.Iteratee, fn func(*AuditRecord) error) error {
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+
+	for it.Next() {
+		rec := new(AuditRecord)
+		if err := json.Unmarshal(it.Value(), rec); err != nil {
+			return fmt.Errorf("invalid audit record %x: %v", it.Key(), err)
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
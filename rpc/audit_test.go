@@ -0,0 +1,143 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/ethdb/memorydb"
+)
+
+// This test checks that audit files are rotated once exceeding the size limit, that
+// the oldest ones are deleted and that the remaining records are read in order.
+func TestAuditFileRotation(t *testing.T) {
+	dir := t.TempDir()
+	af, err := OpenAuditFile(dir, 200, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if err := af.Append(&AuditRecord{Method: fmt.Sprintf("test_%d", i)}); err != nil {
+			t.Fatalf("record %d: append failed: %v", i, err)
+		}
+	}
+	if err := af.Close(); err != nil {
+		t.Fatal(err)
+	}
+	files, err := auditFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("file count mismatch: have %d, want 3", len(files))
+	}
+	var methods []string
+	if err := ReadAuditFiles(dir, func(rec *AuditRecord) error {
+		methods = append(methods, rec.Method)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(methods) == 0 || methods[len(methods)-1] != "test_19" {
+		t.Fatalf("records mismatch: have %v", methods)
+	}
+	for i := 1; i < len(methods); i++ {
+		var prev, cur int
+		fmt.Sscanf(methods[i-1], "test_%d", &prev)
+		fmt.Sscanf(methods[i], "test_%d", &cur)
+		if cur != prev+1 {
+			t.Fatalf("records out of order: %v", methods)
+		}
+	}
+}
+
+// This test checks that the calls served by a server are recorded in its audit log.
+func TestServerAudit(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	audit := NewAuditDatabase(memorydb.New())
+	server.SetAuditLog(audit, true)
+
+	hs := httptest.NewServer(server)
+	defer hs.Close()
+	client, err := DialHTTP(hs.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var result echoResult
+	if err := client.Call(&result, "test_echo", "hello", 10, &echoArgs{"world"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(nil, "test_unknown"); err == nil {
+		t.Fatal("unknown method succeeded")
+	}
+	var recs []*AuditRecord
+	if err := ReadAuditDatabase(audit.db, func(rec *AuditRecord) error {
+		recs = append(recs, rec)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("record count mismatch: have %d, want 2", len(recs))
+	}
+	blob, _ := json.Marshal(result)
+	want := AuditResponseHash(blob)
+	echo := recs[0]
+	switch {
+	case echo.Method != "test_echo":
+		t.Errorf("method mismatch: have %s, want test_echo", echo.Method)
+	case string(echo.Params) != `["hello",10,{"S":"world"}]`:
+		t.Errorf("params mismatch: have %s", echo.Params)
+	case echo.Client != "127.0.0.1" || echo.Transport != "http":
+		t.Errorf("client mismatch: have %s over %s", echo.Client, echo.Transport)
+	case echo.ErrorCode != 0 || echo.ResponseSize != len(blob):
+		t.Errorf("response mismatch: have code %d, size %d", echo.ErrorCode, echo.ResponseSize)
+	case echo.ResponseHash == nil || *echo.ResponseHash != want:
+		t.Errorf("response hash mismatch: have %v, want %x", echo.ResponseHash, want)
+	case time.Since(echo.Time) > time.Minute:
+		t.Errorf("time mismatch: have %v", echo.Time)
+	}
+	if recs[1].Method != "test_unknown" || recs[1].ErrorCode != -32601 {
+		t.Errorf("failed call mismatch: have %s, code %d", recs[1].Method, recs[1].ErrorCode)
+	}
+}
+
+// This test checks that the parameters of sensitive calls are not recorded.
+func TestAuditRedaction(t *testing.T) {
+	info := PeerInfo{Transport: "ipc", RemoteAddr: "local"}
+	for method, redacted := range map[string]bool{
+		"personal_unlockAccount": true,
+		"account_signData":       true,
+		"eth_sign":               true,
+		"eth_signTransaction":    true,
+		"eth_getBalance":         false,
+		"personalized_call":      false,
+	} {
+		msg := &jsonrpcMessage{Method: method, Params: json.RawMessage(`["0x01","secret"]`)}
+		rec := newAuditRecord(info, msg, nil, time.Now(), false)
+		if rec.Redacted != redacted || (len(rec.Params) == 0) != redacted {
+			t.Errorf("%s: redaction mismatch: have %v (params %s), want %v", method, rec.Redacted, rec.Params, redacted)
+		}
+	}
+}
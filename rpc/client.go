@@ -36,6 +36,7 @@ var (
 	ErrSubscriptionQueueOverflow = errors.New("subscription queue overflow")
 	errClientReconnected         = errors.New("client reconnected")
 	errDead                      = errors.New("connection lost")
+	errMissingBatchResponse      = errors.New("response batch did not contain a response to this call")
 )
 
 const (
@@ -77,6 +78,9 @@ type Client struct {
 	isHTTP   bool      // connection type: http, ws or ipc
 	services *serviceRegistry
 
+	// Settings applied to calls received from the other end.
+	handlerConfig handlerConfig
+
 	idCounter uint32
 
 	// This function, if non-nil, is called when the connection is lost.
@@ -112,7 +116,7 @@ func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx := context.Background()
 	ctx = context.WithValue(ctx, clientContextKey{}, c)
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())
-	handler := newHandler(ctx, conn, c.idgen, c.services)
+	handler := newHandler(ctx, conn, c.idgen, c.services, c.handlerConfig)
 	return &clientConn{conn, handler}
 }
 
@@ -198,27 +202,28 @@ func newClient(initctx context.Context, connect reconnectFunc) (*Client, error)
 	if err != nil {
 		return nil, err
 	}
-	c := initClient(conn, randomIDGenerator(), new(serviceRegistry))
+	c := initClient(conn, randomIDGenerator(), new(serviceRegistry), handlerConfig{})
 	c.reconnectFunc = connect
 	return c, nil
 }
 
-func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry) *Client {
+func initClient(conn ServerCodec, idgen func() ID, services *serviceRegistry, config handlerConfig) *Client {
 	_, isHTTP := conn.(*httpConn)
 	c := &Client{
-		isHTTP:      isHTTP,
-		idgen:       idgen,
-		services:    services,
-		writeConn:   conn,
-		close:       make(chan struct{}),
-		closing:     make(chan struct{}),
-		didClose:    make(chan struct{}),
-		reconnected: make(chan ServerCodec),
-		readOp:      make(chan readOp),
-		readErr:     make(chan error),
-		reqInit:     make(chan *requestOp),
-		reqSent:     make(chan error, 1),
-		reqTimeout:  make(chan *requestOp),
+		isHTTP:        isHTTP,
+		idgen:         idgen,
+		services:      services,
+		handlerConfig: config,
+		writeConn:     conn,
+		close:         make(chan struct{}),
+		closing:       make(chan struct{}),
+		didClose:      make(chan struct{}),
+		reconnected:   make(chan ServerCodec),
+		readOp:        make(chan readOp),
+		readErr:       make(chan error),
+		reqInit:       make(chan *requestOp),
+		reqSent:       make(chan error, 1),
+		reqTimeout:    make(chan *requestOp),
 	}
 	if !isHTTP {
 		go c.dispatch(conn)
@@ -252,6 +257,8 @@ func (c *Client) SupportedModules() (map[string]string, error) {
 // Close closes the client, aborting any in-flight requests.
 func (c *Client) Close() {
 	if c.isHTTP {
+		// End the streamed subscriptions, if any.
+		c.writeConn.(*httpConn).close()
 		return
 	}
 	select {
@@ -440,7 +447,10 @@ func (c *Client) Subscribe(ctx context.Context, namespace string, channel interf
 		panic("channel given to Subscribe must not be nil")
 	}
 	if c.isHTTP {
-		return nil, ErrNotificationsUnsupported
+		if !c.writeConn.(*httpConn).streaming {
+			return nil, ErrNotificationsUnsupported
+		}
+		return c.subscribeStream(ctx, namespace, chanVal, args...)
 	}
 
 	msg, err := c.newMessage(namespace+subscribeMethodSuffix, args...)
@@ -144,6 +144,85 @@ func TestClientBatchRequest(t *testing.T) {
 	}
 }
 
+func TestClientBatchLimitsWebsocket(t *testing.T) { testClientBatchLimits("ws", t) }
+func TestClientBatchLimitsHTTP(t *testing.T)      { testClientBatchLimits("http", t) }
+func TestClientBatchLimitsIPC(t *testing.T)       { testClientBatchLimits("ipc", t) }
+
+// This test checks that batches exceeding the request limit are rejected as a whole
+// and that batches exceeding the response size limit are cut short.
+func testClientBatchLimits(transport string, t *testing.T) {
+	server := newTestServer()
+	server.SetBatchLimits(3, 60)
+	defer server.Stop()
+
+	var client *Client
+	switch transport {
+	case "ws", "http":
+		c, hs := httpTestClient(server, transport, nil)
+		defer hs.Close()
+		client = c
+	case "ipc":
+		c, l := ipcTestClient(server, nil)
+		defer l.Close()
+		client = c
+	default:
+		panic("unknown transport: " + transport)
+	}
+	defer client.Close()
+
+	makeBatch := func(n int) []BatchElem {
+		batch := make([]BatchElem, n)
+		for i := range batch {
+			batch[i] = BatchElem{
+				Method: "test_echo",
+				Args:   []interface{}{"hello", i, &echoArgs{"world"}},
+				Result: new(echoResult),
+			}
+		}
+		return batch
+	}
+	errorCode := func(err error) int {
+		if err, ok := err.(Error); ok {
+			return err.ErrorCode()
+		}
+		return 0
+	}
+	// A batch within both limits is fully executed
+	batch := makeBatch(1)
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	if batch[0].Error != nil {
+		t.Errorf("small batch failed: %v", batch[0].Error)
+	}
+	// A batch over the request limit is rejected with a single error
+	batch = makeBatch(4)
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	if code := errorCode(batch[0].Error); code != -32004 {
+		t.Errorf("oversized batch error code mismatch: have %d, want %d (%v)", code, -32004, batch[0].Error)
+	}
+	for i, elem := range batch[1:] {
+		if elem.Error == nil || elem.Error.Error() != errMissingBatchResponse.Error() {
+			t.Errorf("oversized batch call %d error mismatch: have %v, want %v", i+1, elem.Error, errMissingBatchResponse)
+		}
+	}
+	// A batch over the response size limit is cut short once exceeded
+	batch = makeBatch(3)
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	if batch[0].Error != nil {
+		t.Errorf("first call of large batch failed: %v", batch[0].Error)
+	}
+	for i, elem := range batch[1:] {
+		if code := errorCode(elem.Error); code != -32003 {
+			t.Errorf("large batch call %d error code mismatch: have %d, want %d (%v)", i+1, code, -32003, elem.Error)
+		}
+	}
+}
+
 func TestClientNotify(t *testing.T) {
 	server := newTestServer()
 	defer server.Stop()
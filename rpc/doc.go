@@ -99,6 +99,12 @@ Subscriptions are deleted when the user sends an unsubscribe request or when the
 connection which was used to create the subscription is closed. This can be initiated by
 the client and server. The server will close the connection for any write error.
 
+Plain HTTP connections don't support notifications. Instead, a single subscribe request
+can be sent with an "Accept: text/event-stream" header, in which case the server responds
+with a stream of server-sent events carrying the subscription response followed by its
+notifications. The subscription ends when the stream is closed. Clients created by
+DialSSE use such streams for all subscriptions.
+
 For more information about subscriptions, see https://github.com/ethereum/go-ethereum/wiki/RPC-PUB-SUB.
 
 Reverse Calls
@@ -106,5 +112,12 @@ Reverse Calls
 In any method handler, an instance of rpc.Client can be accessed through the
 ClientFromContext method. Using this client instance, server-to-client method calls can be
 performed on the RPC connection.
+
+Discovery
+
+The server describes its methods in an OpenRPC document, served at "rpc_discover". The
+parameter and result schemas are derived from the Go types of the methods. Services can
+annotate their methods with summaries and parameter names by implementing
+DocumentedService.
 */
 package rpc
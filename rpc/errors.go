@@ -54,6 +54,10 @@ var (
 	_ Error = new(invalidRequestError)
 	_ Error = new(invalidMessageError)
 	_ Error = new(invalidParamsError)
+	_ Error = new(batchTooLargeError)
+	_ Error = new(responseTooLargeError)
+	_ Error = new(quotaExceededError)
+	_ Error = new(unauthorizedError)
 )
 
 const defaultErrorCode = -32000
@@ -101,3 +105,33 @@ type invalidParamsError struct{ message string }
 func (e *invalidParamsError) ErrorCode() int { return -32602 }
 
 func (e *invalidParamsError) Error() string { return e.message }
+
+// batch contains more requests than the server allows
+type batchTooLargeError struct{ limit int }
+
+func (e *batchTooLargeError) ErrorCode() int { return -32004 }
+
+func (e *batchTooLargeError) Error() string {
+	return fmt.Sprintf("batch too large, limit is %d requests", e.limit)
+}
+
+// responses of a batch exceed the size the server allows
+type responseTooLargeError struct{}
+
+func (e *responseTooLargeError) ErrorCode() int { return -32003 }
+
+func (e *responseTooLargeError) Error() string { return "batch response too large" }
+
+// client exceeded the request or compute unit rate of its quota
+type quotaExceededError struct{}
+
+func (e *quotaExceededError) ErrorCode() int { return -32005 }
+
+func (e *quotaExceededError) Error() string { return "request quota exceeded" }
+
+// client presented no API key or an unknown one
+type unauthorizedError struct{}
+
+func (e *unauthorizedError) ErrorCode() int { return -32006 }
+
+func (e *unauthorizedError) Error() string { return "missing or unknown API key" }
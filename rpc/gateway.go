@@ -0,0 +1,757 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
+	"github.com/Altcoinchain/go-altcoinchain/log"
+)
+
+var (
+	errNoUpstream      = errors.New("no upstream available")
+	errUnknownFilter   = errors.New("filter not found")
+	errGatewayEndpoint = errors.New("gateway needs at least one endpoint")
+)
+
+// GatewayConfig contains the settings of a multi-upstream client.
+type GatewayConfig struct {
+	// MaxLag is the number of blocks an upstream may lag behind the most recent
+	// head seen on any upstream before calls are routed away from it.
+	MaxLag uint64
+
+	// Retries is the number of upstreams a read call is attempted on
+	// before its error is returned.
+	Retries int
+
+	// ProbeInterval is how often head numbers are polled from upstreams without
+	// notification support, and how long to wait before reconnecting to a
+	// failed upstream.
+	ProbeInterval time.Duration
+}
+
+// DefaultGatewayConfig contains the default settings of multi-upstream clients.
+var DefaultGatewayConfig = GatewayConfig{
+	MaxLag:        2,
+	Retries:       3,
+	ProbeInterval: 5 * time.Second,
+}
+
+// gatewayFilterTimeout is how long a filter is tracked without being polled,
+// matching the time after which upstreams uninstall it.
+const gatewayFilterTimeout = 5 * time.Minute
+
+var (
+	// gatewayReadMethods are read-only calls, which are load-balanced and retried
+	// on failure. All other methods are sent to a single upstream, the primary,
+	// and never retried: they may change the state of the upstream, e.g. unlock
+	// an account or send a transaction, and sending them all to the same node
+	// preserves their order.
+	gatewayReadMethods = map[string]bool{
+		"web3_clientVersion":                      true,
+		"web3_sha3":                               true,
+		"net_version":                             true,
+		"net_listening":                           true,
+		"net_peerCount":                           true,
+		"eth_protocolVersion":                     true,
+		"eth_chainId":                             true,
+		"eth_syncing":                             true,
+		"eth_blockNumber":                         true,
+		"eth_gasPrice":                            true,
+		"eth_maxPriorityFeePerGas":                true,
+		"eth_feeHistory":                          true,
+		"eth_getBalance":                          true,
+		"eth_getCode":                             true,
+		"eth_getStorageAt":                        true,
+		"eth_getProof":                            true,
+		"eth_getTransactionCount":                 true,
+		"eth_call":                                true,
+		"eth_estimateGas":                         true,
+		"eth_createAccessList":                    true,
+		"eth_getBlockByHash":                      true,
+		"eth_getBlockByNumber":                    true,
+		"eth_getHeaderByHash":                     true,
+		"eth_getHeaderByNumber":                   true,
+		"eth_getBlockTransactionCountByHash":      true,
+		"eth_getBlockTransactionCountByNumber":    true,
+		"eth_getUncleByBlockHashAndIndex":         true,
+		"eth_getUncleByBlockNumberAndIndex":       true,
+		"eth_getUncleCountByBlockHash":            true,
+		"eth_getUncleCountByBlockNumber":          true,
+		"eth_getTransactionByHash":                true,
+		"eth_getTransactionByBlockHashAndIndex":   true,
+		"eth_getTransactionByBlockNumberAndIndex": true,
+		"eth_getRawTransactionByHash":             true,
+		"eth_getTransactionReceipt":               true,
+		"eth_getLogs":                             true,
+	}
+	// gatewayFilterMethods create filters, whose later polls have to be routed to
+	// the upstream that created them.
+	gatewayFilterMethods = map[string]bool{
+		"eth_newFilter":                   true,
+		"eth_newBlockFilter":              true,
+		"eth_newPendingTransactionFilter": true,
+	}
+	// gatewayFilterPollMethods take a filter ID as their first parameter.
+	gatewayFilterPollMethods = map[string]bool{
+		"eth_getFilterChanges": true,
+		"eth_getFilterLogs":    true,
+		"eth_uninstallFilter":  true,
+	}
+)
+
+// DialGateway creates a client spreading its calls over several endpoints.
+//
+// Read calls are load-balanced over the upstreams whose head is within MaxLag
+// blocks of the most recent head seen, which is tracked through newHeads
+// subscriptions, or by polling on endpoints without notification support. Calls
+// failing with a transport error are retried on another upstream. All other
+// calls, e.g. transactions, signing or admin calls, are always sent to the same
+// upstream and never retried. Filters and subscriptions stay on the upstream
+// that created them. Subscriptions are moved to another upstream if theirs
+// fails.
+//
+// The returned client can be used like any other, e.g. by ethclient.
+func DialGateway(ctx context.Context, endpoints []string, config GatewayConfig) (*Client, error) {
+	if len(endpoints) == 0 {
+		return nil, errGatewayEndpoint
+	}
+	if config.Retries <= 0 {
+		config.Retries = DefaultGatewayConfig.Retries
+	}
+	if config.ProbeInterval <= 0 {
+		config.ProbeInterval = DefaultGatewayConfig.ProbeInterval
+	}
+	g := newGateway(config)
+	var (
+		dialed  int
+		lastErr error
+	)
+	for _, url := range endpoints {
+		u := &gatewayUpstream{url: url}
+		if client, err := DialContext(ctx, url); err != nil {
+			log.Warn("Failed to dial RPC upstream", "url", url, "err", err)
+			lastErr = err
+		} else {
+			u.client = client
+			dialed++
+		}
+		g.upstreams = append(g.upstreams, u)
+	}
+	if dialed == 0 {
+		g.close()
+		return nil, lastErr
+	}
+	for _, u := range g.upstreams {
+		g.wg.Add(1)
+		go g.track(u)
+	}
+	return initClient(g, randomIDGenerator(), new(serviceRegistry), handlerConfig{}), nil
+}
+
+// gatewayUpstream is an endpoint behind a gateway. Its fields are protected by
+// the lock of the gateway.
+type gatewayUpstream struct {
+	url     string
+	client  *Client // nil until dialed
+	head    uint64  // most recent head number
+	healthy bool    // set when a head is received, cleared on failures
+}
+
+// gatewaySub is a subscription created through the gateway.
+type gatewaySub struct {
+	id        ID
+	namespace string
+	args      []interface{}
+	ch        chan json.RawMessage
+	quit      chan struct{}
+
+	// The current upstream subscription, only accessed by the forwarding loop
+	// once the subscription is running.
+	upstream *gatewayUpstream
+	csub     *ClientSubscription
+}
+
+// gatewayFilter is a filter created through the gateway.
+type gatewayFilter struct {
+	upstream *gatewayUpstream // Upstream that created the filter
+	polled   time.Time        // Last time the filter was polled
+}
+
+// gateway routes the calls of a client to its upstreams. It acts as the
+// connection of the client: messages written by the client are forwarded, and
+// the responses are handed back to the client's read loop.
+type gateway struct {
+	config GatewayConfig
+	idgen  func() ID
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	incoming  chan *jsonrpcMessage
+	closeCh   chan interface{}
+	closeOnce sync.Once
+
+	mu        sync.Mutex
+	upstreams []*gatewayUpstream
+	next      int                       // round-robin position
+	primary   *gatewayUpstream          // receives all but the read calls
+	filters   map[string]*gatewayFilter // creators of filters
+	subs      map[ID]*gatewaySub
+	closing   bool // set once close starts, no goroutines may be added after
+}
+
+func newGateway(config GatewayConfig) *gateway {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &gateway{
+		config:   config,
+		idgen:    randomIDGenerator(),
+		ctx:      ctx,
+		cancel:   cancel,
+		incoming: make(chan *jsonrpcMessage),
+		closeCh:  make(chan interface{}),
+		filters:  make(map[string]*gatewayFilter),
+		subs:     make(map[ID]*gatewaySub),
+	}
+}
+
+func (g *gateway) peerInfo() PeerInfo {
+	return PeerInfo{Transport: "gateway", RemoteAddr: g.remoteAddr()}
+}
+
+func (g *gateway) remoteAddr() string {
+	urls := make([]string, len(g.upstreams))
+	for i, u := range g.upstreams {
+		urls[i] = u.url
+	}
+	return strings.Join(urls, ",")
+}
+
+func (g *gateway) readBatch() ([]*jsonrpcMessage, bool, error) {
+	select {
+	case msg := <-g.incoming:
+		return []*jsonrpcMessage{msg}, false, nil
+	case <-g.closeCh:
+		return nil, false, ErrClientQuit
+	}
+}
+
+// writeJSON forwards the messages written by the client. The responses are
+// delivered asynchronously, batches are answered one call at a time.
+func (g *gateway) writeJSON(ctx context.Context, v interface{}) error {
+	select {
+	case <-g.closeCh:
+		return ErrClientQuit
+	default:
+	}
+	switch msg := v.(type) {
+	case *jsonrpcMessage:
+		go g.handle(ctx, msg)
+	case []*jsonrpcMessage:
+		for _, m := range msg {
+			go g.handle(ctx, m)
+		}
+	default:
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	return nil
+}
+
+func (g *gateway) closed() <-chan interface{} {
+	return g.closeCh
+}
+
+// close shuts down the gateway along with its upstream connections.
+func (g *gateway) close() {
+	g.closeOnce.Do(func() {
+		g.mu.Lock()
+		g.closing = true
+		g.mu.Unlock()
+
+		close(g.closeCh)
+		g.cancel()
+		g.wg.Wait()
+		for _, u := range g.upstreams {
+			if u.client != nil {
+				u.client.Close()
+			}
+		}
+	})
+}
+
+// deliver hands a message to the read loop of the client.
+func (g *gateway) deliver(msg *jsonrpcMessage) {
+	select {
+	case g.incoming <- msg:
+	case <-g.closeCh:
+	}
+}
+
+// handle forwards a single message written by the client.
+func (g *gateway) handle(ctx context.Context, msg *jsonrpcMessage) {
+	if msg.isNotification() {
+		if u := g.pick(nil); u != nil {
+			if args, err := gatewayArgs(msg.Params); err == nil {
+				u.client.Notify(ctx, msg.Method, args...)
+			}
+		}
+		return
+	}
+	if !msg.isCall() {
+		return // responses to calls made by upstreams are not supported
+	}
+	switch {
+	case msg.isSubscribe():
+		resp, sub := g.subscribe(ctx, msg)
+		g.deliver(resp)
+		if sub != nil {
+			// The forwarding starts once the client knows the subscription ID, so
+			// that no notification gets ahead of it.
+			g.mu.Lock()
+			if g.closing {
+				g.mu.Unlock()
+				sub.csub.Unsubscribe()
+				return
+			}
+			g.wg.Add(1)
+			g.mu.Unlock()
+			go g.forwardSub(sub)
+		}
+	case msg.isUnsubscribe():
+		g.deliver(g.unsubscribe(msg))
+	default:
+		g.deliver(g.call(ctx, msg))
+	}
+}
+
+// call forwards a method call, choosing its upstream based on the method.
+func (g *gateway) call(ctx context.Context, msg *jsonrpcMessage) *jsonrpcMessage {
+	args, err := gatewayArgs(msg.Params)
+	if err != nil {
+		return msg.errorResponse(&invalidParamsError{err.Error()})
+	}
+	switch {
+	case gatewayFilterPollMethods[msg.Method]:
+		var id string
+		if len(args) > 0 {
+			json.Unmarshal(args[0].(json.RawMessage), &id)
+		}
+		g.mu.Lock()
+		var u *gatewayUpstream
+		if filter := g.filters[id]; filter != nil {
+			u, filter.polled = filter.upstream, time.Now()
+		}
+		if msg.Method == "eth_uninstallFilter" {
+			delete(g.filters, id)
+		}
+		g.mu.Unlock()
+		if u == nil {
+			return msg.errorResponse(errUnknownFilter)
+		}
+		resp, err := g.forward(ctx, u, msg, args)
+		if err != nil {
+			g.fail(u, err)
+			return msg.errorResponse(err)
+		}
+		return resp
+
+	case !gatewayReadMethods[msg.Method] && !gatewayFilterMethods[msg.Method]:
+		u := g.pickPrimary()
+		if u == nil {
+			return msg.errorResponse(errNoUpstream)
+		}
+		resp, err := g.forward(ctx, u, msg, args)
+		if err != nil {
+			g.fail(u, err)
+			return msg.errorResponse(err)
+		}
+		return resp
+	}
+
+	// Read calls are idempotent and retried on failure.
+	var (
+		tried   = make(map[*gatewayUpstream]bool)
+		lastErr = errNoUpstream
+	)
+	for i := 0; i < g.config.Retries; i++ {
+		u := g.pick(tried)
+		if u == nil {
+			break
+		}
+		tried[u] = true
+		resp, err := g.forward(ctx, u, msg, args)
+		if err == nil {
+			if gatewayFilterMethods[msg.Method] && resp.Error == nil {
+				var id string
+				if json.Unmarshal(resp.Result, &id) == nil {
+					g.addFilter(id, u)
+				}
+			}
+			return resp
+		}
+		if ctx.Err() != nil {
+			return msg.errorResponse(ctx.Err())
+		}
+		log.Debug("RPC upstream call failed", "url", u.url, "method", msg.Method, "err", err)
+		g.fail(u, err)
+		lastErr = err
+	}
+	return msg.errorResponse(lastErr)
+}
+
+// addFilter records the upstream that created a filter, dropping the filters
+// which weren't polled for long enough to be uninstalled by their upstream.
+func (g *gateway) addFilter(id string, u *gatewayUpstream) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	for fid, filter := range g.filters {
+		if now.Sub(filter.polled) > gatewayFilterTimeout {
+			delete(g.filters, fid)
+		}
+	}
+	g.filters[id] = &gatewayFilter{upstream: u, polled: now}
+}
+
+// forward executes a call on an upstream. Errors returned by the upstream are
+// part of the response, only transport errors are returned.
+func (g *gateway) forward(ctx context.Context, u *gatewayUpstream, msg *jsonrpcMessage, args []interface{}) (*jsonrpcMessage, error) {
+	var result json.RawMessage
+	err := u.client.CallContext(ctx, &result, msg.Method, args...)
+	if err == nil {
+		if result == nil {
+			result = null
+		}
+		return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: result}, nil
+	}
+	if _, ok := err.(Error); ok {
+		return msg.errorResponse(err), nil
+	}
+	return nil, err
+}
+
+// subscribe creates a subscription on an upstream.
+func (g *gateway) subscribe(ctx context.Context, msg *jsonrpcMessage) (*jsonrpcMessage, *gatewaySub) {
+	args, err := gatewayArgs(msg.Params)
+	if err != nil {
+		return msg.errorResponse(&invalidParamsError{err.Error()}), nil
+	}
+	sub := &gatewaySub{
+		id:        g.idgen(),
+		namespace: msg.namespace(),
+		args:      args,
+		ch:        make(chan json.RawMessage),
+		quit:      make(chan struct{}),
+	}
+	if err := g.startSub(ctx, sub); err != nil {
+		return msg.errorResponse(err), nil
+	}
+	g.mu.Lock()
+	g.subs[sub.id] = sub
+	g.mu.Unlock()
+	return msg.response(sub.id), sub
+}
+
+// startSub subscribes on the first upstream accepting the subscription.
+func (g *gateway) startSub(ctx context.Context, sub *gatewaySub) error {
+	var (
+		tried   = make(map[*gatewayUpstream]bool)
+		lastErr = errNoUpstream
+	)
+	for i := 0; i < g.config.Retries; i++ {
+		u := g.pick(tried)
+		if u == nil {
+			break
+		}
+		tried[u] = true
+		csub, err := u.client.Subscribe(ctx, sub.namespace, sub.ch, sub.args...)
+		if err == nil {
+			sub.upstream, sub.csub = u, csub
+			return nil
+		}
+		if _, ok := err.(Error); ok {
+			return err // rejected by the upstream, e.g. unknown subscription
+		}
+		if err != ErrNotificationsUnsupported {
+			g.fail(u, err)
+		}
+		lastErr = err
+	}
+	return lastErr
+}
+
+// forwardSub delivers the notifications of a subscription to the client, moving
+// the subscription to another upstream if its upstream fails.
+func (g *gateway) forwardSub(sub *gatewaySub) {
+	defer g.wg.Done()
+
+	for {
+		select {
+		case result := <-sub.ch:
+			params, _ := json.Marshal(&subscriptionResult{ID: string(sub.id), Result: result})
+			g.deliver(&jsonrpcMessage{Version: vsn, Method: sub.namespace + notificationMethodSuffix, Params: params})
+
+		case err := <-sub.csub.Err():
+			if g.ctx.Err() != nil {
+				return
+			}
+			log.Debug("RPC upstream subscription failed", "url", sub.upstream.url, "id", sub.id, "err", err)
+			g.fail(sub.upstream, err)
+			for g.startSub(g.ctx, sub) != nil {
+				select {
+				case <-time.After(g.config.ProbeInterval):
+				case <-sub.quit:
+					return
+				case <-g.ctx.Done():
+					return
+				}
+			}
+
+		case <-sub.quit:
+			sub.csub.Unsubscribe()
+			return
+
+		case <-g.ctx.Done():
+			return
+		}
+	}
+}
+
+// unsubscribe ends a subscription created through the gateway.
+func (g *gateway) unsubscribe(msg *jsonrpcMessage) *jsonrpcMessage {
+	var args []ID
+	if err := json.Unmarshal(msg.Params, &args); err != nil || len(args) != 1 {
+		return msg.errorResponse(&invalidParamsError{"expected subscription id as first argument"})
+	}
+	g.mu.Lock()
+	sub := g.subs[args[0]]
+	delete(g.subs, args[0])
+	g.mu.Unlock()
+	if sub == nil {
+		return msg.errorResponse(ErrSubscriptionNotFound)
+	}
+	close(sub.quit)
+	return msg.response(true)
+}
+
+// pick chooses the upstream for a read call among the ones not tried yet. It
+// prefers healthy upstreams in sync with the most recent head, then lagging
+// ones, then the ones that failed recently.
+func (g *gateway) pick(tried map[*gatewayUpstream]bool) *gatewayUpstream {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var synced, lagging, failed []*gatewayUpstream
+	best := g.bestHead()
+	for _, u := range g.upstreams {
+		switch {
+		case tried[u] || u.client == nil:
+		case u.healthy && u.head+g.config.MaxLag >= best:
+			synced = append(synced, u)
+		case u.healthy:
+			lagging = append(lagging, u)
+		default:
+			failed = append(failed, u)
+		}
+	}
+	for _, candidates := range [][]*gatewayUpstream{synced, lagging, failed} {
+		if len(candidates) > 0 {
+			g.next++
+			return candidates[g.next%len(candidates)]
+		}
+	}
+	return nil
+}
+
+// pickPrimary returns the upstream receiving all but the read calls. The primary only
+// changes when it fails or falls behind.
+func (g *gateway) pickPrimary() *gatewayUpstream {
+	g.mu.Lock()
+	best := g.bestHead()
+	if p := g.primary; p != nil && p.healthy && p.head+g.config.MaxLag >= best {
+		g.mu.Unlock()
+		return p
+	}
+	for _, u := range g.upstreams {
+		if u.client != nil && u.healthy && u.head+g.config.MaxLag >= best {
+			g.primary = u
+			g.mu.Unlock()
+			return u
+		}
+	}
+	g.mu.Unlock()
+
+	// No upstream is in sync, fall back to any.
+	u := g.pick(nil)
+	g.mu.Lock()
+	g.primary = u
+	g.mu.Unlock()
+	return u
+}
+
+// bestHead returns the most recent head number of the healthy upstreams. It
+// must be called with the lock held.
+func (g *gateway) bestHead() uint64 {
+	var best uint64
+	for _, u := range g.upstreams {
+		if u.healthy && u.head > best {
+			best = u.head
+		}
+	}
+	return best
+}
+
+// fail marks an upstream as failed until its next head is received.
+func (g *gateway) fail(u *gatewayUpstream, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if u.healthy {
+		log.Info("RPC upstream failed", "url", u.url, "err", err)
+	}
+	u.healthy = false
+}
+
+// setHead records the head of an upstream, marking it healthy.
+func (g *gateway) setHead(u *gatewayUpstream, number uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !u.healthy {
+		log.Debug("RPC upstream available", "url", u.url, "head", number)
+	}
+	u.head, u.healthy = number, true
+}
+
+// track follows the head of an upstream until the gateway is closed.
+func (g *gateway) track(u *gatewayUpstream) {
+	defer g.wg.Done()
+
+	for {
+		if err := g.followHeads(u); err != nil {
+			g.fail(u, err)
+		}
+		select {
+		case <-time.After(g.config.ProbeInterval):
+		case <-g.ctx.Done():
+			return
+		}
+	}
+}
+
+// gatewayHead is the part of the newHeads notifications used by the gateway.
+type gatewayHead struct {
+	Number hexutil.Uint64 `json:"number"`
+}
+
+// followHeads updates the head of an upstream through a newHeads subscription,
+// or by polling if the upstream doesn't support subscriptions. It returns when
+// the upstream fails.
+func (g *gateway) followHeads(u *gatewayUpstream) error {
+	g.mu.Lock()
+	client := u.client
+	g.mu.Unlock()
+	if client == nil {
+		var err error
+		if client, err = DialContext(g.ctx, u.url); err != nil {
+			return err
+		}
+		g.mu.Lock()
+		u.client = client
+		g.mu.Unlock()
+	}
+	if err := g.pollHead(u); err != nil {
+		return err
+	}
+	heads := make(chan *gatewayHead, 16)
+	sub, err := client.EthSubscribe(g.ctx, heads, "newHeads")
+	if err == ErrNotificationsUnsupported {
+		return g.pollHeads(u)
+	}
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case head := <-heads:
+			g.setHead(u, uint64(head.Number))
+		case err := <-sub.Err():
+			if err == nil {
+				err = errDead
+			}
+			return err
+		case <-g.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// pollHeads updates the head of an upstream periodically.
+func (g *gateway) pollHeads(u *gatewayUpstream) error {
+	ticker := time.NewTicker(g.config.ProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := g.pollHead(u); err != nil {
+				return err
+			}
+		case <-g.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// pollHead fetches the head number of an upstream.
+func (g *gateway) pollHead(u *gatewayUpstream) error {
+	ctx, cancel := context.WithTimeout(g.ctx, g.config.ProbeInterval)
+	defer cancel()
+
+	var number hexutil.Uint64
+	if err := u.client.CallContext(ctx, &number, "eth_blockNumber"); err != nil {
+		return err
+	}
+	g.setHead(u, uint64(number))
+	return nil
+}
+
+// gatewayArgs splits the positional parameters of a call.
+func gatewayArgs(params json.RawMessage) ([]interface{}, error) {
+	if len(params) == 0 || string(params) == "null" {
+		return nil, nil
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(params, &raw); err != nil {
+		return nil, errors.New("non-array args")
+	}
+	args := make([]interface{}, len(raw))
+	for i := range raw {
+		args[i] = raw[i]
+	}
+	return args, nil
+}
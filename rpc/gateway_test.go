@@ -0,0 +1,268 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
+)
+
+// gatewayTestService simulates the eth namespace of a node.
+type gatewayTestService struct {
+	name string
+	head uint64
+}
+
+func (s *gatewayTestService) BlockNumber() hexutil.Uint64 { return hexutil.Uint64(s.head) }
+
+func (s *gatewayTestService) Name() string { return s.name }
+
+func (s *gatewayTestService) ClientVersion() string { return s.name }
+
+func (s *gatewayTestService) SendRawTransaction(tx hexutil.Bytes) string { return s.name }
+
+func (s *gatewayTestService) NewBlockFilter() string { return s.name + "-filter" }
+
+func (s *gatewayTestService) GetFilterChanges(id string) (string, error) {
+	if !strings.HasPrefix(id, s.name) {
+		return "", errors.New("filter not found")
+	}
+	return s.name, nil
+}
+
+func (s *gatewayTestService) NewHeads(ctx context.Context) (*Subscription, error) {
+	notifier, _ := NotifierFromContext(ctx)
+	return notifier.CreateSubscription(), nil
+}
+
+func (s *gatewayTestService) Ticks(ctx context.Context) (*Subscription, error) {
+	notifier, supported := NotifierFromContext(ctx)
+	if !supported {
+		return nil, ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for i := 0; ; i++ {
+			if notifier.Notify(sub.ID, s.name) != nil {
+				return
+			}
+			select {
+			case <-time.After(10 * time.Millisecond):
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+// startGatewayUpstreams starts an upstream served over websocket and another one
+// served over HTTP.
+func startGatewayUpstreams(t *testing.T, wsHead, httpHead uint64) (ws, http *httptest.Server) {
+	start := func(name string, head uint64) *Server {
+		server := NewServer()
+		service := &gatewayTestService{name, head}
+		if err := server.RegisterName("eth", service); err != nil {
+			t.Fatal(err)
+		}
+		if err := server.RegisterName("web3", service); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(server.Stop)
+		return server
+	}
+	ws = httptest.NewServer(start("ws", wsHead).WebsocketHandler([]string{"*"}))
+	http = httptest.NewServer(start("http", httpHead))
+	t.Cleanup(ws.Close)
+	t.Cleanup(http.Close)
+	return ws, http
+}
+
+func dialTestGateway(t *testing.T, ws, http *httptest.Server) (*Client, *gateway) {
+	config := GatewayConfig{MaxLag: 2, Retries: 2, ProbeInterval: 50 * time.Millisecond}
+	client, err := DialGateway(context.Background(), []string{"ws" + strings.TrimPrefix(ws.URL, "http"), http.URL}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(client.Close)
+	g := client.writeConn.(*gateway)
+
+	// Wait for the heads of both upstreams
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		g.mu.Lock()
+		healthy := g.upstreams[0].healthy && g.upstreams[1].healthy
+		g.mu.Unlock()
+		if healthy {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("upstream heads not received")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return client, g
+}
+
+// callCounts returns how many of n calls to the given method were served by each
+// upstream.
+func callCounts(t *testing.T, client *Client, method string, n int) map[string]int {
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		var name string
+		if err := client.Call(&name, method); err != nil {
+			t.Fatalf("call %d failed: %v", i, err)
+		}
+		counts[name]++
+	}
+	return counts
+}
+
+func TestGatewayLoadBalance(t *testing.T) {
+	ws, http := startGatewayUpstreams(t, 100, 99)
+	client, _ := dialTestGateway(t, ws, http)
+
+	counts := callCounts(t, client, "web3_clientVersion", 10)
+	if counts["ws"] != 5 || counts["http"] != 5 {
+		t.Errorf("calls not balanced: %v", counts)
+	}
+	// Batches are spread over the upstreams as well
+	batch := make([]BatchElem, 4)
+	for i := range batch {
+		batch[i] = BatchElem{Method: "web3_clientVersion", Result: new(string)}
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	for i, elem := range batch {
+		if elem.Error != nil || *elem.Result.(*string) == "" {
+			t.Errorf("batch element %d failed: %v", i, elem.Error)
+		}
+	}
+}
+
+func TestGatewayLag(t *testing.T) {
+	ws, http := startGatewayUpstreams(t, 100, 90)
+	client, _ := dialTestGateway(t, ws, http)
+
+	if counts := callCounts(t, client, "web3_clientVersion", 10); counts["ws"] != 10 {
+		t.Errorf("calls sent to lagging upstream: %v", counts)
+	}
+}
+
+func TestGatewayFailover(t *testing.T) {
+	ws, http := startGatewayUpstreams(t, 100, 100)
+	client, g := dialTestGateway(t, ws, http)
+
+	http.Close()
+	if counts := callCounts(t, client, "web3_clientVersion", 10); counts["ws"] != 10 {
+		t.Errorf("calls not failed over: %v", counts)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.upstreams[1].healthy {
+		t.Error("failed upstream still healthy")
+	}
+}
+
+func TestGatewayPinning(t *testing.T) {
+	ws, http := startGatewayUpstreams(t, 100, 100)
+	client, g := dialTestGateway(t, ws, http)
+
+	// Transactions all go to the primary upstream
+	var first string
+	for i := 0; i < 5; i++ {
+		var name string
+		if err := client.Call(&name, "eth_sendRawTransaction", hexutil.Bytes{1}); err != nil {
+			t.Fatal(err)
+		}
+		if first == "" {
+			first = name
+		} else if name != first {
+			t.Fatalf("transaction %d sent to %s, want %s", i, name, first)
+		}
+	}
+	// Calls not known to be read-only are not balanced either
+	if counts := callCounts(t, client, "eth_name", 10); counts[first] != 10 {
+		t.Errorf("non-read calls spread over upstreams: %v", counts)
+	}
+	// Filters are polled on the upstream that created them
+	for i := 0; i < 4; i++ {
+		var id, name string
+		if err := client.Call(&id, "eth_newBlockFilter"); err != nil {
+			t.Fatal(err)
+		}
+		if err := client.Call(&name, "eth_getFilterChanges", id); err != nil {
+			t.Fatalf("filter %s: %v", id, err)
+		}
+	}
+	if err := client.Call(nil, "eth_getFilterChanges", "unknown"); err == nil || err.Error() != errUnknownFilter.Error() {
+		t.Errorf("unknown filter: have error %v", err)
+	}
+	// Filters not polled for long are forgotten
+	g.mu.Lock()
+	for _, filter := range g.filters {
+		filter.polled = time.Now().Add(-gatewayFilterTimeout - time.Second)
+	}
+	g.mu.Unlock()
+
+	var id string
+	if err := client.Call(&id, "eth_newBlockFilter"); err != nil {
+		t.Fatal(err)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.filters) != 1 {
+		t.Errorf("expired filters not dropped: %d tracked", len(g.filters))
+	}
+}
+
+func TestGatewaySubscription(t *testing.T) {
+	ws, http := startGatewayUpstreams(t, 100, 100)
+	client, g := dialTestGateway(t, ws, http)
+
+	ticks := make(chan string)
+	sub, err := client.EthSubscribe(context.Background(), ticks, "ticks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the websocket upstream supports subscriptions
+	for i := 0; i < 3; i++ {
+		select {
+		case name := <-ticks:
+			if name != "ws" {
+				t.Fatalf("notification from %s", name)
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("notification timeout")
+		}
+	}
+	sub.Unsubscribe()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.subs) != 0 {
+		t.Errorf("subscription not removed: %d left", len(g.subs))
+	}
+}
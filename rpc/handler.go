@@ -64,6 +64,18 @@ type handler struct {
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
+
+	handlerConfig
+}
+
+// handlerConfig contains the server settings applied to incoming calls.
+type handlerConfig struct {
+	batchRequestLimit    int // maximum number of requests in a batch, zero = unlimited
+	batchResponseMaxSize int // maximum number of response bytes of a batch, zero = unlimited
+
+	policy      *policyEnforcer // access policy of incoming calls, nil = unrestricted
+	audit       AuditLog        // destination of the audit records of served calls, nil = none
+	auditHashes bool            // whether audit records include the hash of the response
 }
 
 type callProc struct {
@@ -71,7 +83,7 @@ type callProc struct {
 	notifiers []*Notifier
 }
 
-func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *serviceRegistry) *handler {
+func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *serviceRegistry, config handlerConfig) *handler {
 	rootCtx, cancelRoot := context.WithCancel(connCtx)
 	h := &handler{
 		reg:            reg,
@@ -84,6 +96,7 @@ func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *
 		allowSubscribe: true,
 		serverSubs:     make(map[ID]*Subscription),
 		log:            log.Root(),
+		handlerConfig:  config,
 	}
 	if conn.remoteAddr() != "" {
 		h.log = h.log.New("conn", conn.remoteAddr())
@@ -101,23 +114,60 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 		})
 		return
 	}
+	// Reject batches exceeding the request limit as a whole:
+	if h.batchRequestLimit != 0 && len(msgs) > h.batchRequestLimit {
+		h.startCallProc(func(cp *callProc) {
+			h.respondWithBatchTooLarge(cp, msgs)
+		})
+		return
+	}
 
 	// Handle non-call messages first:
-	calls := make([]*jsonrpcMessage, 0, len(msgs))
+	var (
+		calls = make([]*jsonrpcMessage, 0, len(msgs))
+		ops   = make(map[*requestOp]struct{})
+	)
 	for _, msg := range msgs {
+		if msg.isResponse() {
+			if op := h.respWait[string(msg.ID)]; op != nil {
+				ops[op] = struct{}{}
+			}
+		}
 		if handled := h.handleImmediate(msg); !handled {
 			calls = append(calls, msg)
 		}
 	}
+	h.failMissingResponses(ops)
 	if len(calls) == 0 {
 		return
 	}
 	// Process calls on a goroutine because they may block indefinitely:
 	h.startCallProc(func(cp *callProc) {
-		answers := make([]*jsonrpcMessage, 0, len(msgs))
-		for _, msg := range calls {
-			if answer := h.handleCallMsg(cp, msg); answer != nil {
-				answers = append(answers, answer)
+		var (
+			answers       = make([]*jsonrpcMessage, 0, len(msgs))
+			responseBytes int
+		)
+		for i, msg := range calls {
+			answer := h.handleCallMsg(cp, msg)
+			if answer == nil {
+				continue
+			}
+			answers = append(answers, answer)
+
+			// Stop executing the batch once the responses grow too large, failing
+			// the remaining calls instead.
+			if h.batchResponseMaxSize != 0 {
+				responseBytes += len(answer.Result)
+				if responseBytes > h.batchResponseMaxSize {
+					err := &responseTooLargeError{}
+					answers[len(answers)-1] = msg.errorResponse(err)
+					for _, msg := range calls[i+1:] {
+						if !msg.isNotification() {
+							answers = append(answers, msg.errorResponse(err))
+						}
+					}
+					break
+				}
 			}
 		}
 		h.addSubscriptions(cp.notifiers)
@@ -130,6 +180,37 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 	})
 }
 
+// respondWithBatchTooLarge replies to a batch exceeding the request limit with a
+// single error. As the protocol has no way of reporting an error for the entire
+// batch, the error carries the ID of the first call.
+func (h *handler) respondWithBatchTooLarge(cp *callProc, batch []*jsonrpcMessage) {
+	resp := errorMessage(&batchTooLargeError{limit: h.batchRequestLimit})
+	for _, msg := range batch {
+		if msg.isCall() {
+			resp.ID = msg.ID
+			break
+		}
+	}
+	h.conn.writeJSON(cp.ctx, []*jsonrpcMessage{resp})
+}
+
+// failMissingResponses fails the calls of the given requests which were left
+// unanswered by a batch response, as no further responses will arrive for them.
+func (h *handler) failMissingResponses(ops map[*requestOp]struct{}) {
+	for op := range ops {
+		for _, id := range op.ids {
+			if h.respWait[string(id)] != op {
+				continue
+			}
+			delete(h.respWait, string(id))
+
+			resp := errorMessage(errMissingBatchResponse)
+			resp.ID = id
+			op.resp <- resp
+		}
+	}
+}
+
 // handleMsg handles a single message.
 func (h *handler) handleMsg(msg *jsonrpcMessage) {
 	if ok := h.handleImmediate(msg); ok {
@@ -293,9 +374,11 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 	case msg.isNotification():
 		h.handleCall(ctx, msg)
 		h.log.Debug("Served "+msg.Method, "duration", time.Since(start))
+		h.auditCall(ctx, msg, nil, start)
 		return nil
 	case msg.isCall():
 		resp := h.handleCall(ctx, msg)
+		h.auditCall(ctx, msg, resp, start)
 		var ctx []interface{}
 		ctx = append(ctx, "reqid", idForLog{msg.ID}, "duration", time.Since(start))
 		if resp.Error != nil {
@@ -315,6 +398,17 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 	}
 }
 
+// auditCall records a served call in the audit log, if enabled.
+func (h *handler) auditCall(cp *callProc, msg, resp *jsonrpcMessage, start time.Time) {
+	if h.audit == nil {
+		return
+	}
+	rec := newAuditRecord(PeerInfoFromContext(cp.ctx), msg, resp, start, h.auditHashes)
+	if err := h.audit.Append(rec); err != nil {
+		h.log.Warn("Failed to write RPC audit record", "method", msg.Method, "err", err)
+	}
+}
+
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
 	if msg.isSubscribe() {
@@ -329,6 +423,11 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if callb == nil {
 		return msg.errorResponse(&methodNotFoundError{method: msg.Method})
 	}
+	if h.policy != nil && callb != h.unsubscribeCb {
+		if err := h.policy.check(PeerInfoFromContext(cp.ctx), msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 	args, err := parsePositionalArguments(msg.Params, callb.argTypes)
 	if err != nil {
 		return msg.errorResponse(&invalidParamsError{err.Error()})
@@ -367,6 +466,11 @@ func (h *handler) handleSubscribe(cp *callProc, msg *jsonrpcMessage) *jsonrpcMes
 	if callb == nil {
 		return msg.errorResponse(&subscriptionNotFoundError{namespace, name})
 	}
+	if h.policy != nil {
+		if err := h.policy.check(PeerInfoFromContext(cp.ctx), msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 
 	// Parse subscription name arg too, but remove it before calling the callback.
 	argTypes := append([]reflect.Type{stringType}, callb.argTypes...)
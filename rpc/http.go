@@ -45,6 +45,7 @@ type httpConn struct {
 	closeCh   chan interface{}
 	mu        sync.Mutex // protects headers
 	headers   http.Header
+	streaming bool // whether subscriptions are streamed, see DialSSE
 }
 
 // httpConn implements ServerCodec, but it is treated specially by Client
@@ -173,13 +174,33 @@ func (c *Client) sendBatchHTTP(ctx context.Context, op *requestOp, msgs []*jsonr
 	if err := json.NewDecoder(respBody).Decode(&respmsgs); err != nil {
 		return err
 	}
+	answered := make(map[string]bool, len(respmsgs))
 	for i := 0; i < len(respmsgs); i++ {
 		op.resp <- &respmsgs[i]
+		answered[string(respmsgs[i].ID)] = true
+	}
+	// Fail the calls the server didn't answer, e.g. because the batch was too large
+	for _, id := range op.ids {
+		if !answered[string(id)] && len(op.resp) < cap(op.resp) {
+			resp := errorMessage(errMissingBatchResponse)
+			resp.ID = id
+			op.resp <- resp
+		}
 	}
 	return nil
 }
 
 func (hc *httpConn) doRequest(ctx context.Context, msg interface{}) (io.ReadCloser, error) {
+	resp, err := hc.do(ctx, msg, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// do sends msg to the server, overriding the client's headers with the given ones.
+// Responses with a non-successful status code are returned as HTTPError.
+func (hc *httpConn) do(ctx context.Context, msg interface{}, header http.Header) (*http.Response, error) {
 	body, err := json.Marshal(msg)
 	if err != nil {
 		return nil, err
@@ -195,6 +216,9 @@ func (hc *httpConn) doRequest(ctx context.Context, msg interface{}) (io.ReadClos
 	hc.mu.Lock()
 	req.Header = hc.headers.Clone()
 	hc.mu.Unlock()
+	for key, values := range header {
+		req.Header[key] = values
+	}
 
 	// do request
 	resp, err := hc.client.Do(req)
@@ -214,7 +238,7 @@ func (hc *httpConn) doRequest(ctx context.Context, msg interface{}) (io.ReadClos
 			Body:       body,
 		}
 	}
-	return resp.Body, nil
+	return resp, nil
 }
 
 // httpServerConn turns a HTTP connection into a Conn.
@@ -259,9 +283,16 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	connInfo.HTTP.Host = r.Host
 	connInfo.HTTP.Origin = r.Header.Get("Origin")
 	connInfo.HTTP.UserAgent = r.Header.Get("User-Agent")
+	connInfo.HTTP.APIKey = requestAPIKey(r)
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, connInfo)
 
+	// Subscriptions are served on a stream of their own if requested.
+	if IsStreamRequest(r) {
+		s.serveStream(ctx, w, r)
+		return
+	}
+
 	// All checks passed, create a codec that reads directly from the request body
 	// until EOF, writes the response to w, and orders the server to process a
 	// single request.
@@ -32,6 +32,9 @@ var (
 	serveTimeHistName = "rpc/duration"
 
 	rpcServingTimer = metrics.NewRegisteredTimer("rpc/duration/all", nil)
+
+	// unauthorizedCounter counts the calls rejected for a missing or unknown API key.
+	unauthorizedCounter = metrics.NewRegisteredCounter("rpc/quota/unauthorized", nil)
 )
 
 // updateServeTimeHistogram tracks the serving time of a remote RPC call.
@@ -0,0 +1,351 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
+)
+
+// openRPCVersion is the version of the OpenRPC specification of the documents.
+const openRPCVersion = "1.2.6"
+
+// MethodDoc annotates a method in the OpenRPC document of a server.
+type MethodDoc struct {
+	Summary     string
+	Description string
+	Params      []string // Names of the parameters, in order
+	Result      string   // Name of the result
+	Deprecated  bool
+}
+
+// DocumentedService is implemented by services annotating their methods in the
+// OpenRPC document. The returned annotations are keyed by Go method name. The
+// MethodDocs method itself is not exposed over RPC.
+type DocumentedService interface {
+	MethodDocs() map[string]MethodDoc
+}
+
+// OpenRPCDocument is an OpenRPC description of the methods served by a server.
+type OpenRPCDocument struct {
+	OpenRPC    string            `json:"openrpc"`
+	Info       OpenRPCInfo       `json:"info"`
+	Methods    []*OpenRPCMethod  `json:"methods"`
+	Components OpenRPCComponents `json:"components"`
+}
+
+// OpenRPCInfo contains the metadata of an OpenRPC document.
+type OpenRPCInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenRPCMethod describes a single method.
+type OpenRPCMethod struct {
+	Name        string               `json:"name"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Params      []*OpenRPCDescriptor `json:"params"`
+	Result      *OpenRPCDescriptor   `json:"result"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+}
+
+// OpenRPCDescriptor describes a parameter or result of a method.
+type OpenRPCDescriptor struct {
+	Name     string  `json:"name"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// OpenRPCComponents contains the definitions referenced by the methods.
+type OpenRPCComponents struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Schema is the subset of JSON schema used to describe the types of parameters
+// and results.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	// knownSchemas are the schemas of types whose JSON encoding can't be derived
+	// from their Go type.
+	knownSchemas = map[reflect.Type]Schema{
+		reflect.TypeOf(common.Address{}):     {Type: "string", Pattern: "^0x[0-9a-fA-F]{40}$"},
+		reflect.TypeOf(common.Hash{}):        {Type: "string", Pattern: "^0x[0-9a-f]{64}$"},
+		reflect.TypeOf(hexutil.Bytes{}):      {Type: "string", Pattern: "^0x([0-9a-f][0-9a-f])*$"},
+		reflect.TypeOf(hexutil.Big{}):        {Type: "string", Pattern: "^0x(0|[1-9a-f][0-9a-f]*)$"},
+		reflect.TypeOf(hexutil.Uint64(0)):    {Type: "string", Pattern: "^0x(0|[1-9a-f][0-9a-f]*)$"},
+		reflect.TypeOf(hexutil.Uint(0)):      {Type: "string", Pattern: "^0x(0|[1-9a-f][0-9a-f]*)$"},
+		reflect.TypeOf(big.Int{}):            {Type: "integer"},
+		reflect.TypeOf(BlockNumber(0)):       {Type: "string", Description: "Hex block number or tag (earliest, latest, pending)"},
+		reflect.TypeOf(BlockNumberOrHash{}):  {Type: "string", Description: "Hex block number, tag or block hash"},
+		reflect.TypeOf(json.RawMessage(nil)): {},
+	}
+)
+
+// OpenRPC generates the OpenRPC document describing the methods registered on
+// the server.
+func (s *Server) OpenRPC() *OpenRPCDocument {
+	s.services.mu.Lock()
+	defer s.services.mu.Unlock()
+
+	doc := &OpenRPCDocument{
+		OpenRPC: openRPCVersion,
+		Info:    OpenRPCInfo{Title: "JSON-RPC API", Version: "1.0"},
+	}
+	gen := &schemaGen{defs: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+
+	namespaces := make([]string, 0, len(s.services.services))
+	for name := range s.services.services {
+		namespaces = append(namespaces, name)
+	}
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		svc := s.services.services[namespace]
+
+		names := make([]string, 0, len(svc.callbacks))
+		for name := range svc.callbacks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			doc.Methods = append(doc.Methods, gen.method(namespace+serviceMethodSeparator+name, svc.callbacks[name]))
+		}
+		if len(svc.subscriptions) > 0 {
+			doc.Methods = append(doc.Methods, gen.subscriptionMethods(namespace, svc.subscriptions)...)
+		}
+	}
+	doc.Components.Schemas = gen.defs
+	return doc
+}
+
+// schemaGen derives JSON schemas from Go types, collecting the named struct types
+// as shared definitions.
+type schemaGen struct {
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+// method describes a method callback.
+func (g *schemaGen) method(name string, cb *callback) *OpenRPCMethod {
+	m := &OpenRPCMethod{Name: name, Params: []*OpenRPCDescriptor{}}
+	doc := cb.doc
+	if doc != nil {
+		m.Summary, m.Description, m.Deprecated = doc.Summary, doc.Description, doc.Deprecated
+	}
+	for i, typ := range cb.argTypes {
+		param := &OpenRPCDescriptor{
+			Name:     fmt.Sprintf("param%d", i),
+			Required: !optionalArg(cb.argTypes, i),
+			Schema:   g.schema(typ),
+		}
+		if doc != nil && i < len(doc.Params) {
+			param.Name = doc.Params[i]
+		}
+		m.Params = append(m.Params, param)
+	}
+	m.Result = &OpenRPCDescriptor{Name: "result", Schema: &Schema{Type: "null"}}
+	if doc != nil && doc.Result != "" {
+		m.Result.Name = doc.Result
+	}
+	if fntype := cb.fn.Type(); fntype.NumOut() > 0 && cb.errPos != 0 {
+		m.Result.Schema = g.schema(fntype.Out(0))
+	}
+	return m
+}
+
+// subscriptionMethods describes the subscribe and unsubscribe methods of a
+// namespace. The subscriptions and their arguments are listed in the description
+// of the subscribe method.
+func (g *schemaGen) subscriptionMethods(namespace string, subs map[string]*callback) []*OpenRPCMethod {
+	names := make([]string, 0, len(subs))
+	for name := range subs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var desc strings.Builder
+	desc.WriteString("Available subscriptions:")
+	for _, name := range names {
+		var args []string
+		for _, typ := range subs[name].argTypes {
+			args = append(args, typ.String())
+		}
+		fmt.Fprintf(&desc, "\n- %s(%s)", name, strings.Join(args, ", "))
+	}
+	return []*OpenRPCMethod{
+		{
+			Name:        namespace + subscribeMethodSuffix,
+			Summary:     "Creates a subscription, delivering notifications as " + namespace + notificationMethodSuffix,
+			Description: desc.String(),
+			Params: []*OpenRPCDescriptor{
+				{Name: "subscription", Required: true, Schema: &Schema{Type: "string", Enum: names}},
+				{Name: "arguments", Schema: &Schema{Description: "Arguments of the subscription"}},
+			},
+			Result: &OpenRPCDescriptor{Name: "id", Schema: &Schema{Type: "string"}},
+		},
+		{
+			Name:    namespace + unsubscribeMethodSuffix,
+			Summary: "Cancels a subscription",
+			Params: []*OpenRPCDescriptor{
+				{Name: "id", Required: true, Schema: &Schema{Type: "string"}},
+			},
+			Result: &OpenRPCDescriptor{Name: "result", Schema: &Schema{Type: "boolean"}},
+		},
+	}
+}
+
+// optionalArg reports whether an argument may be omitted. Trailing pointer
+// arguments are optional.
+func optionalArg(types []reflect.Type, i int) bool {
+	for ; i < len(types); i++ {
+		if types[i].Kind() != reflect.Ptr {
+			return false
+		}
+	}
+	return true
+}
+
+// schema returns the JSON schema of values of a Go type.
+func (g *schemaGen) schema(typ reflect.Type) *Schema {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if known, ok := knownSchemas[typ]; ok {
+		return &known
+	}
+	ptr := reflect.PtrTo(typ)
+	if typ.Implements(jsonMarshalerType) || ptr.Implements(jsonMarshalerType) {
+		// The encoding is custom, the Go type says nothing about it.
+		if typ.Kind() == reflect.Struct {
+			return &Schema{Type: "object"}
+		}
+		return &Schema{}
+	}
+	if typ.Implements(textMarshalerType) || ptr.Implements(textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Description: "Base64 encoded bytes"}
+		}
+		return &Schema{Type: "array", Items: g.schema(typ.Elem())}
+	case reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(typ.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(typ.Elem())}
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return g.structSchema(typ)
+		}
+		return g.structRef(typ)
+	default:
+		// Interfaces and anything else can't be described.
+		return &Schema{}
+	}
+}
+
+// structRef returns a reference to the shared definition of a named struct type,
+// creating it if necessary.
+func (g *schemaGen) structRef(typ reflect.Type) *Schema {
+	name, ok := g.names[typ]
+	if !ok {
+		name = typ.Name()
+		if _, taken := g.defs[name]; taken {
+			name = path.Base(typ.PkgPath()) + "." + typ.Name()
+		}
+		g.names[typ] = name
+		g.defs[name] = &Schema{} // placeholder for recursive types
+		*g.defs[name] = *g.structSchema(typ)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// structSchema describes the JSON object encoding a struct.
+func (g *schemaGen) structSchema(typ reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(s, typ)
+	return s
+}
+
+// addFields adds the fields of a struct to an object schema, following the rules
+// of encoding/json.
+func (g *schemaGen) addFields(s *Schema, typ reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if idx := strings.IndexByte(tag, ','); idx >= 0 {
+			name, opts = tag[:idx], tag[idx:]
+		}
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = g.schema(field.Type)
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
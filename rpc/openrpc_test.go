@@ -0,0 +1,136 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/common/hexutil"
+)
+
+type documentedService struct{}
+
+type lookupResult struct {
+	Address common.Address  `json:"address"`
+	Balance *hexutil.Big    `json:"balance"`
+	Nonce   hexutil.Uint64  `json:"nonce,omitempty"`
+	Next    *lookupResult   `json:"next"`
+	Tags    map[string]bool `json:"tags"`
+	hidden  int
+}
+
+func (s *documentedService) Lookup(addr common.Address, block *BlockNumber) (*lookupResult, error) {
+	return nil, nil
+}
+
+func (s *documentedService) Clear() error { return nil }
+
+func (s *documentedService) MethodDocs() map[string]MethodDoc {
+	return map[string]MethodDoc{
+		"Lookup": {Summary: "Looks up an account", Params: []string{"address", "block"}, Result: "account"},
+	}
+}
+
+func findMethod(doc *OpenRPCDocument, name string) *OpenRPCMethod {
+	for _, m := range doc.Methods {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// This test checks the OpenRPC document generated for the registered services.
+func TestOpenRPC(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	if err := server.RegisterName("doc", new(documentedService)); err != nil {
+		t.Fatal(err)
+	}
+	doc := server.OpenRPC()
+
+	if findMethod(doc, "doc_methodDocs") != nil {
+		t.Error("annotation method exposed")
+	}
+	lookup := findMethod(doc, "doc_lookup")
+	if lookup == nil {
+		t.Fatal("doc_lookup missing")
+	}
+	if lookup.Summary != "Looks up an account" || lookup.Result.Name != "account" {
+		t.Errorf("annotation mismatch: summary %q, result %q", lookup.Summary, lookup.Result.Name)
+	}
+	if len(lookup.Params) != 2 {
+		t.Fatalf("param count mismatch: have %d, want 2", len(lookup.Params))
+	}
+	if p := lookup.Params[0]; p.Name != "address" || !p.Required || p.Schema.Pattern == "" {
+		t.Errorf("address param mismatch: %+v", p)
+	}
+	if p := lookup.Params[1]; p.Name != "block" || p.Required {
+		t.Errorf("block param mismatch: %+v", p)
+	}
+	if lookup.Result.Schema.Ref != "#/components/schemas/lookupResult" {
+		t.Errorf("result schema mismatch: %+v", lookup.Result.Schema)
+	}
+	def := doc.Components.Schemas["lookupResult"]
+	if def == nil {
+		t.Fatal("lookupResult definition missing")
+	}
+	if !reflect.DeepEqual(def.Required, []string{"address", "tags"}) {
+		t.Errorf("required fields mismatch: have %v", def.Required)
+	}
+	if len(def.Properties) != 5 || def.Properties["next"].Ref != "#/components/schemas/lookupResult" {
+		t.Errorf("properties mismatch: %+v", def.Properties)
+	}
+	if def.Properties["tags"].AdditionalProperties.Type != "boolean" {
+		t.Errorf("map schema mismatch: %+v", def.Properties["tags"])
+	}
+	if clear := findMethod(doc, "doc_clear"); clear == nil || clear.Result.Schema.Type != "null" {
+		t.Errorf("doc_clear mismatch: %+v", clear)
+	}
+	// Subscriptions are described by the subscribe method of their namespace
+	sub := findMethod(doc, "nftest_subscribe")
+	if sub == nil || len(sub.Params) == 0 || len(sub.Params[0].Schema.Enum) == 0 {
+		t.Fatalf("nftest_subscribe mismatch: %+v", sub)
+	}
+	if findMethod(doc, "nftest_unsubscribe") == nil {
+		t.Error("nftest_unsubscribe missing")
+	}
+}
+
+// This test checks that the document is served at rpc_discover.
+func TestDiscover(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	var doc OpenRPCDocument
+	if err := client.Call(&doc, "rpc_discover"); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenRPC != openRPCVersion {
+		t.Errorf("version mismatch: have %q", doc.OpenRPC)
+	}
+	if echo := findMethod(&doc, "test_echo"); echo == nil || len(echo.Params) != 3 {
+		t.Errorf("test_echo mismatch: %+v", echo)
+	}
+	if findMethod(&doc, "rpc_discover") == nil {
+		t.Error("rpc_discover missing")
+	}
+}
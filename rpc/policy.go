@@ -0,0 +1,323 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Altcoinchain/go-altcoinchain/metrics"
+	"github.com/hashicorp/golang-lru/simplelru"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// APIKeyHeader is the HTTP header carrying the API key of a request.
+	APIKeyHeader = "X-Api-Key"
+
+	// APIKeyPathPrefix is the URL path prefix carrying the API key of a request as
+	// an alternative to the header, e.g. /key/<apikey>.
+	APIKeyPathPrefix = "/key/"
+
+	// maxPolicyClients is the number of clients whose budget is tracked. The least
+	// recently seen ones are dropped beyond it, which only loses the budget spent
+	// by clients that have been idle for a while.
+	maxPolicyClients = 4096
+)
+
+// AccessPolicy restricts the methods available through a server and assigns
+// request budgets to its clients. Method patterns are either full method names,
+// like eth_getLogs, whole namespaces, like debug_*, or * for all methods.
+type AccessPolicy struct {
+	Allow []string         `json:"allow,omitempty"` // Methods available to clients, empty = all
+	Deny  []string         `json:"deny,omitempty"`  // Methods unavailable to clients, takes precedence over allow
+	Costs map[string]int   `json:"costs,omitempty"` // Compute units charged per method pattern, default 1
+	Keys  map[string]Quota `json:"keys,omitempty"`  // Quotas of the accepted API keys
+
+	// Anonymous is the quota of every client IP not presenting an API key. If
+	// unset, requests without an API key are rejected.
+	Anonymous *Quota `json:"anonymous,omitempty"`
+
+	enforcer     *policyEnforcer // rate limiters shared by all servers using the policy
+	enforcerOnce sync.Once
+}
+
+// Quota is the request budget of a single client.
+type Quota struct {
+	Name  string   `json:"name,omitempty"`  // Name of the client in metrics and logs
+	Allow []string `json:"allow,omitempty"` // Overrides the methods allowed by the policy if set
+	Deny  []string `json:"deny,omitempty"`  // Overrides the methods denied by the policy if set
+
+	RequestsPerSecond     float64 `json:"requestsPerSecond,omitempty"`     // Sustained request rate, 0 = unlimited
+	ComputeUnitsPerSecond float64 `json:"computeUnitsPerSecond,omitempty"` // Sustained compute unit rate, 0 = unlimited
+}
+
+// LoadAccessPolicy reads an access policy from a JSON file.
+func LoadAccessPolicy(path string) (*AccessPolicy, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	policy := new(AccessPolicy)
+	if err := json.Unmarshal(blob, policy); err != nil {
+		return nil, fmt.Errorf("invalid access policy %s: %v", path, err)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid access policy %s: %v", path, err)
+	}
+	return policy, nil
+}
+
+// validate checks that the policy is well formed.
+func (p *AccessPolicy) validate() error {
+	patterns := append(append([]string{}, p.Allow...), p.Deny...)
+	for pattern, cost := range p.Costs {
+		if cost < 0 {
+			return fmt.Errorf("negative cost %d for %q", cost, pattern)
+		}
+		patterns = append(patterns, pattern)
+	}
+	quotas := make(map[string]Quota, len(p.Keys)+1)
+	for key, quota := range p.Keys {
+		if key == "" {
+			return fmt.Errorf("empty API key")
+		}
+		quotas[key] = quota
+	}
+	if p.Anonymous != nil {
+		quotas[""] = *p.Anonymous
+	}
+	for key, quota := range quotas {
+		if quota.RequestsPerSecond < 0 || quota.ComputeUnitsPerSecond < 0 {
+			return fmt.Errorf("negative rate for key %q", key)
+		}
+		patterns = append(append(patterns, quota.Allow...), quota.Deny...)
+	}
+	for _, pattern := range patterns {
+		if !validPattern(pattern) {
+			return fmt.Errorf("invalid method pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// validPattern reports whether a method pattern is a full method name, a whole
+// namespace or *. Any other wildcard would never match a method.
+func validPattern(pattern string) bool {
+	if pattern == "" {
+		return false
+	}
+	if !strings.Contains(pattern, "*") {
+		return true
+	}
+	if pattern == "*" {
+		return true
+	}
+	// Namespace patterns have a single underscore, right before the wildcard
+	idx := strings.IndexByte(pattern, '_')
+	return idx > 0 && idx == len(pattern)-2 && strings.IndexByte(pattern, '*') == idx+1
+}
+
+// matchMethod reports whether a method matches any of the patterns.
+func matchMethod(patterns []string, method string) bool {
+	for _, pattern := range patterns {
+		if pattern == method || pattern == "*" {
+			return true
+		}
+		if strings.HasSuffix(pattern, "_*") && strings.HasPrefix(method, pattern[:len(pattern)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestAPIKey extracts the API key of an HTTP request, either from the header
+// or the URL path.
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return key
+	}
+	if idx := strings.LastIndex(r.URL.Path, APIKeyPathPrefix); idx >= 0 {
+		return strings.TrimSuffix(r.URL.Path[idx+len(APIKeyPathPrefix):], "/")
+	}
+	return ""
+}
+
+// policyEnforcer applies an access policy to the calls served by its servers. The
+// rate limiters are shared by all connections of these servers.
+type policyEnforcer struct {
+	policy  *AccessPolicy
+	maxCost int
+
+	lock    sync.Mutex
+	clients *simplelru.LRU // Quota trackers of the recently seen clients, id -> *clientQuota
+}
+
+// clientQuota tracks the budget of a single API key or anonymous client IP.
+type clientQuota struct {
+	quota    *Quota
+	requests *rate.Limiter
+	units    *rate.Limiter
+
+	served  metrics.Counter
+	charged metrics.Counter
+	limited metrics.Counter
+	denied  metrics.Counter
+}
+
+// enforce returns the enforcer of the policy, creating it on first use.
+func (p *AccessPolicy) enforce() *policyEnforcer {
+	p.enforcerOnce.Do(func() { p.enforcer = newPolicyEnforcer(p) })
+	return p.enforcer
+}
+
+func newPolicyEnforcer(policy *AccessPolicy) *policyEnforcer {
+	maxCost := 1
+	for _, cost := range policy.Costs {
+		if cost > maxCost {
+			maxCost = cost
+		}
+	}
+	clients, _ := simplelru.NewLRU(maxPolicyClients, nil)
+	return &policyEnforcer{
+		policy:  policy,
+		maxCost: maxCost,
+		clients: clients,
+	}
+}
+
+// client returns the quota tracker of the client identified by its API key or,
+// for anonymous clients, its IP address. Nil is returned for unknown API keys
+// and if anonymous access is not permitted.
+func (e *policyEnforcer) client(info PeerInfo) *clientQuota {
+	var (
+		id    string
+		quota *Quota
+		name  string
+	)
+	if key := info.HTTP.APIKey; key != "" {
+		q, ok := e.policy.Keys[key]
+		if !ok {
+			return nil
+		}
+		id, quota, name = "key:"+key, &q, q.Name
+		if name == "" {
+			name = "key"
+		}
+	} else {
+		if e.policy.Anonymous == nil {
+			return nil
+		}
+		host, _, err := net.SplitHostPort(info.RemoteAddr)
+		if err != nil {
+			host = info.RemoteAddr
+		}
+		id, quota, name = "ip:"+host, e.policy.Anonymous, "anonymous"
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if client, ok := e.clients.Get(id); ok {
+		return client.(*clientQuota)
+	}
+	client := &clientQuota{
+		quota:    quota,
+		requests: newQuotaLimiter(quota.RequestsPerSecond, 1),
+		units:    newQuotaLimiter(quota.ComputeUnitsPerSecond, e.maxCost),
+		served:   metrics.GetOrRegisterCounter("rpc/quota/"+name+"/requests", nil),
+		charged:  metrics.GetOrRegisterCounter("rpc/quota/"+name+"/units", nil),
+		limited:  metrics.GetOrRegisterCounter("rpc/quota/"+name+"/limited", nil),
+		denied:   metrics.GetOrRegisterCounter("rpc/quota/"+name+"/denied", nil),
+	}
+	e.clients.Add(id, client)
+	return client
+}
+
+// newQuotaLimiter creates a rate limiter allowing bursts of a second worth of
+// budget, but at least of the given size.
+func newQuotaLimiter(perSecond float64, minBurst int) *rate.Limiter {
+	if perSecond == 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	burst := int(math.Ceil(perSecond))
+	if burst < minBurst {
+		burst = minBurst
+	}
+	return rate.NewLimiter(rate.Limit(perSecond), burst)
+}
+
+// cost returns the compute units charged for a method. The most specific
+// matching pattern is used.
+func (e *policyEnforcer) cost(method string) int {
+	if cost, ok := e.policy.Costs[method]; ok {
+		return cost
+	}
+	if idx := strings.IndexByte(method, '_'); idx >= 0 {
+		if cost, ok := e.policy.Costs[method[:idx+1]+"*"]; ok {
+			return cost
+		}
+	}
+	if cost, ok := e.policy.Costs["*"]; ok {
+		return cost
+	}
+	return 1
+}
+
+// check decides whether the client may call the given method, charging its
+// budget if so.
+func (e *policyEnforcer) check(info PeerInfo, method string) error {
+	client := e.client(info)
+	if client == nil {
+		unauthorizedCounter.Inc(1)
+		return &unauthorizedError{}
+	}
+	allow, deny := e.policy.Allow, e.policy.Deny
+	if client.quota.Allow != nil {
+		allow = client.quota.Allow
+	}
+	if client.quota.Deny != nil {
+		deny = client.quota.Deny
+	}
+	if (len(allow) > 0 && !matchMethod(allow, method)) || matchMethod(deny, method) {
+		client.denied.Inc(1)
+		return &methodNotFoundError{method: method}
+	}
+	// Reserve both budgets at once, so a call rejected by one of the limiters
+	// doesn't spend the budget of the other
+	var (
+		cost     = e.cost(method)
+		now      = time.Now()
+		requests = client.requests.ReserveN(now, 1)
+		units    = client.units.ReserveN(now, cost)
+	)
+	if !requests.OK() || requests.DelayFrom(now) > 0 || !units.OK() || units.DelayFrom(now) > 0 {
+		requests.CancelAt(now)
+		units.CancelAt(now)
+		client.limited.Inc(1)
+		return &quotaExceededError{}
+	}
+	client.served.Inc(1)
+	client.charged.Inc(int64(cost))
+	return nil
+}
@@ -0,0 +1,237 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAccessPolicyValidate(t *testing.T) {
+	valid := &AccessPolicy{
+		Allow: []string{"eth_*", "net_version"},
+		Deny:  []string{"debug_traceTransaction"},
+		Costs: map[string]int{"*": 1, "eth_getLogs": 10},
+		Keys:  map[string]Quota{"secret": {Name: "team", RequestsPerSecond: 5}},
+	}
+	if err := valid.validate(); err != nil {
+		t.Errorf("valid policy rejected: %v", err)
+	}
+	invalid := []*AccessPolicy{
+		{Allow: []string{""}},
+		{Deny: []string{"debug_*trace"}},
+		{Deny: []string{"debug*"}},
+		{Deny: []string{"eth_get*"}},
+		{Allow: []string{"_*"}},
+		{Costs: map[string]int{"eth_get*": 5}},
+		{Costs: map[string]int{"eth_call": -1}},
+		{Keys: map[string]Quota{"": {}}},
+		{Anonymous: &Quota{RequestsPerSecond: -1}},
+	}
+	for i, policy := range invalid {
+		if err := policy.validate(); err == nil {
+			t.Errorf("invalid policy %d accepted", i)
+		}
+	}
+}
+
+func TestLoadAccessPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	blob := `{"deny": ["debug_*"], "keys": {"secret": {"name": "team", "computeUnitsPerSecond": 100}}}`
+	if err := os.WriteFile(path, []byte(blob), 0600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := LoadAccessPolicy(path)
+	if err != nil {
+		t.Fatalf("failed to load policy: %v", err)
+	}
+	if quota, ok := policy.Keys["secret"]; !ok || quota.Name != "team" || quota.ComputeUnitsPerSecond != 100 {
+		t.Errorf("key quota mismatch: have %+v", policy.Keys)
+	}
+	if err := os.WriteFile(path, []byte(`{"allow": ["*_*"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadAccessPolicy(path); err == nil {
+		t.Errorf("invalid policy loaded")
+	}
+}
+
+func TestPolicyEnforcer(t *testing.T) {
+	policy := &AccessPolicy{
+		Deny:  []string{"debug_*"},
+		Costs: map[string]int{"eth_getLogs": 5, "eth_*": 2},
+		Keys: map[string]Quota{
+			"limited": {RequestsPerSecond: 2},
+			"units":   {ComputeUnitsPerSecond: 5},
+			"debug":   {Deny: []string{}, Allow: []string{"debug_traceTransaction"}},
+		},
+	}
+	enforcer := newPolicyEnforcer(policy)
+	peer := func(key string) PeerInfo {
+		var info PeerInfo
+		info.RemoteAddr = "127.0.0.1:1234"
+		info.HTTP.APIKey = key
+		return info
+	}
+	errorCode := func(err error) int {
+		if err, ok := err.(Error); ok {
+			return err.ErrorCode()
+		}
+		return 0
+	}
+	tests := []struct {
+		key    string
+		method string
+		code   int
+	}{
+		{"", "eth_blockNumber", -32006},        // anonymous access not permitted
+		{"unknown", "eth_blockNumber", -32006}, // unknown key
+		{"limited", "debug_traceTransaction", -32601},
+		{"limited", "eth_blockNumber", 0},
+		{"limited", "eth_blockNumber", 0},
+		{"limited", "eth_blockNumber", -32005}, // request rate exhausted
+		{"units", "eth_getLogs", 0},
+		{"units", "net_version", -32005}, // compute units exhausted
+		{"debug", "debug_traceTransaction", 0},
+		{"debug", "debug_traceBlock", -32601}, // key allow list overrides policy
+		{"debug", "eth_call", -32601},
+	}
+	for i, tt := range tests {
+		if code := errorCode(enforcer.check(peer(tt.key), tt.method)); code != tt.code {
+			t.Errorf("test %d (%s, %s): error code mismatch: have %d, want %d", i, tt.key, tt.method, code, tt.code)
+		}
+	}
+	// Calls rejected for their compute units must not spend the request budget
+	mixed := newPolicyEnforcer(&AccessPolicy{
+		Costs: map[string]int{"eth_getLogs": 10, "net_version": 0},
+		Keys:  map[string]Quota{"mixed": {RequestsPerSecond: 2, ComputeUnitsPerSecond: 10}},
+	})
+	if err := mixed.check(peer("mixed"), "eth_getLogs"); err != nil {
+		t.Fatalf("expensive call rejected: %v", err)
+	}
+	if code := errorCode(mixed.check(peer("mixed"), "eth_getLogs")); code != -32005 {
+		t.Errorf("expensive call error code mismatch: have %d, want %d", code, -32005)
+	}
+	if err := mixed.check(peer("mixed"), "net_version"); err != nil {
+		t.Errorf("free call rejected after expensive one: %v", err)
+	}
+	if cost := enforcer.cost("eth_call"); cost != 2 {
+		t.Errorf("namespace cost mismatch: have %d, want 2", cost)
+	}
+	if cost := enforcer.cost("net_version"); cost != 1 {
+		t.Errorf("default cost mismatch: have %d, want 1", cost)
+	}
+}
+
+// This test checks that the tracked clients are bounded, dropping the least
+// recently seen ones.
+func TestPolicyEnforcerEviction(t *testing.T) {
+	enforcer := newPolicyEnforcer(&AccessPolicy{Anonymous: &Quota{RequestsPerSecond: 1}})
+	peer := func(i int) PeerInfo {
+		var info PeerInfo
+		info.RemoteAddr = fmt.Sprintf("10.%d.%d.%d:1234", i>>16&0xff, i>>8&0xff, i&0xff)
+		return info
+	}
+	if err := enforcer.check(peer(0), "eth_blockNumber"); err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	for i := 1; i < 2*maxPolicyClients; i++ {
+		// Keep the first client in use
+		enforcer.check(peer(0), "eth_blockNumber")
+		if err := enforcer.check(peer(i), "eth_blockNumber"); err != nil {
+			t.Fatalf("client %d call failed: %v", i, err)
+		}
+	}
+	if n := enforcer.clients.Len(); n != maxPolicyClients {
+		t.Fatalf("tracked client count mismatch: have %d, want %d", n, maxPolicyClients)
+	}
+	if enforcer.clients.Contains("ip:10.0.0.1") {
+		t.Fatalf("idle client not evicted")
+	}
+	if !enforcer.clients.Contains("ip:10.0.0.0") {
+		t.Fatalf("active client evicted")
+	}
+	// The budget of the active client is kept, not reset by eviction
+	if err := enforcer.check(peer(0), "eth_blockNumber"); err == nil {
+		t.Fatalf("active client budget reset")
+	}
+}
+
+// This test checks that the API key is picked up from the header and the URL path,
+// and that the policy is enforced on calls served over HTTP.
+func TestAccessPolicyHTTP(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	policy := &AccessPolicy{
+		Allow:     []string{"test_echo", "test_peerInfo"},
+		Keys:      map[string]Quota{"secret": {Name: "test"}},
+		Anonymous: &Quota{RequestsPerSecond: 1},
+	}
+	if err := server.SetAccessPolicy(policy); err != nil {
+		t.Fatal(err)
+	}
+	hs := httptest.NewServer(server)
+	defer hs.Close()
+
+	// Keys in the URL path and the header identify the client
+	client, err := DialHTTP(hs.URL + "/key/secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var info PeerInfo
+	if err := client.Call(&info, "test_peerInfo"); err != nil {
+		t.Fatal(err)
+	}
+	if info.HTTP.APIKey != "secret" {
+		t.Errorf("path API key mismatch: have %q, want %q", info.HTTP.APIKey, "secret")
+	}
+	client.Close()
+
+	client, err = DialHTTPWithClient(hs.URL, new(http.Client))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.SetHeader(APIKeyHeader, "secret")
+	for i := 0; i < 3; i++ {
+		if err := client.Call(&info, "test_peerInfo"); err != nil {
+			t.Fatalf("call %d with header key failed: %v", i, err)
+		}
+	}
+	if info.HTTP.APIKey != "secret" {
+		t.Errorf("header API key mismatch: have %q, want %q", info.HTTP.APIKey, "secret")
+	}
+	// Methods not allowed by the policy are unavailable
+	var result echoResult
+	if err := client.Call(&result, "test_sleep", 0); err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Errorf("denied method error mismatch: have %v", err)
+	}
+	// Anonymous clients are subject to their own quota
+	client.SetHeader(APIKeyHeader, "")
+	if err := client.CallContext(context.Background(), &result, "test_echo", "x", 1, &echoArgs{"y"}); err != nil {
+		t.Fatalf("anonymous call failed: %v", err)
+	}
+	if err := client.CallContext(context.Background(), &result, "test_echo", "x", 1, &echoArgs{"y"}); err == nil || err.(Error).ErrorCode() != -32005 {
+		t.Errorf("anonymous quota error mismatch: have %v", err)
+	}
+}
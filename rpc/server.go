@@ -47,6 +47,8 @@ type Server struct {
 	idgen    func() ID
 	run      int32
 	codecs   mapset.Set
+
+	handlerConfig handlerConfig
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -67,6 +69,47 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
+// SetBatchLimits sets the limits applied to batch requests: requestLimit is the
+// maximum number of requests in a batch, responseMaxSize the maximum number of
+// response bytes across all requests of a batch. Zero disables the respective
+// limit.
+//
+// This method should be called before processing any requests via ServeCodec,
+// ServeHTTP, ServeListener etc.
+func (s *Server) SetBatchLimits(requestLimit, responseMaxSize int) {
+	s.handlerConfig.batchRequestLimit = requestLimit
+	s.handlerConfig.batchResponseMaxSize = responseMaxSize
+}
+
+// SetAccessPolicy restricts the methods callable through the server and enforces
+// the request quotas of its clients. The rate limits are shared by all servers
+// using the same policy. A nil policy removes all restrictions.
+//
+// This method should be called before processing any requests via ServeCodec,
+// ServeHTTP, ServeListener etc.
+func (s *Server) SetAccessPolicy(policy *AccessPolicy) error {
+	if policy == nil {
+		s.handlerConfig.policy = nil
+		return nil
+	}
+	if err := policy.validate(); err != nil {
+		return err
+	}
+	s.handlerConfig.policy = policy.enforce()
+	return nil
+}
+
+// SetAuditLog configures the server to record every served call into the given
+// audit log, including the hash of the response if hashResponses is set. A nil
+// log disables auditing. The log is not closed by the server.
+//
+// This method should be called before processing any requests via ServeCodec,
+// ServeHTTP, ServeListener etc.
+func (s *Server) SetAuditLog(log AuditLog, hashResponses bool) {
+	s.handlerConfig.audit = log
+	s.handlerConfig.auditHashes = hashResponses
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
 // the response back using the given codec. It will block until the codec is closed or the
 // server is stopped. In either case the codec is closed.
@@ -84,7 +127,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.codecs.Add(codec)
 	defer s.codecs.Remove(codec)
 
-	c := initClient(codec, s.idgen, &s.services)
+	c := initClient(codec, s.idgen, &s.services, s.handlerConfig)
 	<-codec.closed()
 	c.Close()
 }
@@ -98,7 +141,7 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 		return
 	}
 
-	h := newHandler(ctx, codec, s.idgen, &s.services)
+	h := newHandler(ctx, codec, s.idgen, &s.services, s.handlerConfig)
 	h.allowSubscribe = false
 	defer h.close(io.EOF, nil)
 
@@ -147,6 +190,11 @@ func (s *RPCService) Modules() map[string]string {
 	return modules
 }
 
+// Discover returns the OpenRPC document describing the methods of the server.
+func (s *RPCService) Discover() *OpenRPCDocument {
+	return s.server.OpenRPC()
+}
+
 // PeerInfo contains information about the remote end of the network connection.
 //
 // This is available within RPC method handlers through the context. Call
@@ -168,6 +216,8 @@ type PeerInfo struct {
 		UserAgent string
 		Origin    string
 		Host      string
+		// API key presented by the client, either as header or in the URL path.
+		APIKey string
 	}
 }
 
@@ -34,6 +34,8 @@ var (
 	errorType        = reflect.TypeOf((*error)(nil)).Elem()
 	subscriptionType = reflect.TypeOf(Subscription{})
 	stringType       = reflect.TypeOf("")
+
+	documentedServiceType = reflect.TypeOf((*DocumentedService)(nil)).Elem()
 )
 
 type serviceRegistry struct {
@@ -56,6 +58,7 @@ type callback struct {
 	hasCtx      bool           // method's first argument is a context (not included in argTypes)
 	errPos      int            // err return idx, of -1 when method cannot return error
 	isSubscribe bool           // true if this is a subscription callback
+	doc         *MethodDoc     // annotation for the OpenRPC document, if any
 }
 
 func (r *serviceRegistry) registerName(name string, rcvr interface{}) error {
@@ -67,6 +70,14 @@ func (r *serviceRegistry) registerName(name string, rcvr interface{}) error {
 	if len(callbacks) == 0 {
 		return fmt.Errorf("service %T doesn't have any suitable methods/subscriptions to expose", rcvr)
 	}
+	if documented, ok := rcvr.(DocumentedService); ok {
+		for method, doc := range documented.MethodDocs() {
+			if cb := callbacks[formatName(method)]; cb != nil {
+				doc := doc
+				cb.doc = &doc
+			}
+		}
+	}
 
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -121,6 +132,9 @@ func suitableCallbacks(receiver reflect.Value) map[string]*callback {
 		if method.PkgPath != "" {
 			continue // method not exported
 		}
+		if method.Name == "MethodDocs" && typ.Implements(documentedServiceType) {
+			continue // OpenRPC annotations
+		}
 		cb := newCallback(receiver, method.Func)
 		if cb == nil {
 			continue // function invalid
@@ -0,0 +1,427 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httputil"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sseContentType is the content type of subscription streams, which carry the
+	// subscription response and its notifications as server-sent events.
+	sseContentType = "text/event-stream"
+
+	// sseKeepAliveInterval is the interval of the comments sent on idle streams to
+	// keep intermediate load balancers from timing out the connection.
+	sseKeepAliveInterval = 15 * time.Second
+)
+
+var errStreamClosed = errors.New("stream closed")
+
+// IsStreamRequest reports whether an HTTP request asks for a subscription to be
+// streamed as server-sent events. The responses to such requests must not be
+// buffered or compressed by intermediate handlers.
+func IsStreamRequest(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("accept"), ",") {
+		if mt, _, err := mime.ParseMediaType(strings.TrimSpace(accept)); err == nil && mt == sseContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// serveStream serves a single subscription request over HTTP, streaming the
+// notifications to the client until either side closes the connection.
+func (s *Server) serveStream(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var msg jsonrpcMessage
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxRequestContentLength)).Decode(&msg); err != nil {
+		w.Header().Set("content-type", contentType)
+		json.NewEncoder(w).Encode(errorMessage(&invalidMessageError{"parse error"}))
+		return
+	}
+	if !msg.isSubscribe() || !msg.hasValidID() {
+		w.Header().Set("content-type", contentType)
+		json.NewEncoder(w).Encode(msg.errorResponse(&invalidRequestError{"only subscriptions can be streamed"}))
+		return
+	}
+	codec, err := newSSEServerCodec(w, r, &msg, PeerInfoFromContext(ctx))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.ServeCodec(codec, 0)
+}
+
+// sseServerCodec is the server side codec of a subscription stream. It yields the
+// subscription request once and writes all responses as server-sent events.
+//
+// On HTTP/1.x the connection is taken over from the HTTP server and the events
+// are written as chunked body, so the stream is not bound by the write timeout
+// of the server. Other protocol versions flush every event through the response
+// writer instead.
+type sseServerCodec struct {
+	info PeerInfo
+	req  *jsonrpcMessage
+
+	mu     sync.Mutex // protects writes and closing
+	w      io.Writer
+	flush  func() error
+	finish func()
+	done   bool
+
+	readOnce  sync.Once
+	closeOnce sync.Once
+	closeCh   chan interface{}
+}
+
+func newSSEServerCodec(w http.ResponseWriter, r *http.Request, req *jsonrpcMessage, info PeerInfo) (*sseServerCodec, error) {
+	c := &sseServerCodec{info: info, req: req, closeCh: make(chan interface{})}
+
+	if hj, ok := w.(http.Hijacker); ok && r.ProtoMajor == 1 && r.ProtoAtLeast(1, 1) {
+		conn, rw, err := hj.Hijack()
+		if err != nil {
+			return nil, err
+		}
+		conn.SetDeadline(time.Time{})
+
+		header := w.Header().Clone()
+		header.Set("content-type", sseContentType)
+		header.Set("cache-control", "no-cache")
+		header.Set("transfer-encoding", "chunked")
+		header.Set("connection", "close")
+		fmt.Fprintf(rw, "HTTP/%d.%d 200 OK\r\n", r.ProtoMajor, r.ProtoMinor)
+		header.Write(rw)
+		rw.WriteString("\r\n")
+
+		chunked := httputil.NewChunkedWriter(rw)
+		c.w, c.flush = chunked, rw.Flush
+		c.finish = func() {
+			chunked.Close()
+			rw.WriteString("\r\n")
+			rw.Flush()
+			conn.Close()
+		}
+		// The client sends nothing after the request, so a read only returns once
+		// the connection is gone.
+		go func() {
+			io.Copy(io.Discard, rw)
+			c.close()
+		}()
+	} else {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			return nil, errors.New("streaming unsupported")
+		}
+		w.Header().Set("content-type", sseContentType)
+		w.Header().Set("cache-control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+
+		c.w, c.flush = w, func() error { flusher.Flush(); return nil }
+		c.finish = func() {}
+		go func() {
+			select {
+			case <-r.Context().Done():
+				c.close()
+			case <-c.closeCh:
+			}
+		}()
+	}
+	if err := c.flush(); err != nil {
+		c.close()
+		return nil, err
+	}
+	go c.keepAlive()
+	return c, nil
+}
+
+// keepAlive sends a comment on the stream periodically until it is closed.
+func (c *sseServerCodec) keepAlive() {
+	ticker := time.NewTicker(sseKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.write([]byte(": keepalive\n\n")); err != nil {
+				c.close()
+				return
+			}
+		case <-c.closeCh:
+			return
+		}
+	}
+}
+
+func (c *sseServerCodec) write(event []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.done {
+		return errStreamClosed
+	}
+	if _, err := c.w.Write(event); err != nil {
+		return err
+	}
+	return c.flush()
+}
+
+func (c *sseServerCodec) peerInfo() PeerInfo {
+	return c.info
+}
+
+func (c *sseServerCodec) remoteAddr() string {
+	return c.info.RemoteAddr
+}
+
+func (c *sseServerCodec) readBatch() ([]*jsonrpcMessage, bool, error) {
+	var req *jsonrpcMessage
+	c.readOnce.Do(func() { req = c.req })
+	if req != nil {
+		return []*jsonrpcMessage{req}, false, nil
+	}
+	<-c.closeCh
+	return nil, false, io.EOF
+}
+
+func (c *sseServerCodec) writeJSON(ctx context.Context, v interface{}) error {
+	blob, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var event bytes.Buffer
+	event.WriteString("data: ")
+	event.Write(blob)
+	event.WriteString("\n\n")
+	if err := c.write(event.Bytes()); err != nil {
+		return err
+	}
+	// A failed subscription ends the stream, there's nothing to follow.
+	if msg, ok := v.(*jsonrpcMessage); ok && msg.Error != nil {
+		c.close()
+	}
+	return nil
+}
+
+func (c *sseServerCodec) close() {
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.done = true
+		c.finish()
+		c.mu.Unlock()
+		close(c.closeCh)
+	})
+}
+
+func (c *sseServerCodec) closed() <-chan interface{} {
+	return c.closeCh
+}
+
+// sseReader splits a stream of server-sent events into their data fields.
+type sseReader struct {
+	r *bufio.Reader
+}
+
+func newSSEReader(r io.Reader) *sseReader {
+	return &sseReader{r: bufio.NewReader(r)}
+}
+
+// next returns the data of the next event, skipping comments and events without
+// data.
+func (r *sseReader) next() ([]byte, error) {
+	var data []byte
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		line = bytes.TrimRight(line, "\r\n")
+		switch {
+		case len(line) == 0:
+			if len(data) > 0 {
+				return data, nil
+			}
+		case line[0] == ':':
+			// Comment, used as keepalive
+		case bytes.HasPrefix(line, []byte("data:")):
+			if len(data) > 0 {
+				data = append(data, '\n')
+			}
+			data = append(data, bytes.TrimPrefix(line[len("data:"):], []byte(" "))...)
+		}
+	}
+}
+
+// DialSSE creates a new RPC client that connects to an RPC server over HTTP.
+// Unlike clients created by DialHTTP, it supports subscriptions, streaming the
+// notifications of each one over a separate HTTP request.
+func DialSSE(endpoint string) (*Client, error) {
+	return DialSSEWithClient(endpoint, new(http.Client))
+}
+
+// DialSSEWithClient creates a new RPC client that connects to an RPC server over
+// HTTP using the provided HTTP Client, streaming subscriptions like DialSSE.
+//
+// The client must not time out requests, as that would end the subscriptions.
+func DialSSEWithClient(endpoint string, client *http.Client) (*Client, error) {
+	c, err := DialHTTPWithClient(endpoint, client)
+	if err != nil {
+		return nil, err
+	}
+	c.writeConn.(*httpConn).streaming = true
+	return c, nil
+}
+
+// subscribeStream creates a subscription whose notifications are streamed over
+// a dedicated HTTP request.
+func (c *Client) subscribeStream(ctx context.Context, namespace string, channel reflect.Value, args ...interface{}) (*ClientSubscription, error) {
+	hc := c.writeConn.(*httpConn)
+	msg, err := c.newMessage(namespace+subscribeMethodSuffix, args...)
+	if err != nil {
+		return nil, err
+	}
+	// The stream outlives the context of the call, which only bounds the time to
+	// establish the subscription.
+	streamCtx, cancel := context.WithCancel(context.Background())
+	established := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			cancel()
+		case <-hc.closeCh:
+			cancel()
+		case <-established:
+		}
+	}()
+	resp, err := hc.do(streamCtx, msg, http.Header{"Accept": {sseContentType}})
+	if err != nil {
+		close(established)
+		cancel()
+		return nil, err
+	}
+	first, events, err := readStreamResponse(resp)
+	close(established)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, err
+	}
+	sub := newClientSubscription(c, namespace, channel)
+	if err := json.Unmarshal(first.Result, &sub.subid); err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, err
+	}
+	sub.closeStream = cancel
+	go sub.run()
+	go c.readStream(sub, events, resp.Body, streamCtx, cancel)
+	return sub, nil
+}
+
+// readStreamResponse reads the response to a subscription request, returning
+// the event reader of the notifications following it. Servers not supporting
+// streams answer with a plain JSON-RPC response.
+func readStreamResponse(resp *http.Response) (*jsonrpcMessage, *sseReader, error) {
+	var (
+		msg      jsonrpcMessage
+		events   *sseReader
+		mt, _, _ = mime.ParseMediaType(resp.Header.Get("content-type"))
+	)
+	if mt == sseContentType {
+		events = newSSEReader(resp.Body)
+		data, err := events.next()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, nil, err
+		}
+	} else if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case msg.Error != nil:
+		return nil, nil, msg.Error
+	case events == nil:
+		return nil, nil, ErrNotificationsUnsupported
+	}
+	return &msg, events, nil
+}
+
+// readStream delivers the notifications of a streamed subscription until the
+// stream ends.
+func (c *Client) readStream(sub *ClientSubscription, events *sseReader, body io.Closer, ctx context.Context, cancel func()) {
+	defer cancel()
+	defer body.Close()
+
+	hc := c.writeConn.(*httpConn)
+	go func() {
+		select {
+		case <-hc.closeCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	for {
+		data, err := events.next()
+		if err != nil {
+			select {
+			case <-hc.closeCh:
+				err = ErrClientQuit
+			default:
+				if err == io.EOF {
+					err = errStreamClosed
+				}
+			}
+			sub.close(err)
+			return
+		}
+		var msg jsonrpcMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			sub.close(err)
+			return
+		}
+		if !msg.isNotification() || msg.Method != sub.namespace+notificationMethodSuffix {
+			continue
+		}
+		var result subscriptionResult
+		if err := json.Unmarshal(msg.Params, &result); err != nil || result.ID != sub.subid {
+			continue
+		}
+		if !sub.deliver(result.Result) {
+			return
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEReader(t *testing.T) {
+	stream := ": keepalive\n\ndata: {\"a\":1}\n\nevent: x\r\ndata: first\r\ndata: second\r\n\r\n\n\ndata: partial"
+	r := newSSEReader(strings.NewReader(stream))
+	for i, want := range []string{`{"a":1}`, "first\nsecond"} {
+		data, err := r.next()
+		if err != nil {
+			t.Fatalf("event %d: read failed: %v", i, err)
+		}
+		if string(data) != want {
+			t.Errorf("event %d: data mismatch: have %q, want %q", i, data, want)
+		}
+	}
+	if _, err := r.next(); err != io.ErrUnexpectedEOF {
+		t.Errorf("truncated event error mismatch: have %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestSSESubscriptionHijack(t *testing.T) {
+	testSSESubscription(t, func(srv *Server) http.Handler { return srv })
+}
+
+// This test serves the stream through a response writer that can't be hijacked,
+// as is the case for HTTP/2 connections.
+func TestSSESubscriptionFlush(t *testing.T) {
+	testSSESubscription(t, func(srv *Server) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			srv.ServeHTTP(struct {
+				http.ResponseWriter
+				http.Flusher
+			}{w, w.(http.Flusher)}, r)
+		})
+	})
+}
+
+func testSSESubscription(t *testing.T, handler func(*Server) http.Handler) {
+	var (
+		server  = NewServer()
+		service = &notificationTestService{unsubscribed: make(chan string, 1)}
+	)
+	defer server.Stop()
+	if err := server.RegisterName("nftest", service); err != nil {
+		t.Fatal(err)
+	}
+	hs := httptest.NewServer(handler(server))
+	defer hs.Close()
+
+	client, err := DialSSE(hs.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Notifications are streamed until unsubscribing
+	nc := make(chan int)
+	count := 10
+	sub, err := client.Subscribe(context.Background(), "nftest", nc, "someSubscription", count, 0)
+	if err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	for i := 0; i < count; i++ {
+		if val := <-nc; val != i {
+			t.Fatalf("value mismatch: got %d, want %d", val, i)
+		}
+	}
+	sub.Unsubscribe()
+	select {
+	case err := <-sub.Err():
+		if err != nil {
+			t.Fatalf("Err returned a non-nil error after explicit unsubscribe: %q", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("subscription not closed within 1s after unsubscribe")
+	}
+	select {
+	case <-service.unsubscribed:
+	case <-time.After(time.Second):
+		t.Fatalf("server side subscription not closed within 1s after unsubscribe")
+	}
+	// Failed subscriptions end the stream right away
+	if _, err := client.Subscribe(context.Background(), "nftest", nc, "unknownSubscription"); err == nil {
+		t.Fatal("subscribing to unknown subscription succeeded")
+	}
+	// Closing the client ends all streams without error
+	if _, err = client.Subscribe(context.Background(), "nftest", nc, "someSubscription", 0, 0); err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	sub, err = client.Subscribe(context.Background(), "nftest", nc, "someSubscription", 0, 0)
+	if err != nil {
+		t.Fatal("can't subscribe:", err)
+	}
+	client.Close()
+	select {
+	case err := <-sub.Err():
+		if err != nil {
+			t.Fatalf("Err returned a non-nil error after closing the client: %q", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("subscription not closed within 1s after closing the client")
+	}
+}
+
+// This test checks that clients not dialed for streaming keep rejecting subscriptions
+// and that non-subscription calls can't be streamed.
+func TestSSEUnsupported(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	hs := httptest.NewServer(server)
+	defer hs.Close()
+
+	client, err := DialHTTP(hs.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if _, err := client.Subscribe(context.Background(), "nftest", make(chan int), "someSubscription", 1, 0); err != ErrNotificationsUnsupported {
+		t.Fatalf("subscription error mismatch: have %v, want %v", err, ErrNotificationsUnsupported)
+	}
+	req, _ := http.NewRequest("POST", hs.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"test_echo","params":[]}`))
+	req.Header.Set("content-type", contentType)
+	req.Header.Set("accept", sseContentType)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if _, _, err := readStreamResponse(resp); err == nil || !strings.Contains(err.Error(), "only subscriptions") {
+		t.Fatalf("streamed call error mismatch: have %v", err)
+	}
+}
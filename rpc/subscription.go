@@ -223,6 +223,10 @@ type ClientSubscription struct {
 	quit        chan error
 	forwardDone chan struct{}
 	unsubDone   chan struct{}
+
+	// closeStream ends the HTTP stream of subscriptions created by DialSSE clients,
+	// which unsubscribes on the server side.
+	closeStream func()
 }
 
 // This is the sentinel value sent on sub.quit when Unsubscribe is called.
@@ -370,6 +374,10 @@ func (sub *ClientSubscription) unmarshal(result json.RawMessage) (interface{}, e
 }
 
 func (sub *ClientSubscription) requestUnsubscribe() error {
+	if sub.closeStream != nil {
+		sub.closeStream()
+		return nil
+	}
 	var result interface{}
 	return sub.client.Call(&result, sub.namespace+unsubscribeMethodSuffix, sub.subid)
 }
@@ -33,13 +33,15 @@ type leaf struct {
 // insertion order.
 type committer struct {
 	nodes       *NodeSet
+	tracer      *tracer
 	collectLeaf bool
 }
 
 // newCommitter creates a new committer or picks one from the pool.
-func newCommitter(owner common.Hash, collectLeaf bool) *committer {
+func newCommitter(owner common.Hash, tracer *tracer, collectLeaf bool) *committer {
 	return &committer{
 		nodes:       NewNodeSet(owner),
+		tracer:      tracer,
 		collectLeaf: collectLeaf,
 	}
 }
@@ -50,7 +52,19 @@ func (c *committer) Commit(n node) (hashNode, *NodeSet, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	return h.(hashNode), c.nodes, nil
+	return h.(hashNode), c.commitDeletions(), nil
+}
+
+// commitDeletions marks the nodes removed from the trie, which were loaded from
+// the database, as deleted in the node set. Nothing is tracked if the tracer
+// isn't enabled.
+func (c *committer) commitDeletions() *NodeSet {
+	for _, path := range c.tracer.deleteList() {
+		if c.tracer.getPrev(path) != nil {
+			c.nodes.MarkDeleted(path)
+		}
+	}
+	return c.nodes
 }
 
 // commit collapses a node down into a hash node and inserts it into the database
@@ -146,6 +160,11 @@ func (c *committer) store(path []byte, n node) node {
 	// usually is leaf node). But small value(less than 32bytes) is not
 	// our target(leaves in account trie only).
 	if hash == nil {
+		// If the node was stored on its own before, the stale copy has to be
+		// deleted as it's embedded in the parent now.
+		if c.tracer.getPrev(path) != nil {
+			c.nodes.MarkDeleted(path)
+		}
 		return n
 	}
 	// We have the hash already, estimate the RLP encoding-size of the node.
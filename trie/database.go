@@ -87,6 +87,8 @@ type Database struct {
 	childrenSize common.StorageSize // Storage size of the external children tracking
 	preimages    *preimageStore     // The store for caching preimages
 
+	path *pathDB // Path-based node storage, nil if nodes are stored by hash
+
 	lock sync.RWMutex
 }
 
@@ -268,6 +270,7 @@ type Config struct {
 	Cache     int    // Memory allowance (MB) to use for caching trie nodes in memory
 	Journal   string // Journal of clean cache to survive node restarts
 	Preimages bool   // Flag whether the preimage of trie key is recorded
+	Scheme    string // Scheme the trie nodes are stored with, the hash scheme if empty
 }
 
 // NewDatabase creates a new trie database to store ephemeral trie content before
@@ -301,9 +304,20 @@ func NewDatabaseWithConfig(diskdb ethdb.KeyValueStore, config *Config) *Database
 		}},
 		preimages: preimage,
 	}
+	if config != nil && config.Scheme == rawdb.PathScheme {
+		db.path = newPathDB(diskdb, cleans)
+	}
 	return db
 }
 
+// Scheme returns the scheme the trie nodes are stored with.
+func (db *Database) Scheme() string {
+	if db.path != nil {
+		return rawdb.PathScheme
+	}
+	return rawdb.HashScheme
+}
+
 // DiskDB retrieves the persistent storage backing the trie database.
 func (db *Database) DiskDB() ethdb.KeyValueStore {
 	return db.diskdb
@@ -383,8 +397,12 @@ func (db *Database) node(hash common.Hash) node {
 }
 
 // Node retrieves an encoded cached trie node from memory. If it cannot be found
-// cached, the method queries the persistent database for the content.
+// cached, the method queries the persistent database for the content. Nodes
+// can't be retrieved by hash alone in the path scheme.
 func (db *Database) Node(hash common.Hash) ([]byte, error) {
+	if db.path != nil {
+		return nil, errUnsupportedScheme
+	}
 	// It doesn't make sense to retrieve the metaroot
 	if hash == (common.Hash{}) {
 		return nil, errors.New("not found")
@@ -443,6 +461,10 @@ func (db *Database) Nodes() []common.Hash {
 // and external node(e.g. storage trie root), all internal trie nodes
 // are referenced together by database itself.
 func (db *Database) Reference(child common.Hash, parent common.Hash) {
+	// The path scheme doesn't track references, states are kept in layers
+	if db.path != nil {
+		return
+	}
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
@@ -477,6 +499,9 @@ func (db *Database) Dereference(root common.Hash) {
 		log.Error("Attempted to dereference the trie cache meta root")
 		return
 	}
+	if db.path != nil {
+		return
+	}
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
@@ -546,11 +571,20 @@ func (db *Database) dereference(child common.Hash, parent common.Hash) {
 }
 
 // Cap iteratively flushes old but still referenced trie nodes until the total
-// memory usage goes below the given threshold.
+// memory usage goes below the given threshold. In the path scheme, the oldest
+// diff layers of the most recent state are flushed.
 //
 // Note, this method is a non-synchronized mutator. It is unsafe to call this
 // concurrently with other mutators.
 func (db *Database) Cap(limit common.StorageSize) error {
+	if db.path != nil {
+		if db.preimages != nil {
+			if err := db.preimages.commit(false); err != nil {
+				return err
+			}
+		}
+		return db.path.capSize(limit)
+	}
 	// Create a database batch to flush persistent data out. It is important that
 	// outside code doesn't see an inconsistent state (referenced data removed from
 	// memory cache during commit but not yet in persistent storage). This is ensured
@@ -633,9 +667,20 @@ func (db *Database) Cap(limit common.StorageSize) error {
 // to disk, forcefully tearing down all references in both directions. As a side
 // effect, all pre-images accumulated up to this point are also written.
 //
+// In the path scheme, the node is a state root and the diff layers leading to
+// it are flushed to disk. The callback isn't invoked.
+//
 // Note, this method is a non-synchronized mutator. It is unsafe to call this
 // concurrently with other mutators.
 func (db *Database) Commit(node common.Hash, report bool, callback func(common.Hash)) error {
+	if db.path != nil {
+		if db.preimages != nil {
+			if err := db.preimages.commit(true); err != nil {
+				return err
+			}
+		}
+		return db.path.commit(node)
+	}
 	// Create a database batch to flush persistent data out. It is important that
 	// outside code doesn't see an inconsistent state (referenced data removed from
 	// memory cache during commit but not yet in persistent storage). This is ensured
@@ -769,8 +814,13 @@ func (c *cleaner) Delete(key []byte) error {
 }
 
 // Update inserts the dirty nodes in provided nodeset into database and
-// link the account trie with multiple storage tries if necessary.
+// link the account trie with multiple storage tries if necessary. It's only
+// supported in the hash scheme, use UpdateState to insert the nodes of a
+// state transition in either scheme.
 func (db *Database) Update(nodes *MergedNodeSet) error {
+	if db.path != nil {
+		return errUnsupportedScheme
+	}
 	db.lock.Lock()
 	defer db.lock.Unlock()
 
@@ -797,6 +847,10 @@ func (db *Database) Update(nodes *MergedNodeSet) error {
 			if !ok {
 				return fmt.Errorf("missing node %x %v", owner, path)
 			}
+			// Deleted nodes are left to the garbage collection
+			if n.isDeleted() {
+				continue
+			}
 			db.insert(n.hash, int(n.size), n.node)
 		}
 	}
@@ -816,9 +870,47 @@ func (db *Database) Update(nodes *MergedNodeSet) error {
 	return nil
 }
 
+// UpdateState inserts the dirty nodes of the transition from the parent state
+// to the given one into the database. In the path scheme, they are kept as a
+// diff layer until enough newer states are added, at which point the oldest
+// layers are flushed to disk.
+func (db *Database) UpdateState(root common.Hash, parent common.Hash, nodes *MergedNodeSet) error {
+	if db.path != nil {
+		return db.path.update(root, parent, nodes)
+	}
+	return db.Update(nodes)
+}
+
+// Recoverable reports whether the persisted state can be rewound to the given
+// state root. It's only possible in the path scheme.
+func (db *Database) Recoverable(root common.Hash) bool {
+	if db.path == nil {
+		return false
+	}
+	return db.path.recoverable(root)
+}
+
+// Recover rewinds the persisted state to the given state root, discarding all
+// newer states kept in memory. It's only supported in the path scheme.
+func (db *Database) Recover(root common.Hash) error {
+	if db.path == nil {
+		return errUnsupportedScheme
+	}
+	return db.path.recover(root)
+}
+
 // Size returns the current storage size of the memory cache in front of the
 // persistent database layer.
 func (db *Database) Size() (common.StorageSize, common.StorageSize) {
+	if db.path != nil {
+		var preimageSize common.StorageSize
+		if db.preimages != nil {
+			preimageSize = db.preimages.size()
+		}
+		db.path.lock.RLock()
+		defer db.path.lock.RUnlock()
+		return db.path.size, preimageSize
+	}
 	db.lock.RLock()
 	defer db.lock.RUnlock()
 
@@ -874,6 +966,15 @@ func (db *Database) SaveCachePeriodically(dir string, interval time.Duration, st
 	}
 }
 
+// Preimage retrieves the pre-image of a hashed key, either from the cached
+// ones or from the persistent database.
+func (db *Database) Preimage(hash common.Hash) []byte {
+	if db.preimages == nil {
+		return rawdb.ReadPreimage(db.diskdb, hash)
+	}
+	return db.preimages.preimage(hash)
+}
+
 // CommitPreimages flushes the dangling preimages to disk. It is meant to be
 // called when closing the blockchain object, so that preimages are persisted
 // to the database.
@@ -44,7 +44,12 @@ func TestEmptyIterator(t *testing.T) {
 }
 
 func TestIterator(t *testing.T) {
-	db := NewDatabase(rawdb.NewMemoryDatabase())
+	testIterator(t, rawdb.HashScheme)
+	testIterator(t, rawdb.PathScheme)
+}
+
+func testIterator(t *testing.T, scheme string) {
+	db := newTestDatabase(rawdb.NewMemoryDatabase(), scheme)
 	trie := NewEmpty(db)
 	vals := []struct{ k, v string }{
 		{"do", "verb"},
@@ -64,7 +69,7 @@ func TestIterator(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to commit trie %v", err)
 	}
-	db.Update(NewWithNodeSet(nodes))
+	db.UpdateState(root, emptyRoot, NewWithNodeSet(nodes))
 
 	trie, _ = New(common.Hash{}, root, db)
 	found := make(map[string]string)
@@ -220,22 +225,27 @@ func checkIteratorOrder(want []kvs, it *Iterator) error {
 }
 
 func TestDifferenceIterator(t *testing.T) {
-	dba := NewDatabase(rawdb.NewMemoryDatabase())
+	testDifferenceIterator(t, rawdb.HashScheme)
+	testDifferenceIterator(t, rawdb.PathScheme)
+}
+
+func testDifferenceIterator(t *testing.T, scheme string) {
+	dba := newTestDatabase(rawdb.NewMemoryDatabase(), scheme)
 	triea := NewEmpty(dba)
 	for _, val := range testdata1 {
 		triea.Update([]byte(val.k), []byte(val.v))
 	}
 	rootA, nodesA, _ := triea.Commit(false)
-	dba.Update(NewWithNodeSet(nodesA))
+	dba.UpdateState(rootA, emptyRoot, NewWithNodeSet(nodesA))
 	triea, _ = New(common.Hash{}, rootA, dba)
 
-	dbb := NewDatabase(rawdb.NewMemoryDatabase())
+	dbb := newTestDatabase(rawdb.NewMemoryDatabase(), scheme)
 	trieb := NewEmpty(dbb)
 	for _, val := range testdata2 {
 		trieb.Update([]byte(val.k), []byte(val.v))
 	}
 	rootB, nodesB, _ := trieb.Commit(false)
-	dbb.Update(NewWithNodeSet(nodesB))
+	dbb.UpdateState(rootB, emptyRoot, NewWithNodeSet(nodesB))
 	trieb, _ = New(common.Hash{}, rootB, dbb)
 
 	found := make(map[string]string)
@@ -262,22 +272,27 @@ func TestDifferenceIterator(t *testing.T) {
 }
 
 func TestUnionIterator(t *testing.T) {
-	dba := NewDatabase(rawdb.NewMemoryDatabase())
+	testUnionIterator(t, rawdb.HashScheme)
+	testUnionIterator(t, rawdb.PathScheme)
+}
+
+func testUnionIterator(t *testing.T, scheme string) {
+	dba := newTestDatabase(rawdb.NewMemoryDatabase(), scheme)
 	triea := NewEmpty(dba)
 	for _, val := range testdata1 {
 		triea.Update([]byte(val.k), []byte(val.v))
 	}
 	rootA, nodesA, _ := triea.Commit(false)
-	dba.Update(NewWithNodeSet(nodesA))
+	dba.UpdateState(rootA, emptyRoot, NewWithNodeSet(nodesA))
 	triea, _ = New(common.Hash{}, rootA, dba)
 
-	dbb := NewDatabase(rawdb.NewMemoryDatabase())
+	dbb := newTestDatabase(rawdb.NewMemoryDatabase(), scheme)
 	trieb := NewEmpty(dbb)
 	for _, val := range testdata2 {
 		trieb.Update([]byte(val.k), []byte(val.v))
 	}
 	rootB, nodesB, _ := trieb.Commit(false)
-	dbb.Update(NewWithNodeSet(nodesB))
+	dbb.UpdateState(rootB, emptyRoot, NewWithNodeSet(nodesB))
 	trieb, _ = New(common.Hash{}, rootB, dbb)
 
 	di, _ := NewUnionIterator([]NodeIterator{triea.NodeIterator(nil), trieb.NodeIterator(nil)})
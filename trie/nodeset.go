@@ -30,6 +30,12 @@ type memoryNode struct {
 	node node        // Cached collapsed trie node, or raw rlp data
 }
 
+// isDeleted returns whether the node marks the deletion of the node previously
+// stored at its path.
+func (n *memoryNode) isDeleted() bool {
+	return n.hash == (common.Hash{})
+}
+
 // NodeSet contains all dirty nodes collected during the commit operation.
 // Each node is keyed by path. It's not thread-safe to use.
 type NodeSet struct {
@@ -55,6 +61,16 @@ func (set *NodeSet) add(path string, node *memoryNode) {
 	set.nodes[path] = node
 }
 
+// MarkDeleted records the deletion of the node stored at the given path, unless
+// a new node is stored there. Deletions are only tracked in the path scheme,
+// where the stale nodes are removed from disk.
+func (set *NodeSet) MarkDeleted(path []byte) {
+	if _, ok := set.nodes[string(path)]; ok {
+		return
+	}
+	set.add(string(path), &memoryNode{})
+}
+
 // addLeaf caches the provided leaf node.
 func (set *NodeSet) addLeaf(node *leaf) {
 	set.leaves = append(set.leaves, node)
@@ -0,0 +1,502 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/fastcache"
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/crypto"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb"
+	"github.com/Altcoinchain/go-altcoinchain/log"
+	"github.com/Altcoinchain/go-altcoinchain/rlp"
+)
+
+const (
+	// maxDiffLayers is the number of states kept in memory on top of the
+	// persisted one, matching the number of recent states kept by the
+	// snapshot tree.
+	maxDiffLayers = 128
+
+	// maxReverseDiffs is the number of reverse diffs kept on disk, which is
+	// how far the persisted state can be rewound.
+	maxReverseDiffs = 90000
+)
+
+var (
+	// errStateUnavailable is returned if a state is neither persisted nor kept
+	// in a diff layer.
+	errStateUnavailable = errors.New("state not available")
+
+	// errStateUnrecoverable is returned if the persisted state can't be rewound
+	// to the requested state.
+	errStateUnrecoverable = errors.New("state not recoverable")
+
+	// errUnsupportedScheme is returned by the operations which are only
+	// available in the other storage scheme.
+	errUnsupportedScheme = errors.New("operation not supported by the state scheme")
+)
+
+// pathNode is a trie node kept in a diff layer.
+type pathNode struct {
+	hash common.Hash // Node hash, zero if the node was deleted
+	blob []byte      // RLP encoding of the node, nil if the node was deleted
+}
+
+// diffLayer holds the trie nodes changed by a state transition, keyed by the
+// owner of their trie and their path.
+type diffLayer struct {
+	root   common.Hash                          // State root of the layer
+	parent common.Hash                          // State root the layer is based on
+	nodes  map[common.Hash]map[string]*pathNode // Changed nodes by owner and path
+	size   common.StorageSize                   // Approximate memory used by the nodes
+}
+
+// reverseDiff holds the previous values of the trie nodes changed when a diff
+// layer was persisted, which is used to rewind the persisted state.
+type reverseDiff struct {
+	Parent common.Hash       // State root the diff reverts to
+	Root   common.Hash       // State root the diff is applied on
+	Nodes  []reverseDiffNode // Previous values of the changed nodes
+}
+
+// reverseDiffNode is the previous value of a trie node in a reverse diff.
+type reverseDiffNode struct {
+	Owner common.Hash // Owner of the trie, zero for the account trie
+	Path  []byte      // Path of the node in its trie
+	Blob  []byte      // Previous RLP encoding of the node, empty if it didn't exist
+}
+
+// pathDB is the path-based backend of the trie database. Only the latest
+// version of every trie node is persisted, keyed by the owner of its trie and
+// its path, so that stale nodes are overwritten or deleted as newer states are
+// flushed to disk. The recent states are kept as diff layers in memory and the
+// persisted state can be rewound with the reverse diffs stored when flushing.
+//
+// Nodes are looked up by path and verified against the requested hash, so that
+// any state kept in memory or on disk can be accessed without knowing which
+// layer it belongs to.
+type pathDB struct {
+	diskdb ethdb.KeyValueStore
+	cleans *fastcache.Cache // Clean node cache keyed by owner and path
+
+	root   common.Hash                // State root of the persisted trie nodes
+	head   common.Hash                // State root of the most recently added layer
+	layers map[common.Hash]*diffLayer // Diff layers keyed by their state root
+	index  map[string][]*pathNode     // Nodes of all diff layers keyed by owner and path
+	size   common.StorageSize         // Approximate memory used by the diff layers
+
+	lock sync.RWMutex
+}
+
+// newPathDB creates the path-based backend on top of the persisted state.
+func newPathDB(diskdb ethdb.KeyValueStore, cleans *fastcache.Cache) *pathDB {
+	db := &pathDB{
+		diskdb: diskdb,
+		cleans: cleans,
+		layers: make(map[common.Hash]*diffLayer),
+		index:  make(map[string][]*pathNode),
+	}
+	db.root = db.diskRoot()
+	db.head = db.root
+	return db
+}
+
+// pathNodeKey returns the key identifying a trie node across all tries.
+func pathNodeKey(owner common.Hash, path []byte) string {
+	return string(owner.Bytes()) + string(path)
+}
+
+// diskRoot derives the state root of the persisted trie nodes from the root
+// node of the account trie.
+func (db *pathDB) diskRoot() common.Hash {
+	blob := rawdb.ReadAccountTrieNode(db.diskdb, nil)
+	if len(blob) == 0 {
+		return emptyRoot
+	}
+	return crypto.Keccak256Hash(blob)
+}
+
+// readDisk retrieves a persisted trie node, nil if none is stored at the path.
+func (db *pathDB) readDisk(owner common.Hash, path []byte) []byte {
+	if owner == (common.Hash{}) {
+		return rawdb.ReadAccountTrieNode(db.diskdb, path)
+	}
+	return rawdb.ReadStorageTrieNode(db.diskdb, owner, path)
+}
+
+// node retrieves the RLP encoding of the trie node with the given hash, stored
+// at the path of the owner's trie. Nil is returned if the node is unavailable.
+// The returned blob must not be modified.
+func (db *pathDB) node(owner common.Hash, path []byte, hash common.Hash) []byte {
+	key := pathNodeKey(owner, path)
+
+	// Retrieve the node from the diff layers if available
+	db.lock.RLock()
+	for _, n := range db.index[key] {
+		if n.hash == hash {
+			db.lock.RUnlock()
+
+			memcacheDirtyHitMeter.Mark(1)
+			memcacheDirtyReadMeter.Mark(int64(len(n.blob)))
+			return n.blob
+		}
+	}
+	db.lock.RUnlock()
+	memcacheDirtyMissMeter.Mark(1)
+
+	// Retrieve the node from the clean cache if it's still the requested one
+	if db.cleans != nil {
+		if blob := db.cleans.Get(nil, []byte(key)); len(blob) != 0 && crypto.Keccak256Hash(blob) == hash {
+			memcacheCleanHitMeter.Mark(1)
+			memcacheCleanReadMeter.Mark(int64(len(blob)))
+			return blob
+		}
+	}
+	// Content unavailable in memory, attempt to retrieve from disk
+	blob := db.readDisk(owner, path)
+	if len(blob) == 0 || crypto.Keccak256Hash(blob) != hash {
+		return nil
+	}
+	if db.cleans != nil {
+		db.cleans.Set([]byte(key), blob)
+		memcacheCleanMissMeter.Mark(1)
+		memcacheCleanWriteMeter.Mark(int64(len(blob)))
+	}
+	return blob
+}
+
+// update adds the nodes changed by the transition from the parent state to the
+// given one as a new diff layer, flushing the oldest layers of the new state to
+// disk beyond maxDiffLayers.
+func (db *pathDB) update(root common.Hash, parent common.Hash, nodes *MergedNodeSet) error {
+	if parent == (common.Hash{}) {
+		parent = emptyRoot
+	}
+	// Nothing changed if the state root is the same (e.g. empty blocks)
+	if root == parent {
+		return nil
+	}
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if _, ok := db.layers[root]; ok || root == db.root {
+		return nil
+	}
+	if _, ok := db.layers[parent]; !ok && parent != db.root {
+		return fmt.Errorf("%w: parent %x", errStateUnavailable, parent)
+	}
+	layer := &diffLayer{
+		root:   root,
+		parent: parent,
+		nodes:  make(map[common.Hash]map[string]*pathNode),
+	}
+	for owner, set := range nodes.sets {
+		subset := make(map[string]*pathNode, len(set.nodes))
+		for path, n := range set.nodes {
+			item := &pathNode{hash: n.hash}
+			if !n.isDeleted() {
+				item.blob = nodeToBytes(n.node)
+				memcacheDirtyWriteMeter.Mark(int64(len(item.blob)))
+
+				key := pathNodeKey(owner, []byte(path))
+				db.index[key] = append(db.index[key], item)
+			}
+			subset[path] = item
+			layer.size += common.StorageSize(common.HashLength + len(path) + len(item.blob))
+		}
+		layer.nodes[owner] = subset
+	}
+	db.layers[root] = layer
+	db.head = root
+	db.size += layer.size
+
+	// Keep the number of layers bounded, discarding the oldest ones
+	layers := db.ancestry(root)
+	for len(layers) > maxDiffLayers {
+		if err := db.flush(layers[len(layers)-1]); err != nil {
+			return err
+		}
+		layers = layers[:len(layers)-1]
+	}
+	return nil
+}
+
+// ancestry returns the diff layers leading to the given state, starting with the
+// layer of the state itself and ending with the one based on the persisted state.
+func (db *pathDB) ancestry(root common.Hash) []*diffLayer {
+	var layers []*diffLayer
+	for {
+		layer, ok := db.layers[root]
+		if !ok {
+			return layers
+		}
+		layers = append(layers, layer)
+		root = layer.parent
+	}
+}
+
+// commit flushes the diff layers leading to the given state to disk.
+func (db *pathDB) commit(root common.Hash) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if root == db.root {
+		return nil
+	}
+	layers := db.ancestry(root)
+	if len(layers) == 0 {
+		return fmt.Errorf("%w: %x", errStateUnavailable, root)
+	}
+	start := time.Now()
+	for i := len(layers) - 1; i >= 0; i-- {
+		if err := db.flush(layers[i]); err != nil {
+			return err
+		}
+	}
+	log.Debug("Persisted path-based trie state", "root", root, "layers", len(layers), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// capSize flushes the oldest diff layers of the most recent state to disk until
+// the memory used by the layers is below the given limit.
+func (db *pathDB) capSize(limit common.StorageSize) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	layers := db.ancestry(db.head)
+	for db.size > limit && len(layers) > 0 {
+		if err := db.flush(layers[len(layers)-1]); err != nil {
+			return err
+		}
+		layers = layers[:len(layers)-1]
+	}
+	return nil
+}
+
+// flush persists a diff layer based on the persisted state, storing a reverse
+// diff along with the new nodes. The other layers based on the previously
+// persisted state are discarded.
+//
+// The caller must hold the write lock.
+func (db *pathDB) flush(layer *diffLayer) error {
+	if layer.parent != db.root {
+		return fmt.Errorf("layer %x not based on the persisted state %x", layer.root, db.root)
+	}
+	var (
+		start = time.Now()
+		batch = db.diskdb.NewBatch()
+		diff  = &reverseDiff{Parent: layer.parent, Root: layer.root}
+	)
+	for owner, subset := range layer.nodes {
+		for path, n := range subset {
+			diff.Nodes = append(diff.Nodes, reverseDiffNode{
+				Owner: owner,
+				Path:  []byte(path),
+				Blob:  db.readDisk(owner, []byte(path)),
+			})
+			db.writeNode(batch, owner, []byte(path), n.blob)
+		}
+	}
+	// Keep the encoding of the diff independent of the map iteration order
+	sort.Slice(diff.Nodes, func(i, j int) bool {
+		if c := bytes.Compare(diff.Nodes[i].Owner[:], diff.Nodes[j].Owner[:]); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(diff.Nodes[i].Path, diff.Nodes[j].Path) < 0
+	})
+	blob, err := rlp.EncodeToBytes(diff)
+	if err != nil {
+		return err
+	}
+	id := rawdb.ReadReverseDiffHead(db.diskdb) + 1
+	rawdb.WriteReverseDiff(batch, id, diff.Parent, blob)
+	rawdb.WriteReverseDiffHead(batch, id)
+	if id > maxReverseDiffs {
+		db.pruneReverseDiff(batch, id-maxReverseDiffs)
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	memcacheFlushTimeTimer.Update(time.Since(start))
+	memcacheFlushNodesMeter.Mark(int64(len(diff.Nodes)))
+	memcacheFlushSizeMeter.Mark(int64(layer.size))
+
+	// Discard the flushed layer and the ones which can't be applied anymore
+	db.root = layer.root
+	db.removeLayer(layer)
+	for _, sibling := range db.layers {
+		if sibling.parent == layer.parent {
+			db.discard(sibling)
+		}
+	}
+	if _, ok := db.layers[db.head]; !ok {
+		db.head = db.root
+	}
+	return nil
+}
+
+// writeNode persists a trie node, deleting it if the blob is empty, and keeps
+// the clean cache up to date.
+func (db *pathDB) writeNode(batch ethdb.KeyValueWriter, owner common.Hash, path []byte, blob []byte) {
+	if owner == (common.Hash{}) {
+		if len(blob) == 0 {
+			rawdb.DeleteAccountTrieNode(batch, path)
+		} else {
+			rawdb.WriteAccountTrieNode(batch, path, blob)
+		}
+	} else {
+		if len(blob) == 0 {
+			rawdb.DeleteStorageTrieNode(batch, owner, path)
+		} else {
+			rawdb.WriteStorageTrieNode(batch, owner, path, blob)
+		}
+	}
+	if db.cleans != nil {
+		key := []byte(pathNodeKey(owner, path))
+		if len(blob) == 0 {
+			db.cleans.Del(key)
+		} else {
+			db.cleans.Set(key, blob)
+		}
+	}
+}
+
+// pruneReverseDiff deletes the reverse diff with the given id, which is too old
+// to be retained.
+func (db *pathDB) pruneReverseDiff(batch ethdb.KeyValueWriter, id uint64) {
+	blob := rawdb.ReadReverseDiff(db.diskdb, id)
+	if len(blob) == 0 {
+		return
+	}
+	var diff reverseDiff
+	if err := rlp.DecodeBytes(blob, &diff); err != nil {
+		log.Error("Invalid reverse diff", "id", id, "err", err)
+		return
+	}
+	db.deleteReverseDiff(batch, id, diff.Parent)
+}
+
+// deleteReverseDiff removes a reverse diff along with the lookup of the state
+// root it reverts to, unless the lookup points to a diff of a recurring root.
+func (db *pathDB) deleteReverseDiff(batch ethdb.KeyValueWriter, id uint64, parent common.Hash) {
+	rawdb.DeleteReverseDiff(batch, id)
+	if lookup := rawdb.ReadReverseDiffLookup(db.diskdb, parent); lookup != nil && *lookup == id {
+		rawdb.DeleteReverseDiffLookup(batch, parent)
+	}
+}
+
+// removeLayer drops a diff layer and removes its nodes from the index.
+func (db *pathDB) removeLayer(layer *diffLayer) {
+	for owner, subset := range layer.nodes {
+		for path, n := range subset {
+			if n.blob == nil {
+				continue
+			}
+			key := pathNodeKey(owner, []byte(path))
+			list := db.index[key]
+			for i, item := range list {
+				if item == n {
+					list = append(list[:i], list[i+1:]...)
+					break
+				}
+			}
+			if len(list) == 0 {
+				delete(db.index, key)
+			} else {
+				db.index[key] = list
+			}
+		}
+	}
+	delete(db.layers, layer.root)
+	db.size -= layer.size
+}
+
+// discard drops a diff layer along with all layers based on it.
+func (db *pathDB) discard(layer *diffLayer) {
+	db.removeLayer(layer)
+	for _, child := range db.layers {
+		if child.parent == layer.root {
+			db.discard(child)
+		}
+	}
+}
+
+// recoverable reports whether the persisted state can be rewound to the given
+// state with the stored reverse diffs.
+func (db *pathDB) recoverable(root common.Hash) bool {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	id := rawdb.ReadReverseDiffLookup(db.diskdb, root)
+	return id != nil && *id <= rawdb.ReadReverseDiffHead(db.diskdb)
+}
+
+// recover rewinds the persisted state to the given one by applying the reverse
+// diffs, discarding all diff layers.
+func (db *pathDB) recover(root common.Hash) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if root == db.root {
+		return nil
+	}
+	id := rawdb.ReadReverseDiffLookup(db.diskdb, root)
+	head := rawdb.ReadReverseDiffHead(db.diskdb)
+	if id == nil || *id > head {
+		return fmt.Errorf("%w: %x", errStateUnrecoverable, root)
+	}
+	// The diff layers are based on the persisted state, drop them all
+	db.layers = make(map[common.Hash]*diffLayer)
+	db.index = make(map[string][]*pathNode)
+	db.size = 0
+
+	start := time.Now()
+	for ; head >= *id; head-- {
+		blob := rawdb.ReadReverseDiff(db.diskdb, head)
+		if len(blob) == 0 {
+			return fmt.Errorf("reverse diff %d missing", head)
+		}
+		var diff reverseDiff
+		if err := rlp.DecodeBytes(blob, &diff); err != nil {
+			return fmt.Errorf("invalid reverse diff %d: %v", head, err)
+		}
+		if diff.Root != db.root {
+			return fmt.Errorf("reverse diff %d applies to %x, persisted state %x", head, diff.Root, db.root)
+		}
+		batch := db.diskdb.NewBatch()
+		for _, n := range diff.Nodes {
+			db.writeNode(batch, n.Owner, n.Path, n.Blob)
+		}
+		db.deleteReverseDiff(batch, head, diff.Parent)
+		rawdb.WriteReverseDiffHead(batch, head-1)
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		db.root = diff.Parent
+	}
+	db.head = db.root
+	log.Info("Rewound path-based trie state", "root", root, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
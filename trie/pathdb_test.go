@@ -0,0 +1,258 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package trie
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/Altcoinchain/go-altcoinchain/common"
+	"github.com/Altcoinchain/go-altcoinchain/core/rawdb"
+	"github.com/Altcoinchain/go-altcoinchain/crypto"
+	"github.com/Altcoinchain/go-altcoinchain/ethdb/memorydb"
+)
+
+// pathTester commits random modifications of a trie to a path-based database,
+// tracking the contents of every state.
+type pathTester struct {
+	diskdb *memorydb.Database
+	db     *Database
+	rand   *rand.Rand
+	roots  []common.Hash
+	states map[common.Hash]map[string][]byte
+}
+
+func newPathTester() *pathTester {
+	diskdb := memorydb.New()
+	return &pathTester{
+		diskdb: diskdb,
+		db:     NewDatabaseWithConfig(diskdb, &Config{Cache: 1, Scheme: rawdb.PathScheme}),
+		rand:   rand.New(rand.NewSource(1)),
+		states: map[common.Hash]map[string][]byte{emptyRoot: {}},
+	}
+}
+
+// update applies random changes to the given state and adds the result as a new
+// state, returning its root.
+func (pt *pathTester) update(t *testing.T, parent common.Hash) common.Hash {
+	tr, err := New(common.Hash{}, parent, pt.db)
+	if err != nil {
+		t.Fatalf("failed to open state %x: %v", parent, err)
+	}
+	content := make(map[string][]byte)
+	for key, val := range pt.states[parent] {
+		content[key] = val
+	}
+	for i := 0; i < 20; i++ {
+		// Pick the keys from a small range to update and delete existing ones,
+		// and use short values to move nodes in and out of their parents.
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, uint64(pt.rand.Intn(300)))
+		key = crypto.Keccak256(key)
+
+		if pt.rand.Intn(3) == 0 {
+			tr.Delete(key)
+			delete(content, string(key))
+			continue
+		}
+		val := make([]byte, 1+pt.rand.Intn(40))
+		pt.rand.Read(val)
+		tr.Update(key, val)
+		content[string(key)] = val
+	}
+	root, set, err := tr.Commit(false)
+	if err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	nodes := NewMergedNodeSet()
+	if set != nil {
+		nodes.Merge(set)
+	}
+	if err := pt.db.UpdateState(root, parent, nodes); err != nil {
+		t.Fatalf("failed to update state %x: %v", root, err)
+	}
+	pt.roots = append(pt.roots, root)
+	pt.states[root] = content
+	return root
+}
+
+// check verifies that the given state is accessible and holds the expected
+// content.
+func (pt *pathTester) check(t *testing.T, root common.Hash) {
+	t.Helper()
+
+	tr, err := New(common.Hash{}, root, pt.db)
+	if err != nil {
+		t.Fatalf("failed to open state %x: %v", root, err)
+	}
+	content := pt.states[root]
+	it := NewIterator(tr.NodeIterator(nil))
+	var leaves int
+	for it.Next() {
+		if want := content[string(it.Key)]; !bytes.Equal(it.Value, want) {
+			t.Fatalf("state %x: value mismatch for %x: have %x, want %x", root, it.Key, it.Value, want)
+		}
+		leaves++
+	}
+	if it.Err != nil {
+		t.Fatalf("state %x: failed to iterate: %v", root, it.Err)
+	}
+	if leaves != len(content) {
+		t.Fatalf("state %x: leaf count mismatch: have %d, want %d", root, leaves, len(content))
+	}
+}
+
+// checkDisk verifies that exactly the nodes of the given state are persisted.
+func (pt *pathTester) checkDisk(t *testing.T, root common.Hash) {
+	t.Helper()
+
+	if have := pt.db.path.diskRoot(); have != root {
+		t.Fatalf("persisted root mismatch: have %x, want %x", have, root)
+	}
+	var want int
+	if root != emptyRoot {
+		tr, err := New(common.Hash{}, root, pt.db)
+		if err != nil {
+			t.Fatalf("failed to open state %x: %v", root, err)
+		}
+		for it := tr.NodeIterator(nil); it.Next(true); {
+			if it.Hash() != (common.Hash{}) {
+				want++
+			}
+		}
+	}
+	var have int
+	it := pt.diskdb.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if rawdb.IsPathTrieNodeKey(it.Key()) {
+			have++
+		}
+	}
+	if have != want {
+		t.Fatalf("persisted node count mismatch: have %d, want %d", have, want)
+	}
+}
+
+// Tests that the recent states are kept in memory, the older ones are flushed
+// to disk and that stale nodes are removed as new states are persisted.
+func TestPathDatabaseLayers(t *testing.T) {
+	pt := newPathTester()
+
+	root := emptyRoot
+	for i := 0; i < maxDiffLayers+72; i++ {
+		root = pt.update(t, root)
+	}
+	if len(pt.db.path.layers) != maxDiffLayers {
+		t.Fatalf("layer count mismatch: have %d, want %d", len(pt.db.path.layers), maxDiffLayers)
+	}
+	// The states not kept in memory anymore are unavailable, apart from the
+	// persisted one
+	persisted := len(pt.roots) - maxDiffLayers - 1
+	pt.checkDisk(t, pt.roots[persisted])
+	if _, err := New(common.Hash{}, pt.roots[persisted-1], pt.db); err == nil {
+		t.Fatalf("stale state %d still accessible", persisted-1)
+	}
+	for i := persisted; i < len(pt.roots); i++ {
+		pt.check(t, pt.roots[i])
+	}
+	// Persist the head state and ensure the others are gone
+	if err := pt.db.Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if len(pt.db.path.layers) != 0 || pt.db.path.size != 0 {
+		t.Fatalf("layers left after commit: %d, size %v", len(pt.db.path.layers), pt.db.path.size)
+	}
+	pt.checkDisk(t, root)
+	pt.check(t, root)
+
+	// Ensure a fresh database reads the persisted state
+	pt.db = NewDatabaseWithConfig(pt.diskdb, &Config{Scheme: rawdb.PathScheme})
+	pt.check(t, root)
+}
+
+// Tests that flushing a state discards the states forked off its parent.
+func TestPathDatabaseForks(t *testing.T) {
+	pt := newPathTester()
+
+	base := pt.update(t, emptyRoot)
+	left := pt.update(t, pt.update(t, base))
+	right := pt.update(t, base)
+	pt.check(t, left)
+	pt.check(t, right)
+
+	if err := pt.db.Commit(left, false, nil); err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	pt.checkDisk(t, left)
+	if _, err := New(common.Hash{}, right, pt.db); err == nil {
+		t.Fatalf("discarded fork still accessible")
+	}
+	if err := pt.db.UpdateState(common.Hash{0x01}, right, NewMergedNodeSet()); err == nil {
+		t.Fatalf("state added on top of a discarded fork")
+	}
+}
+
+// Tests that the persisted state can be rewound with the reverse diffs.
+func TestPathDatabaseRecover(t *testing.T) {
+	pt := newPathTester()
+
+	root := emptyRoot
+	for i := 0; i < 32; i++ {
+		root = pt.update(t, root)
+		if i%4 == 0 {
+			if err := pt.db.Commit(root, false, nil); err != nil {
+				t.Fatalf("failed to commit state: %v", err)
+			}
+		}
+	}
+	if err := pt.db.Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if pt.db.Recoverable(common.Hash{0x01}) {
+		t.Fatalf("unknown state reported recoverable")
+	}
+	for _, i := range []int{30, 17, 5} {
+		target := pt.roots[i]
+		if !pt.db.Recoverable(target) {
+			t.Fatalf("state %d not recoverable", i)
+		}
+		if err := pt.db.Recover(target); err != nil {
+			t.Fatalf("failed to recover state %d: %v", i, err)
+		}
+		pt.checkDisk(t, target)
+		pt.check(t, target)
+		if pt.db.Recoverable(pt.roots[i+1]) {
+			t.Fatalf("state %d recoverable after rewinding past it", i+1)
+		}
+	}
+	// New states can be added on top of the recovered one
+	root = pt.update(t, pt.roots[5])
+	if err := pt.db.Commit(root, false, nil); err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	pt.checkDisk(t, root)
+	pt.check(t, root)
+
+	// Rewinding to the empty state clears the disk
+	if err := pt.db.Recover(emptyRoot); err != nil {
+		t.Fatalf("failed to recover empty state: %v", err)
+	}
+	pt.checkDisk(t, emptyRoot)
+}
@@ -75,6 +75,19 @@ func NewStateTrie(owner common.Hash, root common.Hash, db *Database) (*StateTrie
 	return &StateTrie{trie: *trie, preimages: db.preimages}, nil
 }
 
+// NewStateTrieWithWitness creates a StateTrie like NewStateTrie, reporting all
+// the nodes it loads from the database to the given witness recorder.
+func NewStateTrieWithWitness(owner common.Hash, root common.Hash, db *Database, witness WitnessRecorder) (*StateTrie, error) {
+	if db == nil {
+		panic("trie.NewStateTrieWithWitness called without a database")
+	}
+	trie, err := NewWithWitness(owner, root, db, witness)
+	if err != nil {
+		return nil, err
+	}
+	return &StateTrie{trie: *trie, preimages: db.preimages}, nil
+}
+
 // Get returns the value for key stored in the trie.
 // The value bytes must not be modified by the caller.
 func (t *StateTrie) Get(key []byte) []byte {
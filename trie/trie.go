@@ -74,6 +74,17 @@ type Trie struct {
 	// tracer is the tool to track the trie changes.
 	// It will be reset after each commit operation.
 	tracer *tracer
+
+	// witness, if set, is notified of every node loaded from the database.
+	witness WitnessRecorder
+}
+
+// WitnessRecorder collects the trie nodes resolved from the database, e.g. to
+// assemble the witness needed to execute a block without the full state. It
+// may be shared by several tries, so implementations must be thread-safe.
+type WitnessRecorder interface {
+	// AddNode records the rlp-encoded blob of a resolved trie node.
+	AddNode(blob []byte)
 }
 
 // newFlag returns the cache flag value for a newly created node.
@@ -89,6 +100,7 @@ func (t *Trie) Copy() *Trie {
 		unhashed: t.unhashed,
 		db:       t.db,
 		tracer:   t.tracer.copy(),
+		witness:  t.witness,
 	}
 }
 
@@ -100,10 +112,25 @@ func (t *Trie) Copy() *Trie {
 // New will panic if db is nil and returns a MissingNodeError if root does
 // not exist in the database. Accessing the trie loads nodes from db on demand.
 func New(owner common.Hash, root common.Hash, db *Database) (*Trie, error) {
+	return newTrie(owner, root, db, nil)
+}
+
+// NewWithWitness creates a trie like New, reporting all the nodes it loads from
+// the database, starting with the root, to the given witness recorder.
+func NewWithWitness(owner common.Hash, root common.Hash, db *Database, witness WitnessRecorder) (*Trie, error) {
+	return newTrie(owner, root, db, witness)
+}
+
+func newTrie(owner common.Hash, root common.Hash, db *Database, witness WitnessRecorder) (*Trie, error) {
 	trie := &Trie{
-		owner: owner,
-		db:    db,
-		//tracer: newTracer(),
+		owner:   owner,
+		db:      db,
+		witness: witness,
+	}
+	// The deleted nodes are only tracked in the path scheme, where the stale
+	// nodes are removed from disk.
+	if db != nil && db.path != nil {
+		trie.tracer = newTracer()
 	}
 	if root != (common.Hash{}) && root != emptyRoot {
 		rootnode, err := trie.resolveHash(root[:], nil)
@@ -219,7 +246,7 @@ func (t *Trie) tryGetNode(origNode node, path []byte, pos int) (item []byte, new
 		if hash == nil {
 			return nil, origNode, 0, errors.New("non-consensus node")
 		}
-		blob, err := t.db.Node(common.BytesToHash(hash))
+		blob, err := t.resolveBlob(hash, path)
 		return blob, origNode, 1, err
 	}
 	// Path still needs to be traversed, descend into children
@@ -557,7 +584,22 @@ func (t *Trie) resolve(n node, prefix []byte) (node, error) {
 // node hash and path prefix.
 func (t *Trie) resolveHash(n hashNode, prefix []byte) (node, error) {
 	hash := common.BytesToHash(n)
-	if node := t.db.node(hash); node != nil {
+	if t.db.path != nil {
+		if blob := t.db.path.node(t.owner, prefix, hash); len(blob) != 0 {
+			t.tracer.onRead(prefix, blob)
+			if t.witness != nil {
+				t.witness.AddNode(blob)
+			}
+			return mustDecodeNodeUnsafe(n, blob), nil
+		}
+	} else if t.witness != nil {
+		// The witness needs the encoded node, load it instead of the cached
+		// decoded one.
+		if blob, _ := t.db.Node(hash); len(blob) != 0 {
+			t.witness.AddNode(blob)
+			return mustDecodeNode(n, blob), nil
+		}
+	} else if node := t.db.node(hash); node != nil {
 		return node, nil
 	}
 	return nil, &MissingNodeError{Owner: t.owner, NodeHash: hash, Path: prefix}
@@ -567,7 +609,13 @@ func (t *Trie) resolveHash(n hashNode, prefix []byte) (node, error) {
 // with the provided node hash and path prefix.
 func (t *Trie) resolveBlob(n hashNode, prefix []byte) ([]byte, error) {
 	hash := common.BytesToHash(n)
-	blob, _ := t.db.Node(hash)
+
+	var blob []byte
+	if t.db.path != nil {
+		blob = t.db.path.node(t.owner, prefix, hash)
+	} else {
+		blob, _ = t.db.Node(hash)
+	}
 	if len(blob) != 0 {
 		return blob, nil
 	}
@@ -592,6 +640,11 @@ func (t *Trie) Commit(collectLeaf bool) (common.Hash, *NodeSet, error) {
 	defer t.tracer.reset()
 
 	if t.root == nil {
+		// The persisted nodes of an emptied trie are deleted in the path scheme
+		h := newCommitter(t.owner, t.tracer, collectLeaf)
+		if nodes := h.commitDeletions(); nodes.Len() != 0 {
+			return emptyRoot, nodes, nil
+		}
 		return emptyRoot, nil, nil
 	}
 	// Derive the hash for all dirty nodes first. We hold the assumption
@@ -606,7 +659,7 @@ func (t *Trie) Commit(collectLeaf bool) (common.Hash, *NodeSet, error) {
 		t.root = hashedNode
 		return rootHash, nil, nil
 	}
-	h := newCommitter(t.owner, collectLeaf)
+	h := newCommitter(t.owner, t.tracer, collectLeaf)
 	newRoot, nodes, err := h.Commit(t.root)
 	if err != nil {
 		return common.Hash{}, nil, err
@@ -73,18 +73,30 @@ func TestMissingRoot(t *testing.T) {
 	}
 }
 
-func TestMissingNodeDisk(t *testing.T)    { testMissingNode(t, false) }
-func TestMissingNodeMemonly(t *testing.T) { testMissingNode(t, true) }
+// newTestDatabase creates a trie database storing the nodes in the given scheme.
+func newTestDatabase(diskdb ethdb.KeyValueStore, scheme string) *Database {
+	return NewDatabaseWithConfig(diskdb, &Config{Scheme: scheme})
+}
+
+func TestMissingNodeDisk(t *testing.T) {
+	testMissingNode(t, false, rawdb.HashScheme)
+	testMissingNode(t, false, rawdb.PathScheme)
+}
 
-func testMissingNode(t *testing.T, memonly bool) {
+func TestMissingNodeMemonly(t *testing.T) {
+	testMissingNode(t, true, rawdb.HashScheme)
+	testMissingNode(t, true, rawdb.PathScheme)
+}
+
+func testMissingNode(t *testing.T, memonly bool, scheme string) {
 	diskdb := memorydb.New()
-	triedb := NewDatabase(diskdb)
+	triedb := newTestDatabase(diskdb, scheme)
 
 	trie := NewEmpty(triedb)
 	updateString(trie, "120000", "qwerqwerqwerqwerqwerqwerqwerqwer")
 	updateString(trie, "123456", "asdfasdfasdfasdfasdfasdfasdfasdf")
 	root, nodes, _ := trie.Commit(false)
-	triedb.Update(NewWithNodeSet(nodes))
+	triedb.UpdateState(root, emptyRoot, NewWithNodeSet(nodes))
 	if !memonly {
 		triedb.Commit(root, true, nil)
 	}
@@ -116,7 +128,20 @@ func testMissingNode(t *testing.T, memonly bool) {
 	}
 
 	hash := common.HexToHash("0xe1d943cc8f061a0c0b98162830b970395ac9315654824bf21b73b891365262f9")
-	if memonly {
+	if scheme == rawdb.PathScheme {
+		var path []byte
+		trie, _ = New(common.Hash{}, root, triedb)
+		for it := trie.NodeIterator(nil); it.Next(true); {
+			if it.Hash() == hash {
+				path = common.CopyBytes(it.Path())
+			}
+		}
+		if memonly {
+			delete(triedb.path.index, pathNodeKey(common.Hash{}, path))
+		} else {
+			rawdb.DeleteAccountTrieNode(diskdb, path)
+		}
+	} else if memonly {
 		delete(triedb.dirties, hash)
 	} else {
 		diskdb.Delete(hash[:])
@@ -176,7 +201,12 @@ func TestInsert(t *testing.T) {
 }
 
 func TestGet(t *testing.T) {
-	db := NewDatabase(rawdb.NewMemoryDatabase())
+	testGet(t, rawdb.HashScheme)
+	testGet(t, rawdb.PathScheme)
+}
+
+func testGet(t *testing.T, scheme string) {
+	db := newTestDatabase(rawdb.NewMemoryDatabase(), scheme)
 	trie := NewEmpty(db)
 	updateString(trie, "doe", "reindeer")
 	updateString(trie, "dog", "puppy")
@@ -195,7 +225,7 @@ func TestGet(t *testing.T) {
 			return
 		}
 		root, nodes, _ := trie.Commit(false)
-		db.Update(NewWithNodeSet(nodes))
+		db.UpdateState(root, emptyRoot, NewWithNodeSet(nodes))
 		trie, _ = New(common.Hash{}, root, db)
 	}
 }
@@ -252,7 +282,12 @@ func TestEmptyValues(t *testing.T) {
 }
 
 func TestReplication(t *testing.T) {
-	triedb := NewDatabase(rawdb.NewMemoryDatabase())
+	testReplication(t, rawdb.HashScheme)
+	testReplication(t, rawdb.PathScheme)
+}
+
+func testReplication(t *testing.T, scheme string) {
+	triedb := newTestDatabase(rawdb.NewMemoryDatabase(), scheme)
 	trie := NewEmpty(triedb)
 	vals := []struct{ k, v string }{
 		{"do", "verb"},
@@ -270,7 +305,7 @@ func TestReplication(t *testing.T) {
 	if err != nil {
 		t.Fatalf("commit error: %v", err)
 	}
-	triedb.Update(NewWithNodeSet(nodes))
+	triedb.UpdateState(exp, emptyRoot, NewWithNodeSet(nodes))
 
 	// create a new trie on top of the database and check that lookups work.
 	trie2, err := New(common.Hash{}, exp, triedb)
@@ -292,7 +327,7 @@ func TestReplication(t *testing.T) {
 
 	// recreate the trie after commit
 	if nodes != nil {
-		triedb.Update(NewWithNodeSet(nodes))
+		triedb.UpdateState(hash, exp, NewWithNodeSet(nodes))
 	}
 	trie2, err = New(common.Hash{}, hash, triedb)
 	if err != nil {
@@ -355,7 +390,8 @@ func TestRandomCases(t *testing.T) {
 		{op: 1, key: common.Hex2Bytes("980c393656413a15c8da01978ed9f89feb80b502f58f2d640e3a2f5f7a99a7018f1b573befd92053ac6f78fca4a87268"), value: common.Hex2Bytes("")}, // step 24
 		{op: 1, key: common.Hex2Bytes("fd"), value: common.Hex2Bytes("")},                                                                                               // step 25
 	}
-	runRandTest(rt)
+	runRandTest(rt, rawdb.HashScheme)
+	runRandTest(rt, rawdb.PathScheme)
 }
 
 // randTest performs random trie operations.
@@ -410,10 +446,11 @@ func (randTest) Generate(r *rand.Rand, size int) reflect.Value {
 	return reflect.ValueOf(steps)
 }
 
-func runRandTest(rt randTest) bool {
+func runRandTest(rt randTest, scheme string) bool {
 	var (
-		triedb   = NewDatabase(memorydb.New())
+		triedb   = newTestDatabase(memorydb.New(), scheme)
 		tr       = NewEmpty(triedb)
+		root     = emptyRoot               // root of the last committed trie
 		values   = make(map[string]string) // tracks content of the trie
 		origTrie = NewEmpty(triedb)
 	)
@@ -445,8 +482,12 @@ func runRandTest(rt randTest) bool {
 				return false
 			}
 			if nodes != nil {
-				triedb.Update(NewWithNodeSet(nodes))
+				if err := triedb.UpdateState(hash, root, NewWithNodeSet(nodes)); err != nil {
+					rt[i].err = err
+					return false
+				}
 			}
+			root = hash
 			newtr, err := New(common.Hash{}, hash, triedb)
 			if err != nil {
 				rt[i].err = err
@@ -528,7 +569,13 @@ func runRandTest(rt randTest) bool {
 }
 
 func TestRandom(t *testing.T) {
-	if err := quick.Check(runRandTest, nil); err != nil {
+	testRandom(t, rawdb.HashScheme)
+	testRandom(t, rawdb.PathScheme)
+}
+
+func testRandom(t *testing.T, scheme string) {
+	run := func(rt randTest) bool { return runRandTest(rt, scheme) }
+	if err := quick.Check(run, nil); err != nil {
 		if cerr, ok := err.(*quick.CheckError); ok {
 			t.Fatalf("random test iteration %d failed: %s", cerr.Count, spew.Sdump(cerr.In))
 		}
@@ -1128,3 +1175,68 @@ func TestDecodeNode(t *testing.T) {
 		decodeNode(hash, elems)
 	}
 }
+
+// witnessSet is a WitnessRecorder collecting the node blobs into a set.
+type witnessSet map[string]struct{}
+
+func (set witnessSet) AddNode(blob []byte) { set[string(blob)] = struct{}{} }
+
+func TestWitnessHash(t *testing.T) { testWitness(t, rawdb.HashScheme) }
+func TestWitnessPath(t *testing.T) { testWitness(t, rawdb.PathScheme) }
+
+// Tests that the nodes recorded while modifying a trie are sufficient to repeat
+// the same modifications on a database holding nothing else.
+func testWitness(t *testing.T, scheme string) {
+	var (
+		db   = NewDatabaseWithConfig(rawdb.NewMemoryDatabase(), &Config{Scheme: scheme})
+		trie = NewEmpty(db)
+		keys [][]byte
+	)
+	for i := 0; i < 1000; i++ {
+		key := crypto.Keccak256(big.NewInt(int64(i)).Bytes())
+		trie.Update(key, key[:1+i%32])
+		keys = append(keys, key)
+	}
+	root, nodes, _ := trie.Commit(false)
+	if err := db.UpdateState(root, emptyRoot, NewWithNodeSet(nodes)); err != nil {
+		t.Fatalf("failed to update state: %v", err)
+	}
+	// Read some keys, modify others and delete enough to collapse nodes
+	modify := func(trie *Trie) common.Hash {
+		for i, key := range keys[:200] {
+			switch i % 4 {
+			case 0:
+				if val, err := trie.TryGet(key); err != nil || !bytes.Equal(val, key[:1+i%32]) {
+					t.Fatalf("key %d: value mismatch: %x, %v", i, val, err)
+				}
+			case 1:
+				if err := trie.TryUpdate(key, []byte{0x01}); err != nil {
+					t.Fatalf("key %d: failed to update: %v", i, err)
+				}
+			default:
+				if err := trie.TryDelete(key); err != nil {
+					t.Fatalf("key %d: failed to delete: %v", i, err)
+				}
+			}
+		}
+		return trie.Hash()
+	}
+	witness := make(witnessSet)
+	trie, err := NewWithWitness(common.Hash{}, root, db, witness)
+	if err != nil {
+		t.Fatalf("failed to open trie: %v", err)
+	}
+	want := modify(trie)
+
+	diskdb := rawdb.NewMemoryDatabase()
+	for blob := range witness {
+		rawdb.WriteTrieNode(diskdb, crypto.Keccak256Hash([]byte(blob)), []byte(blob))
+	}
+	trie, err = New(common.Hash{}, root, NewDatabase(diskdb))
+	if err != nil {
+		t.Fatalf("failed to open trie from witness: %v", err)
+	}
+	if have := modify(trie); have != want {
+		t.Fatalf("root mismatch: have %x, want %x", have, want)
+	}
+}
@@ -50,22 +50,20 @@ func newTracer() *tracer {
 	}
 }
 
-/*
 // onRead tracks the newly loaded trie node and caches the rlp-encoded blob internally.
 // Don't change the value outside of function since it's not deep-copied.
 func (t *tracer) onRead(key []byte, val []byte) {
-	// Tracer isn't used right now, remove this check later.
+	// The tracer is only enabled in the path scheme.
 	if t == nil {
 		return
 	}
 	t.origin[string(key)] = val
 }
-*/
 
 // onInsert tracks the newly inserted trie node. If it's already in the deletion set
 // (resurrected node), then just wipe it from the deletion set as the "untouched".
 func (t *tracer) onInsert(key []byte) {
-	// Tracer isn't used right now, remove this check later.
+	// The tracer is only enabled in the path scheme.
 	if t == nil {
 		return
 	}
@@ -80,7 +78,7 @@ func (t *tracer) onInsert(key []byte) {
 // in the addition set, then just wipe it from the addition set
 // as it's untouched.
 func (t *tracer) onDelete(key []byte) {
-	// Tracer isn't used right now, remove this check later.
+	// The tracer is only enabled in the path scheme.
 	if t == nil {
 		return
 	}
@@ -93,7 +91,7 @@ func (t *tracer) onDelete(key []byte) {
 
 // insertList returns the tracked inserted trie nodes in list format.
 func (t *tracer) insertList() [][]byte {
-	// Tracer isn't used right now, remove this check later.
+	// The tracer is only enabled in the path scheme.
 	if t == nil {
 		return nil
 	}
@@ -106,7 +104,7 @@ func (t *tracer) insertList() [][]byte {
 
 // deleteList returns the tracked deleted trie nodes in list format.
 func (t *tracer) deleteList() [][]byte {
-	// Tracer isn't used right now, remove this check later.
+	// The tracer is only enabled in the path scheme.
 	if t == nil {
 		return nil
 	}
@@ -117,7 +115,6 @@ func (t *tracer) deleteList() [][]byte {
 	return ret
 }
 
-/*
 // getPrev returns the cached original value of the specified node.
 func (t *tracer) getPrev(key []byte) []byte {
 	// Don't panic on uninitialized tracer, it's possible in testing.
@@ -126,11 +123,10 @@ func (t *tracer) getPrev(key []byte) []byte {
 	}
 	return t.origin[string(key)]
 }
-*/
 
 // reset clears the content tracked by tracer.
 func (t *tracer) reset() {
-	// Tracer isn't used right now, remove this check later.
+	// The tracer is only enabled in the path scheme.
 	if t == nil {
 		return
 	}
@@ -141,7 +137,7 @@ func (t *tracer) reset() {
 
 // copy returns a deep copied tracer instance.
 func (t *tracer) copy() *tracer {
-	// Tracer isn't used right now, remove this check later.
+	// The tracer is only enabled in the path scheme.
 	if t == nil {
 		return nil
 	}